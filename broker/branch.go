@@ -0,0 +1,22 @@
+package broker
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+// CreateBranchVersion adds a version of a pacticipant to a branch, creating
+// the pacticipant, branch and version if they do not exist
+func (c *Client) CreateBranchVersion(pacticipant string, branch string, version string) error {
+	log.Println("[DEBUG] pact broker: create branch version")
+
+	if pacticipant == "" || branch == "" || version == "" {
+		return types.NewError(types.ErrInvalidRequest, fmt.Errorf("pacticipant, branch and version are mandatory"))
+	}
+
+	path := "pacticipants/" + escape(pacticipant) + "/branches/" + escape(branch) + "/versions/" + escape(version)
+
+	return c.call("PUT", path, map[string]interface{}{}, nil)
+}
@@ -0,0 +1,35 @@
+package broker
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+func TestClient_CreateBranchVersion(t *testing.T) {
+	var method, path string
+	server, client := setupBroker(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		path = r.URL.EscapedPath()
+		w.WriteHeader(http.StatusCreated)
+	})
+	defer server.Close()
+
+	if err := client.CreateBranchVersion("My Provider", "feat/foo", "1.0.0"); err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	if method != "PUT" || path != "/pacticipants/My%20Provider/branches/feat%2Ffoo/versions/1.0.0" {
+		t.Fatalf("unexpected request: %s %s", method, path)
+	}
+}
+
+func TestClient_CreateBranchVersionInvalid(t *testing.T) {
+	client := &Client{BrokerURL: "http://localhost"}
+
+	if err := client.CreateBranchVersion("provider", "", "1.0.0"); !errors.Is(err, types.ErrInvalidRequest) {
+		t.Fatalf("expected an invalid request error but got '%v'", err)
+	}
+}
@@ -0,0 +1,235 @@
+// Package broker is a client for the Pact Broker HTTP API, covering the parts
+// of the API not exposed by the Pact CLI tools.
+package broker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+// Client communicates with a Pact Broker
+type Client struct {
+	// BrokerURL is the base URL of the Pact Broker e.g. https://broker.example.com
+	BrokerURL string
+
+	// Username for Pact Broker basic authentication. Optional
+	BrokerUsername string
+
+	// Password for Pact Broker basic authentication. Optional
+	BrokerPassword string
+
+	// BrokerToken is required when authenticating using the Bearer token mechanism
+	BrokerToken string
+
+	// TokenSource supplies the bearer token for each request when BrokerToken
+	// is not set, e.g. for short-lived OIDC tokens
+	TokenSource types.TokenSource
+
+	// HTTPClient is used to make requests. If set, Transport and ProxyURL are
+	// ignored.
+	HTTPClient *http.Client
+
+	// Transport is used to make requests e.g. to add custom headers or TLS
+	// configuration. Defaults to a transport that uses the HTTP_PROXY,
+	// HTTPS_PROXY and NO_PROXY environment variables.
+	Transport http.RoundTripper
+
+	// ProxyURL is the proxy to send requests through, overriding the
+	// environment. Ignored if Transport is set.
+	ProxyURL string
+
+	// MaxRetries is the number of times to retry a request that fails with a
+	// 5xx or a 429 response, or an idempotent request that fails with a network
+	// error. Defaults to 0 (no retries)
+	MaxRetries int
+
+	// RetryBackoff is the delay before the first retry, doubling with each
+	// subsequent retry. A Retry-After header takes precedence. Defaults to 1s
+	RetryBackoff time.Duration
+
+	// MaxRetryBackoff caps the delay between retries, including any requested
+	// by a Retry-After header. Defaults to 30s
+	MaxRetryBackoff time.Duration
+
+	// PageSize is the number of items requested per page when listing
+	// collections. Defaults to the broker's page size.
+	PageSize int
+}
+
+// ResponseError is returned when the Pact Broker responds with a non 2xx
+// status code. It is categorised as a types.ErrBroker, or types.ErrBrokerAuth
+// for a 401 or 403.
+type ResponseError struct {
+	// StatusCode of the response
+	StatusCode int
+
+	// Body of the response
+	Body string
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("pact broker responded with status %d: %s", e.StatusCode, e.Body)
+}
+
+// escape escapes each segment of a path, so that names containing "/" (e.g.
+// branches such as "feat/foo") are not mistaken for separate path segments
+func escape(segments ...string) string {
+	escaped := make([]string, len(segments))
+	for i, s := range segments {
+		escaped[i] = url.PathEscape(s)
+	}
+
+	return strings.Join(escaped, "/")
+}
+
+// url resolves path against the BrokerURL. Absolute URLs, such as the href of
+// a HAL link, are returned as is.
+func (c *Client) url(path string) string {
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return path
+	}
+
+	return strings.TrimSuffix(c.BrokerURL, "/") + "/" + path
+}
+
+// newRequest creates a request to the broker, with authentication. A new
+// request is created for each attempt, so that a fresh token is used.
+func (c *Client) newRequest(method string, path string, data []byte) (*http.Request, error) {
+	var body io.Reader
+	if data != nil {
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, c.url(path), body)
+	if err != nil {
+		return nil, types.NewError(types.ErrInvalidRequest, err)
+	}
+
+	req.Header.Set("Accept", "application/hal+json, application/json")
+	if data != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if c.BrokerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.BrokerToken)
+	} else if c.TokenSource != nil {
+		token, err := c.TokenSource.Token()
+		if err != nil {
+			return nil, types.NewError(types.ErrBrokerAuth, err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	} else if c.BrokerUsername != "" {
+		req.SetBasicAuth(c.BrokerUsername, c.BrokerPassword)
+	}
+
+	return req, nil
+}
+
+// httpClient returns the client used to send requests
+func (c *Client) httpClient() (*http.Client, error) {
+	if c.HTTPClient != nil {
+		return c.HTTPClient, nil
+	}
+
+	if c.Transport != nil {
+		return &http.Client{Transport: c.Transport}, nil
+	}
+
+	// http.DefaultTransport may have been replaced with another RoundTripper
+	transport := &http.Transport{}
+	if defaultTransport, ok := http.DefaultTransport.(*http.Transport); ok {
+		transport = defaultTransport.Clone()
+	}
+	transport.Proxy = http.ProxyFromEnvironment
+
+	if c.ProxyURL != "" {
+		proxy, err := url.Parse(c.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid 'ProxyURL': %v", err)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	return &http.Client{Transport: transport}, nil
+}
+
+// call sends a request to the broker, retrying if configured, and decodes any
+// response into result
+func (c *Client) call(method string, path string, content interface{}, result interface{}) error {
+	if c.BrokerURL == "" {
+		return types.NewError(types.ErrInvalidRequest, fmt.Errorf("'BrokerURL' is mandatory"))
+	}
+
+	var data []byte
+	if content != nil {
+		var err error
+		if data, err = json.Marshal(content); err != nil {
+			return err
+		}
+	}
+
+	client, err := c.httpClient()
+	if err != nil {
+		return types.NewError(types.ErrInvalidRequest, err)
+	}
+
+	var res *http.Response
+	var responseBody []byte
+	for attempt := 0; ; attempt++ {
+		req, err := c.newRequest(method, path, data)
+		if err != nil {
+			return err
+		}
+
+		log.Printf("[DEBUG] pact broker: %s %s\n", method, req.URL)
+
+		res, err = client.Do(req)
+		if err == nil {
+			responseBody, err = ioutil.ReadAll(res.Body)
+			res.Body.Close()
+		}
+
+		if attempt >= c.MaxRetries || !shouldRetry(method, res, err) {
+			if err != nil {
+				return types.NewError(types.ErrBroker, err)
+			}
+			break
+		}
+
+		delay := c.retryDelay(attempt, res)
+		log.Printf("[WARN] pact broker: request failed (%s), retrying in %s\n", describeFailure(res, err), delay)
+		sleep(delay)
+	}
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		category := types.ErrBroker
+		if res.StatusCode == http.StatusUnauthorized || res.StatusCode == http.StatusForbidden {
+			category = types.ErrBrokerAuth
+		}
+
+		return types.NewError(category, &ResponseError{
+			StatusCode: res.StatusCode,
+			Body:       strings.TrimSpace(string(responseBody)),
+		})
+	}
+
+	if result == nil || len(responseBody) == 0 {
+		return nil
+	}
+
+	if err = json.Unmarshal(responseBody, result); err != nil {
+		return types.NewError(types.ErrBroker, fmt.Errorf("unable to parse pact broker response: %v", err))
+	}
+
+	return nil
+}
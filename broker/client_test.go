@@ -0,0 +1,121 @@
+package broker
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+// setupBroker starts a fake broker, returning a client configured to use it
+func setupBroker(handler http.HandlerFunc) (*httptest.Server, *Client) {
+	server := httptest.NewServer(handler)
+
+	return server, &Client{BrokerURL: server.URL}
+}
+
+func TestClient_Authentication(t *testing.T) {
+	var auth string
+	server, client := setupBroker(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+	})
+	defer server.Close()
+
+	client.BrokerUsername = "foo"
+	client.BrokerPassword = "bar"
+	if err := client.call("GET", "", nil, nil); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if auth != "Basic Zm9vOmJhcg==" {
+		t.Fatalf("expected basic authentication but got '%s'", auth)
+	}
+
+	client.BrokerToken = "token"
+	if err := client.call("GET", "", nil, nil); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if auth != "Bearer token" {
+		t.Fatalf("expected bearer authentication but got '%s'", auth)
+	}
+}
+
+func TestClient_Errors(t *testing.T) {
+	tests := []struct {
+		status   int
+		category error
+	}{
+		{http.StatusUnauthorized, types.ErrBrokerAuth},
+		{http.StatusForbidden, types.ErrBrokerAuth},
+		{http.StatusNotFound, types.ErrBroker},
+		{http.StatusInternalServerError, types.ErrBroker},
+	}
+
+	for _, tt := range tests {
+		server, client := setupBroker(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+			fmt.Fprintln(w, "nope")
+		})
+
+		err := client.call("GET", "", nil, nil)
+		server.Close()
+
+		if !errors.Is(err, tt.category) {
+			t.Fatalf("expected status %d to be a '%v' but got '%v'", tt.status, tt.category, err)
+		}
+
+		var res *ResponseError
+		if !errors.As(err, &res) || res.StatusCode != tt.status || res.Body != "nope" {
+			t.Fatalf("expected a ResponseError but got '%v'", err)
+		}
+	}
+}
+
+func TestClient_InvalidResponse(t *testing.T) {
+	server, client := setupBroker(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "broken response")
+	})
+	defer server.Close()
+
+	var result map[string]interface{}
+	if err := client.call("GET", "", nil, &result); !errors.Is(err, types.ErrBroker) {
+		t.Fatalf("expected a broker error but got '%v'", err)
+	}
+}
+
+func TestClient_MissingURL(t *testing.T) {
+	client := &Client{}
+	if err := client.call("GET", "", nil, nil); !errors.Is(err, types.ErrInvalidRequest) {
+		t.Fatalf("expected an invalid request error but got '%v'", err)
+	}
+}
+
+func TestClient_TokenSource(t *testing.T) {
+	var auth []string
+	server, client := setupBroker(func(w http.ResponseWriter, r *http.Request) {
+		auth = append(auth, r.Header.Get("Authorization"))
+	})
+	defer server.Close()
+
+	tokens := 0
+	client.TokenSource = types.TokenSourceFunc(func() (string, error) {
+		tokens++
+		return fmt.Sprintf("token-%d", tokens), nil
+	})
+
+	client.call("GET", "", nil, nil)
+	client.call("GET", "", nil, nil)
+
+	if fmt.Sprint(auth) != "[Bearer token-1 Bearer token-2]" {
+		t.Fatalf("expected a token to be requested for each request but got %v", auth)
+	}
+
+	client.TokenSource = types.TokenSourceFunc(func() (string, error) {
+		return "", errors.New("token expired")
+	})
+	if err := client.call("GET", "", nil, nil); !errors.Is(err, types.ErrBrokerAuth) {
+		t.Fatalf("expected a broker authentication error but got '%v'", err)
+	}
+}
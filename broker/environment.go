@@ -0,0 +1,114 @@
+package broker
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+// Environment is an environment an application may be deployed or released
+// to e.g. "test" or "production"
+type Environment struct {
+	// UUID is assigned by the broker when the environment is created
+	UUID string `json:"uuid,omitempty"`
+
+	// Name of the environment, used when recording deployments. Required.
+	Name string `json:"name"`
+
+	// DisplayName is shown in the broker UI. Optional
+	DisplayName string `json:"displayName,omitempty"`
+
+	// Production is true for production environments
+	Production bool `json:"production"`
+
+	// Contacts for the environment. Optional
+	Contacts []Contact `json:"contacts,omitempty"`
+}
+
+// Contact is a person or team responsible for an environment
+type Contact struct {
+	// Name of the contact
+	Name string `json:"name"`
+
+	// Details e.g. email address or Slack channel
+	Details map[string]interface{} `json:"details,omitempty"`
+}
+
+type environmentsResponse struct {
+	Embedded struct {
+		Environments []Environment `json:"environments"`
+	} `json:"_embedded"`
+}
+
+// ListEnvironments returns all environments
+func (c *Client) ListEnvironments() ([]Environment, error) {
+	log.Println("[DEBUG] pact broker: list environments")
+
+	var res environmentsResponse
+	if err := c.call("GET", "environments", nil, &res); err != nil {
+		return nil, err
+	}
+
+	return res.Embedded.Environments, nil
+}
+
+// GetEnvironment returns the environment with the given UUID
+func (c *Client) GetEnvironment(uuid string) (*Environment, error) {
+	log.Println("[DEBUG] pact broker: get environment")
+
+	if uuid == "" {
+		return nil, types.NewError(types.ErrInvalidRequest, fmt.Errorf("environment UUID is mandatory"))
+	}
+
+	var env Environment
+	if err := c.call("GET", "environments/"+escape(uuid), nil, &env); err != nil {
+		return nil, err
+	}
+
+	return &env, nil
+}
+
+// CreateEnvironment creates an environment, returning it with the UUID
+// assigned by the broker
+func (c *Client) CreateEnvironment(env Environment) (*Environment, error) {
+	log.Println("[DEBUG] pact broker: create environment")
+
+	if env.Name == "" {
+		return nil, types.NewError(types.ErrInvalidRequest, fmt.Errorf("environment name is mandatory"))
+	}
+
+	var created Environment
+	if err := c.call("POST", "environments", env, &created); err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// UpdateEnvironment replaces the environment with the UUID of env
+func (c *Client) UpdateEnvironment(env Environment) (*Environment, error) {
+	log.Println("[DEBUG] pact broker: update environment")
+
+	if env.UUID == "" || env.Name == "" {
+		return nil, types.NewError(types.ErrInvalidRequest, fmt.Errorf("environment UUID and name are mandatory"))
+	}
+
+	var updated Environment
+	if err := c.call("PUT", "environments/"+escape(env.UUID), env, &updated); err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// DeleteEnvironment deletes the environment with the given UUID
+func (c *Client) DeleteEnvironment(uuid string) error {
+	log.Println("[DEBUG] pact broker: delete environment")
+
+	if uuid == "" {
+		return types.NewError(types.ErrInvalidRequest, fmt.Errorf("environment UUID is mandatory"))
+	}
+
+	return c.call("DELETE", "environments/"+escape(uuid), nil, nil)
+}
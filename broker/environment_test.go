@@ -0,0 +1,104 @@
+package broker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+func TestClient_Environments(t *testing.T) {
+	var requests []string
+	var created Environment
+
+	server, client := setupBroker(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/hal+json")
+
+		switch r.Method {
+		case "GET":
+			if r.URL.Path == "/environments" {
+				fmt.Fprint(w, `{"_embedded":{"environments":[{"uuid":"1234","name":"test","production":false},{"uuid":"5678","name":"production","production":true}]}}`)
+				return
+			}
+			fmt.Fprint(w, `{"uuid":"1234","name":"test","displayName":"Test","production":false}`)
+		case "POST", "PUT":
+			body, _ := ioutil.ReadAll(r.Body)
+			json.Unmarshal(body, &created)
+			created.UUID = "1234"
+			json.NewEncoder(w).Encode(created)
+		case "DELETE":
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+	defer server.Close()
+
+	envs, err := client.ListEnvironments()
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(envs) != 2 || envs[1].Name != "production" || !envs[1].Production {
+		t.Fatalf("unexpected environments: %+v", envs)
+	}
+
+	env, err := client.GetEnvironment("1234")
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if env.DisplayName != "Test" {
+		t.Fatalf("unexpected environment: %+v", env)
+	}
+
+	env, err = client.CreateEnvironment(Environment{
+		Name:       "staging",
+		Production: false,
+		Contacts:   []Contact{{Name: "Team A", Details: map[string]interface{}{"email": "a@example.com"}}},
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if env.UUID != "1234" || created.Contacts[0].Details["email"] != "a@example.com" {
+		t.Fatalf("unexpected environment: %+v", env)
+	}
+
+	env.DisplayName = "Staging"
+	if _, err = client.UpdateEnvironment(*env); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if created.DisplayName != "Staging" {
+		t.Fatalf("expected the environment to be updated but got %+v", created)
+	}
+
+	if err = client.DeleteEnvironment("1234"); err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	expected := []string{
+		"GET /environments",
+		"GET /environments/1234",
+		"POST /environments",
+		"PUT /environments/1234",
+		"DELETE /environments/1234",
+	}
+	if fmt.Sprint(requests) != fmt.Sprint(expected) {
+		t.Fatalf("expected requests %v but got %v", expected, requests)
+	}
+}
+
+func TestClient_EnvironmentsInvalid(t *testing.T) {
+	client := &Client{BrokerURL: "http://localhost"}
+
+	if _, err := client.CreateEnvironment(Environment{}); !errors.Is(err, types.ErrInvalidRequest) {
+		t.Fatalf("expected an invalid request error but got '%v'", err)
+	}
+	if _, err := client.UpdateEnvironment(Environment{Name: "test"}); !errors.Is(err, types.ErrInvalidRequest) {
+		t.Fatalf("expected an invalid request error but got '%v'", err)
+	}
+	if err := client.DeleteEnvironment(""); !errors.Is(err, types.ErrInvalidRequest) {
+		t.Fatalf("expected an invalid request error but got '%v'", err)
+	}
+}
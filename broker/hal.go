@@ -0,0 +1,188 @@
+package broker
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+// Link is a HAL link to a related broker resource
+type Link struct {
+	Href      string `json:"href"`
+	Title     string `json:"title,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Templated bool   `json:"templated,omitempty"`
+}
+
+// Expand substitutes params into a templated link, e.g. {provider}. Values are
+// escaped as path segments.
+func (l Link) Expand(params map[string]string) string {
+	href := l.Href
+	for k, v := range params {
+		href = strings.Replace(href, "{"+k+"}", url.PathEscape(v), -1)
+	}
+
+	return href
+}
+
+// Links are the HAL links of a resource, by relation. A relation may have one
+// or many links.
+type Links map[string][]Link
+
+// UnmarshalJSON accepts either a single link or an array of links for each
+// relation
+func (l *Links) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	links := make(Links, len(raw))
+	for rel, value := range raw {
+		var many []Link
+		if err := json.Unmarshal(value, &many); err == nil {
+			links[rel] = many
+			continue
+		}
+
+		var one Link
+		if err := json.Unmarshal(value, &one); err != nil {
+			// e.g. curies, which we have no use for
+			continue
+		}
+		links[rel] = []Link{one}
+	}
+	*l = links
+
+	return nil
+}
+
+// Get returns the first link for the relation
+func (l Links) Get(rel string) (Link, bool) {
+	if len(l[rel]) == 0 {
+		return Link{}, false
+	}
+
+	return l[rel][0], true
+}
+
+// Resource is a HAL resource returned by the broker
+type Resource struct {
+	Links    Links                      `json:"_links"`
+	Embedded map[string]json.RawMessage `json:"_embedded"`
+}
+
+// Index returns the root resource of the broker, which links to everything else
+func (c *Client) Index() (*Resource, error) {
+	log.Println("[DEBUG] pact broker: index")
+
+	var index Resource
+	if err := c.call("GET", "", nil, &index); err != nil {
+		return nil, err
+	}
+
+	return &index, nil
+}
+
+// Follow fetches the resource the relation rel of the broker index links to,
+// expanding any templated parameters
+func (c *Client) Follow(rel string, params map[string]string) (*Resource, error) {
+	href, err := c.indexLink(rel, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var res Resource
+	if err = c.call("GET", href, nil, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// indexLink returns the expanded href of the relation rel of the broker index
+func (c *Client) indexLink(rel string, params map[string]string) (string, error) {
+	index, err := c.Index()
+	if err != nil {
+		return "", err
+	}
+
+	link, ok := index.Links.Get(rel)
+	if !ok {
+		return "", types.NewError(types.ErrBroker, fmt.Errorf("the pact broker does not support '%s'", rel))
+	}
+
+	return link.Expand(params), nil
+}
+
+// pager iterates over the embedded items of a collection, following "next"
+// links until every page has been read
+type pager struct {
+	client   *Client
+	method   string
+	href     string
+	content  interface{}
+	embedded string
+
+	items   []json.RawMessage
+	current json.RawMessage
+	err     error
+}
+
+// next advances to the next item, fetching the next page if required
+func (p *pager) next() bool {
+	for len(p.items) == 0 {
+		if p.href == "" || p.err != nil {
+			return false
+		}
+
+		var page Resource
+		if p.err = p.client.call(p.method, p.href, p.content, &page); p.err != nil {
+			return false
+		}
+
+		p.href = ""
+		if next, ok := page.Links.Get("next"); ok {
+			p.href = next.Href
+		}
+
+		if raw, ok := page.Embedded[p.embedded]; ok {
+			if err := json.Unmarshal(raw, &p.items); err != nil {
+				p.err = types.NewError(types.ErrBroker, fmt.Errorf("unable to parse pact broker response: %v", err))
+				return false
+			}
+		}
+	}
+
+	p.current, p.items = p.items[0], p.items[1:]
+
+	return true
+}
+
+// decode the current item into v
+func (p *pager) decode(v interface{}) bool {
+	if err := json.Unmarshal(p.current, v); err != nil {
+		p.err = types.NewError(types.ErrBroker, fmt.Errorf("unable to parse pact broker response: %v", err))
+		return false
+	}
+
+	return true
+}
+
+// Err returns the first error encountered whilst iterating, if any
+func (p *pager) Err() error {
+	return p.err
+}
+
+// pageQuery adds the page size to the first page of a collection
+func (c *Client) pageQuery(path string) string {
+	if c.PageSize <= 0 {
+		return path
+	}
+
+	return fmt.Sprintf("%s?size=%d", path, c.PageSize)
+}
@@ -0,0 +1,71 @@
+package broker
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestLinks_UnmarshalJSON(t *testing.T) {
+	var res Resource
+	err := json.Unmarshal([]byte(`{"_links":{
+		"self":{"href":"http://broker/pacticipants/foo"},
+		"pb:pacts":[{"href":"http://broker/1","name":"one"},{"href":"http://broker/2","name":"two"}],
+		"curies":[{"name":"pb","href":"http://broker/doc/{rel}","templated":true}],
+		"broken":"nope"
+	}}`), &res)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	if link, ok := res.Links.Get("self"); !ok || link.Href != "http://broker/pacticipants/foo" {
+		t.Fatalf("unexpected self link: %+v", link)
+	}
+	if len(res.Links["pb:pacts"]) != 2 || res.Links["pb:pacts"][1].Name != "two" {
+		t.Fatalf("unexpected pacts links: %+v", res.Links["pb:pacts"])
+	}
+	if _, ok := res.Links.Get("broken"); ok {
+		t.Fatal("expected an invalid link to be ignored")
+	}
+	if _, ok := res.Links.Get("missing"); ok {
+		t.Fatal("expected a missing link not to be found")
+	}
+}
+
+func TestLink_Expand(t *testing.T) {
+	link := Link{Href: "http://broker/pacts/provider/{provider}/consumer/{consumer}", Templated: true}
+
+	got := link.Expand(map[string]string{"provider": "My Provider", "consumer": "a/b"})
+	if got != "http://broker/pacts/provider/My%20Provider/consumer/a%2Fb" {
+		t.Fatalf("unexpected expanded link '%s'", got)
+	}
+}
+
+func TestClient_Follow(t *testing.T) {
+	server, client := setupBroker(nil)
+	defer server.Close()
+
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			fmt.Fprintf(w, `{"_links":{"pb:latest-provider-pacts":{"href":"%s/pacts/provider/{provider}/latest","templated":true}}}`, server.URL)
+		case "/pacts/provider/bobby/latest":
+			fmt.Fprint(w, `{"_links":{"pb:pacts":[{"href":"http://broker/1"}]}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	res, err := client.Follow("pb:latest-provider-pacts", map[string]string{"provider": "bobby"})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if link, _ := res.Links.Get("pb:pacts"); link.Href != "http://broker/1" {
+		t.Fatalf("unexpected resource: %+v", res)
+	}
+
+	if _, err = client.Follow("pb:unknown", nil); err == nil {
+		t.Fatal("expected an error following an unknown relation")
+	}
+}
@@ -0,0 +1,155 @@
+package broker
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+// MatrixSelector selects the versions of a pacticipant to include in the
+// matrix. Without a version, branch, tag or Latest, all versions are
+// included.
+type MatrixSelector struct {
+	// Pacticipant name. Required
+	Pacticipant string
+
+	// Version number of the pacticipant
+	Version string
+
+	// Branch of the pacticipant, used with Latest to select the latest version
+	// on the branch
+	Branch string
+
+	// Tag of the pacticipant, used with Latest to select the latest version
+	// with the tag
+	Tag string
+
+	// Latest selects the latest version, of the Branch or Tag if given
+	Latest bool
+}
+
+// MatrixQuery describes which rows of the matrix to return
+// See https://docs.pact.io/pact_broker/matrix
+type MatrixQuery struct {
+	// Selectors for the pacticipant versions. Required
+	Selectors []MatrixSelector
+
+	// LatestBy returns only the latest row for each consumer version ("cvp"),
+	// or each consumer and provider version pair ("cvpv"). Optional
+	LatestBy string
+
+	// Limit is the maximum number of rows to return. Optional
+	Limit int
+}
+
+// MatrixSummary summarises whether the selected versions can be deployed
+// together
+type MatrixSummary struct {
+	Deployable *bool  `json:"deployable"`
+	Reason     string `json:"reason"`
+	Success    int    `json:"success"`
+	Failed     int    `json:"failed"`
+	Unknown    int    `json:"unknown"`
+}
+
+// MatrixPacticipant is the consumer or provider of a row of the matrix
+type MatrixPacticipant struct {
+	Name    string `json:"name"`
+	Version struct {
+		Number   string `json:"number"`
+		Branch   string `json:"branch,omitempty"`
+		Branches []struct {
+			Name string `json:"name"`
+		} `json:"branches,omitempty"`
+		Tags []struct {
+			Name   string `json:"name"`
+			Latest bool   `json:"latest"`
+		} `json:"tags,omitempty"`
+	} `json:"version"`
+}
+
+// MatrixRow is the result of verifying (if at all) the pact between a
+// consumer version and a provider version
+type MatrixRow struct {
+	Consumer MatrixPacticipant `json:"consumer"`
+	Provider MatrixPacticipant `json:"provider"`
+	Pact     struct {
+		CreatedAt string `json:"createdAt"`
+		Links     Links  `json:"_links"`
+	} `json:"pact"`
+
+	// VerificationResult is nil if the pact has not been verified by the
+	// provider version
+	VerificationResult *struct {
+		Success    bool   `json:"success"`
+		VerifiedAt string `json:"verifiedAt"`
+		Links      Links  `json:"_links"`
+	} `json:"verificationResult"`
+}
+
+// Matrix is the compatibility matrix of the selected pacticipant versions
+type Matrix struct {
+	Summary MatrixSummary `json:"summary"`
+	Rows    []MatrixRow   `json:"matrix"`
+	Notices []Notice      `json:"notices,omitempty"`
+}
+
+// Matrix queries the compatibility matrix
+func (c *Client) Matrix(query MatrixQuery) (*Matrix, error) {
+	log.Println("[DEBUG] pact broker: matrix")
+
+	if len(query.Selectors) == 0 {
+		return nil, types.NewError(types.ErrInvalidRequest, fmt.Errorf("at least one matrix selector is mandatory"))
+	}
+
+	// The broker groups the parameters of each selector by the order they
+	// appear in, starting a new selector with each pacticipant
+	var params []string
+	param := func(name string, value string) {
+		if value != "" {
+			params = append(params, url.QueryEscape(name)+"="+url.QueryEscape(value))
+		}
+	}
+
+	for _, s := range query.Selectors {
+		if s.Pacticipant == "" {
+			return nil, types.NewError(types.ErrInvalidRequest, fmt.Errorf("pacticipant is mandatory for each matrix selector"))
+		}
+
+		param("q[][pacticipant]", s.Pacticipant)
+		param("q[][version]", s.Version)
+		param("q[][branch]", s.Branch)
+		param("q[][tag]", s.Tag)
+		if s.Latest {
+			param("q[][latest]", "true")
+		}
+	}
+	param("latestby", query.LatestBy)
+	if query.Limit > 0 {
+		param("limit", strconv.Itoa(query.Limit))
+	}
+
+	var matrix Matrix
+	if err := c.call("GET", "matrix?"+strings.Join(params, "&"), nil, &matrix); err != nil {
+		return nil, err
+	}
+
+	return &matrix, nil
+}
+
+// MatrixForPair returns the latest verification of each consumer version by
+// each provider version, for the given consumer and provider
+func (c *Client) MatrixForPair(consumer string, provider string) (*Matrix, error) {
+	if consumer == "" || provider == "" {
+		return nil, types.NewError(types.ErrInvalidRequest, fmt.Errorf("consumer and provider are mandatory"))
+	}
+
+	return c.Matrix(MatrixQuery{
+		Selectors: []MatrixSelector{{Pacticipant: consumer}, {Pacticipant: provider}},
+		LatestBy:  "cvpv",
+	})
+}
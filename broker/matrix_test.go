@@ -0,0 +1,101 @@
+package broker
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+func TestClient_Matrix(t *testing.T) {
+	var query string
+	server, client := setupBroker(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		fmt.Fprint(w, `{
+			"summary": {"deployable": false, "reason": "one or more verifications failed", "success": 1, "failed": 1, "unknown": 0},
+			"matrix": [
+				{
+					"consumer": {"name": "consumer", "version": {"number": "1.0.0", "branch": "main"}},
+					"provider": {"name": "provider", "version": {"number": "2.0.0"}},
+					"pact": {"createdAt": "2021-01-01T00:00:00+00:00", "_links": {"self": {"href": "http://broker/pact"}}},
+					"verificationResult": {"success": false, "verifiedAt": "2021-01-02T00:00:00+00:00"}
+				},
+				{
+					"consumer": {"name": "consumer", "version": {"number": "1.0.1"}},
+					"provider": {"name": "provider", "version": {"number": "2.0.0"}},
+					"pact": {"createdAt": "2021-01-03T00:00:00+00:00"},
+					"verificationResult": null
+				}
+			]
+		}`)
+	})
+	defer server.Close()
+
+	matrix, err := client.Matrix(MatrixQuery{
+		Selectors: []MatrixSelector{
+			{Pacticipant: "consumer", Branch: "feat/foo", Latest: true},
+			{Pacticipant: "provider", Version: "2.0.0"},
+		},
+		LatestBy: "cvpv",
+		Limit:    10,
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	expected := "q%5B%5D%5Bpacticipant%5D=consumer&q%5B%5D%5Bbranch%5D=feat%2Ffoo&q%5B%5D%5Blatest%5D=true" +
+		"&q%5B%5D%5Bpacticipant%5D=provider&q%5B%5D%5Bversion%5D=2.0.0&latestby=cvpv&limit=10"
+	if query != expected {
+		t.Fatalf("expected query '%s' but got '%s'", expected, query)
+	}
+
+	if matrix.Summary.Deployable == nil || *matrix.Summary.Deployable || matrix.Summary.Failed != 1 {
+		t.Fatalf("unexpected summary %+v", matrix.Summary)
+	}
+	if len(matrix.Rows) != 2 {
+		t.Fatalf("expected 2 rows but got %d", len(matrix.Rows))
+	}
+	if row := matrix.Rows[0]; row.Consumer.Version.Branch != "main" || row.VerificationResult == nil || row.VerificationResult.Success {
+		t.Fatalf("unexpected row %+v", row)
+	}
+	if matrix.Rows[1].VerificationResult != nil {
+		t.Fatal("expected the second row not to be verified")
+	}
+}
+
+func TestClient_MatrixForPair(t *testing.T) {
+	var query string
+	server, client := setupBroker(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		fmt.Fprint(w, `{"summary": {"deployable": null}, "matrix": []}`)
+	})
+	defer server.Close()
+
+	matrix, err := client.MatrixForPair("consumer", "provider")
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	if query != "q%5B%5D%5Bpacticipant%5D=consumer&q%5B%5D%5Bpacticipant%5D=provider&latestby=cvpv" {
+		t.Fatalf("unexpected query '%s'", query)
+	}
+	if matrix.Summary.Deployable != nil {
+		t.Fatal("expected deployable to be unknown")
+	}
+}
+
+func TestClient_MatrixInvalid(t *testing.T) {
+	client := &Client{BrokerURL: "http://localhost"}
+
+	if _, err := client.Matrix(MatrixQuery{}); !errors.Is(err, types.ErrInvalidRequest) {
+		t.Fatalf("expected an invalid request error but got '%v'", err)
+	}
+	if _, err := client.Matrix(MatrixQuery{Selectors: []MatrixSelector{{Version: "1.0.0"}}}); !errors.Is(err, types.ErrInvalidRequest) {
+		t.Fatalf("expected an invalid request error but got '%v'", err)
+	}
+	if _, err := client.MatrixForPair("consumer", ""); !errors.Is(err, types.ErrInvalidRequest) {
+		t.Fatalf("expected an invalid request error but got '%v'", err)
+	}
+}
@@ -0,0 +1,182 @@
+package broker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+// PactSelector selects the consumer version of a pact. Without a version,
+// branch or tag the latest pact is selected.
+type PactSelector struct {
+	// Version number of the consumer
+	Version string
+
+	// Branch of the consumer, selecting its latest pact on the branch
+	Branch string
+
+	// Tag of the consumer, selecting its latest pact with the tag
+	Tag string
+}
+
+// path returns the path of the pact selected between consumer and provider
+func (s PactSelector) path(consumer string, provider string) (string, error) {
+	base := "pacts/provider/" + escape(provider) + "/consumer/" + escape(consumer)
+
+	switch {
+	case s.Version != "" && s.Branch == "" && s.Tag == "":
+		return base + "/version/" + escape(s.Version), nil
+	case s.Branch != "" && s.Version == "" && s.Tag == "":
+		return base + "/branch/" + escape(s.Branch) + "/latest", nil
+	case s.Tag != "" && s.Version == "" && s.Branch == "":
+		return base + "/latest/" + escape(s.Tag), nil
+	case s.Version == "" && s.Branch == "" && s.Tag == "":
+		return base + "/latest", nil
+	}
+
+	return "", fmt.Errorf("only one of Version, Branch or Tag may be selected")
+}
+
+// Pact is a pact published to the broker
+type Pact struct {
+	// Content is the pact document, as published
+	Content json.RawMessage
+
+	// ConsumerVersion is the number of the consumer version the pact belongs to
+	ConsumerVersion string
+
+	// Links of the pact resource, e.g. to its verification results
+	Links Links
+}
+
+// PublishPact publishes the pact document for the consumer version, first
+// tagging the version with any tags as the Pact CLI tools do. The consumer and
+// provider are those named in the pact.
+func (c *Client) PublishPact(content []byte, consumerVersion string, tags ...string) error {
+	log.Println("[DEBUG] pact broker: publish pact")
+
+	var pact struct {
+		Consumer struct {
+			Name string `json:"name"`
+		} `json:"consumer"`
+		Provider struct {
+			Name string `json:"name"`
+		} `json:"provider"`
+	}
+	if err := json.Unmarshal(content, &pact); err != nil || pact.Consumer.Name == "" || pact.Provider.Name == "" {
+		return types.NewError(types.ErrInvalidRequest, fmt.Errorf("unable to find the consumer and provider of the pact"))
+	}
+	if consumerVersion == "" {
+		return types.NewError(types.ErrInvalidRequest, fmt.Errorf("consumer version is mandatory"))
+	}
+
+	version := "pacticipants/" + escape(pact.Consumer.Name) + "/versions/" + escape(consumerVersion)
+	for _, tag := range tags {
+		if err := c.call("PUT", version+"/tags/"+escape(tag), map[string]interface{}{}, nil); err != nil {
+			return err
+		}
+	}
+
+	path := "pacts/provider/" + escape(pact.Provider.Name) + "/consumer/" + escape(pact.Consumer.Name) + "/version/" + escape(consumerVersion)
+
+	return c.call("PUT", path, json.RawMessage(content), nil)
+}
+
+// VerificationResult is the result of a provider version verifying a pact
+type VerificationResult struct {
+	Success                    bool                   `json:"success"`
+	ProviderName               string                 `json:"providerName"`
+	ProviderApplicationVersion string                 `json:"providerApplicationVersion"`
+	VerificationDate           string                 `json:"verificationDate"`
+	BuildURL                   string                 `json:"buildUrl,omitempty"`
+	TestResults                map[string]interface{} `json:"testResults,omitempty"`
+	Links                      Links                  `json:"_links,omitempty"`
+}
+
+// GetPact returns the pact between consumer and provider for the selected
+// consumer version
+func (c *Client) GetPact(consumer string, provider string, selector PactSelector) (*Pact, error) {
+	log.Println("[DEBUG] pact broker: get pact")
+
+	if consumer == "" || provider == "" {
+		return nil, types.NewError(types.ErrInvalidRequest, fmt.Errorf("consumer and provider are mandatory"))
+	}
+
+	path, err := selector.path(consumer, provider)
+	if err != nil {
+		return nil, types.NewError(types.ErrInvalidRequest, err)
+	}
+
+	return c.getPact(path)
+}
+
+// GetPactAt returns the pact at the given URL, e.g. that of a pact selected by
+// PactsForVerification
+func (c *Client) GetPactAt(url string) (*Pact, error) {
+	log.Println("[DEBUG] pact broker: get pact at", url)
+
+	if url == "" {
+		return nil, types.NewError(types.ErrInvalidRequest, fmt.Errorf("pact URL is mandatory"))
+	}
+
+	return c.getPact(url)
+}
+
+func (c *Client) getPact(path string) (*Pact, error) {
+	var content json.RawMessage
+	if err := c.call("GET", path, nil, &content); err != nil {
+		return nil, err
+	}
+
+	var resource Resource
+	if err := json.Unmarshal(content, &resource); err != nil {
+		return nil, types.NewError(types.ErrBroker, fmt.Errorf("unable to parse pact broker response: %v", err))
+	}
+
+	pact := &Pact{
+		Content: content,
+		Links:   resource.Links,
+	}
+	if version, ok := resource.Links.Get("pb:consumer-version"); ok {
+		pact.ConsumerVersion = version.Name
+	}
+
+	return pact, nil
+}
+
+// LatestVerificationResult returns the latest result of verifying the selected
+// pact between consumer and provider, or nil if it has not been verified
+func (c *Client) LatestVerificationResult(consumer string, provider string, selector PactSelector) (*VerificationResult, error) {
+	log.Println("[DEBUG] pact broker: latest verification result")
+
+	pact, err := c.GetPact(consumer, provider, selector)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.LatestVerificationResultOf(pact)
+}
+
+// LatestVerificationResultOf returns the latest result of verifying the pact,
+// e.g. as returned by GetPactAt, or nil if it has not been verified
+func (c *Client) LatestVerificationResultOf(pact *Pact) (*VerificationResult, error) {
+	link, ok := pact.Links.Get("pb:latest-verification-results")
+	if !ok {
+		return nil, types.NewError(types.ErrBroker, fmt.Errorf("the pact broker does not support 'pb:latest-verification-results'"))
+	}
+
+	var result VerificationResult
+	if err := c.call("GET", link.Href, nil, &result); err != nil {
+		var re *ResponseError
+		if errors.As(err, &re) && re.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,140 @@
+package broker
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+func TestClient_GetPact(t *testing.T) {
+	var paths []string
+	server, client := setupBroker(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.EscapedPath())
+		fmt.Fprint(w, `{
+			"consumer": {"name": "consumer"},
+			"interactions": [],
+			"_links": {"pb:consumer-version": {"name": "1.0.0", "href": "http://broker/version"}}
+		}`)
+	})
+	defer server.Close()
+
+	selectors := []PactSelector{{}, {Version: "1.0.0"}, {Branch: "feat/foo"}, {Tag: "prod"}}
+	for _, selector := range selectors {
+		pact, err := client.GetPact("My Consumer", "provider", selector)
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		if pact.ConsumerVersion != "1.0.0" {
+			t.Fatalf("expected consumer version 1.0.0 but got '%s'", pact.ConsumerVersion)
+		}
+		if len(pact.Content) == 0 {
+			t.Fatal("expected the pact content")
+		}
+	}
+
+	expected := []string{
+		"/pacts/provider/provider/consumer/My%20Consumer/latest",
+		"/pacts/provider/provider/consumer/My%20Consumer/version/1.0.0",
+		"/pacts/provider/provider/consumer/My%20Consumer/branch/feat%2Ffoo/latest",
+		"/pacts/provider/provider/consumer/My%20Consumer/latest/prod",
+	}
+	for i, path := range expected {
+		if paths[i] != path {
+			t.Fatalf("expected path '%s' but got '%s'", path, paths[i])
+		}
+	}
+}
+
+func TestClient_PublishPact(t *testing.T) {
+	var requests []string
+	var published string
+	server, client := setupBroker(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.EscapedPath())
+		if strings.HasPrefix(r.URL.Path, "/pacts/") {
+			body, _ := ioutil.ReadAll(r.Body)
+			published = string(body)
+		}
+	})
+	defer server.Close()
+
+	content := `{"consumer":{"name":"My Consumer"},"provider":{"name":"provider"},"interactions":[]}`
+	if err := client.PublishPact([]byte(content), "1.0.0", "feat/foo"); err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	expected := []string{
+		"PUT /pacticipants/My%20Consumer/versions/1.0.0/tags/feat%2Ffoo",
+		"PUT /pacts/provider/provider/consumer/My%20Consumer/version/1.0.0",
+	}
+	if fmt.Sprint(requests) != fmt.Sprint(expected) {
+		t.Fatalf("expected requests %v but got %v", expected, requests)
+	}
+	if published != content {
+		t.Fatalf("expected the pact to be published as is but got %s", published)
+	}
+
+	if err := client.PublishPact([]byte(`{"consumer":{"name":"My Consumer"}}`), "1.0.0"); !errors.Is(err, types.ErrInvalidRequest) {
+		t.Fatalf("expected an invalid request error without a provider but got '%v'", err)
+	}
+}
+
+func TestClient_GetPactInvalid(t *testing.T) {
+	client := &Client{BrokerURL: "http://localhost"}
+
+	if _, err := client.GetPact("consumer", "provider", PactSelector{Tag: "prod", Branch: "main"}); !errors.Is(err, types.ErrInvalidRequest) {
+		t.Fatalf("expected an invalid request error but got '%v'", err)
+	}
+	if _, err := client.GetPact("", "provider", PactSelector{}); !errors.Is(err, types.ErrInvalidRequest) {
+		t.Fatalf("expected an invalid request error but got '%v'", err)
+	}
+}
+
+func TestClient_LatestVerificationResult(t *testing.T) {
+	var url string
+	server, client := setupBroker(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/pacts/provider/provider/consumer/consumer/latest":
+			fmt.Fprintf(w, `{"_links": {"pb:latest-verification-results": {"href": "%s/verification-results/latest"}}}`, url)
+		case "/verification-results/latest":
+			fmt.Fprint(w, `{"success": true, "providerName": "provider", "providerApplicationVersion": "2.0.0", "verificationDate": "2021-01-02"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer server.Close()
+	url = server.URL
+
+	result, err := client.LatestVerificationResult("consumer", "provider", PactSelector{})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if !result.Success || result.ProviderApplicationVersion != "2.0.0" {
+		t.Fatalf("unexpected verification result %+v", result)
+	}
+}
+
+func TestClient_LatestVerificationResultUnverified(t *testing.T) {
+	var url string
+	server, client := setupBroker(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/pacts/provider/provider/consumer/consumer/latest" {
+			fmt.Fprintf(w, `{"_links": {"pb:latest-verification-results": {"href": "%s/verification-results/latest"}}}`, url)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer server.Close()
+	url = server.URL
+
+	result, err := client.LatestVerificationResult("consumer", "provider", PactSelector{})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if result != nil {
+		t.Fatalf("expected no verification result but got %+v", result)
+	}
+}
@@ -0,0 +1,216 @@
+package broker
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+// Pacticipant is a consumer or provider application known to the broker
+type Pacticipant struct {
+	Name          string `json:"name"`
+	DisplayName   string `json:"displayName,omitempty"`
+	RepositoryURL string `json:"repositoryUrl,omitempty"`
+	MainBranch    string `json:"mainBranch,omitempty"`
+	CreatedAt     string `json:"createdAt,omitempty"`
+	Links         Links  `json:"_links,omitempty"`
+
+	// Labels of the pacticipant e.g. "backend" or "team-payments", which are
+	// managed with AddLabel and RemoveLabel
+	Labels []string `json:"-"`
+}
+
+// pacticipantResponse is a pacticipant as returned by the broker, with its
+// labels embedded
+type pacticipantResponse struct {
+	Pacticipant
+	Embedded struct {
+		Labels []struct {
+			Name string `json:"name"`
+		} `json:"labels"`
+	} `json:"_embedded"`
+}
+
+func (r pacticipantResponse) pacticipant() Pacticipant {
+	p := r.Pacticipant
+	p.Labels = nil
+	for _, label := range r.Embedded.Labels {
+		p.Labels = append(p.Labels, label.Name)
+	}
+
+	return p
+}
+
+// Version is a version of a pacticipant
+type Version struct {
+	Number    string `json:"number"`
+	BuildURL  string `json:"buildUrl,omitempty"`
+	CreatedAt string `json:"createdAt,omitempty"`
+	Links     Links  `json:"_links,omitempty"`
+}
+
+// PacticipantIterator iterates over pacticipants, fetching pages as required
+//
+//	it := client.Pacticipants()
+//	for it.Next() {
+//		fmt.Println(it.Pacticipant().Name)
+//	}
+//	if err := it.Err(); err != nil { ... }
+type PacticipantIterator struct {
+	pager
+	value Pacticipant
+}
+
+// Next advances to the next pacticipant, returning false when there are no
+// more or an error occurs
+func (it *PacticipantIterator) Next() bool {
+	if !it.next() {
+		return false
+	}
+	var res pacticipantResponse
+	ok := it.decode(&res)
+	it.value = res.pacticipant()
+
+	return ok
+}
+
+// Pacticipant returns the current pacticipant
+func (it *PacticipantIterator) Pacticipant() Pacticipant {
+	return it.value
+}
+
+// Pacticipants lists all pacticipants
+func (c *Client) Pacticipants() *PacticipantIterator {
+	log.Println("[DEBUG] pact broker: list pacticipants")
+
+	return &PacticipantIterator{
+		pager: pager{
+			client:   c,
+			method:   "GET",
+			href:     c.pageQuery("pacticipants"),
+			embedded: "pacticipants",
+		},
+	}
+}
+
+// VersionIterator iterates over versions of a pacticipant, fetching pages as
+// required
+type VersionIterator struct {
+	pager
+	value Version
+}
+
+// Next advances to the next version, returning false when there are no more or
+// an error occurs
+func (it *VersionIterator) Next() bool {
+	if !it.next() {
+		return false
+	}
+	it.value = Version{}
+
+	return it.decode(&it.value)
+}
+
+// Version returns the current version
+func (it *VersionIterator) Version() Version {
+	return it.value
+}
+
+// Versions lists the versions of a pacticipant, most recent first
+func (c *Client) Versions(pacticipant string) *VersionIterator {
+	log.Println("[DEBUG] pact broker: list versions")
+
+	it := &VersionIterator{
+		pager: pager{
+			client:   c,
+			method:   "GET",
+			href:     c.pageQuery("pacticipants/" + escape(pacticipant) + "/versions"),
+			embedded: "versions",
+		},
+	}
+
+	if pacticipant == "" {
+		it.href = ""
+		it.err = types.NewError(types.ErrInvalidRequest, fmt.Errorf("pacticipant is mandatory"))
+	}
+
+	return it
+}
+
+// GetPacticipant returns the pacticipant with the given name, and its labels
+func (c *Client) GetPacticipant(name string) (*Pacticipant, error) {
+	log.Println("[DEBUG] pact broker: get pacticipant")
+
+	if name == "" {
+		return nil, types.NewError(types.ErrInvalidRequest, fmt.Errorf("pacticipant name is mandatory"))
+	}
+
+	var res pacticipantResponse
+	if err := c.call("GET", "pacticipants/"+escape(name), nil, &res); err != nil {
+		return nil, err
+	}
+	p := res.pacticipant()
+
+	return &p, nil
+}
+
+// CreateOrUpdatePacticipant creates the pacticipant if it does not exist, or
+// else updates the details given, leaving those not given (e.g. an empty
+// RepositoryURL) unchanged. Its Labels are added, without removing any others,
+// so that a new application appears in the broker fully described:
+//
+//	client.CreateOrUpdatePacticipant(broker.Pacticipant{
+//		Name:          "MyConsumer",
+//		RepositoryURL: "https://github.com/myorg/myconsumer",
+//		MainBranch:    "main",
+//		Labels:        []string{"frontend"},
+//	})
+func (c *Client) CreateOrUpdatePacticipant(pacticipant Pacticipant) (*Pacticipant, error) {
+	log.Println("[DEBUG] pact broker: create or update pacticipant")
+
+	if pacticipant.Name == "" {
+		return nil, types.NewError(types.ErrInvalidRequest, fmt.Errorf("pacticipant name is mandatory"))
+	}
+
+	details := Pacticipant{
+		Name:          pacticipant.Name,
+		DisplayName:   pacticipant.DisplayName,
+		RepositoryURL: pacticipant.RepositoryURL,
+		MainBranch:    pacticipant.MainBranch,
+	}
+	if err := c.call("PATCH", "pacticipants/"+escape(pacticipant.Name), details, nil); err != nil {
+		return nil, err
+	}
+
+	for _, label := range pacticipant.Labels {
+		if err := c.AddLabel(pacticipant.Name, label); err != nil {
+			return nil, err
+		}
+	}
+
+	return c.GetPacticipant(pacticipant.Name)
+}
+
+// AddLabel labels the pacticipant, creating the pacticipant if it does not
+// exist
+func (c *Client) AddLabel(pacticipant string, label string) error {
+	log.Println("[DEBUG] pact broker: add label")
+
+	if pacticipant == "" || label == "" {
+		return types.NewError(types.ErrInvalidRequest, fmt.Errorf("pacticipant and label are mandatory"))
+	}
+
+	return c.call("PUT", "pacticipants/"+escape(pacticipant)+"/labels/"+escape(label), map[string]interface{}{}, nil)
+}
+
+// RemoveLabel removes a label from the pacticipant
+func (c *Client) RemoveLabel(pacticipant string, label string) error {
+	log.Println("[DEBUG] pact broker: remove label")
+
+	if pacticipant == "" || label == "" {
+		return types.NewError(types.ErrInvalidRequest, fmt.Errorf("pacticipant and label are mandatory"))
+	}
+
+	return c.call("DELETE", "pacticipants/"+escape(pacticipant)+"/labels/"+escape(label), nil, nil)
+}
@@ -0,0 +1,137 @@
+package broker
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+func TestClient_Pacticipants(t *testing.T) {
+	var queries []string
+	server, client := setupBroker(nil)
+	defer server.Close()
+
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+
+		switch r.URL.Query().Get("page") {
+		case "":
+			fmt.Fprintf(w, `{"_embedded":{"pacticipants":[{"name":"one"},{"name":"two"}]},"_links":{"next":{"href":"%s/pacticipants?page=2&size=2"}}}`, server.URL)
+		case "2":
+			fmt.Fprintf(w, `{"_embedded":{"pacticipants":[{"name":"three","repositoryUrl":"https://example.com/three"}]},"_links":{"next":{"href":"%s/pacticipants?page=3&size=2"}}}`, server.URL)
+		default:
+			fmt.Fprint(w, `{"_embedded":{"pacticipants":[]},"_links":{}}`)
+		}
+	})
+
+	client.PageSize = 2
+	it := client.Pacticipants()
+
+	var names []string
+	for it.Next() {
+		names = append(names, it.Pacticipant().Name)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	if fmt.Sprint(names) != "[one two three]" {
+		t.Fatalf("unexpected pacticipants %v", names)
+	}
+	if fmt.Sprint(queries) != "[size=2 page=2&size=2 page=3&size=2]" {
+		t.Fatalf("unexpected pages requested %v", queries)
+	}
+}
+
+func TestClient_Versions(t *testing.T) {
+	server, client := setupBroker(nil)
+	defer server.Close()
+
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "" {
+			fmt.Fprintf(w, `{"_embedded":{"versions":[{"number":"2.0.0"}]},"_links":{"next":{"href":"%s/pacticipants/My%%20Consumer/versions?page=2"}}}`, server.URL)
+			return
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	it := client.Versions("My Consumer")
+
+	if !it.Next() || it.Version().Number != "2.0.0" {
+		t.Fatalf("expected the first version, got %+v", it.Version())
+	}
+	if it.Next() {
+		t.Fatal("expected iteration to stop on error")
+	}
+	if !errors.Is(it.Err(), types.ErrBroker) {
+		t.Fatalf("expected a broker error but got '%v'", it.Err())
+	}
+
+	it = client.Versions("")
+	if it.Next() || !errors.Is(it.Err(), types.ErrInvalidRequest) {
+		t.Fatalf("expected an invalid request error but got '%v'", it.Err())
+	}
+}
+
+func TestClient_CreateOrUpdatePacticipant(t *testing.T) {
+	var requests []string
+	server, client := setupBroker(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		requests = append(requests, strings.TrimSpace(fmt.Sprintf("%s %s %s", r.Method, r.URL.EscapedPath(), body)))
+
+		if r.Method == "GET" {
+			fmt.Fprint(w, `{"name":"My Consumer","repositoryUrl":"https://example.com/consumer","_embedded":{"labels":[{"name":"frontend"},{"name":"legacy"}]}}`)
+		}
+	})
+	defer server.Close()
+
+	p, err := client.CreateOrUpdatePacticipant(Pacticipant{
+		Name:          "My Consumer",
+		RepositoryURL: "https://example.com/consumer",
+		Labels:        []string{"frontend"},
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	want := []string{
+		`PATCH /pacticipants/My%20Consumer {"name":"My Consumer","repositoryUrl":"https://example.com/consumer"}`,
+		`PUT /pacticipants/My%20Consumer/labels/frontend {}`,
+		`GET /pacticipants/My%20Consumer`,
+	}
+	if fmt.Sprintf("%q", requests) != fmt.Sprintf("%q", want) {
+		t.Fatalf("expected requests %q but got %q", want, requests)
+	}
+	if p.RepositoryURL != "https://example.com/consumer" || fmt.Sprint(p.Labels) != "[frontend legacy]" {
+		t.Fatalf("unexpected pacticipant %+v", p)
+	}
+
+	if _, err = client.CreateOrUpdatePacticipant(Pacticipant{}); !errors.Is(err, types.ErrInvalidRequest) {
+		t.Fatalf("expected the name to be mandatory but got '%v'", err)
+	}
+}
+
+func TestClient_RemoveLabel(t *testing.T) {
+	var request string
+	server, client := setupBroker(func(w http.ResponseWriter, r *http.Request) {
+		request = r.Method + " " + r.URL.EscapedPath()
+	})
+	defer server.Close()
+
+	if err := client.RemoveLabel("My Consumer", "legacy"); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if request != "DELETE /pacticipants/My%20Consumer/labels/legacy" {
+		t.Fatalf("unexpected request '%s'", request)
+	}
+
+	if err := client.RemoveLabel("My Consumer", ""); !errors.Is(err, types.ErrInvalidRequest) {
+		t.Fatalf("expected the label to be mandatory but got '%v'", err)
+	}
+}
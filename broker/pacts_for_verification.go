@@ -0,0 +1,104 @@
+package broker
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+// PactsForVerificationRequest selects the pacts a provider should verify
+// See https://docs.pact.io/pact_broker/advanced_topics/provider_verification_results
+type PactsForVerificationRequest struct {
+	// ConsumerVersionSelectors select the consumer versions to verify
+	ConsumerVersionSelectors []types.ConsumerVersionSelector `json:"consumerVersionSelectors,omitempty"`
+
+	// ProviderVersionTags are the tags that will be applied to the provider
+	// version, used to determine pending pacts
+	ProviderVersionTags []string `json:"providerVersionTags,omitempty"`
+
+	// ProviderVersionBranch is the branch of the provider version, used to
+	// determine pending pacts
+	ProviderVersionBranch string `json:"providerVersionBranch,omitempty"`
+
+	// IncludePendingStatus includes the pending status of each pact
+	IncludePendingStatus bool `json:"includePendingStatus,omitempty"`
+
+	// IncludeWipPactsSince includes WIP pacts created after this date
+	// (ISO 8601 e.g. "2020-01-01")
+	IncludeWipPactsSince string `json:"includeWipPactsSince,omitempty"`
+}
+
+// PactForVerification is a pact selected for verification
+type PactForVerification struct {
+	ShortDescription       string                 `json:"shortDescription"`
+	VerificationProperties VerificationProperties `json:"verificationProperties"`
+	Links                  Links                  `json:"_links"`
+}
+
+// URL is location of the pact content
+func (p PactForVerification) URL() string {
+	link, _ := p.Links.Get("self")
+
+	return link.Href
+}
+
+// VerificationProperties describe how the result of verifying a pact is treated
+type VerificationProperties struct {
+	Pending bool     `json:"pending"`
+	Wip     bool     `json:"wip"`
+	Notices []Notice `json:"notices"`
+}
+
+// Notice explains why a pact was selected, and what its verification means
+type Notice struct {
+	When string `json:"when"`
+	Text string `json:"text"`
+}
+
+// PactForVerificationIterator iterates over the pacts for verification,
+// fetching pages as required
+type PactForVerificationIterator struct {
+	pager
+	value PactForVerification
+}
+
+// Next advances to the next pact, returning false when there are no more or an
+// error occurs
+func (it *PactForVerificationIterator) Next() bool {
+	if !it.next() {
+		return false
+	}
+	it.value = PactForVerification{}
+
+	return it.decode(&it.value)
+}
+
+// Pact returns the current pact
+func (it *PactForVerificationIterator) Pact() PactForVerification {
+	return it.value
+}
+
+// PactsForVerification lists the pacts the provider should verify, navigating
+// from the broker index to the pacts for verification resource
+func (c *Client) PactsForVerification(provider string, request PactsForVerificationRequest) *PactForVerificationIterator {
+	log.Println("[DEBUG] pact broker: pacts for verification")
+
+	it := &PactForVerificationIterator{
+		pager: pager{
+			client:   c,
+			method:   "POST",
+			content:  request,
+			embedded: "pacts",
+		},
+	}
+
+	if provider == "" {
+		it.err = types.NewError(types.ErrInvalidRequest, fmt.Errorf("provider is mandatory"))
+		return it
+	}
+
+	it.href, it.err = c.indexLink("pb:provider-pacts-for-verification", map[string]string{"provider": provider})
+
+	return it
+}
@@ -0,0 +1,62 @@
+package broker
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+func TestClient_PactsForVerification(t *testing.T) {
+	var request PactsForVerificationRequest
+	server, client := setupBroker(nil)
+	defer server.Close()
+
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			fmt.Fprintf(w, `{"_links":{"pb:provider-pacts-for-verification":{"href":"%s/pacts/provider/{provider}/for-verification","templated":true}}}`, server.URL)
+		case "/pacts/provider/bobby/for-verification":
+			if r.Method != "POST" {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			json.NewDecoder(r.Body).Decode(&request)
+			fmt.Fprintf(w, `{"_embedded":{"pacts":[
+				{"shortDescription":"latest master","verificationProperties":{"pending":false,"notices":[{"when":"before_verification","text":"The pact at ... is being verified"}]},"_links":{"self":{"href":"%s/pacts/1"}}},
+				{"shortDescription":"latest feat","verificationProperties":{"pending":true,"wip":true},"_links":{"self":{"href":"%s/pacts/2"}}}
+			]}}`, server.URL, server.URL)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	it := client.PactsForVerification("bobby", PactsForVerificationRequest{
+		ConsumerVersionSelectors: []types.ConsumerVersionSelector{{Tag: "master", Latest: true}},
+		ProviderVersionTags:      []string{"master"},
+		IncludePendingStatus:     true,
+	})
+
+	var pacts []PactForVerification
+	for it.Next() {
+		pacts = append(pacts, it.Pact())
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	if len(pacts) != 2 {
+		t.Fatalf("expected 2 pacts but got %d", len(pacts))
+	}
+	if pacts[0].URL() != server.URL+"/pacts/1" || len(pacts[0].VerificationProperties.Notices) != 1 {
+		t.Fatalf("unexpected pact %+v", pacts[0])
+	}
+	if !pacts[1].VerificationProperties.Pending || !pacts[1].VerificationProperties.Wip {
+		t.Fatalf("expected the second pact to be pending and WIP: %+v", pacts[1])
+	}
+	if !request.IncludePendingStatus || request.ConsumerVersionSelectors[0].Tag != "master" {
+		t.Fatalf("unexpected request %+v", request)
+	}
+}
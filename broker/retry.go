@@ -0,0 +1,96 @@
+package broker
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultRetryBackoff    = 1 * time.Second
+	defaultMaxRetryBackoff = 30 * time.Second
+)
+
+// sleep is replaced in tests
+var sleep = time.Sleep
+
+// shouldRetry reports whether a request that failed with err, or received res,
+// may succeed if sent again. A request that failed without a response may
+// still have reached the broker, so only idempotent methods are retried then:
+// sending a POST or PUT again could publish twice
+func shouldRetry(method string, res *http.Response, err error) bool {
+	if err != nil {
+		switch method {
+		case "GET", "HEAD", "OPTIONS", "DELETE":
+			return true
+		}
+		return false
+	}
+
+	return res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= 500
+}
+
+// retryDelay returns how long to wait before the attempt after attempt (zero
+// based), honouring any Retry-After header sent by the broker
+func (c *Client) retryDelay(attempt int, res *http.Response) time.Duration {
+	max := c.MaxRetryBackoff
+	if max <= 0 {
+		max = defaultMaxRetryBackoff
+	}
+
+	if res != nil {
+		if delay, ok := parseRetryAfter(res.Header.Get("Retry-After")); ok {
+			if delay > max {
+				return max
+			}
+			return delay
+		}
+	}
+
+	delay := c.RetryBackoff
+	if delay <= 0 {
+		delay = defaultRetryBackoff
+	}
+
+	for i := 0; i < attempt && delay < max; i++ {
+		delay *= 2
+	}
+
+	if delay > max {
+		return max
+	}
+
+	return delay
+}
+
+// parseRetryAfter parses a Retry-After header, which is either a number of
+// seconds or an HTTP date
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		delay := time.Until(date)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+
+	return 0, false
+}
+
+// describeFailure summarises why a request is being retried
+func describeFailure(res *http.Response, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+
+	return fmt.Sprintf("status %d", res.StatusCode)
+}
@@ -0,0 +1,226 @@
+package broker
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+// stubSleep records delays rather than sleeping
+func stubSleep() (*[]time.Duration, func()) {
+	var delays []time.Duration
+	sleep = func(d time.Duration) {
+		delays = append(delays, d)
+	}
+
+	return &delays, func() {
+		sleep = time.Sleep
+	}
+}
+
+func TestClient_Retry(t *testing.T) {
+	delays, restore := stubSleep()
+	defer restore()
+
+	attempts := 0
+	server, client := setupBroker(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		switch attempts {
+		case 1:
+			w.WriteHeader(http.StatusBadGateway)
+		case 2:
+			w.Header().Set("Retry-After", "5")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 3:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.Write([]byte(`{"name":"ok"}`))
+		}
+	})
+	defer server.Close()
+
+	client.MaxRetries = 3
+	client.RetryBackoff = 100 * time.Millisecond
+
+	var result map[string]string
+	if err := client.call("POST", "", map[string]string{"a": "b"}, &result); err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	if attempts != 4 || result["name"] != "ok" {
+		t.Fatalf("expected 4 attempts but got %d", attempts)
+	}
+
+	expected := []time.Duration{100 * time.Millisecond, 5 * time.Second, 400 * time.Millisecond}
+	for i, d := range expected {
+		if (*delays)[i] != d {
+			t.Fatalf("expected delays %v but got %v", expected, *delays)
+		}
+	}
+}
+
+func TestClient_RetryExhausted(t *testing.T) {
+	delays, restore := stubSleep()
+	defer restore()
+
+	attempts := 0
+	server, client := setupBroker(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	defer server.Close()
+
+	client.MaxRetries = 2
+	err := client.call("GET", "", nil, nil)
+
+	var res *ResponseError
+	if !errors.As(err, &res) || res.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("expected the last response to be returned but got '%v'", err)
+	}
+	if attempts != 3 || len(*delays) != 2 {
+		t.Fatalf("expected 3 attempts but got %d", attempts)
+	}
+}
+
+func TestClient_NoRetry(t *testing.T) {
+	_, restore := stubSleep()
+	defer restore()
+
+	attempts := 0
+	server, client := setupBroker(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusBadRequest)
+	})
+	defer server.Close()
+
+	client.MaxRetries = 3
+	if err := client.call("GET", "", nil, nil); !errors.Is(err, types.ErrBroker) {
+		t.Fatalf("expected a broker error but got '%v'", err)
+	}
+	if attempts != 1 {
+		t.Fatalf("expected a 4xx not to be retried but got %d attempts", attempts)
+	}
+}
+
+func TestClient_RetryNetworkError(t *testing.T) {
+	_, restore := stubSleep()
+	defer restore()
+
+	tests := []struct {
+		method   string
+		attempts int
+	}{
+		{"GET", 3},
+		{"PUT", 1},
+		{"POST", 1},
+	}
+
+	for _, tt := range tests {
+		attempts := 0
+		client := &Client{BrokerURL: "http://broker.example.com", MaxRetries: 2}
+		client.Transport = roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			attempts++
+			return nil, errors.New("connection reset by peer")
+		})
+
+		if err := client.call(tt.method, "", nil, nil); !errors.Is(err, types.ErrBroker) {
+			t.Fatalf("expected a broker error but got '%v'", err)
+		}
+		if attempts != tt.attempts {
+			t.Fatalf("expected %d attempts for a %s that failed without a response but got %d", tt.attempts, tt.method, attempts)
+		}
+	}
+}
+
+func TestClient_retryDelay(t *testing.T) {
+	client := &Client{RetryBackoff: time.Second, MaxRetryBackoff: 5 * time.Second}
+	res := &http.Response{Header: http.Header{}}
+
+	tests := []struct {
+		attempt    int
+		retryAfter string
+		want       time.Duration
+	}{
+		{0, "", time.Second},
+		{1, "", 2 * time.Second},
+		{2, "", 4 * time.Second},
+		{3, "", 5 * time.Second},
+		{0, "3", 3 * time.Second},
+		{0, "60", 5 * time.Second},
+		{0, "soon", time.Second},
+		{0, time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat), 0},
+	}
+
+	for _, tt := range tests {
+		res.Header.Set("Retry-After", tt.retryAfter)
+		if got := client.retryDelay(tt.attempt, res); got != tt.want {
+			t.Fatalf("expected delay for attempt %d with Retry-After '%s' to be %s but got %s", tt.attempt, tt.retryAfter, tt.want, got)
+		}
+	}
+}
+
+func TestClient_Transport(t *testing.T) {
+	var header string
+	server, client := setupBroker(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get("X-Custom")
+	})
+	defer server.Close()
+
+	client.Transport = roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		r.Header.Set("X-Custom", "true")
+		return http.DefaultTransport.RoundTrip(r)
+	})
+
+	if err := client.call("GET", "", nil, nil); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if header != "true" {
+		t.Fatal("expected the custom transport to be used")
+	}
+}
+
+func TestClient_WrappedDefaultTransport(t *testing.T) {
+	server, client := setupBroker(func(w http.ResponseWriter, r *http.Request) {})
+	defer server.Close()
+
+	defaultTransport := http.DefaultTransport
+	http.DefaultTransport = roundTripperFunc(defaultTransport.RoundTrip)
+	defer func() {
+		http.DefaultTransport = defaultTransport
+	}()
+
+	if err := client.call("GET", "", nil, nil); err != nil {
+		t.Fatal("Error:", err)
+	}
+}
+
+func TestClient_ProxyURL(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+	}))
+	defer proxy.Close()
+
+	client := &Client{BrokerURL: "http://broker.example.com", ProxyURL: proxy.URL}
+	if err := client.call("GET", "pacticipants", nil, nil); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if proxied != "http://broker.example.com/pacticipants" {
+		t.Fatalf("expected the request to be proxied but got '%s'", proxied)
+	}
+
+	client.ProxyURL = "://"
+	if err := client.call("GET", "", nil, nil); !errors.Is(err, types.ErrInvalidRequest) {
+		t.Fatalf("expected an invalid proxy to be rejected but got '%v'", err)
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
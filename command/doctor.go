@@ -0,0 +1,72 @@
+package command
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/ray-xu-deltatre/pact-go/broker"
+	"github.com/ray-xu-deltatre/pact-go/install"
+	"github.com/ray-xu-deltatre/pact-go/types"
+	"github.com/ray-xu-deltatre/pact-go/utils"
+
+	"github.com/spf13/cobra"
+)
+
+var doctorOptions install.DoctorOptions
+var doctorBroker broker.Client
+var doctorPorts string
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the environment is able to run Pact tests",
+	Long: `Checks that the Pact CLI tools are installed at compatible versions, that
+the pact and log directories are writable, that a port can be found for the
+Mock Service and that the Pact Broker (if given) can be reached, suggesting how
+to fix any problems found.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		setLogLevel(verbose, logLevel)
+
+		if err := doctor(install.NewInstaller(), os.Stdout); err != nil {
+			log.Println("[ERROR]", err)
+			os.Exit(types.ExitCode(err))
+		}
+	},
+}
+
+// doctor runs the checks, writing the result of each to out
+func doctor(installer *install.Installer, out io.Writer) error {
+	options := doctorOptions
+	if doctorBroker.BrokerURL != "" {
+		options.Broker = &doctorBroker
+	}
+	if doctorPorts != "" {
+		ports, err := utils.PortRange(doctorPorts)
+		if err != nil {
+			return types.NewError(types.ErrInvalidRequest, err)
+		}
+		options.Ports = ports
+	}
+
+	checks := installer.Doctor(options)
+	for _, check := range checks {
+		if check.Err != nil {
+			fmt.Fprintf(out, "FAIL %s: %v\n    %s\n", check.Name, check.Err, check.Remedy)
+			continue
+		}
+		fmt.Fprintf(out, "OK   %s: %s\n", check.Name, check.Detail)
+	}
+
+	return install.Healthy(checks)
+}
+
+func init() {
+	doctorCmd.Flags().StringVar(&doctorOptions.PactDir, "pact-dir", "pacts", "Directory pacts are written to")
+	doctorCmd.Flags().StringVar(&doctorOptions.LogDir, "log-dir", "logs", "Directory logs are written to")
+	doctorCmd.Flags().StringVar(&doctorPorts, "ports", "", "Ports the Mock Service may use e.g. 8081-8085. Defaults to any free port")
+	doctorCmd.Flags().StringVar(&doctorBroker.BrokerURL, "broker-url", "", "URL of the Pact Broker to check")
+	doctorCmd.Flags().StringVar(&doctorBroker.BrokerUsername, "broker-username", "", "Username for the Pact Broker")
+	doctorCmd.Flags().StringVar(&doctorBroker.BrokerPassword, "broker-password", "", "Password for the Pact Broker")
+	doctorCmd.Flags().StringVar(&doctorBroker.BrokerToken, "broker-token", "", "Bearer token for the Pact Broker")
+	RootCmd.AddCommand(doctorCmd)
+}
@@ -0,0 +1,23 @@
+package command
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/ray-xu-deltatre/pact-go/install"
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+func TestDoctorCommand_InvalidPorts(t *testing.T) {
+	doctorPorts = "not-ports"
+	defer func() { doctorPorts = "" }()
+
+	var out bytes.Buffer
+	if err := doctor(install.NewInstaller(), &out); !errors.Is(err, types.ErrInvalidRequest) {
+		t.Fatalf("expected an invalid request error but got '%v'", err)
+	}
+	if out.Len() != 0 {
+		t.Fatalf("expected no checks to be made but got '%s'", out.String())
+	}
+}
@@ -0,0 +1,79 @@
+package command
+
+import (
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/ray-xu-deltatre/pact-go/gen"
+
+	"github.com/spf13/cobra"
+)
+
+var genPackage string
+var genOutput string
+var genInteractions bool
+var genFunction string
+var genCmd = &cobra.Command{
+	Use:   "gen [pact file]",
+	Short: "Generate consumer tests from a pact file",
+	Long: `Generates a skeleton Go consumer test for each interaction in a pact file,
+re-creating the interaction with the DSL. The call to the provider is left
+for you to fill in. With --interactions, a function adding the interactions to
+a dsl.Pact is generated instead, to be shared by the consumer tests of several
+packages.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		setLogLevel(verbose, logLevel)
+
+		if len(args) != 1 {
+			log.Println("[ERROR] a single pact file must be given")
+			os.Exit(1)
+		}
+
+		if err := generate(args[0]); err != nil {
+			log.Println("[ERROR]", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// generate writes the tests (or interactions) for the pact file to the output,
+// or stdout
+func generate(pactFile string) error {
+	pact, err := ioutil.ReadFile(pactFile)
+	if err != nil {
+		return err
+	}
+
+	options := gen.Options{
+		Package:  genPackage,
+		Source:   filepath.Base(pactFile),
+		Function: genFunction,
+	}
+
+	var src []byte
+	if genInteractions {
+		src, err = gen.Interactions(pact, options)
+	} else {
+		src, err = gen.ConsumerTest(pact, options)
+	}
+	if err != nil {
+		return err
+	}
+
+	if genOutput == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+
+	return ioutil.WriteFile(genOutput, src, 0644)
+}
+
+func init() {
+	genCmd.Flags().StringVarP(&genPackage, "package", "p", "main", "Package of the generated test")
+	genCmd.Flags().StringVarP(&genOutput, "output", "o", "", "File to write the test to. Defaults to stdout")
+	genCmd.Flags().BoolVar(&genInteractions, "interactions", false, "Generate a function adding the interactions to a dsl.Pact, to be shared by consumer tests, rather than tests")
+	genCmd.Flags().StringVar(&genFunction, "function", "AddInteractions", "Name of the function generated with --interactions")
+	RootCmd.AddCommand(genCmd)
+}
@@ -0,0 +1,62 @@
+package command
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenCommand(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pact-go-gen")
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	defer os.RemoveAll(dir)
+
+	genPackage = "consumer"
+	genOutput = filepath.Join(dir, "consumer_test.go")
+	defer func() { genPackage, genOutput = "main", "" }()
+
+	if err = generate("../examples/pacts/myconsumer-myprovider.json"); err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	src, err := ioutil.ReadFile(genOutput)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if !strings.Contains(string(src), "func TestMyConsumer_ARequestToGetFoo(t *testing.T)") {
+		t.Fatalf("unexpected test generated:\n%s", src)
+	}
+
+	if err = generate(filepath.Join(dir, "missing.json")); err == nil {
+		t.Fatal("expected an error for a missing pact file")
+	}
+}
+
+func TestGenCommand_Interactions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pact-go-gen")
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	defer os.RemoveAll(dir)
+
+	genPackage = "consumer"
+	genOutput = filepath.Join(dir, "interactions.go")
+	genInteractions = true
+	defer func() { genPackage, genOutput, genInteractions = "main", "", false }()
+
+	if err = generate("../examples/pacts/myconsumer-myprovider.json"); err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	src, err := ioutil.ReadFile(genOutput)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if !strings.Contains(string(src), "func AddInteractions(pact *dsl.Pact)") {
+		t.Fatalf("unexpected interactions generated:\n%s", src)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"github.com/ray-xu-deltatre/pact-go/install"
+	"github.com/ray-xu-deltatre/pact-go/types"
 
 	"github.com/spf13/cobra"
 )
@@ -22,7 +23,7 @@ var installCmd = &cobra.Command{
 		var err error
 		if err = i.CheckInstallation(); err != nil {
 			log.Println("[ERROR] Your Pact CLI installation is out of date, please update to the latest version. Error:", err)
-			os.Exit(1)
+			os.Exit(types.ExitCode(err))
 		}
 	},
 }
@@ -0,0 +1,51 @@
+package command
+
+import (
+	"log"
+	"os"
+
+	"github.com/ray-xu-deltatre/pact-go/dsl"
+	"github.com/ray-xu-deltatre/pact-go/types"
+
+	"github.com/spf13/cobra"
+)
+
+var publishRequest types.PublishRequest
+var publishCmd = &cobra.Command{
+	Use:   "publish [pact files or directories]",
+	Short: "Publish pacts to a Pact Broker",
+	Long:  "Publishes pact files to a Pact Broker for the given consumer version, optionally tagging it",
+	Run: func(cmd *cobra.Command, args []string) {
+		setLogLevel(verbose, logLevel)
+
+		if err := publish(args); err != nil {
+			log.Println("[ERROR]", err)
+			os.Exit(types.ExitCode(err))
+		}
+	},
+}
+
+// publish sends the pact files to the broker
+func publish(pactFiles []string) error {
+	request := publishRequest
+	request.PactURLs = pactFiles
+
+	p := &dsl.Publisher{LogLevel: logLevel}
+
+	return p.Publish(request)
+}
+
+func init() {
+	publishCmd.Flags().StringVar(&publishRequest.PactBroker, "broker-url", "", "URL of the Pact Broker")
+	publishCmd.Flags().StringVar(&publishRequest.BrokerUsername, "broker-username", "", "Username for the Pact Broker")
+	publishCmd.Flags().StringVar(&publishRequest.BrokerPassword, "broker-password", "", "Password for the Pact Broker")
+	publishCmd.Flags().StringVar(&publishRequest.BrokerToken, "broker-token", "", "Bearer token for the Pact Broker")
+	publishCmd.Flags().StringVar(&publishRequest.ConsumerVersion, "consumer-app-version", "", "Version of the consumer the pacts belong to")
+	publishCmd.Flags().StringSliceVar(&publishRequest.Tags, "tag", nil, "Tag for the consumer version. May be repeated")
+	publishCmd.Flags().StringVar(&publishRequest.ConsumerRepositoryURL, "consumer-repository-url", "", "Repository URL to record for the consumer")
+	publishCmd.Flags().StringVar(&publishRequest.ConsumerMainBranch, "consumer-main-branch", "", "Main branch to record for the consumer")
+	publishCmd.Flags().StringSliceVar(&publishRequest.ConsumerLabels, "consumer-label", nil, "Label to add to the consumer. May be repeated")
+	publishCmd.Flags().IntVar(&publishRequest.MaxRetries, "max-retries", 0, "Number of times to retry publishing a pact that fails with a 5xx or a 429")
+	publishCmd.Flags().StringVar(&publishRequest.ProxyURL, "proxy-url", "", "Proxy to publish through, overriding HTTP_PROXY and HTTPS_PROXY")
+	RootCmd.AddCommand(publishCmd)
+}
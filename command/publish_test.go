@@ -0,0 +1,12 @@
+package command
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPublishCommand_MissingPactFiles(t *testing.T) {
+	if err := publish(nil); err == nil || !strings.Contains(err.Error(), "PactURLs") {
+		t.Fatalf("expected an error without pact files but got '%v'", err)
+	}
+}
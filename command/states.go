@@ -0,0 +1,93 @@
+package command
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/ray-xu-deltatre/pact-go/gen"
+
+	"github.com/spf13/cobra"
+)
+
+var statesHandlers bool
+var statesPackage string
+var statesOutput string
+var statesFunction string
+var statesCmd = &cobra.Command{
+	Use:   "states [pact files]",
+	Short: "List the provider states required by pact files",
+	Long: `Lists the provider states that a provider must be able to set up to verify
+the given pact files, along with their parameters and the consumers and
+interactions requiring them. With --handlers, a function returning a skeleton
+state handler for each state is generated instead.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		setLogLevel(verbose, logLevel)
+
+		if len(args) == 0 {
+			log.Println("[ERROR] at least one pact file must be given")
+			os.Exit(1)
+		}
+
+		if err := listStates(args, os.Stdout); err != nil {
+			log.Println("[ERROR]", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// listStates writes the catalogue of provider states in the pact files to out,
+// or the state handlers to the output (or out)
+func listStates(pactFiles []string, out io.Writer) error {
+	pacts := make([][]byte, 0, len(pactFiles))
+	for _, pactFile := range pactFiles {
+		pact, err := ioutil.ReadFile(pactFile)
+		if err != nil {
+			return err
+		}
+		pacts = append(pacts, pact)
+	}
+
+	states, err := gen.ProviderStates(pacts...)
+	if err != nil {
+		return err
+	}
+
+	if !statesHandlers {
+		for _, state := range states {
+			fmt.Fprint(out, state.Name)
+			if len(state.Params) > 0 {
+				fmt.Fprintf(out, " (%s)", strings.Join(state.Params, ", "))
+			}
+			fmt.Fprintf(out, "\n    consumers: %s\n    interactions: %s\n", strings.Join(state.Consumers, ", "), strings.Join(state.Interactions, "; "))
+		}
+		return nil
+	}
+
+	src, err := gen.StateHandlers(states, gen.Options{
+		Package:  statesPackage,
+		Source:   strings.Join(pactFiles, ", "),
+		Function: statesFunction,
+	})
+	if err != nil {
+		return err
+	}
+
+	if statesOutput == "" {
+		_, err = out.Write(src)
+		return err
+	}
+
+	return ioutil.WriteFile(statesOutput, src, 0644)
+}
+
+func init() {
+	statesCmd.Flags().BoolVar(&statesHandlers, "handlers", false, "Generate a function returning a skeleton state handler for each state")
+	statesCmd.Flags().StringVarP(&statesPackage, "package", "p", "main", "Package of the generated state handlers")
+	statesCmd.Flags().StringVarP(&statesOutput, "output", "o", "", "File to write the state handlers to. Defaults to stdout")
+	statesCmd.Flags().StringVar(&statesFunction, "function", "StateHandlers", "Name of the function generated with --handlers")
+	RootCmd.AddCommand(statesCmd)
+}
@@ -0,0 +1,52 @@
+package command
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStatesCommand(t *testing.T) {
+	var out bytes.Buffer
+	if err := listStates([]string{"../examples/pacts/myconsumer-myprovider.json", "../examples/pacts/jmarie-loginprovider.json"}, &out); err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	for _, want := range []string{"User foo exists\n    consumers: MyConsumer\n", "User jmarie exists\n    consumers: jmarie\n"} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected '%s' in the catalogue:\n%s", want, out.String())
+		}
+	}
+
+	if err := listStates([]string{"missing.json"}, &out); err == nil {
+		t.Fatal("expected an error for a missing pact file")
+	}
+}
+
+func TestStatesCommand_Handlers(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pact-go-states")
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	defer os.RemoveAll(dir)
+
+	statesHandlers = true
+	statesPackage = "provider"
+	statesOutput = filepath.Join(dir, "states.go")
+	defer func() { statesHandlers, statesPackage, statesOutput = false, "main", "" }()
+
+	if err = listStates([]string{"../examples/pacts/myconsumer-myprovider.json"}, ioutil.Discard); err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	src, err := ioutil.ReadFile(statesOutput)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if !strings.Contains(string(src), `"User foo exists": func() error {`) {
+		t.Fatalf("unexpected state handlers generated:\n%s", src)
+	}
+}
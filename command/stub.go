@@ -0,0 +1,69 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"strconv"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+
+	"github.com/spf13/cobra"
+)
+
+var stubPort int
+var stubHost string
+var stubCmd = &cobra.Command{
+	Use:   "stub [pact files or directories]",
+	Short: "Run a stub server from pacts",
+	Long: `Runs a stub server that responds to requests with the responses of the
+matching interactions in the given pacts, until interrupted. Useful to stand in
+for a provider e.g. in end to end tests of the consumer.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		setLogLevel(verbose, logLevel)
+
+		if err := stub(args); err != nil {
+			log.Println("[ERROR]", err)
+			os.Exit(types.ExitCode(err))
+		}
+	},
+}
+
+// stubArgs returns the arguments to the Pact Stub Service
+func stubArgs(pactFiles []string) ([]string, error) {
+	if len(pactFiles) == 0 {
+		return nil, types.NewError(types.ErrInvalidRequest, errors.New("at least one pact file must be given"))
+	}
+
+	return append(append([]string{}, pactFiles...), "--port", strconv.Itoa(stubPort), "--host", stubHost), nil
+}
+
+// stub runs the Pact Stub Service in the foreground
+func stub(pactFiles []string) error {
+	args, err := stubArgs(pactFiles)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command("pact-stub-service", args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err = cmd.Run(); err != nil {
+		var execErr *exec.Error
+		if errors.As(err, &execErr) {
+			return types.NewError(types.ErrCLITools, fmt.Errorf("unable to run the Pact Stub Service: %v", err))
+		}
+		return err
+	}
+
+	return nil
+}
+
+func init() {
+	stubCmd.Flags().IntVarP(&stubPort, "port", "p", 8080, "Port to run the stub server on")
+	stubCmd.Flags().StringVar(&stubHost, "host", "localhost", "Host to run the stub server on")
+	RootCmd.AddCommand(stubCmd)
+}
@@ -0,0 +1,26 @@
+package command
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+func TestStubCommand_Args(t *testing.T) {
+	stubPort, stubHost = 1234, "0.0.0.0"
+	defer func() { stubPort, stubHost = 8080, "localhost" }()
+
+	args, err := stubArgs([]string{"a.json", "pacts"})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if got := strings.Join(args, " "); got != "a.json pacts --port 1234 --host 0.0.0.0" {
+		t.Fatalf("unexpected arguments to the stub service: %s", got)
+	}
+
+	if _, err = stubArgs(nil); !errors.Is(err, types.ErrInvalidRequest) {
+		t.Fatalf("expected an invalid request error without pact files but got '%v'", err)
+	}
+}
@@ -0,0 +1,153 @@
+package command
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/ray-xu-deltatre/pact-go/dsl"
+	"github.com/ray-xu-deltatre/pact-go/types"
+
+	"github.com/spf13/cobra"
+)
+
+var verifyConfig string
+var verifyPlan bool
+var verifyRequest types.VerifyRequest
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Verify a provider against its pacts",
+	Long: `Verifies a running provider against pact files or URLs, or the pacts
+fetched from a Pact Broker, optionally publishing the results to the broker.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		setLogLevel(verbose, logLevel)
+
+		if err := verify(os.Stdout); err != nil {
+			log.Println("[ERROR]", err)
+			os.Exit(types.ExitCode(err))
+		}
+	},
+}
+
+// verify runs the verification, writing the result of each interaction to out
+func verify(out io.Writer) error {
+	request, err := verifyRequestFromFlags()
+	if err != nil {
+		return err
+	}
+	if verifyPlan {
+		plan, err := (&dsl.Pact{Provider: request.Provider, LogLevel: logLevel}).PlanVerification(request)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(out, plan)
+		return nil
+	}
+	if request.ProviderBaseURL == "" {
+		return types.NewError(types.ErrInvalidRequest, fmt.Errorf("a provider base URL is required"))
+	}
+
+	pact := &dsl.Pact{
+		Provider: request.Provider,
+		LogLevel: logLevel,
+	}
+	responses, err := pact.VerifyProviderRaw(request)
+
+	for _, response := range responses {
+		for _, example := range response.Examples {
+			status := example.Status
+			if example.Flaky {
+				status += " (flaky)"
+			}
+			fmt.Fprintf(out, "%s: %s\n", status, example.FullDescription)
+			for _, mismatch := range example.Mismatches {
+				fmt.Fprintf(out, "    %s\n", mismatch)
+			}
+			if example.Deprecation != nil {
+				fmt.Fprintf(out, "    deprecated: %s\n", example.Deprecation)
+			}
+		}
+	}
+
+	return err
+}
+
+// verifyRequestFromFlags returns the request given by the flags, on top of
+// that of any config file
+func verifyRequestFromFlags() (types.VerifyRequest, error) {
+	if verifyConfig == "" {
+		return verifyRequest, nil
+	}
+
+	request, err := types.LoadVerifyRequest(verifyConfig)
+	if err != nil {
+		return request, err
+	}
+
+	for _, value := range []struct {
+		flag string
+		to   *string
+	}{
+		{verifyRequest.Provider, &request.Provider},
+		{verifyRequest.ProviderBaseURL, &request.ProviderBaseURL},
+		{verifyRequest.BrokerURL, &request.BrokerURL},
+		{verifyRequest.BrokerUsername, &request.BrokerUsername},
+		{verifyRequest.BrokerPassword, &request.BrokerPassword},
+		{verifyRequest.BrokerToken, &request.BrokerToken},
+		{verifyRequest.ProviderVersion, &request.ProviderVersion},
+		{verifyRequest.ProviderStatesSetupURL, &request.ProviderStatesSetupURL},
+		{verifyRequest.FailureReportFile, &request.FailureReportFile},
+	} {
+		if value.flag != "" {
+			*value.to = value.flag
+		}
+	}
+	for _, value := range []struct {
+		flag []string
+		to   *[]string
+	}{
+		{verifyRequest.PactURLs, &request.PactURLs},
+		{verifyRequest.Tags, &request.Tags},
+		{verifyRequest.ProviderTags, &request.ProviderTags},
+	} {
+		if len(value.flag) > 0 {
+			*value.to = value.flag
+		}
+	}
+	request.PublishVerificationResults = request.PublishVerificationResults || verifyRequest.PublishVerificationResults
+	request.EnablePending = request.EnablePending || verifyRequest.EnablePending
+	request.RerunFailed = verifyRequest.RerunFailed
+	request.ReportDeprecations = request.ReportDeprecations || verifyRequest.ReportDeprecations
+	if verifyRequest.Retries > 0 {
+		request.Retries = verifyRequest.Retries
+		request.RetryDelay = verifyRequest.RetryDelay
+	}
+
+	return request, nil
+}
+
+func init() {
+	verifyCmd.Flags().StringVarP(&verifyConfig, "config", "c", "", "YAML (or JSON) file describing the verification. Flags override its settings")
+	verifyCmd.Flags().StringVar(&verifyRequest.Provider, "provider", "", "Name of the provider")
+	verifyCmd.Flags().StringVar(&verifyRequest.ProviderBaseURL, "provider-base-url", "", "Base URL of the running provider")
+	verifyCmd.Flags().StringSliceVar(&verifyRequest.PactURLs, "pact-url", nil, "Pact file or URL to verify. May be repeated")
+	verifyCmd.Flags().StringVar(&verifyRequest.BrokerURL, "broker-url", "", "URL of the Pact Broker to fetch pacts from")
+	verifyCmd.Flags().StringVar(&verifyRequest.BrokerUsername, "broker-username", "", "Username for the Pact Broker")
+	verifyCmd.Flags().StringVar(&verifyRequest.BrokerPassword, "broker-password", "", "Password for the Pact Broker")
+	verifyCmd.Flags().StringVar(&verifyRequest.BrokerToken, "broker-token", "", "Bearer token for the Pact Broker")
+	verifyCmd.Flags().StringSliceVar(&verifyRequest.Tags, "consumer-version-tag", nil, "Tag of the consumer versions to verify. May be repeated")
+	verifyCmd.Flags().StringSliceVar(&verifyRequest.ProviderTags, "provider-version-tag", nil, "Tag of the provider version. May be repeated")
+	verifyCmd.Flags().StringVar(&verifyRequest.ProviderVersion, "provider-app-version", "", "Version of the provider, required to publish results")
+	verifyCmd.Flags().StringVar(&verifyRequest.ProviderStatesSetupURL, "provider-states-setup-url", "", "URL to POST provider states to before each interaction")
+	verifyCmd.Flags().BoolVar(&verifyRequest.PublishVerificationResults, "publish-verification-results", false, "Publish the results to the Pact Broker")
+	verifyCmd.Flags().BoolVar(&verifyRequest.EnablePending, "enable-pending", false, "Allow pending pacts to be included in verification")
+	verifyCmd.Flags().BoolVar(&verifyPlan, "plan", false, "List the pacts, interactions and provider states that would be verified, without verifying them")
+	verifyCmd.Flags().IntVar(&verifyRequest.Retries, "retries", 0, "Number of times to verify pacts with failed interactions again")
+	verifyCmd.Flags().DurationVar(&verifyRequest.RetryDelay, "retry-delay", time.Second, "Time to wait before each retry")
+	verifyCmd.Flags().StringVar(&verifyRequest.FailureReportFile, "failure-report", "", "File to write the interactions that fail to, as JSON")
+	verifyCmd.Flags().BoolVar(&verifyRequest.RerunFailed, "rerun-failed", false, "Verify only the interactions in the --failure-report of a previous verification")
+	verifyCmd.Flags().BoolVar(&verifyRequest.ReportDeprecations, "report-deprecations", false, "Report the Deprecation and Sunset headers of the responses of the provider")
+	RootCmd.AddCommand(verifyCmd)
+}
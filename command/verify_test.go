@@ -0,0 +1,88 @@
+package command
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+func TestVerifyCommand_MissingProviderBaseURL(t *testing.T) {
+	var out bytes.Buffer
+	if err := verify(&out); !errors.Is(err, types.ErrInvalidRequest) {
+		t.Fatalf("expected an invalid request error without a provider base URL but got '%v'", err)
+	}
+}
+
+func TestVerifyCommand_ConfigOverriddenByFlags(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pact-go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	config := filepath.Join(dir, "verify.yml")
+	content := "provider: bobby\nproviderBaseUrl: http://localhost:8080\npactUrls: [pacts/foo-bar.json]\n"
+	if err = ioutil.WriteFile(config, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	verifyConfig = config
+	verifyRequest.ProviderBaseURL = "http://localhost:9090"
+	verifyRequest.FailureReportFile = "failures.json"
+	verifyRequest.RerunFailed = true
+	verifyRequest.ReportDeprecations = true
+	defer func() {
+		verifyConfig = ""
+		verifyRequest = types.VerifyRequest{}
+	}()
+
+	request, err := verifyRequestFromFlags()
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if request.Provider != "bobby" || request.ProviderBaseURL != "http://localhost:9090" || len(request.PactURLs) != 1 {
+		t.Fatalf("expected the flags to override the config: %+v", request)
+	}
+	if request.FailureReportFile != "failures.json" || !request.RerunFailed {
+		t.Fatalf("expected the failure report flags to be used: %+v", request)
+	}
+	if !request.ReportDeprecations {
+		t.Fatalf("expected the report deprecations flag to be used: %+v", request)
+	}
+}
+
+func TestVerifyCommand_Plan(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pact-go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "foo-bar.json")
+	content := `{"consumer":{"name":"foo"},"provider":{"name":"bar"},"interactions":[{"description":"A request","providerState":"bar exists"}]}`
+	if err = ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	verifyPlan = true
+	verifyRequest.PactURLs = []string{file}
+	defer func() {
+		verifyPlan = false
+		verifyRequest = types.VerifyRequest{}
+	}()
+
+	var out bytes.Buffer
+	if err = verify(&out); err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	want := "Pact between foo and bar: " + file + "\n  - A request\n      given \"bar exists\" (no state handler)\n"
+	if out.String() != want {
+		t.Fatalf("expected the plan '%s' but got '%s'", want, out.String())
+	}
+}
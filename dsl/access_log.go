@@ -0,0 +1,96 @@
+package dsl
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// writeAccessLog appends a line for each request made to the Mock Server in
+// the last Verify to the access log of the test, recording the unmatched ones
+func (p *Pact) writeAccessLog(interactions []*Interaction) {
+	p.accessLogFile = ""
+	p.unmatched = nil
+	if !p.AccessLog || p.proxy == nil {
+		return
+	}
+
+	var lines []string
+	for _, rec := range p.proxy.Requests() {
+		line := accessLogLine(rec, interactions)
+		lines = append(lines, line)
+		if rec.Unmatched {
+			p.unmatched = append(p.unmatched, line)
+		}
+	}
+
+	name := "access.log"
+	if names := testNamesOf(interactions); len(names) > 0 {
+		name = "access-" + unsafeBranchChars.ReplaceAllString(names[0], "_") + ".log"
+	}
+	p.accessLogFile = filepath.Join(p.LogDir, name)
+
+	if len(lines) == 0 {
+		return
+	}
+
+	f, err := os.OpenFile(p.accessLogFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err == nil {
+		_, err = f.WriteString(strings.Join(lines, "\n") + "\n")
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		log.Println("[WARN] unable to write the access log:", err)
+	}
+}
+
+// accessLogLine describes the request, and the interaction it matched
+func accessLogLine(rec *recordedRequest, interactions []*Interaction) string {
+	uri := rec.Path
+	if rec.Query != "" {
+		uri += "?" + rec.Query
+	}
+
+	return fmt.Sprintf("%s %s %s %d %s %s", rec.Received.UTC().Format(time.RFC3339Nano), rec.Method, uri, rec.Status, rec.Latency.Round(time.Microsecond), requestOutcome(rec, interactions))
+}
+
+// requestOutcome describes how the Mock Server handled the request: the
+// interaction it matched, "unmatched" or "passthrough"
+func requestOutcome(rec *recordedRequest, interactions []*Interaction) string {
+	if rec.Passthrough {
+		return "passthrough"
+	}
+	if rec.Unmatched {
+		return "unmatched"
+	}
+
+	for _, i := range interactions {
+		if requestMatches(i.Request, rec) {
+			return fmt.Sprintf("matched %q", i.Description)
+		}
+	}
+
+	return "matched"
+}
+
+// unmatchedRequests returns the access log lines of the requests over the
+// transport that were not matched
+func (p *Pact) unmatchedRequests(transport string) []string {
+	if transport != TransportHTTP {
+		return nil
+	}
+
+	return p.unmatched
+}
+
+// UnmatchedRequests returns the access log lines of the requests made to the
+// Mock Server in the last Verify that were not matched to an interaction, e.g.
+// to include in the failure of a test. Requires AccessLog to be set.
+func (p *Pact) UnmatchedRequests() []string {
+	return p.unmatched
+}
@@ -0,0 +1,68 @@
+package dsl
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+func TestAccessLog_write(t *testing.T) {
+	ms := setupMismatchMockServer()
+	defer ms.Close()
+
+	dir, err := ioutil.TempDir("", "pact-go-access-log")
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	defer os.RemoveAll(dir)
+
+	pact := &Pact{
+		Server:    &types.MockServer{Port: getPort(ms.URL)},
+		Host:      "localhost",
+		Network:   "tcp",
+		LogDir:    dir,
+		AccessLog: true,
+	}
+	pact.startProxy()
+	defer pact.proxy.Stop()
+
+	url := fmt.Sprintf("http://localhost:%d", pact.Server.Port)
+	if _, err = http.Post(url+"/users?name=billy", "application/json", strings.NewReader(`{"name":"billy"}`)); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err = http.Get(url + "/error"); err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	interactions := []*Interaction{
+		(&Interaction{testName: "TestClient/errors"}).
+			UponReceiving("A request that errors").
+			WithRequest(Request{Method: "GET", Path: String("/error")}),
+	}
+	pact.writeAccessLog(interactions)
+
+	content, err := ioutil.ReadFile(filepath.Join(dir, "access-TestClient_errors.log"))
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	want := regexp.MustCompile(`^\S+ POST /users\?name=billy 500 \S+ unmatched\n\S+ GET /error 500 \S+ matched "A request that errors"\n$`)
+	if !want.Match(content) {
+		t.Fatalf("unexpected access log:\n%s", content)
+	}
+
+	unmatched := pact.UnmatchedRequests()
+	if len(unmatched) != 1 || !strings.Contains(unmatched[0], "POST /users?name=billy") {
+		t.Fatalf("expected the unmatched request but got %q", unmatched)
+	}
+	if len(pact.unmatchedRequests(TransportHTTPS)) != 0 {
+		t.Fatal("expected no unmatched requests over TLS")
+	}
+}
@@ -0,0 +1,137 @@
+package dsl
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// redactedSecret replaces the credentials in the example value of a header
+// when redacting secrets from a pact file
+const redactedSecret = "REDACTED"
+
+// basicAuthRegex matches any basic authentication credentials
+const basicAuthRegex = `^Basic [A-Za-z0-9+/]+=*$`
+
+// WithBasicAuth expects the request to authenticate with basic authentication.
+// The given credentials are used as the example, and any credentials are
+// matched. Must be called after WithRequest.
+func (i *Interaction) WithBasicAuth(username string, password string) *Interaction {
+	if strings.Contains(username, ":") {
+		i.Request.err = fmt.Errorf("invalid basic authentication username '%s', must not contain ':'", username)
+		return i
+	}
+
+	credentials := base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+
+	return i.withAuthorization(Term("Basic "+credentials, basicAuthRegex))
+}
+
+// WithBearerToken expects the request to authenticate with the given bearer
+// token. The regex of a Term matcher is used to match the token, otherwise any
+// token is matched. Must be called after WithRequest.
+func (i *Interaction) WithBearerToken(token Matcher) *Interaction {
+	if token == nil {
+		i.Request.err = fmt.Errorf("a bearer token is required")
+		return i
+	}
+
+	example := fmt.Sprintf("%v", token.GetValue())
+	regex := `\S+`
+	if t, ok := token.(term); ok {
+		regex = fmt.Sprintf("(%s)", strings.TrimSuffix(strings.TrimPrefix(fmt.Sprintf("%v", t.Data.Matcher.Regex), "^"), "$"))
+	}
+
+	return i.withAuthorization(Term("Bearer "+example, "^Bearer "+regex+"$"))
+}
+
+// withAuthorization sets the Authorization header of the request, marking it
+// as a secret
+func (i *Interaction) withAuthorization(value Matcher) *Interaction {
+	headers, err := setHeader(i.Request.Headers, "Authorization", value)
+	i.Request.Headers = headers
+	if err != nil {
+		i.Request.err = err
+		return i
+	}
+	i.secretHeaders = appendUnique(i.secretHeaders, "Authorization")
+
+	return i
+}
+
+// recordSecretHeaders remembers the request headers of an interaction that hold
+// secrets, so that they can be redacted from the pact file
+func (p *Pact) recordSecretHeaders(key string, headers []string) {
+	if !p.RedactSecrets || len(headers) == 0 {
+		return
+	}
+
+	if p.secretHeaders == nil {
+		p.secretHeaders = make(map[string][]string)
+	}
+	for _, h := range headers {
+		p.secretHeaders[key] = appendUnique(p.secretHeaders[key], h)
+	}
+}
+
+// redactHeaders replaces the example value of the given request headers of a
+// compact interaction, keeping the authentication scheme, if any. The matching
+// rules are left as is.
+func redactHeaders(raw json.RawMessage, headers []string) json.RawMessage {
+	var interaction struct {
+		Request struct {
+			Headers map[string]json.RawMessage `json:"headers"`
+		} `json:"request"`
+	}
+	if err := json.Unmarshal(raw, &interaction); err != nil {
+		return raw
+	}
+
+	for name, value := range interaction.Request.Headers {
+		if !containsFold(headers, name) {
+			continue
+		}
+
+		var example string
+		if err := json.Unmarshal(value, &example); err != nil {
+			continue
+		}
+
+		key, _ := json.Marshal(name)
+		replacement, _ := json.Marshal(redactCredentials(example))
+		original := string(key) + ":" + string(value)
+		raw = bytes.Replace(raw, []byte(original), []byte(string(key)+":"+string(replacement)), 1)
+	}
+
+	return raw
+}
+
+// redactCredentials replaces the credentials of the value of an authorization
+// header, keeping the authentication scheme, if any
+func redactCredentials(value string) string {
+	if scheme := strings.SplitN(value, " ", 2); len(scheme) == 2 {
+		return scheme[0] + " " + redactedSecret
+	}
+
+	return redactedSecret
+}
+
+func appendUnique(values []string, value string) []string {
+	if containsFold(values, value) {
+		return values
+	}
+
+	return append(values, value)
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,98 @@
+package dsl
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestAuth_WithBasicAuth(t *testing.T) {
+	i := (&Interaction{}).
+		WithRequest(Request{Method: "GET", Path: String("/profile")}).
+		WithBasicAuth("billy", "secret")
+
+	if err := i.validate(); err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	m := i.Request.Headers["Authorization"].(term)
+	if m.Data.Generate != "Basic YmlsbHk6c2VjcmV0" {
+		t.Fatalf("unexpected example '%v'", m.Data.Generate)
+	}
+	if !regexp.MustCompile(m.Data.Matcher.Regex.(string)).MatchString("Basic Ym9iYnk6cGFzcw==") {
+		t.Fatalf("expected regex '%v' to match any credentials", m.Data.Matcher.Regex)
+	}
+	if len(i.secretHeaders) != 1 || i.secretHeaders[0] != "Authorization" {
+		t.Fatalf("expected the Authorization header to be secret, got %v", i.secretHeaders)
+	}
+}
+
+func TestAuth_WithBasicAuthInvalidUsername(t *testing.T) {
+	i := (&Interaction{}).WithBasicAuth("bil:ly", "secret")
+
+	if err := i.validate(); err == nil || !strings.Contains(err.Error(), "must not contain ':'") {
+		t.Fatalf("expected an invalid username error but got '%v'", err)
+	}
+}
+
+func TestAuth_WithBearerToken(t *testing.T) {
+	tests := []struct {
+		token   Matcher
+		example string
+		regex   string
+	}{
+		{Term("abc123", `^[a-z0-9]+$`), "Bearer abc123", `^Bearer ([a-z0-9]+)$`},
+		{String("abc123"), "Bearer abc123", `^Bearer \S+$`},
+		{Like("abc123"), "Bearer abc123", `^Bearer \S+$`},
+	}
+
+	for _, test := range tests {
+		i := (&Interaction{}).WithBearerToken(test.token)
+
+		m := i.Request.Headers["Authorization"].(term)
+		if m.Data.Generate != test.example {
+			t.Fatalf("expected example '%s' but got '%v'", test.example, m.Data.Generate)
+		}
+		if m.Data.Matcher.Regex != test.regex {
+			t.Fatalf("expected regex '%s' but got '%v'", test.regex, m.Data.Matcher.Regex)
+		}
+	}
+}
+
+func TestAuth_RedactSecrets(t *testing.T) {
+	pact := `{"interactions":[{"description":"a request","request":{"method":"GET","path":"/","headers":{"Authorization":"Bearer abc123","Accept":"Bearer abc123"},"matchingRules":{"$.headers.Authorization":{"regex":"^Bearer \\S+$"}}},"response":{"status":200}}]}`
+
+	r := &pactRewriter{secretHeaders: map[string][]string{"a request": {"Authorization"}}}
+	redacted, err := r.rewrite([]byte(pact))
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	if strings.Contains(string(redacted), `"Authorization": "Bearer abc123"`) {
+		t.Fatalf("expected the bearer token to be redacted, got %s", redacted)
+	}
+	if !strings.Contains(string(redacted), `"Authorization": "Bearer REDACTED"`) {
+		t.Fatalf("expected a redacted bearer token, got %s", redacted)
+	}
+	if !strings.Contains(string(redacted), `"Accept": "Bearer abc123"`) {
+		t.Fatalf("expected other headers to be kept, got %s", redacted)
+	}
+	if !strings.Contains(string(redacted), `"regex": "^Bearer \\S+$"`) {
+		t.Fatalf("expected matching rules to be kept, got %s", redacted)
+	}
+}
+
+func TestAuth_RecordSecretHeadersOnlyWhenRedacting(t *testing.T) {
+	p := &Pact{}
+	p.recordSecretHeaders("a request", []string{"Authorization"})
+	if p.secretHeaders != nil {
+		t.Fatalf("expected no secret headers to be recorded, got %v", p.secretHeaders)
+	}
+
+	p.RedactSecrets = true
+	p.recordSecretHeaders("a request", []string{"Authorization"})
+	p.recordSecretHeaders("a request", []string{"authorization"})
+	if len(p.secretHeaders["a request"]) != 1 {
+		t.Fatalf("expected one secret header, got %v", p.secretHeaders)
+	}
+}
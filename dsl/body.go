@@ -0,0 +1,132 @@
+package dsl
+
+import (
+	"fmt"
+	"mime"
+	"strings"
+	"unicode/utf8"
+)
+
+// Content types set by the body builders, if not given in the headers
+const (
+	jsonContentType   = "application/json"
+	binaryContentType = "application/octet-stream"
+)
+
+// WithJSONBody sets the body of the request to a JSON value, which may contain
+// matchers. The Content-Type header is set to application/json if it has not
+// already been set, or else normalised (see WithBinaryBody). A Content-Type that
+// is not JSON is an error.
+func (r *Request) WithJSONBody(body interface{}) *Request {
+	r.Body = body
+	r.setBodyContentType(jsonContentType, isJSONContentType)
+
+	return r
+}
+
+// WithBinaryBody sets the body of the request to content, which must be valid
+// UTF-8 as the Mock Service only supports string bodies: payloads that are
+// truly binary, e.g. images, can't be used as bodies. The Content-Type
+// header is set to application/octet-stream if it has not already been set, or
+// else normalised: the media type and parameter names are lower cased, as is
+// the charset, and the parameters separated by "; " e.g. "text/csv; charset=utf-8".
+// A JSON or text Content-Type is an error.
+func (r *Request) WithBinaryBody(content []byte) *Request {
+	r.Body = string(content)
+	r.setBodyContentType(binaryContentType, isBinaryContentType)
+	if !utf8.Valid(content) {
+		r.err = fmt.Errorf("binary bodies must be valid UTF-8, as the Mock Service only supports string bodies")
+	}
+
+	return r
+}
+
+func (r *Request) setBodyContentType(contentType string, compatible func(string) bool) {
+	headers, err := withBodyContentType(r.Headers, contentType, compatible)
+	r.Headers = headers
+	if err != nil {
+		r.err = err
+	}
+}
+
+// WithJSONBody is as per Request.WithJSONBody, for the response
+func (r *Response) WithJSONBody(body interface{}) *Response {
+	r.Body = body
+	r.setBodyContentType(jsonContentType, isJSONContentType)
+
+	return r
+}
+
+// WithBinaryBody is as per Request.WithBinaryBody, for the response
+func (r *Response) WithBinaryBody(content []byte) *Response {
+	r.Body = string(content)
+	r.setBodyContentType(binaryContentType, isBinaryContentType)
+	if !utf8.Valid(content) {
+		r.err = fmt.Errorf("binary bodies must be valid UTF-8, as the Mock Service only supports string bodies")
+	}
+
+	return r
+}
+
+func (r *Response) setBodyContentType(contentType string, compatible func(string) bool) {
+	headers, err := withBodyContentType(r.Headers, contentType, compatible)
+	r.Headers = headers
+	if err != nil {
+		r.err = err
+	}
+}
+
+// withBodyContentType returns a copy of the headers with the Content-Type set
+// to contentType if absent, or else normalised, and an error if the
+// Content-Type is not compatible with the body
+func withBodyContentType(headers MapMatcher, contentType string, compatible func(string) bool) (MapMatcher, error) {
+	updated := make(MapMatcher, len(headers)+1)
+	for k, v := range headers {
+		updated[k] = v
+	}
+
+	for k, v := range updated {
+		if !strings.EqualFold(k, "Content-Type") {
+			continue
+		}
+
+		// Only plain values are normalised, a matcher is left to match as given
+		if s, ok := v.(String); ok {
+			updated[k] = String(normaliseContentTypeHeader(string(s)))
+		}
+
+		if example := metadataValueString(v); !compatible(example) {
+			return updated, fmt.Errorf("Content-Type '%s' conflicts with the body, expected a content type like '%s'", example, contentType)
+		}
+
+		return updated, nil
+	}
+
+	updated["Content-Type"] = String(contentType)
+
+	return updated, nil
+}
+
+// normaliseContentTypeHeader formats a Content-Type consistently, leaving it
+// unchanged if it can't be parsed
+func normaliseContentTypeHeader(contentType string) string {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return contentType
+	}
+
+	if charset, ok := params["charset"]; ok {
+		params["charset"] = strings.ToLower(charset)
+	}
+
+	if normalised := mime.FormatMediaType(mediaType, params); normalised != "" {
+		return normalised
+	}
+
+	return contentType
+}
+
+// isBinaryContentType returns true for content types other than JSON and text
+func isBinaryContentType(contentType string) bool {
+	return !isJSONContentType(contentType) && !strings.HasPrefix(normaliseContentType(contentType), "text/")
+}
@@ -0,0 +1,48 @@
+package dsl
+
+import (
+	"testing"
+)
+
+func TestBody_WithJSONBody(t *testing.T) {
+	request := (&Request{Method: "POST", Path: String("/users")}).WithJSONBody(map[string]interface{}{"name": Like("bob")})
+	if request.err != nil || request.Headers["Content-Type"] != String("application/json") {
+		t.Fatalf("expected the content type to be inferred but got %v (%v)", request.Headers, request.err)
+	}
+
+	response := (&Response{Headers: MapMatcher{"content-type": String("Application/HAL+JSON;Charset=UTF-8")}}).WithJSONBody(Like(1))
+	if response.err != nil || response.Headers["content-type"] != String("application/hal+json; charset=utf-8") || len(response.Headers) != 1 {
+		t.Fatalf("expected the content type to be normalised but got %v (%v)", response.Headers, response.err)
+	}
+
+	matched := (&Response{Headers: MapMatcher{"Content-Type": Term("application/json;charset=UTF-8", `^application/json`)}}).WithJSONBody(Like(1))
+	if _, ok := matched.Headers["Content-Type"].(term); !ok || matched.err != nil {
+		t.Fatalf("expected a content type matcher to be kept but got %v (%v)", matched.Headers, matched.err)
+	}
+
+	if conflict := (&Response{Headers: MapMatcher{"Content-Type": String("text/plain")}}).WithJSONBody(Like(1)); conflict.err == nil {
+		t.Fatal("expected a conflicting content type to be an error")
+	}
+}
+
+func TestBody_WithBinaryBody(t *testing.T) {
+	response := (&Response{}).WithBinaryBody([]byte("%PDF-1.4"))
+	if response.err != nil || response.Body != "%PDF-1.4" || response.Headers["Content-Type"] != String("application/octet-stream") {
+		t.Fatalf("expected a binary body but got %v, %v (%v)", response.Body, response.Headers, response.err)
+	}
+
+	request := (&Request{Headers: MapMatcher{"Content-Type": String("image/png")}}).WithBinaryBody([]byte("png"))
+	if request.err != nil || request.Headers["Content-Type"] != String("image/png") {
+		t.Fatalf("expected the content type to be kept but got %v (%v)", request.Headers, request.err)
+	}
+
+	for name, invalid := range map[string]*Request{
+		"json":  (&Request{Headers: MapMatcher{"Content-Type": String("application/json")}}).WithBinaryBody([]byte("{}")),
+		"text":  (&Request{Headers: MapMatcher{"Content-Type": String("text/csv")}}).WithBinaryBody([]byte("a,b")),
+		"utf-8": (&Request{}).WithBinaryBody([]byte{0xff, 0xfe}),
+	} {
+		if invalid.err == nil {
+			t.Fatalf("%s: expected the binary body to be rejected", name)
+		}
+	}
+}
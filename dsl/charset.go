@@ -0,0 +1,222 @@
+package dsl
+
+import (
+	"fmt"
+	"mime"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// WithCharset sets the charset parameter of the Content-Type of the request,
+// e.g. "utf-8". The Content-Type must already be set, e.g. by WithJSONBody, and
+// not be a matcher. The Mock Service writes bodies as UTF-8, so the charset must
+// be "utf-8", or "us-ascii" for a body without any non-ASCII characters.
+func (r *Request) WithCharset(charset string) *Request {
+	headers, err := withCharset(r.Headers, charset)
+	r.Headers = headers
+	if err != nil {
+		r.err = err
+	}
+
+	return r
+}
+
+// WithCharset is as per Request.WithCharset, for the response
+func (r *Response) WithCharset(charset string) *Response {
+	headers, err := withCharset(r.Headers, charset)
+	r.Headers = headers
+	if err != nil {
+		r.err = err
+	}
+
+	return r
+}
+
+// withCharset returns a copy of the headers with the charset of the
+// Content-Type set to charset
+func withCharset(headers MapMatcher, charset string) (MapMatcher, error) {
+	charset = strings.ToLower(strings.TrimSpace(charset))
+	if !isSupportedCharset(charset) {
+		return headers, fmt.Errorf("unsupported charset '%s', the Mock Service only writes bodies as 'utf-8' (or 'us-ascii')", charset)
+	}
+
+	updated := make(MapMatcher, len(headers))
+	for k, v := range headers {
+		updated[k] = v
+	}
+
+	for k, v := range updated {
+		if !strings.EqualFold(k, "Content-Type") {
+			continue
+		}
+
+		s, ok := v.(String)
+		if !ok {
+			return updated, fmt.Errorf("unable to set the charset of the Content-Type matcher %v", v)
+		}
+		mediaType, params, err := mime.ParseMediaType(string(s))
+		if err != nil {
+			return updated, fmt.Errorf("unable to set the charset of the Content-Type '%s': %v", s, err)
+		}
+		params["charset"] = charset
+		updated[k] = String(normaliseContentTypeHeader(mime.FormatMediaType(mediaType, params)))
+
+		return updated, nil
+	}
+
+	return updated, fmt.Errorf("unable to set the charset '%s' without a Content-Type", charset)
+}
+
+func isSupportedCharset(charset string) bool {
+	switch strings.ToLower(charset) {
+	case "utf-8", "utf8", "us-ascii", "ascii":
+		return true
+	}
+
+	return false
+}
+
+// validateEncoding checks that the text of the request and response can be
+// sent through the Mock Service as is: paths, queries and bodies must be valid
+// UTF-8 (rather than have invalid bytes replaced as they are serialised),
+// headers ASCII, and bodies in the charset of their Content-Type
+func (i *Interaction) validateEncoding() error {
+	if path := metadataValueString(i.Request.Path); !utf8.ValidString(path) {
+		return fmt.Errorf("request path %q is not valid UTF-8", path)
+	}
+	for name, value := range i.Request.Query {
+		if !isValidUTF8(reflect.ValueOf(value)) {
+			return fmt.Errorf("request query parameter '%s' is not valid UTF-8", name)
+		}
+	}
+
+	for _, part := range []struct {
+		kind    string
+		headers MapMatcher
+		body    interface{}
+	}{
+		{"request", i.Request.Headers, i.Request.Body},
+		{"response", i.Response.Headers, i.Response.Body},
+	} {
+		if err := checkHeaderEncoding(part.kind, part.headers); err != nil {
+			return err
+		}
+		if err := checkBodyEncoding(part.kind, part.headers, part.body); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkHeaderEncoding returns an error if the name or example value of any of
+// the headers has non-ASCII characters, as clients and the Mock Service don't
+// agree on how those are encoded
+func checkHeaderEncoding(kind string, headers MapMatcher) error {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if !isASCII(name) {
+			return fmt.Errorf("%s header name %q must be ASCII", kind, name)
+		}
+		if value := metadataValueString(headers[name]); !isASCII(value) {
+			return fmt.Errorf("%s header '%s' has non-ASCII characters in %q, encode them e.g. as per RFC 8187 (filename*=UTF-8''%%E2%%9C%%93)", kind, name, value)
+		}
+	}
+
+	return nil
+}
+
+// checkBodyEncoding returns an error if the body isn't valid UTF-8, or has
+// characters that the charset of its Content-Type can't encode
+func checkBodyEncoding(kind string, headers MapMatcher, body interface{}) error {
+	if body == nil {
+		return nil
+	}
+
+	v := reflect.ValueOf(body)
+	if !isValidUTF8(v) {
+		return fmt.Errorf("%s body is not valid UTF-8", kind)
+	}
+
+	_, params, err := mime.ParseMediaType(headerValue(headers, "Content-Type"))
+	if err != nil || params["charset"] == "" {
+		return nil
+	}
+	switch charset := strings.ToLower(params["charset"]); charset {
+	case "utf-8", "utf8":
+	case "us-ascii", "ascii":
+		if !isASCIIValue(v) {
+			return fmt.Errorf("%s body has non-ASCII characters, but its Content-Type has the charset '%s'", kind, charset)
+		}
+	default:
+		if !isASCIIValue(v) {
+			return fmt.Errorf("%s body has non-ASCII characters, but the Mock Service writes bodies as UTF-8 rather than the charset '%s' of its Content-Type", kind, charset)
+		}
+	}
+
+	return nil
+}
+
+// isValidUTF8 returns whether all the strings in v, including the keys of
+// maps, are valid UTF-8
+func isValidUTF8(v reflect.Value) bool {
+	return allStrings(v, utf8.ValidString)
+}
+
+// isASCIIValue returns whether all the strings in v, including the keys of
+// maps, are ASCII
+func isASCIIValue(v reflect.Value) bool {
+	return allStrings(v, isASCII)
+}
+
+// allStrings returns whether all the strings in v, including the keys of maps
+// and the exported fields of structs, are ok
+func allStrings(v reflect.Value, ok func(string) bool) bool {
+	switch v.Kind() {
+	case reflect.String:
+		return ok(v.String())
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil() || allStrings(v.Elem(), ok)
+	case reflect.Map:
+		for _, key := range v.MapKeys() {
+			if !allStrings(key, ok) || !allStrings(v.MapIndex(key), ok) {
+				return false
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		// Bytes are serialised as base64
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return true
+		}
+		for n := 0; n < v.Len(); n++ {
+			if !allStrings(v.Index(n), ok) {
+				return false
+			}
+		}
+	case reflect.Struct:
+		for n := 0; n < v.NumField(); n++ {
+			if v.Type().Field(n).PkgPath == "" && !allStrings(v.Field(n), ok) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func isASCII(s string) bool {
+	for n := 0; n < len(s); n++ {
+		if s[n] >= utf8.RuneSelf {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,141 @@
+package dsl
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestCharset_NonASCIIInteraction(t *testing.T) {
+	i := (&Interaction{}).
+		UponReceiving("A request for a greeting 👋").
+		WithRequest(Request{
+			Method:  "GET",
+			Path:    String("/greetings/你好"),
+			Query:   MapMatcher{"lang": String("日本語")},
+			Headers: MapMatcher{"Accept-Language": String("zh-CN")},
+		}).
+		WillRespondWith(*(&Response{Status: 200}).WithJSONBody(map[string]interface{}{
+			"greeting": Like("你好 🎉"),
+			"名前":       "Ünïcödé",
+		}).WithCharset("UTF-8"))
+
+	if err := i.validate(); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if contentType := headerValue(i.Response.Headers, "Content-Type"); contentType != "application/json; charset=utf-8" {
+		t.Fatalf("expected the charset to be added to the Content-Type but got '%s'", contentType)
+	}
+
+	encoded, err := json.Marshal(i)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	for _, s := range []string{"/greetings/你好", "日本語", "你好 🎉", `"名前":"Ünïcödé"`, "👋"} {
+		if !strings.Contains(string(encoded), s) {
+			t.Fatalf("expected '%s' to be written to the Mock Service as is but got %s", s, encoded)
+		}
+	}
+}
+
+func TestCharset_validateEncoding(t *testing.T) {
+	for _, tt := range []struct {
+		request  Request
+		response Response
+		want     string
+	}{
+		{Request{Path: String("/caf\xe9")}, Response{}, "request path \"/caf\\xe9\" is not valid UTF-8"},
+		{Request{Path: String("/"), Query: MapMatcher{"name": String("caf\xe9")}}, Response{}, "request query parameter 'name' is not valid UTF-8"},
+		{Request{Path: String("/"), Body: map[string]interface{}{"caf\xe9": 1}}, Response{}, "request body is not valid UTF-8"},
+		{Request{Path: String("/")}, Response{Body: Like([]string{"ok", "caf\xe9"})}, "response body is not valid UTF-8"},
+		{Request{Path: String("/"), Headers: MapMatcher{"X-Name": String("café")}}, Response{}, "request header 'X-Name' has non-ASCII characters"},
+		{Request{Path: String("/")}, Response{Headers: MapMatcher{"Nämé": String("a")}}, "response header name \"Nämé\" must be ASCII"},
+		{
+			Request{Path: String("/")},
+			Response{Headers: MapMatcher{"Content-Type": String("text/plain; charset=us-ascii")}, Body: "🎉"},
+			"response body has non-ASCII characters, but its Content-Type has the charset 'us-ascii'",
+		},
+		{
+			Request{Path: String("/"), Headers: MapMatcher{"Content-Type": String("application/json; charset=iso-8859-1")}, Body: map[string]string{"name": "café"}},
+			Response{},
+			"the Mock Service writes bodies as UTF-8 rather than the charset 'iso-8859-1'",
+		},
+	} {
+		i := (&Interaction{}).UponReceiving("A request").WithRequest(tt.request).WillRespondWith(tt.response)
+		if err := i.validate(); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Fatalf("expected an error containing '%s' but got '%v'", tt.want, err)
+		}
+	}
+
+	ascii := (&Interaction{}).
+		UponReceiving("A request").
+		WithRequest(Request{Path: String("/"), Headers: MapMatcher{"Content-Type": String("text/plain; charset=iso-8859-1")}, Body: "cafe"}).
+		WillRespondWith(Response{Status: 200})
+	if err := ascii.validate(); err != nil {
+		t.Fatal("expected an ASCII body to be valid in any charset:", err)
+	}
+}
+
+func TestCharset_WithCharset(t *testing.T) {
+	r := (&Request{Headers: MapMatcher{"content-type": String("text/plain; charset=UTF-8")}}).WithCharset("us-ascii")
+	if r.err != nil || headerValue(r.Headers, "Content-Type") != "text/plain; charset=us-ascii" {
+		t.Fatalf("expected the charset to be replaced but got %v (%v)", r.Headers, r.err)
+	}
+
+	for _, tt := range []struct {
+		headers MapMatcher
+		charset string
+		want    string
+	}{
+		{MapMatcher{"Content-Type": String("text/plain")}, "iso-8859-1", "unsupported charset 'iso-8859-1'"},
+		{MapMatcher{}, "utf-8", "unable to set the charset 'utf-8' without a Content-Type"},
+		{MapMatcher{"Content-Type": Term("text/plain", `text/.*`)}, "utf-8", "unable to set the charset of the Content-Type matcher"},
+	} {
+		if r := (&Response{Headers: tt.headers}).WithCharset(tt.charset); r.err == nil || !strings.Contains(r.err.Error(), tt.want) {
+			t.Fatalf("expected an error containing '%s' but got '%v'", tt.want, r.err)
+		}
+	}
+}
+
+func TestCharset_MockServerProxy(t *testing.T) {
+	ms := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		fmt.Fprintf(w, `{"path":%q,"lang":%q,"body":%s}`, r.URL.Path, r.URL.Query().Get("lang"), body)
+	}))
+	defer ms.Close()
+
+	proxy, err := startMockServerProxy("tcp", "localhost", 0, ms.URL, mockServerTimeouts{})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	defer proxy.Stop()
+
+	u := fmt.Sprintf("http://localhost:%d/greetings/%s?lang=%s", proxy.Port, url.PathEscape("你好"), url.QueryEscape("日本語"))
+	res, err := http.Post(u, "application/json; charset=utf-8", strings.NewReader(`{"emoji":"🎉👋🏽","cjk":"漢字かなカナ"}`))
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	content, _ := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+
+	var body struct {
+		Path string `json:"path"`
+		Lang string `json:"lang"`
+		Body struct {
+			Emoji string `json:"emoji"`
+			CJK   string `json:"cjk"`
+		} `json:"body"`
+	}
+	if err = json.Unmarshal(content, &body); err != nil {
+		t.Fatalf("expected a JSON response but got %s: %v", content, err)
+	}
+	if body.Path != "/greetings/你好" || body.Lang != "日本語" || body.Body.Emoji != "🎉👋🏽" || body.Body.CJK != "漢字かなカナ" {
+		t.Fatalf("expected the request and response to pass through the proxy unchanged but got %s", content)
+	}
+}
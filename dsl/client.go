@@ -97,12 +97,13 @@ func (p *PactClient) StartServer(args []string, port int) *types.MockServer {
 	svc := p.pactMockSvcManager.NewService(args)
 	cmd := svc.Start()
 
-	waitForPort(port, p.getNetworkInterface(), p.Address, p.TimeoutDuration,
+	err := waitForPort(port, p.getNetworkInterface(), p.Address, p.TimeoutDuration,
 		fmt.Sprintf(`Timed out waiting for Mock Server to start on port %d - are you sure it's running?`, port))
 
 	return &types.MockServer{
-		Pid:  cmd.Process.Pid,
-		Port: port,
+		Pid:   cmd.Process.Pid,
+		Port:  port,
+		Error: types.NewError(types.ErrServiceStartup, err),
 	}
 }
 
@@ -152,7 +153,7 @@ func (p *PactClient) VerifyProvider(request types.VerifyRequest) ([]types.Provid
 	// Convert request into flags, and validate request
 	err := request.Validate()
 	if err != nil {
-		return response, err
+		return response, types.NewError(types.ErrInvalidRequest, err)
 	}
 
 	address := getAddress(request.ProviderBaseURL)
@@ -194,22 +195,25 @@ func (p *PactClient) VerifyProvider(request types.VerifyRequest) ([]types.Provid
 	// Split by lines, as the content is JSONL formatted
 	// Each pact is verified by line, and the results (as JSON) sent to stdout.
 	// See https://github.com/ray-xu-deltatre/pact-go/issues/88#issuecomment-404686337
+	wg.Add(2)
 	stdOutScanner := bufio.NewScanner(stdOutPipe)
 	go func() {
-		wg.Add(1)
 		defer wg.Done()
 		stdOutBuf := make([]byte, bufio.MaxScanTokenSize)
 		stdOutScanner.Buffer(stdOutBuf, 64*1024*1024)
 
 		for stdOutScanner.Scan() {
 			verifications = append(verifications, stdOutScanner.Text())
+
+			if request.ProgressHandler != nil {
+				reportProgress(request.ProgressHandler, stdOutScanner.Text())
+			}
 		}
 	}()
 
 	// Scrape errors
 	stdErrScanner := bufio.NewScanner(stdErrPipe)
 	go func() {
-		wg.Add(1)
 		defer wg.Done()
 		for stdErrScanner.Scan() {
 			stdErr.WriteString(fmt.Sprintf("%s\n", stdErrScanner.Text()))
@@ -223,10 +227,11 @@ func (p *PactClient) VerifyProvider(request types.VerifyRequest) ([]types.Provid
 	}
 
 	// Wait for watch goroutine before Cmd.Wait(), race condition!
-	err = cmd.Wait()
 	wg.Wait()
+	err = cmd.Wait()
 
 	var verification types.ProviderVerifierResponse
+	var output strings.Builder
 	for _, v := range verifications {
 		v = strings.TrimSpace(v)
 
@@ -242,14 +247,23 @@ func (p *PactClient) VerifyProvider(request types.VerifyRequest) ([]types.Provid
 			if dErr != nil {
 				err = dErr
 			}
+		} else if v != "" {
+			output.WriteString(v + "\n")
 		}
 	}
 
+	// Capture the output of this run, rather than interleaving it with that of
+	// other verifications
+	output.WriteString(stdErr.String())
+	for i := range response {
+		response[i].Output = output.String()
+	}
+
 	if err == nil {
 		return response, err
 	}
 
-	return response, fmt.Errorf("error verifying provider: %s\n\nSTDERR:\n%s\n\nSTDOUT:\n%s", err, stdErr.String(), strings.Join(verifications, "\n"))
+	return response, types.NewError(types.ErrVerification, fmt.Errorf("error verifying provider: %s\n\nSTDERR:\n%s\n\nSTDOUT:\n%s", err, stdErr.String(), strings.Join(verifications, "\n")))
 }
 
 // UpdateMessagePact adds a pact message to a contract file
@@ -292,7 +306,7 @@ func (p *PactClient) UpdateMessagePact(request types.PactMessageRequest) error {
 		return nil
 	}
 
-	return fmt.Errorf("error creating message: %s\n\nSTDERR:\n%s\n\nSTDOUT:\n%s", err, stdErr, stdOut)
+	return types.NewError(types.ErrMessage, fmt.Errorf("error creating message: %s\n\nSTDERR:\n%s\n\nSTDOUT:\n%s", err, stdErr, stdOut))
 }
 
 // PublishPacts publishes a set of pacts to a pact broker
@@ -306,7 +320,7 @@ func (p *PactClient) PublishPacts(request types.PublishRequest) error {
 
 	log.Println("[DEBUG] response from publish", err)
 
-	return err
+	return types.NewError(types.ErrBroker, err)
 }
 
 // ReifyMessage takes a structured object, potentially containing nested Matchers
@@ -368,7 +382,7 @@ func (p *PactClient) ReifyMessage(request *types.PactReificationRequest) (res *t
 		return
 	}
 
-	err = fmt.Errorf("error creating message: %s\n\nSTDERR:\n%s\n\nSTDOUT:\n%s", err, stdErr, stdOut)
+	err = types.NewError(types.ErrMessage, fmt.Errorf("error creating message: %s\n\nSTDERR:\n%s\n\nSTDOUT:\n%s", err, stdErr, stdOut))
 
 	return
 }
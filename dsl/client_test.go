@@ -1,6 +1,7 @@
 package dsl
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -76,11 +77,17 @@ func TestClient_VerifyProvider(t *testing.T) {
 		BrokerPassword:         "foo",
 		ProviderStatesSetupURL: "http://foo/states/setup",
 	}
-	_, err := client.VerifyProvider(req)
+	res, err := client.VerifyProvider(req)
 
 	if err != nil {
 		t.Fatal("Error: ", err)
 	}
+
+	for _, r := range res {
+		if !strings.Contains(r.Output, "INFO: reading pact") || !strings.Contains(r.Output, "verifier log output") {
+			t.Fatalf("Expected the verifier output to be captured but got '%s'", r.Output)
+		}
+	}
 }
 
 func TestClient_VerifyProviderFailValidation(t *testing.T) {
@@ -96,6 +103,10 @@ func TestClient_VerifyProviderFailValidation(t *testing.T) {
 	if !strings.Contains(err.Error(), "One of 'PactURLs' or 'BrokerURL' must be specified") {
 		t.Fatalf("Expected a proper error message but got '%s'", err.Error())
 	}
+
+	if !errors.Is(err, types.ErrInvalidRequest) {
+		t.Fatalf("Expected an ErrInvalidRequest but got '%v'", err)
+	}
 }
 
 func TestClient_VerifyProviderFailExecution(t *testing.T) {
@@ -117,6 +128,10 @@ func TestClient_VerifyProviderFailExecution(t *testing.T) {
 	if !strings.Contains(err.Error(), "COMMAND: oh noes!") {
 		t.Fatalf("Expected a proper error message but got '%s'", err.Error())
 	}
+
+	if !errors.Is(err, types.ErrVerification) {
+		t.Fatalf("Expected an ErrVerification but got '%v'", err)
+	}
 }
 
 func TestClient_getPort(t *testing.T) {
@@ -241,7 +256,8 @@ func TestHelperProcess(t *testing.T) {
 	}
 
 	// Success :)
-	fmt.Fprintf(os.Stdout, "{\"summary_line\":\"1 examples, 0 failures\"}\n{\"summary_line\":\"1 examples, 0 failures\"}")
+	fmt.Fprintf(os.Stderr, "verifier log output\n")
+	fmt.Fprintf(os.Stdout, "INFO: reading pact\n{\"summary_line\":\"1 examples, 0 failures\"}\n{\"summary_line\":\"1 examples, 0 failures\"}")
 	os.Exit(0)
 }
 
@@ -0,0 +1,210 @@
+package dsl
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Metadata keys used to describe encoded message content
+const (
+	contentTypeMetadataKey     = "contentType"
+	contentEncodingMetadataKey = "contentEncoding"
+	base64ContentEncoding      = "base64"
+)
+
+// ContentCodec serialises message content of a given content type, allowing
+// message bodies to be supplied as Go values rather than pre-serialised strings.
+//
+// A JSON codec is registered by default. Other formats, such as protobuf or
+// msgpack, can be supported by registering a codec with RegisterContentCodec.
+type ContentCodec interface {
+	// ContentType is the MIME type the codec handles e.g. "application/x-protobuf"
+	ContentType() string
+
+	// Encode serialises the given value
+	Encode(v interface{}) ([]byte, error)
+
+	// Decode deserialises data into the value pointed to by v
+	Decode(data []byte, v interface{}) error
+}
+
+var codecs = struct {
+	sync.RWMutex
+	registry map[string]ContentCodec
+}{
+	registry: map[string]ContentCodec{
+		"application/json": jsonCodec{},
+	},
+}
+
+// RegisterContentCodec registers (or replaces) the codec for its content type
+func RegisterContentCodec(codec ContentCodec) {
+	codecs.Lock()
+	defer codecs.Unlock()
+
+	codecs.registry[normaliseContentType(codec.ContentType())] = codec
+}
+
+// contentCodecFor finds the codec for a content type, ignoring any parameters
+// such as the charset
+func contentCodecFor(contentType string) (ContentCodec, error) {
+	codecs.RLock()
+	defer codecs.RUnlock()
+
+	codec, ok := codecs.registry[normaliseContentType(contentType)]
+	if !ok {
+		return nil, fmt.Errorf("no content codec registered for content type '%s'", contentType)
+	}
+
+	return codec, nil
+}
+
+func normaliseContentType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(contentType))
+	}
+
+	return mediaType
+}
+
+// jsonCodec is the default codec. Content is left untouched when
+// encoding a message so that matchers continue to work.
+type jsonCodec struct{}
+
+func (jsonCodec) ContentType() string {
+	return "application/json"
+}
+
+func (jsonCodec) Encode(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Decode(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// isJSONCodec returns true if the codec handles JSON content
+func isJSONCodec(codec ContentCodec) bool {
+	_, ok := codec.(jsonCodec)
+	return ok
+}
+
+// WithContentType specifies the content of the message as a Go value, to be
+// serialised by the codec registered for the given content type.
+// The content type is added to the message metadata.
+//
+// JSON content may contain matchers. Other content is written to the contract
+// verbatim, as a string, or base64 encoded if the serialised value is binary.
+func (p *Message) WithContentType(contentType string, content interface{}) *Message {
+	p.ContentType = contentType
+	p.Content = content
+	p.err = nil
+
+	codec, err := contentCodecFor(contentType)
+	if err != nil {
+		p.err = err
+		return p
+	}
+
+	if isJSONCodec(codec) {
+		return p
+	}
+
+	encoded, encoding, err := encodeContent(codec, content)
+	if err != nil {
+		p.err = err
+		return p
+	}
+
+	p.Content = encoded
+	p.contentEncoding = encoding
+
+	return p
+}
+
+// contentMetadata applies the content type and encoding to the metadata
+func (p *Message) contentMetadata() {
+	if p.ContentType == "" {
+		return
+	}
+
+	if p.Metadata == nil {
+		p.Metadata = MapMatcher{}
+	}
+
+	p.Metadata[contentTypeMetadataKey] = String(p.ContentType)
+	if p.contentEncoding != "" {
+		p.Metadata[contentEncodingMetadataKey] = String(p.contentEncoding)
+	}
+}
+
+// encodeContent serialises content with the given codec, returning the string
+// representation to store in the contract and the encoding used (if any)
+func encodeContent(codec ContentCodec, content interface{}) (string, string, error) {
+	body, err := codec.Encode(content)
+	if err != nil {
+		return "", "", fmt.Errorf("unable to encode message content as '%s': %v", codec.ContentType(), err)
+	}
+
+	if utf8.Valid(body) {
+		return string(body), "", nil
+	}
+
+	return base64.StdEncoding.EncodeToString(body), base64ContentEncoding, nil
+}
+
+// decodeContent reverses encodeContent, deserialising into v
+func decodeContent(codec ContentCodec, content interface{}, encoding string, v interface{}) error {
+	body, err := encodedContentBytes(content, encoding)
+	if err != nil {
+		return err
+	}
+
+	return codec.Decode(body, v)
+}
+
+// encodedContentBytes returns the serialised content stored by encodeContent
+func encodedContentBytes(content interface{}, encoding string) ([]byte, error) {
+	s, ok := content.(string)
+	if !ok {
+		return nil, fmt.Errorf("expected encoded message content to be a string, but got %T", content)
+	}
+
+	if encoding != base64ContentEncoding {
+		return []byte(s), nil
+	}
+
+	body, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode base64 message content: %v", err)
+	}
+
+	return body, nil
+}
+
+// EncodedMessageHandler wraps a MessageHandler whose result should be
+// serialised by the codec registered for contentType, for use when verifying
+// messages created with WithContentType.
+func EncodedMessageHandler(contentType string, handler MessageHandler) MessageHandler {
+	return func(m Message) (interface{}, error) {
+		codec, err := contentCodecFor(contentType)
+		if err != nil {
+			return nil, err
+		}
+
+		res, err := handler(m)
+		if err != nil || isJSONCodec(codec) {
+			return res, err
+		}
+
+		encoded, _, err := encodeContent(codec, res)
+
+		return encoded, err
+	}
+}
@@ -0,0 +1,157 @@
+package dsl
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// csvCodec is a trivial codec for a slice of strings
+type csvCodec struct{}
+
+func (csvCodec) ContentType() string { return "text/csv" }
+
+func (csvCodec) Encode(v interface{}) ([]byte, error) {
+	values, ok := v.([]string)
+	if !ok {
+		return nil, errors.New("expected []string")
+	}
+	return []byte(strings.Join(values, ",")), nil
+}
+
+func (csvCodec) Decode(data []byte, v interface{}) error {
+	values, ok := v.(*[]string)
+	if !ok {
+		return errors.New("expected *[]string")
+	}
+	*values = strings.Split(string(data), ",")
+	return nil
+}
+
+// binaryCodec encodes strings as non UTF-8 bytes
+type binaryCodec struct{}
+
+func (binaryCodec) ContentType() string { return "application/x-binary" }
+
+func (binaryCodec) Encode(v interface{}) ([]byte, error) {
+	return append([]byte{0xff, 0xfe}, []byte(v.(string))...), nil
+}
+
+func (binaryCodec) Decode(data []byte, v interface{}) error {
+	*(v.(*string)) = string(data[2:])
+	return nil
+}
+
+func init() {
+	RegisterContentCodec(csvCodec{})
+	RegisterContentCodec(binaryCodec{})
+}
+
+func TestCodec_contentCodecFor(t *testing.T) {
+	codec, err := contentCodecFor("application/json; charset=utf-8")
+	assert.NoError(t, err)
+	assert.True(t, isJSONCodec(codec))
+
+	codec, err = contentCodecFor("TEXT/CSV")
+	assert.NoError(t, err)
+	assert.Equal(t, "text/csv", codec.ContentType())
+
+	_, err = contentCodecFor("application/unknown")
+	assert.Error(t, err)
+}
+
+func TestCodec_WithContentTypeJSON(t *testing.T) {
+	content := map[string]interface{}{"id": Like(1)}
+	m := (&Message{}).WithContentType("application/json", content)
+
+	assert.NoError(t, m.err)
+	assert.Equal(t, content, m.Content, "expected JSON content to be left untouched")
+
+	m.contentMetadata()
+	assert.Equal(t, String("application/json"), m.Metadata[contentTypeMetadataKey])
+}
+
+func TestCodec_WithContentTypeText(t *testing.T) {
+	m := (&Message{}).WithContentType("text/csv", []string{"a", "b"})
+
+	assert.NoError(t, m.err)
+	assert.Equal(t, "a,b", m.Content)
+	assert.Equal(t, "", m.contentEncoding)
+}
+
+func TestCodec_WithContentTypeBinary(t *testing.T) {
+	m := (&Message{}).WithContentType("application/x-binary", "foo")
+
+	assert.NoError(t, m.err)
+	assert.Equal(t, "//5mb28=", m.Content)
+
+	m.contentMetadata()
+	assert.Equal(t, String(base64ContentEncoding), m.Metadata[contentEncodingMetadataKey])
+
+	var decoded string
+	err := decodeContent(binaryCodec{}, m.Content, m.contentEncoding, &decoded)
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", decoded)
+}
+
+func TestCodec_WithContentTypeFail(t *testing.T) {
+	m := (&Message{}).WithContentType("application/unknown", "foo")
+	assert.Error(t, m.err)
+
+	m = (&Message{}).WithContentType("text/csv", 42)
+	assert.Error(t, m.err)
+}
+
+func TestCodec_VerifyMessageConsumer(t *testing.T) {
+	pact := &Pact{pactClient: newMockClient()}
+
+	message := pact.AddMessage()
+	message.
+		ExpectsToReceive("a list of names").
+		WithContentType("text/csv", []string{"alice", "bob"}).
+		AsType(&[]string{})
+
+	var received []string
+	err := pact.VerifyMessageConsumerRaw(message, func(m Message) error {
+		received = *m.Content.(*[]string)
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"alice", "bob"}, received)
+}
+
+func TestCodec_VerifyMessageConsumerInvalid(t *testing.T) {
+	pact := &Pact{pactClient: newMockClient()}
+
+	message := pact.AddMessage()
+	message.
+		ExpectsToReceive("a list of names").
+		WithContentType("application/unknown", []string{"alice", "bob"})
+
+	err := pact.VerifyMessageConsumerRaw(message, func(m Message) error {
+		return nil
+	})
+
+	assert.Error(t, err)
+}
+
+func TestCodec_EncodedMessageHandler(t *testing.T) {
+	h := EncodedMessageHandler("text/csv", func(m Message) (interface{}, error) {
+		return []string{"alice", "bob"}, nil
+	})
+
+	res, err := h(Message{})
+	assert.NoError(t, err)
+	assert.Equal(t, "alice,bob", res)
+
+	h = EncodedMessageHandler("application/json", func(m Message) (interface{}, error) {
+		return map[string]string{"foo": "bar"}, nil
+	})
+
+	res, err = h(Message{})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"foo": "bar"}, res)
+}
@@ -0,0 +1,154 @@
+package dsl
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// cookieValue matches any cookie value, if no ValueRegex is given
+const cookieValue = `[^;]*`
+
+// Cookie describes a cookie sent with a request (see WithRequestCookies), or
+// set by a response (see WithResponseSetCookie).
+//
+// NOTE: the generated matchers use lookaheads, which are supported by the Pact
+// CLI tools (Ruby) but not by Go's regexp package.
+type Cookie struct {
+	// Name of the cookie, matched verbatim. Mandatory.
+	Name string
+
+	// Value is an example value of the cookie
+	Value string
+
+	// ValueRegex matches the value of the cookie. Defaults to any value.
+	ValueRegex string
+
+	// Path, Domain and SameSite are attributes of a Set-Cookie header, and are
+	// matched verbatim if set
+	Path     string
+	Domain   string
+	SameSite string
+
+	// MaxAge is an example Max-Age of a Set-Cookie header. If greater than 0, the
+	// header must have a Max-Age attribute (of any value).
+	MaxAge int
+
+	// Secure and HttpOnly require the corresponding attributes of a Set-Cookie
+	// header, if set
+	Secure   bool
+	HttpOnly bool
+}
+
+// valueRegex returns the regex matching the name and value of the cookie, up
+// to the end of the cookie
+func (c Cookie) valueRegex() string {
+	value := c.ValueRegex
+	if value == "" {
+		value = cookieValue
+	}
+
+	return fmt.Sprintf("%s=(%s)(;|$)", regexp.QuoteMeta(c.Name), value)
+}
+
+// validate checks the cookie name, and that the example matches the value regex
+func (c Cookie) validate() error {
+	if c.Name == "" || strings.ContainsAny(c.Name, "=; ") {
+		return fmt.Errorf("invalid cookie name '%s'", c.Name)
+	}
+
+	if c.ValueRegex == "" {
+		return nil
+	}
+
+	// Only regexes Go is able to compile can be checked here
+	if r, err := regexp.Compile("^(" + c.ValueRegex + ")$"); err == nil && !r.MatchString(c.Value) {
+		return fmt.Errorf("example value '%s' of cookie '%s' does not match regex '%s'", c.Value, c.Name, c.ValueRegex)
+	}
+
+	return nil
+}
+
+// WithRequestCookies expects the request to send the given cookies in a Cookie
+// header, in any order and alongside any other cookies. Must be called after
+// WithRequest.
+func (i *Interaction) WithRequestCookies(cookies ...Cookie) *Interaction {
+	examples := make([]string, len(cookies))
+	regex := "^"
+
+	for n, c := range cookies {
+		if err := c.validate(); err != nil {
+			i.Request.err = err
+			return i
+		}
+
+		examples[n] = c.Name + "=" + c.Value
+		regex += "(?=(.*; )?" + c.valueRegex() + ")"
+	}
+
+	headers, err := setHeader(i.Request.Headers, "Cookie", Term(strings.Join(examples, "; "), regex))
+	i.Request.Headers = headers
+	if err != nil {
+		i.Request.err = err
+	}
+
+	return i
+}
+
+// WithResponseSetCookie expects the response to set the given cookie in a
+// Set-Cookie header, with (at least) the attributes of the cookie in any order.
+// Only one cookie may be set per response. Must be called after WillRespondWith.
+func (i *Interaction) WithResponseSetCookie(cookie Cookie) *Interaction {
+	if err := cookie.validate(); err != nil {
+		i.Response.err = err
+		return i
+	}
+
+	if i.Response.Headers == nil {
+		i.Response.Headers = MapMatcher{}
+	}
+	for k := range i.Response.Headers {
+		if strings.EqualFold(k, "Set-Cookie") {
+			i.Response.err = fmt.Errorf("only one Set-Cookie header may be expected, found another for cookie '%s'", cookie.Name)
+			return i
+		}
+	}
+
+	example := []string{cookie.Name + "=" + cookie.Value}
+	regex := "^"
+
+	attribute := func(name string, value string, valueRegex string) {
+		if value == "" {
+			example = append(example, name)
+			regex += fmt.Sprintf(`(?=.*;\s*(?i:%s)(;|$))`, regexp.QuoteMeta(name))
+			return
+		}
+
+		example = append(example, name+"="+value)
+		regex += fmt.Sprintf(`(?=.*;\s*(?i:%s)=%s(;|$))`, regexp.QuoteMeta(name), valueRegex)
+	}
+
+	if cookie.Path != "" {
+		attribute("Path", cookie.Path, regexp.QuoteMeta(cookie.Path))
+	}
+	if cookie.Domain != "" {
+		attribute("Domain", cookie.Domain, regexp.QuoteMeta(cookie.Domain))
+	}
+	if cookie.MaxAge > 0 {
+		attribute("Max-Age", strconv.Itoa(cookie.MaxAge), `\d+`)
+	}
+	if cookie.HttpOnly {
+		attribute("HttpOnly", "", "")
+	}
+	if cookie.Secure {
+		attribute("Secure", "", "")
+	}
+	if cookie.SameSite != "" {
+		attribute("SameSite", cookie.SameSite, "(?i:"+regexp.QuoteMeta(cookie.SameSite)+")")
+	}
+
+	i.Response.Headers["Set-Cookie"] = Term(strings.Join(example, "; "), regex+cookie.valueRegex())
+
+	return i
+}
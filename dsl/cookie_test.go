@@ -0,0 +1,79 @@
+package dsl
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCookie_WithRequestCookies(t *testing.T) {
+	i := (&Interaction{}).
+		WithRequest(Request{Method: "GET", Path: String("/profile")}).
+		WithRequestCookies(
+			Cookie{Name: "session", Value: "abc123", ValueRegex: "[a-z0-9]+"},
+			Cookie{Name: "theme", Value: "dark"},
+		)
+
+	if err := i.validate(); err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	m := i.Request.Headers["Cookie"].(term)
+	if m.Data.Generate != "session=abc123; theme=dark" {
+		t.Fatalf("unexpected example '%v'", m.Data.Generate)
+	}
+
+	want := `^(?=(.*; )?session=([a-z0-9]+)(;|$))(?=(.*; )?theme=([^;]*)(;|$))`
+	if m.Data.Matcher.Regex != want {
+		t.Fatalf("expected regex '%s' but got '%v'", want, m.Data.Matcher.Regex)
+	}
+}
+
+func TestCookie_WithResponseSetCookie(t *testing.T) {
+	i := (&Interaction{}).
+		WillRespondWith(Response{Status: 200}).
+		WithResponseSetCookie(Cookie{
+			Name:       "session",
+			Value:      "abc123",
+			ValueRegex: "[a-z0-9]+",
+			Path:       "/",
+			MaxAge:     3600,
+			HttpOnly:   true,
+			Secure:     true,
+			SameSite:   "Lax",
+		})
+
+	if err := i.validate(); err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	m := i.Response.Headers["Set-Cookie"].(term)
+	if m.Data.Generate != "session=abc123; Path=/; Max-Age=3600; HttpOnly; Secure; SameSite=Lax" {
+		t.Fatalf("unexpected example '%v'", m.Data.Generate)
+	}
+
+	want := `^(?=.*;\s*(?i:Path)=/(;|$))` +
+		`(?=.*;\s*(?i:Max-Age)=\d+(;|$))` +
+		`(?=.*;\s*(?i:HttpOnly)(;|$))` +
+		`(?=.*;\s*(?i:Secure)(;|$))` +
+		`(?=.*;\s*(?i:SameSite)=(?i:Lax)(;|$))` +
+		`session=([a-z0-9]+)(;|$)`
+	if m.Data.Matcher.Regex != want {
+		t.Fatalf("expected regex '%s' but got '%v'", want, m.Data.Matcher.Regex)
+	}
+}
+
+func TestCookie_Invalid(t *testing.T) {
+	tests := map[string]*Interaction{
+		"invalid cookie name": (&Interaction{}).WithRequestCookies(Cookie{Name: "a=b"}),
+		"does not match":      (&Interaction{}).WithRequestCookies(Cookie{Name: "id", Value: "abc", ValueRegex: `\d+`}),
+		"only one": (&Interaction{}).
+			WithResponseSetCookie(Cookie{Name: "a"}).
+			WithResponseSetCookie(Cookie{Name: "b"}),
+	}
+
+	for want, i := range tests {
+		if err := i.validate(); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected an error containing '%s' but got '%v'", want, err)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package dsl
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// layoutChunks maps the elements of a Go time layout to the regular expression
+// matching the values they produce. Longer chunks must come first, so that
+// e.g. "January" is not translated as "Jan" followed by "uary".
+var layoutChunks = []struct {
+	chunk string
+	regex string
+}{
+	{"January", `(January|February|March|April|May|June|July|August|September|October|November|December)`},
+	{"Monday", `(Monday|Tuesday|Wednesday|Thursday|Friday|Saturday|Sunday)`},
+	{"2006", `\d{4}`},
+	{"Z07:00:00", `(Z|[+-]\d{2}:\d{2}:\d{2})`},
+	{"-07:00:00", `[+-]\d{2}:\d{2}:\d{2}`},
+	{"Z07:00", `(Z|[+-]\d{2}:\d{2})`},
+	{"-07:00", `[+-]\d{2}:\d{2}`},
+	{"Z0700", `(Z|[+-]\d{4})`},
+	{"-0700", `[+-]\d{4}`},
+	{"Z07", `(Z|[+-]\d{2})`},
+	{"-07", `[+-]\d{2}`},
+	{"Jan", `(Jan|Feb|Mar|Apr|May|Jun|Jul|Aug|Sep|Oct|Nov|Dec)`},
+	{"Mon", `(Mon|Tue|Wed|Thu|Fri|Sat|Sun)`},
+	{"MST", `[A-Z]{3,5}`},
+	{"002", `\d{3}`},
+	{"__2", `[ \d]{2}\d`},
+	{"_2", `( [1-9]|[12]\d|3[01])`},
+	{"01", `(0[1-9]|1[0-2])`},
+	{"02", `(0[1-9]|[12]\d|3[01])`},
+	{"03", `(0[1-9]|1[0-2])`},
+	{"04", `[0-5]\d`},
+	{"05", `[0-5]\d`},
+	{"06", `\d{2}`},
+	{"15", `([01]\d|2[0-3])`},
+	{"PM", `(AM|PM)`},
+	{"pm", `(am|pm)`},
+	{"1", `(1[0-2]|[1-9])`},
+	{"2", `([12]\d|3[01]|[1-9])`},
+	{"3", `(1[0-2]|[1-9])`},
+	{"4", `[0-5]?\d`},
+	{"5", `[0-5]?\d`},
+}
+
+// DateTime defines a matcher that accepts timestamps formatted with the given
+// Go time layout, e.g. time.RFC3339 or "2006-01-02 15:04". The layout is
+// translated into the regular expression written to the contract.
+//
+// If an example is given, it must be valid for the layout otherwise this function
+// will panic. If the example is empty, one is generated from the layout.
+func DateTime(layout string, example string) Matcher {
+	if example == "" {
+		example = timeExample.Format(layout)
+	}
+
+	if _, err := time.Parse(layout, example); err != nil {
+		panic(fmt.Sprintf("DateTime: example '%s' is not valid for layout '%s': %v", example, layout, err))
+	}
+
+	return Regex(example, layoutToRegex(layout))
+}
+
+// layoutToRegex converts a Go time layout into an anchored regular expression
+func layoutToRegex(layout string) string {
+	var regex strings.Builder
+	regex.WriteString("^")
+
+	for i := 0; i < len(layout); {
+		if n := fractionalSecondsLength(layout[i:]); n > 0 {
+			separator := regexp.QuoteMeta(layout[i : i+1])
+			if layout[i+1] == '0' {
+				regex.WriteString(fmt.Sprintf(`%s\d{%d}`, separator, n-1))
+			} else {
+				regex.WriteString(fmt.Sprintf(`(%s\d{1,%d})?`, separator, n-1))
+			}
+			i += n
+			continue
+		}
+
+		matched := false
+		for _, c := range layoutChunks {
+			if strings.HasPrefix(layout[i:], c.chunk) {
+				regex.WriteString(c.regex)
+				i += len(c.chunk)
+				matched = true
+				break
+			}
+		}
+
+		if !matched {
+			regex.WriteString(regexp.QuoteMeta(layout[i : i+1]))
+			i++
+		}
+	}
+
+	regex.WriteString("$")
+
+	return regex.String()
+}
+
+// fractionalSecondsLength returns the length of a fractional seconds element
+// (e.g. ".000" or ",999") at the start of the layout, or 0 if there is none
+func fractionalSecondsLength(layout string) int {
+	if len(layout) < 2 || (layout[0] != '.' && layout[0] != ',') || (layout[1] != '0' && layout[1] != '9') {
+		return 0
+	}
+
+	n := 1
+	for n < len(layout) && layout[n] == layout[1] {
+		n++
+	}
+
+	// Like the time package, only treat the element as fractional seconds
+	// if it isn't followed by another digit
+	if n < len(layout) && layout[n] >= '0' && layout[n] <= '9' {
+		return 0
+	}
+
+	return n
+}
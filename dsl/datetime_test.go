@@ -0,0 +1,82 @@
+package dsl
+
+import (
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestDateTime_layoutToRegex(t *testing.T) {
+	layouts := []string{
+		time.ANSIC,
+		time.RFC822Z,
+		time.RFC1123,
+		time.RFC3339,
+		time.RFC3339Nano,
+		time.Kitchen,
+		time.StampMilli,
+		"2006-01-02 15:04:05.000",
+		"02/01/06 3:04pm",
+		"January 2, 2006",
+		"2006.002",
+	}
+	times := []time.Time{
+		timeExample,
+		time.Date(2019, 12, 31, 23, 59, 59, 123456789, time.FixedZone("AEDT", 11*60*60)),
+		time.Date(1999, 1, 9, 0, 0, 1, 100000000, time.UTC),
+	}
+
+	for _, layout := range layouts {
+		r := regexp.MustCompile(layoutToRegex(layout))
+
+		for _, tm := range times {
+			if v := tm.Format(layout); !r.MatchString(v) {
+				t.Fatalf("expected '%s' to match regex '%s' for layout '%s'", v, r, layout)
+			}
+		}
+	}
+}
+
+func TestDateTime_layoutToRegexMismatch(t *testing.T) {
+	tests := map[string]string{
+		time.RFC3339:              "2006-01-02 15:04:05",
+		"2006-01-02":              "2006-13-02",
+		"2006-01-02 15:04:05.000": "2006-01-02 15:04:05.1",
+		"15:04":                   "25:04",
+	}
+
+	for layout, v := range tests {
+		r := regexp.MustCompile(layoutToRegex(layout))
+
+		if r.MatchString(v) {
+			t.Fatalf("expected '%s' not to match regex '%s' for layout '%s'", v, r, layout)
+		}
+	}
+}
+
+func TestDateTime_DateTime(t *testing.T) {
+	m := DateTime(time.RFC3339, "2019-06-10T10:11:12+10:00").(term)
+
+	if m.Data.Generate != "2019-06-10T10:11:12+10:00" {
+		t.Fatalf("expected the example to be used but got '%v'", m.Data.Generate)
+	}
+	if m.Data.Matcher.Regex != layoutToRegex(time.RFC3339) {
+		t.Fatalf("expected the regex to be derived from the layout but got '%v'", m.Data.Matcher.Regex)
+	}
+
+	m = DateTime("2006-01-02", "").(term)
+
+	if m.Data.Generate != "2000-02-01" {
+		t.Fatalf("expected an example to be generated but got '%v'", m.Data.Generate)
+	}
+}
+
+func TestDateTime_DateTimeInvalidExample(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected DateTime to panic when the example does not match the layout")
+		}
+	}()
+
+	DateTime(time.RFC3339, "10/06/2019")
+}
@@ -0,0 +1,140 @@
+package dsl
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ray-xu-deltatre/pact-go/proxy"
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+// deprecations collects the deprecations announced by the provider during
+// verification, by request
+type deprecations struct {
+	mu        sync.Mutex
+	byRequest map[string]types.Deprecation
+}
+
+func (d *deprecations) add(deprecation types.Deprecation) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.byRequest == nil {
+		d.byRequest = make(map[string]types.Deprecation)
+	}
+	d.byRequest[deprecation.Request] = deprecation
+}
+
+// deprecationMiddleware records the deprecations announced by the headers of
+// the responses of the provider
+func deprecationMiddleware(d *deprecations) proxy.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r)
+
+			if r.URL.Path == providerStatesSetupPath {
+				return
+			}
+			if deprecation, ok := responseDeprecation(r.Method, r.URL.Path, w.Header()); ok {
+				d.add(deprecation)
+			}
+		})
+	}
+}
+
+// responseDeprecation returns the deprecation announced by the headers of the
+// response to the request, if any
+func responseDeprecation(method string, path string, header http.Header) (types.Deprecation, bool) {
+	deprecation := types.Deprecation{
+		Request:     fmt.Sprintf("%s %s", strings.ToUpper(method), path),
+		Deprecation: header.Get("Deprecation"),
+		Sunset:      header.Get("Sunset"),
+	}
+	if deprecation.Deprecation == "" && deprecation.Sunset == "" {
+		return deprecation, false
+	}
+
+	for _, value := range header["Link"] {
+		for _, link := range strings.Split(value, ",") {
+			target, rels := parseLink(link)
+			if target != "" && (rels["deprecation"] || rels["sunset"]) {
+				deprecation.Links = append(deprecation.Links, target)
+			}
+		}
+	}
+
+	return deprecation, true
+}
+
+// parseLink returns the target and relation types of a link of a Link header,
+// e.g. `<https://example.com/deprecation>; rel="deprecation"`
+func parseLink(link string) (string, map[string]bool) {
+	params := strings.Split(link, ";")
+	target := strings.TrimSpace(params[0])
+	if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+		return "", nil
+	}
+
+	rels := make(map[string]bool)
+	for _, param := range params[1:] {
+		kv := strings.SplitN(strings.TrimSpace(param), "=", 2)
+		if len(kv) != 2 || !strings.EqualFold(strings.TrimSpace(kv[0]), "rel") {
+			continue
+		}
+		for _, rel := range strings.Fields(strings.Trim(strings.TrimSpace(kv[1]), `"`)) {
+			rels[strings.ToLower(rel)] = true
+		}
+	}
+
+	return strings.TrimSuffix(strings.TrimPrefix(target, "<"), ">"), rels
+}
+
+// report sets the Deprecation of the examples of the results for the requests
+// of the deprecations, logging each deprecation found
+func (d *deprecations) report(res []types.ProviderVerifierResponse) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	requests := make([]string, 0, len(d.byRequest))
+	for request := range d.byRequest {
+		requests = append(requests, request)
+	}
+	sort.Strings(requests)
+
+	for _, request := range requests {
+		deprecation := d.byRequest[request]
+		log.Printf("[WARN] the provider announced that %s\n", deprecation)
+
+		for i := range res {
+			for j := range res[i].Examples {
+				if exampleOfRequest(res[i].Examples[j].FullDescription, request) {
+					res[i].Examples[j].Deprecation = &deprecation
+				}
+			}
+		}
+	}
+}
+
+// exampleOfRequest returns whether the full description of an example, e.g.
+// "... A request for user 10 with GET /users/10 returns a response which has
+// status code 200", is of the request e.g. "GET /users/10"
+func exampleOfRequest(description string, request string) bool {
+	needle := fmt.Sprintf(" with %s", request)
+
+	for offset := 0; ; {
+		i := strings.Index(description[offset:], needle)
+		if i < 0 {
+			return false
+		}
+
+		end := offset + i + len(needle)
+		if end == len(description) || description[end] == ' ' || description[end] == '?' {
+			return true
+		}
+		offset = end
+	}
+}
@@ -0,0 +1,67 @@
+package dsl
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+func TestDeprecation_Middleware(t *testing.T) {
+	d := &deprecations{}
+	handler := deprecationMiddleware(d)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/users/1" || r.URL.Path == providerStatesSetupPath {
+			w.Header().Set("Deprecation", "@1688169599")
+			w.Header().Set("Sunset", "Sun, 30 Jun 2024 23:59:59 GMT")
+			w.Header().Add("Link", `<https://example.com/docs>; rel="alternate", <https://example.com/deprecation>; rel="deprecation"`)
+			w.Header().Add("Link", `<https://example.com/sunset>; rel="sunset"`)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for _, path := range []string{"/users/1", "/users", providerStatesSetupPath} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+	}
+
+	want := map[string]types.Deprecation{
+		"GET /users/1": {
+			Request:     "GET /users/1",
+			Deprecation: "@1688169599",
+			Sunset:      "Sun, 30 Jun 2024 23:59:59 GMT",
+			Links:       []string{"https://example.com/deprecation", "https://example.com/sunset"},
+		},
+	}
+	if !reflect.DeepEqual(d.byRequest, want) {
+		t.Fatalf("expected the deprecations %+v but got %+v", want, d.byRequest)
+	}
+}
+
+func TestDeprecation_report(t *testing.T) {
+	d := &deprecations{}
+	d.add(types.Deprecation{Request: "GET /users/1", Deprecation: "true"})
+
+	var res types.ProviderVerifierResponse
+	var examples []string
+	for _, description := range []string{
+		"Verifying a pact between billy and bobby A request for user 1 with GET /users/1 returns a response which has status code 200",
+		"Verifying a pact between billy and bobby A request for user 1 with GET /users/1?verbose=true returns a response which has a matching body",
+		"Verifying a pact between billy and bobby A request for user 10 with GET /users/10 returns a response which has status code 200",
+		"Verifying a pact between billy and bobby A request for the users with GET /users returns a response which has status code 200",
+	} {
+		examples = append(examples, fmt.Sprintf(`{"full_description":%q}`, description))
+	}
+	json.Unmarshal([]byte(fmt.Sprintf(`{"examples":[%s,%s,%s,%s]}`, examples[0], examples[1], examples[2], examples[3])), &res)
+
+	results := []types.ProviderVerifierResponse{res}
+	d.report(results)
+
+	for i, want := range []bool{true, true, false, false} {
+		if deprecated := results[0].Examples[i].Deprecation != nil; deprecated != want {
+			t.Fatalf("expected example %d to be deprecated %v but got %v", i, want, deprecated)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package dsl
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// bodyPathPattern finds JSON paths within bodies in mismatches e.g.
+// "$.body.items[2].id"
+var bodyPathPattern = regexp.MustCompile(`\$\.body((?:\.[A-Za-z0-9_-]+|\[(?:\d+|\*)\]|\['[^']*'\])*)`)
+
+// WithMatchedBody sets the body of the request to Match(src), as per
+// WithJSONBody, recording the Go type so that mismatches at JSON paths within
+// the body also name the Go field, e.g. "$.body.items[2].id (Order.Items[2].ID)"
+func (r *Request) WithMatchedBody(src interface{}) *Request {
+	r.WithJSONBody(Match(src))
+	r.bodyType = reflect.TypeOf(src)
+
+	return r
+}
+
+// WithMatchedBody sets the body of the response to Match(src), as per
+// WithJSONBody. Mismatches of responses are reported during provider
+// verification, where GoFieldPath may be used to name the Go fields.
+func (r *Response) WithMatchedBody(src interface{}) *Response {
+	return r.WithJSONBody(Match(src))
+}
+
+// GoFieldPath returns the Go field of src at a JSON path within a body built
+// from it with Match, e.g. "$.items[2].id" of an Order is "Order.Items[2].ID".
+// It returns false if the path is not one of a field of src, e.g. for use in
+// reporting mismatches of responses during provider verification.
+func GoFieldPath(src interface{}, path string) (string, bool) {
+	return goFieldPath(reflect.TypeOf(src), path)
+}
+
+func goFieldPath(t reflect.Type, path string) (string, bool) {
+	if t == nil {
+		return "", false
+	}
+
+	tokens, err := parseJSONPath(path)
+	if err != nil {
+		return "", false
+	}
+
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	field := t.Name()
+	if field == "" {
+		field = t.String()
+	}
+
+	for _, token := range tokens {
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+
+		switch {
+		case strings.HasPrefix(token, "["):
+			if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+				return "", false
+			}
+			field += token
+			t = t.Elem()
+		case t.Kind() == reflect.Struct:
+			f, ok := structFieldByJSONName(t, token[1:])
+			if !ok {
+				return "", false
+			}
+			field += "." + f.Name
+			t = f.Type
+		default:
+			return "", false
+		}
+	}
+
+	return field, true
+}
+
+// structFieldByJSONName returns the field of the struct encoded with the given
+// name, as per Match
+func structFieldByJSONName(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		if field := t.Field(i); getJsonFieldName(field) == name {
+			return field, true
+		}
+	}
+
+	return reflect.StructField{}, false
+}
+
+// fieldPathsError is an error with the JSON paths within bodies in its message
+// annotated with Go fields
+type fieldPathsError struct {
+	err     error
+	message string
+}
+
+func (e *fieldPathsError) Error() string {
+	return e.message
+}
+
+func (e *fieldPathsError) Unwrap() error {
+	return e.err
+}
+
+// withFieldPaths annotates the JSON paths in err with the Go fields of the
+// request bodies of the interactions that were built with WithMatchedBody
+func withFieldPaths(err error, interactions []*Interaction) error {
+	var bodyTypes []reflect.Type
+	for _, i := range interactions {
+		if i.Request.bodyType != nil {
+			bodyTypes = append(bodyTypes, i.Request.bodyType)
+		}
+	}
+	if err == nil || len(bodyTypes) == 0 {
+		return err
+	}
+
+	message := bodyPathPattern.ReplaceAllStringFunc(err.Error(), func(path string) string {
+		for _, t := range bodyTypes {
+			if field, ok := goFieldPath(t, "$"+strings.TrimPrefix(path, "$.body")); ok {
+				return path + " (" + field + ")"
+			}
+		}
+		return path
+	})
+
+	if message == err.Error() {
+		return err
+	}
+
+	return &fieldPathsError{err: err, message: message}
+}
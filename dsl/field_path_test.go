@@ -0,0 +1,62 @@
+package dsl
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+type fieldPathItem struct {
+	ID   int    `json:"id"`
+	Name string `json:"name,omitempty"`
+}
+
+type fieldPathOrder struct {
+	Items    []fieldPathItem         `json:"items"`
+	Customer *struct{ Email string } `json:"customer"`
+	Ignored  string                  `json:"-"`
+}
+
+func TestGoFieldPath(t *testing.T) {
+	for path, want := range map[string]string{
+		"$":                    "fieldPathOrder",
+		"$.items[2].id":        "fieldPathOrder.Items[2].ID",
+		"$.items[*].name":      "fieldPathOrder.Items[*].Name",
+		"$.customer.Email":     "fieldPathOrder.Customer.Email",
+		"$['items'][0]":        "fieldPathOrder.Items[0]",
+		"$.items.id":           "",
+		"$.unknown":            "",
+		"$.Ignored":            "",
+		"items[0]":             "",
+		"$.items[0].id.nested": "",
+	} {
+		field, ok := GoFieldPath(&fieldPathOrder{}, path)
+		if field != want || ok != (want != "") {
+			t.Errorf("%s: expected '%s' but got '%s' (%v)", path, want, field, ok)
+		}
+	}
+}
+
+func TestWithFieldPaths(t *testing.T) {
+	interactions := []*Interaction{
+		{Request: Request{}},
+		{Request: *(&Request{}).WithMatchedBody(fieldPathOrder{})},
+	}
+	cause := types.NewError(types.ErrMismatch, fmt.Errorf(`Expected 1 but got "a" at $.body.items[2].id, and $.body.other`))
+
+	err := withFieldPaths(cause, interactions)
+
+	want := `Expected 1 but got "a" at $.body.items[2].id (fieldPathOrder.Items[2].ID), and $.body.other`
+	if err.Error() != want {
+		t.Fatalf("expected '%s' but got '%s'", want, err)
+	}
+	if !errors.Is(err, types.ErrMismatch) {
+		t.Fatalf("expected the cause to be kept but got %v", err)
+	}
+
+	if err = withFieldPaths(cause, interactions[:1]); err != cause {
+		t.Fatalf("expected the error to be unchanged without body types but got '%v'", err)
+	}
+}
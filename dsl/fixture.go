@@ -0,0 +1,127 @@
+package dsl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// fixtureTemplateFuncs are available to templated fixture files.
+// "json" serialises a value, allowing matchers to be placed in a JSON fixture
+// e.g. { "id": {{ json .ID }} } where ID is dsl.Like(10)
+var fixtureTemplateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+// WithBodyFromFile sets the body of the request to the contents of the file at
+// path. The Content-Type header is inferred from the file extension if it has
+// not already been set, and JSON files are parsed so that they are matched
+// structurally.
+func (r *Request) WithBodyFromFile(path string) *Request {
+	r.Body, r.Headers, r.err = bodyFromFile(path, nil, false, r.Headers)
+	r.fixture = path
+
+	return r
+}
+
+// WithBodyFromTemplate is as per WithBodyFromFile, but the file is first
+// rendered as a text/template using the given data to substitute placeholders
+// e.g. {{ .Name }}.
+func (r *Request) WithBodyFromTemplate(path string, data interface{}) *Request {
+	r.Body, r.Headers, r.err = bodyFromFile(path, data, true, r.Headers)
+	r.fixture = path
+
+	return r
+}
+
+// WithBodyFromFile sets the body of the response to the contents of the file at
+// path. The Content-Type header is inferred from the file extension if it has
+// not already been set, and JSON files are parsed so that they are matched
+// structurally.
+func (r *Response) WithBodyFromFile(path string) *Response {
+	r.Body, r.Headers, r.err = bodyFromFile(path, nil, false, r.Headers)
+
+	return r
+}
+
+// WithBodyFromTemplate is as per WithBodyFromFile, but the file is first
+// rendered as a text/template using the given data to substitute placeholders
+// e.g. {{ .Name }}.
+func (r *Response) WithBodyFromTemplate(path string, data interface{}) *Response {
+	r.Body, r.Headers, r.err = bodyFromFile(path, data, true, r.Headers)
+
+	return r
+}
+
+// bodyFromFile loads (and optionally renders) a fixture, returning the body and
+// the headers with the inferred Content-Type applied
+func bodyFromFile(path string, data interface{}, templated bool, headers MapMatcher) (interface{}, MapMatcher, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, headers, fmt.Errorf("unable to read body fixture: %v", err)
+	}
+
+	if templated {
+		tmpl, err := template.New(filepath.Base(path)).Funcs(fixtureTemplateFuncs).Option("missingkey=error").Parse(string(content))
+		if err != nil {
+			return nil, headers, fmt.Errorf("unable to parse body fixture template '%s': %v", path, err)
+		}
+
+		var buf bytes.Buffer
+		if err = tmpl.Execute(&buf, data); err != nil {
+			return nil, headers, fmt.Errorf("unable to render body fixture template '%s': %v", path, err)
+		}
+		content = buf.Bytes()
+	}
+
+	contentType := headerValue(headers, "Content-Type")
+	if contentType == "" {
+		contentType = mime.TypeByExtension(filepath.Ext(path))
+		if contentType != "" {
+			if headers == nil {
+				headers = MapMatcher{}
+			}
+			headers["Content-Type"] = String(contentType)
+		}
+	}
+
+	if !isJSONContentType(contentType) {
+		return string(content), headers, nil
+	}
+
+	// Numbers keep their precision, e.g. of large integer IDs
+	var body interface{}
+	if err = decodeJSON(content, &body, true); err != nil {
+		return nil, headers, fmt.Errorf("body fixture '%s' is not valid JSON: %v", path, err)
+	}
+
+	return body, headers, nil
+}
+
+// headerValue finds a header by its case-insensitive name, returning the
+// example value if it is a matcher
+func headerValue(headers MapMatcher, name string) string {
+	for k, v := range headers {
+		if strings.EqualFold(k, name) {
+			return metadataValueString(v)
+		}
+	}
+
+	return ""
+}
+
+// isJSONContentType returns true for application/json and any +json types
+// e.g. application/hal+json
+func isJSONContentType(contentType string) bool {
+	mediaType := normaliseContentType(contentType)
+
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
@@ -0,0 +1,171 @@
+package dsl
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+func writeFixture(t *testing.T, name string, content string) string {
+	dir, err := ioutil.TempDir("", "pact-go-fixture")
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	path := filepath.Join(dir, name)
+	if err = ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	return path
+}
+
+func TestFixture_WithBodyFromFileJSON(t *testing.T) {
+	path := writeFixture(t, "user.json", `{"name": "billy", "roles": ["admin"]}`)
+	defer os.RemoveAll(filepath.Dir(path))
+
+	r := (&Request{}).WithBodyFromFile(path)
+
+	if r.err != nil {
+		t.Fatal("Error:", r.err)
+	}
+
+	expected := map[string]interface{}{
+		"name":  "billy",
+		"roles": []interface{}{"admin"},
+	}
+	if !reflect.DeepEqual(r.Body, expected) {
+		t.Fatalf("expected body '%v' but got '%v'", expected, r.Body)
+	}
+	if headerValue(r.Headers, "Content-Type") != "application/json" {
+		t.Fatalf("expected an application/json Content-Type but got '%v'", r.Headers)
+	}
+}
+
+func TestFixture_WithBodyFromFileText(t *testing.T) {
+	path := writeFixture(t, "users.csv", "billy,admin\n")
+	defer os.RemoveAll(filepath.Dir(path))
+
+	r := (&Response{
+		Headers: MapMatcher{"content-type": String("text/plain")},
+	}).WithBodyFromFile(path)
+
+	if r.err != nil {
+		t.Fatal("Error:", r.err)
+	}
+	if r.Body != "billy,admin\n" {
+		t.Fatalf("expected the raw file contents but got '%v'", r.Body)
+	}
+	if len(r.Headers) != 1 || headerValue(r.Headers, "Content-Type") != "text/plain" {
+		t.Fatalf("expected the existing Content-Type to be kept but got '%v'", r.Headers)
+	}
+}
+
+func TestFixture_WithBodyFromTemplate(t *testing.T) {
+	path := writeFixture(t, "user.json", `{"id": {{ json .ID }}, "name": "{{ .Name }}"}`)
+	defer os.RemoveAll(filepath.Dir(path))
+
+	r := (&Response{}).WithBodyFromTemplate(path, map[string]interface{}{
+		"ID":   Like(10),
+		"Name": "billy",
+	})
+
+	if r.err != nil {
+		t.Fatal("Error:", r.err)
+	}
+
+	expected := formatJSON(`{"id": {"contents": 10, "json_class": "Pact::SomethingLike"}, "name": "billy"}`)
+	if body := formatJSON(r.Body); body != expected {
+		t.Fatalf("expected body '%s' but got '%s'", expected, body)
+	}
+}
+
+func TestFixture_WithBodyFromFileFail(t *testing.T) {
+	missing := (&Request{}).WithBodyFromFile("does-not-exist.json")
+	if missing.err == nil {
+		t.Fatal("expected an error for a missing fixture")
+	}
+
+	path := writeFixture(t, "invalid.json", `{"name": `)
+	defer os.RemoveAll(filepath.Dir(path))
+
+	if r := (&Request{}).WithBodyFromFile(path); r.err == nil {
+		t.Fatal("expected an error for an invalid JSON fixture")
+	}
+
+	if r := (&Request{}).WithBodyFromTemplate(path, nil); r.err == nil {
+		t.Fatal("expected an error for an invalid JSON template")
+	}
+
+	path = writeFixture(t, "missing.txt", `{{ .Missing }}`)
+	defer os.RemoveAll(filepath.Dir(path))
+
+	if r := (&Request{}).WithBodyFromTemplate(path, map[string]string{}); r.err == nil {
+		t.Fatal("expected an error for a missing template key")
+	}
+}
+
+func TestFixture_VerifyInvalidFixture(t *testing.T) {
+	ms := setupMockServer(true, t)
+	defer ms.Close()
+
+	pact := &Pact{
+		Server: &types.MockServer{
+			Port: getPort(ms.URL),
+		},
+		Consumer: "My Consumer",
+		Provider: "My Provider",
+	}
+
+	pact.
+		AddInteraction().
+		UponReceiving("Some name for the test").
+		WithRequest(Request{}).
+		WillRespondWith(*(&Response{}).WithBodyFromFile("does-not-exist.json"))
+
+	testCalled := false
+	err := pact.Verify(func() error {
+		testCalled = true
+		return nil
+	})
+
+	if err == nil {
+		t.Fatal("expected an error for an invalid fixture")
+	}
+	if testCalled {
+		t.Fatal("expected the test function not to be called")
+	}
+}
+
+func TestFixture_WithBodyFromFileLargeNumbers(t *testing.T) {
+	path := writeFixture(t, "order.json", `{"id": 1234567890123456789, "total": 10.5}`)
+	defer os.RemoveAll(filepath.Dir(path))
+
+	r := (&Response{}).WithBodyFromFile(path)
+	if r.err != nil {
+		t.Fatal("Error:", r.err)
+	}
+
+	body, err := json.Marshal(Like(r.Body))
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if !strings.Contains(string(body), `{"id":1234567890123456789,"total":10.5}`) {
+		t.Fatalf("expected the numbers to keep their precision but got %s", body)
+	}
+	if encoded, _ := json.Marshal(exampleBody(r.Body)); string(encoded) != `{"id":1234567890123456789,"total":10.5}` {
+		t.Fatalf("expected the example to keep the precision of the numbers but got %s", encoded)
+	}
+
+	path = writeFixture(t, "order.json", `{"id": 1} {"id": 2}`)
+	defer os.RemoveAll(filepath.Dir(path))
+	if r = (&Response{}).WithBodyFromFile(path); r.err == nil {
+		t.Fatal("expected a fixture of more than one JSON value to be invalid")
+	}
+}
@@ -0,0 +1,260 @@
+package dsl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var unsafeFileNameChars = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+
+// updateFixtures returns true if suggested fixture updates should be written
+func (p *Pact) updateFixtures() bool {
+	return p.UpdateFixtures || os.Getenv("PACT_UPDATE_FIXTURES") != ""
+}
+
+// writeFixtureUpdates writes the body of each request the Mock Service could
+// not match as a suggested fixture update, along with a diff against the body
+// of the closest interaction (by method and path).
+//
+// Updates to bodies loaded with WithBodyFromFile are written next to the
+// fixture (e.g. user.json.actual and user.json.diff), all others are written
+// to the "fixture-updates" directory in the LogDir.
+func (p *Pact) writeFixtureUpdates() {
+	if p.proxy == nil {
+		log.Println("[WARN] unable to write fixture updates, requests to the mock server were not recorded")
+		return
+	}
+
+	for _, rec := range p.proxy.Requests() {
+		if !rec.Unmatched {
+			continue
+		}
+
+		interaction := findInteraction(p.snapshotInteractions(), rec)
+		updated, diff := p.fixtureUpdatePaths(interaction, rec)
+		if err := writeFixtureUpdate(interaction, rec, updated, diff); err != nil {
+			log.Println("[ERROR] unable to write fixture update:", err)
+			continue
+		}
+
+		log.Printf("[INFO] request '%s %s' was not matched, a suggested fixture update has been written to '%s' (diff: '%s')", rec.Method, rec.Path, updated, diff)
+	}
+}
+
+// fixtureUpdatePaths returns the location of the suggested fixture and diff
+func (p *Pact) fixtureUpdatePaths(interaction *Interaction, rec *recordedRequest) (string, string) {
+	if interaction != nil && interaction.Request.fixture != "" {
+		return interaction.Request.fixture + ".actual", interaction.Request.fixture + ".diff"
+	}
+
+	name := rec.Method + " " + rec.Path
+	if interaction != nil && interaction.Description != "" {
+		name = interaction.Description
+	}
+	name = strings.Trim(unsafeFileNameChars.ReplaceAllString(strings.ToLower(name), "_"), "_")
+
+	ext := ".txt"
+	if json.Valid(rec.Body) {
+		ext = ".json"
+	}
+
+	dir := filepath.Join(p.LogDir, "fixture-updates")
+
+	return filepath.Join(dir, name+ext), filepath.Join(dir, name+".diff")
+}
+
+func writeFixtureUpdate(interaction *Interaction, rec *recordedRequest, updated string, diff string) error {
+	expected := ""
+	if interaction != nil {
+		var err error
+		if expected, err = formatFixtureBody(exampleBody(interaction.Request.Body)); err != nil {
+			return err
+		}
+	}
+
+	actual := string(rec.Body)
+	var body bytes.Buffer
+	if json.Indent(&body, rec.Body, "", "  ") == nil {
+		actual = body.String() + "\n"
+	}
+
+	if err := os.MkdirAll(filepath.Dir(updated), os.ModePerm); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(updated, []byte(actual), 0644); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(diff, []byte(diffLines(expected, actual)), 0644)
+}
+
+// findInteraction returns the first interaction with the same method and
+// path as the request
+func findInteraction(interactions []*Interaction, rec *recordedRequest) *Interaction {
+	for _, i := range interactions {
+		if strings.EqualFold(i.Request.Method, rec.Method) && pathMatches(i.Request.Path, rec.Path) {
+			return i
+		}
+	}
+
+	return nil
+}
+
+func pathMatches(m Matcher, path string) bool {
+	if m == nil {
+		return false
+	}
+
+	if t, ok := m.(term); ok {
+		if regex, ok := t.Data.Matcher.Regex.(string); ok {
+			r, err := regexp.Compile(regex)
+			return err == nil && r.MatchString(path)
+		}
+	}
+
+	return metadataValueString(m) == path
+}
+
+// exampleBody replaces any matchers in the body with their example values,
+// as they would be generated by the Mock Service. Numbers are json.Number, so
+// that they keep their precision.
+func exampleBody(body interface{}) interface{} {
+	return exampleOf(body, true)
+}
+
+// exampleOf returns the example of the body as per exampleBody, with numbers
+// as json.Number if useNumber, or float64
+func exampleOf(body interface{}, useNumber bool) interface{} {
+	if s, ok := body.(string); ok {
+		return s
+	}
+
+	b, err := json.Marshal(body)
+	if err != nil {
+		return body
+	}
+
+	var v interface{}
+	if err = decodeJSON(b, &v, useNumber); err != nil {
+		return body
+	}
+
+	return exampleValue(v)
+}
+
+func exampleValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		switch value["json_class"] {
+		case "Pact::SomethingLike":
+			return exampleValue(value["contents"])
+		case "Pact::ArrayLike":
+			min, _ := strconv.Atoi(fmt.Sprint(value["min"]))
+			items := make([]interface{}, 0)
+			for i := 0; i < min; i++ {
+				items = append(items, exampleValue(value["contents"]))
+			}
+			return items
+		case "Pact::Term":
+			if data, ok := value["data"].(map[string]interface{}); ok {
+				return data["generate"]
+			}
+		case optionalClass:
+			return exampleValue(value["contents"])
+		case jsonStringClass:
+			return jsonStringExample(value["contents"])
+		}
+
+		obj := make(map[string]interface{}, len(value))
+		for k, item := range value {
+			obj[k] = exampleValue(item)
+		}
+		return obj
+	case []interface{}:
+		items := make([]interface{}, len(value))
+		for i, item := range value {
+			items[i] = exampleValue(item)
+		}
+		return items
+	}
+
+	return v
+}
+
+func formatFixtureBody(body interface{}) (string, error) {
+	switch b := body.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return b, nil
+	}
+
+	formatted, err := json.MarshalIndent(body, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("unable to format expected body: %v", err)
+	}
+
+	return string(formatted) + "\n", nil
+}
+
+// diffLines produces a line based diff of two strings, where removed lines
+// are prefixed with "-" and added lines with "+"
+func diffLines(expected string, actual string) string {
+	a := splitLines(expected)
+	b := splitLines(actual)
+
+	// Longest common subsequence of lines
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var diff strings.Builder
+	diff.WriteString("--- expected\n+++ actual\n")
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			diff.WriteString(" " + a[i] + "\n")
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			diff.WriteString("-" + a[i] + "\n")
+			i++
+		default:
+			diff.WriteString("+" + b[j] + "\n")
+			j++
+		}
+	}
+
+	return diff.String()
+}
+
+func splitLines(s string) []string {
+	s = strings.TrimSuffix(s, "\n")
+	if s == "" {
+		return nil
+	}
+
+	return strings.Split(s, "\n")
+}
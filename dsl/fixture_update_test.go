@@ -0,0 +1,145 @@
+package dsl
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+func TestFixtureUpdate_Verify(t *testing.T) {
+	ms := setupMismatchMockServer()
+	defer ms.Close()
+
+	fixture := writeFixture(t, "user.json", `{"name": "billy"}`)
+	dir := filepath.Dir(fixture)
+	defer os.RemoveAll(dir)
+
+	pact := &Pact{
+		Server: &types.MockServer{
+			Port: getPort(ms.URL),
+		},
+		Consumer:       "My Consumer",
+		Provider:       "My Provider",
+		LogDir:         dir,
+		UpdateFixtures: true,
+		pactClient:     newMockClient(),
+	}
+	pact.Setup(false)
+	pact.startProxy()
+	defer pact.Teardown()
+
+	pact.
+		AddInteraction().
+		UponReceiving("A request to create a user").
+		WithRequest(*(&Request{Method: "POST", Path: String("/users")}).WithBodyFromFile(fixture)).
+		WillRespondWith(Response{Status: 201})
+
+	pact.
+		AddInteraction().
+		UponReceiving("A request to delete a user").
+		WithRequest(Request{Method: "DELETE", Path: Term("/users/1", `^/users/\d+$`)}).
+		WillRespondWith(Response{Status: 204})
+
+	err := pact.Verify(func() error {
+		url := fmt.Sprintf("http://%s:%d", pact.Host, pact.Server.Port)
+
+		if _, err := http.Post(url+"/users", "application/json", strings.NewReader(`{"name":"sampson"}`)); err != nil {
+			return err
+		}
+
+		req, _ := http.NewRequest("DELETE", url+"/users/2", strings.NewReader("force"))
+		_, err := http.DefaultClient.Do(req)
+
+		return err
+	})
+
+	if err == nil {
+		t.Fatal("expected verification to fail")
+	}
+
+	actual, err := ioutil.ReadFile(fixture + ".actual")
+	if err != nil {
+		t.Fatal("expected a suggested fixture update to be written:", err)
+	}
+	if string(actual) != "{\n  \"name\": \"sampson\"\n}\n" {
+		t.Fatalf("unexpected fixture update: '%s'", actual)
+	}
+
+	diff, err := ioutil.ReadFile(fixture + ".diff")
+	if err != nil {
+		t.Fatal("expected a diff to be written:", err)
+	}
+	if !strings.Contains(string(diff), `-  "name": "billy"`) || !strings.Contains(string(diff), `+  "name": "sampson"`) {
+		t.Fatalf("unexpected diff: '%s'", diff)
+	}
+
+	actual, err = ioutil.ReadFile(filepath.Join(dir, "fixture-updates", "a_request_to_delete_a_user.txt"))
+	if err != nil {
+		t.Fatal("expected a fixture update to be written to the log dir:", err)
+	}
+	if string(actual) != "force" {
+		t.Fatalf("unexpected fixture update: '%s'", actual)
+	}
+}
+
+func TestFixtureUpdate_exampleBody(t *testing.T) {
+	body := map[string]interface{}{
+		"id":    Like(10),
+		"date":  Term("2000-01-01", `\d{4}-\d{2}-\d{2}`),
+		"items": EachLike(map[string]interface{}{"name": Like("foo")}, 2),
+		"tags":  []interface{}{"a", Like("b")},
+	}
+
+	expected := map[string]interface{}{
+		"id":    json.Number("10"),
+		"date":  "2000-01-01",
+		"items": []interface{}{map[string]interface{}{"name": "foo"}, map[string]interface{}{"name": "foo"}},
+		"tags":  []interface{}{"a", "b"},
+	}
+
+	if got := exampleBody(body); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected '%v' but got '%v'", expected, got)
+	}
+}
+
+func TestFixtureUpdate_diffLines(t *testing.T) {
+	expected := "{\n  \"a\": 1,\n  \"b\": 2\n}\n"
+	actual := "{\n  \"a\": 1,\n  \"b\": 3,\n  \"c\": 4\n}\n"
+
+	want := `--- expected
++++ actual
+ {
+   "a": 1,
+-  "b": 2
++  "b": 3,
++  "c": 4
+ }
+`
+
+	if got := diffLines(expected, actual); got != want {
+		t.Fatalf("expected diff '%s' but got '%s'", want, got)
+	}
+}
+
+func TestFixtureUpdate_Disabled(t *testing.T) {
+	pact := &Pact{}
+
+	os.Setenv("PACT_UPDATE_FIXTURES", "")
+	if pact.updateFixtures() {
+		t.Fatal("expected fixture updates to be disabled by default")
+	}
+
+	os.Setenv("PACT_UPDATE_FIXTURES", "true")
+	defer os.Unsetenv("PACT_UPDATE_FIXTURES")
+	if !pact.updateFixtures() {
+		t.Fatal("expected fixture updates to be enabled by the environment")
+	}
+}
@@ -0,0 +1,136 @@
+package dsl
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"reflect"
+	"regexp"
+)
+
+// mockServerURL is a Term whose example is replaced, in responses sent to the
+// consumer, by a URL on the running Mock Server
+type mockServerURL struct {
+	term
+
+	// path is the part of the example kept when it is generated, e.g. "/orders/1"
+	path string
+}
+
+// MockServerURL matches a URL, e.g. in a Location header or a link in the body,
+// by the given regex. When the consumer test is run, the example is replaced by
+// a URL on the running Mock Server, so that clients following the URL hit the
+// Mock Server rather than the host in the example. The part of the example
+// matched by the first group in the regex is kept, or its path and query if
+// there is no group:
+//
+//	MockServerURL("http://localhost:8080/orders/1", `.*(/orders/\d+)$`)
+//
+// The example is written to the pact, and it is verified against the provider
+// as per Term.
+func MockServerURL(example string, regex string) Matcher {
+	r, err := regexp.Compile(regex)
+	if err != nil {
+		panic(fmt.Sprintf("MockServerURL: invalid regex '%s': %v", regex, err))
+	}
+
+	match := r.FindStringSubmatch(example)
+	if match == nil {
+		panic(fmt.Sprintf("MockServerURL: example '%s' does not match regex '%s'", example, regex))
+	}
+
+	var path string
+	if len(match) > 1 {
+		path = match[1]
+	} else {
+		u, err := url.Parse(example)
+		if err != nil {
+			panic(fmt.Sprintf("MockServerURL: example '%s' is not a valid URL: %v", example, err))
+		}
+		path = u.RequestURI()
+	}
+
+	return mockServerURL{
+		term: Term(example, regex).(term),
+		path: path,
+	}
+}
+
+// urlGenerator replaces an example URL with one on the running Mock Server
+type urlGenerator struct {
+	example string
+	path    string
+}
+
+// generate returns the URL on the Mock Server at baseURL
+func (g urlGenerator) generate(baseURL string) string {
+	return baseURL + g.path
+}
+
+// urlGenerators returns the generators in the response headers and bodies of
+// the interactions
+func urlGenerators(interactions []*Interaction) []urlGenerator {
+	var generators []urlGenerator
+
+	for _, i := range interactions {
+		collectURLGenerators(reflect.ValueOf(i.Response.Headers), &generators)
+		collectURLGenerators(reflect.ValueOf(i.Response.Body), &generators)
+	}
+
+	return generators
+}
+
+// collectURLGenerators finds any MockServerURL matchers in v, which may be a
+// matcher, map, slice or struct
+func collectURLGenerators(v reflect.Value, generators *[]urlGenerator) {
+	if !v.IsValid() {
+		return
+	}
+
+	if v.CanInterface() {
+		if g, ok := v.Interface().(mockServerURL); ok {
+			*generators = append(*generators, urlGenerator{
+				example: g.Data.Generate.(string),
+				path:    g.path,
+			})
+			return
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Interface, reflect.Ptr:
+		collectURLGenerators(v.Elem(), generators)
+	case reflect.Map:
+		for _, key := range v.MapKeys() {
+			collectURLGenerators(v.MapIndex(key), generators)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			collectURLGenerators(v.Index(i), generators)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Field(i).CanInterface() {
+				collectURLGenerators(v.Field(i), generators)
+			}
+		}
+	}
+}
+
+// setupGenerators ensures that the URLs generated by the HTTP interactions
+// are rewritten to the Mock Server. This requires the recording proxy, which
+// is started on demand.
+func (p *Pact) setupGenerators(interactions map[string][]*Interaction) {
+	if len(urlGenerators(interactions[TransportHTTPS])) > 0 {
+		log.Println("[WARN] MockServerURL is not supported for interactions over TLS, the example will be returned")
+	}
+
+	generators := urlGenerators(interactions[TransportHTTP])
+	if len(generators) > 0 {
+		p.startProxy()
+	}
+
+	if p.proxy != nil {
+		p.proxy.SetGenerators(generators)
+	}
+}
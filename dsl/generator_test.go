@@ -0,0 +1,159 @@
+package dsl
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+func TestGenerator_MockServerURL(t *testing.T) {
+	m := MockServerURL("http://localhost:8080/orders/1?expand=items", `.*(/orders/\d+)`).(mockServerURL)
+	if m.path != "/orders/1" {
+		t.Fatalf("expected the first group to be kept but got '%s'", m.path)
+	}
+
+	m = MockServerURL("http://localhost:8080/orders/1?expand=items", `^http://.*/orders/\d+`).(mockServerURL)
+	if m.path != "/orders/1?expand=items" {
+		t.Fatalf("expected the path and query to be kept but got '%s'", m.path)
+	}
+
+	got, _ := json.Marshal(m)
+	want, _ := json.Marshal(Term("http://localhost:8080/orders/1?expand=items", `^http://.*/orders/\d+`))
+	if string(got) != string(want) {
+		t.Fatalf("expected MockServerURL to be written as a Term, got %s", got)
+	}
+}
+
+func TestGenerator_MockServerURLInvalid(t *testing.T) {
+	tests := map[string][2]string{
+		"invalid regex": {"http://localhost/orders/1", `(`},
+		"no match":      {"http://localhost/orders/1", `/users/\d+`},
+	}
+
+	for name, args := range tests {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("%s: expected MockServerURL to panic", name)
+				}
+			}()
+			MockServerURL(args[0], args[1])
+		}()
+	}
+}
+
+func TestGenerator_urlGenerators(t *testing.T) {
+	interactions := []*Interaction{
+		{Response: Response{Headers: MapMatcher{
+			"Location":     MockServerURL("http://localhost/orders/1", `.*(/orders/\d+)`),
+			"Content-Type": String("application/json"),
+		}}},
+		{Response: Response{}},
+		{Response: Response{Body: map[string]interface{}{
+			"_links": StructMatcher{
+				"self": Like(map[string]interface{}{
+					"href": MockServerURL("http://localhost/orders/2", `.*(/orders/\d+)`),
+				}),
+			},
+			"items": EachLike(struct {
+				Href Matcher `json:"href"`
+			}{MockServerURL("http://localhost/items/3", `.*(/items/\d+)`)}, 1),
+		}}},
+	}
+
+	generators := urlGenerators(interactions)
+	if len(generators) != 3 {
+		t.Fatalf("expected 3 generators but got %+v", generators)
+	}
+
+	paths := map[string]string{}
+	for _, g := range generators {
+		paths[g.example] = g.path
+	}
+	want := map[string]string{
+		"http://localhost/orders/1": "/orders/1",
+		"http://localhost/orders/2": "/orders/2",
+		"http://localhost/items/3":  "/items/3",
+	}
+	for example, path := range want {
+		if paths[example] != path {
+			t.Fatalf("expected a generator for '%s' keeping '%s', got %+v", example, path, generators)
+		}
+	}
+}
+
+func TestGenerator_VerifyMockServerURLs(t *testing.T) {
+	ms := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Pact-Mock-Service") != "" {
+			fmt.Fprintln(w, "OK")
+			return
+		}
+
+		w.Header().Set("Location", "http://localhost:8080/orders/1")
+		w.Header().Set("X-Other", "http://localhost:8080/other")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"_links":{"self":{"href":"http://localhost:8080/orders/1"},"other":{"href":"http://localhost:8080/orders/1/other"}}}`)
+	}))
+	defer ms.Close()
+
+	pact := &Pact{
+		Server: &types.MockServer{
+			Port: getPort(ms.URL),
+		},
+		pactClient: newMockClient(),
+	}
+	defer pact.Teardown()
+
+	pact.
+		AddInteraction().
+		UponReceiving("A request to create an order").
+		WithRequest(Request{Method: "POST", Path: String("/orders")}).
+		WillRespondWith(Response{
+			Status: 201,
+			Headers: MapMatcher{
+				"Location": MockServerURL("http://localhost:8080/orders/1", `.*(/orders/\d+)$`),
+			},
+			Body: map[string]interface{}{
+				"_links": map[string]interface{}{
+					"self": map[string]interface{}{
+						"href": MockServerURL("http://localhost:8080/orders/1", `.*(/orders/\d+)$`),
+					},
+				},
+			},
+		})
+
+	var location, other string
+	var body []byte
+	err := pact.Verify(func() error {
+		res, err := http.Post(fmt.Sprintf("http://localhost:%d/orders", pact.Server.Port), "application/json", nil)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+		location = res.Header.Get("Location")
+		other = res.Header.Get("X-Other")
+		body, err = ioutil.ReadAll(res.Body)
+
+		return err
+	})
+
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if want := fmt.Sprintf("http://localhost:%d/orders/1", pact.Server.Port); location != want {
+		t.Fatalf("expected Location '%s' but got '%s'", want, location)
+	}
+	if other != "http://localhost:8080/other" {
+		t.Fatalf("expected other headers to be unchanged but got '%s'", other)
+	}
+
+	want := fmt.Sprintf(`{"_links":{"self":{"href":"http://localhost:%d/orders/1"},"other":{"href":"http://localhost:8080/orders/1/other"}}}`, pact.Server.Port)
+	if string(body) != want {
+		t.Fatalf("expected body '%s' but got '%s'", want, body)
+	}
+}
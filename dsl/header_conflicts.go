@@ -0,0 +1,77 @@
+package dsl
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// setHeader returns the headers with the header set to value, or an error if
+// the header has already been declared (in any case) with another value, so
+// that builders such as WithBasicAuth don't silently replace headers given to
+// WithRequest
+func setHeader(headers MapMatcher, name string, value Matcher) (MapMatcher, error) {
+	if headers == nil {
+		headers = MapMatcher{}
+	}
+
+	for k, v := range headers {
+		if strings.EqualFold(k, name) && (k != name || !reflect.DeepEqual(v, value)) {
+			return headers, fmt.Errorf("header '%s' is already declared as '%s', remove one of the definitions", name, metadataValueString(v))
+		}
+	}
+	headers[name] = value
+
+	return headers, nil
+}
+
+// validateHeadersAndQuery checks that no header is declared more than once in
+// different cases, and no query parameter is declared in both the path and the
+// query, which would produce ambiguous matching rules
+func (i *Interaction) validateHeadersAndQuery() error {
+	if err := duplicateHeaders("request", i.Request.Headers); err != nil {
+		return err
+	}
+	if err := duplicateHeaders("response", i.Response.Headers); err != nil {
+		return err
+	}
+
+	path := metadataValueString(i.Request.Path)
+	if !strings.Contains(path, "?") || len(i.Request.Query) == 0 {
+		return nil
+	}
+
+	query, err := url.ParseQuery(path[strings.Index(path, "?")+1:])
+	if err != nil {
+		return fmt.Errorf("invalid query string in request path '%s': %v", path, err)
+	}
+	for name := range query {
+		if _, ok := i.Request.Query[name]; ok {
+			return fmt.Errorf("query parameter '%s' is declared in both the request path and Query", name)
+		}
+	}
+
+	return nil
+}
+
+// duplicateHeaders returns an error if a header is declared more than once,
+// in different cases
+func duplicateHeaders(kind string, headers MapMatcher) error {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	seen := make(map[string]string, len(names))
+	for _, name := range names {
+		if other, ok := seen[strings.ToLower(name)]; ok {
+			return fmt.Errorf("%s header '%s' is declared more than once, as '%s' and '%s'", kind, name, other, name)
+		}
+		seen[strings.ToLower(name)] = name
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package dsl
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInteraction_ConflictingHeaders(t *testing.T) {
+	for name, tc := range map[string]struct {
+		interaction *Interaction
+		want        string
+	}{
+		"builder replacing a header": {
+			(&Interaction{}).
+				WithRequest(Request{Method: "GET", Path: String("/"), Headers: MapMatcher{"authorization": String("Bearer abc")}}).
+				WithBearerToken(String("xyz")),
+			"header 'Authorization' is already declared as 'Bearer abc'",
+		},
+		"builders conflicting": {
+			(&Interaction{}).
+				WithRequest(Request{Method: "GET", Path: String("/")}).
+				WithHost(String("a.example.com")).
+				WithHost(String("b.example.com")),
+			"header 'Host' is already declared as 'a.example.com'",
+		},
+		"headers in different cases": {
+			(&Interaction{}).
+				WithRequest(Request{Method: "GET", Path: String("/")}).
+				WillRespondWith(Response{Status: 200, Headers: MapMatcher{"Content-Type": String("text/plain"), "content-type": Like("application/json")}}),
+			"response header 'content-type' is declared more than once, as 'Content-Type' and 'content-type'",
+		},
+		"query in path and Query": {
+			(&Interaction{}).
+				WithRequest(Request{Method: "GET", Path: String("/orders?page=1"), Query: MapMatcher{"page": Term("2", `\d+`)}}),
+			"query parameter 'page' is declared in both the request path and Query",
+		},
+	} {
+		err := tc.interaction.validate()
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: expected '%s' but got '%v'", name, tc.want, err)
+		}
+	}
+}
+
+func TestInteraction_RepeatedHeaders(t *testing.T) {
+	i := (&Interaction{}).
+		WithRequest(Request{Method: "GET", Path: String("/orders?sort=date"), Query: MapMatcher{"page": String("1")}}).
+		WithHost(String("api.example.com")).
+		WithHost(String("api.example.com"))
+
+	if err := i.validate(); err != nil {
+		t.Fatal("Error:", err)
+	}
+}
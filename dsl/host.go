@@ -0,0 +1,76 @@
+package dsl
+
+import (
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+// WithHost expects the request to be sent with the given Host header, e.g. for
+// consumers of a gateway that routes requests by host. During verification the
+// example host is sent to the provider. Must be called after WithRequest.
+//
+// Note that Go HTTP clients send req.Host, rather than any "Host" header set.
+func (i *Interaction) WithHost(host Matcher) *Interaction {
+	if host == nil {
+		i.Request.err = fmt.Errorf("a host is required")
+		return i
+	}
+
+	example := fmt.Sprintf("%v", host.GetValue())
+	if u, err := url.Parse("//" + example); err != nil || example == "" || u.Host != example {
+		i.Request.err = fmt.Errorf("invalid host '%s', expected a host and optional port e.g. 'api.example.com:8080'", example)
+		return i
+	}
+
+	headers, err := setHeader(i.Request.Headers, "Host", host)
+	i.Request.Headers = headers
+	if err != nil {
+		i.Request.err = err
+	}
+
+	return i
+}
+
+// setupTLSServerName issues a certificate for the TLSServerName, written to a
+// temporary directory for the TLS Mock Service, returning its arguments. The
+// Mock Service loads the key with OpenSSL::PKey::RSA, so it must be RSA
+func (p *Pact) setupTLSServerName() ([]string, error) {
+	ca, err := p.testCA()
+	if err != nil {
+		return nil, err
+	}
+
+	cert, key, err := ca.issueRSA(p.TLSServerName, x509.ExtKeyUsageServerAuth, p.TLSServerName, p.Host, "localhost", "127.0.0.1", "::1")
+	if err != nil {
+		return nil, fmt.Errorf("unable to issue a certificate for '%s': %v", p.TLSServerName, err)
+	}
+
+	dir, err := ioutil.TempDir("", "pact-go-tls")
+	if err != nil {
+		return nil, err
+	}
+	p.tlsCertDir = dir
+
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	if err = ioutil.WriteFile(certFile, cert, 0600); err != nil {
+		return nil, err
+	}
+	if err = ioutil.WriteFile(keyFile, key, 0600); err != nil {
+		return nil, err
+	}
+
+	return []string{"--sslcert", certFile, "--sslkey", keyFile}, nil
+}
+
+// removeTLSServerName removes the certificate issued for the TLSServerName
+func (p *Pact) removeTLSServerName() {
+	if p.tlsCertDir != "" {
+		os.RemoveAll(p.tlsCertDir)
+		p.tlsCertDir = ""
+	}
+}
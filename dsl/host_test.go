@@ -0,0 +1,81 @@
+package dsl
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestHost_WithHost(t *testing.T) {
+	i := (&Interaction{}).WithRequest(Request{Method: "GET", Path: String("/")}).WithHost(String("api.example.com:8443"))
+	if i.Request.err != nil || i.Request.Headers["Host"] != String("api.example.com:8443") {
+		t.Fatalf("expected the Host header to be set, got %v (%v)", i.Request.Headers, i.Request.err)
+	}
+
+	i = (&Interaction{}).WithRequest(Request{Method: "GET"}).WithHost(Term("eu.api.example.com", `^\w+\.api\.example\.com$`))
+	if i.Request.err != nil {
+		t.Fatal("Error:", i.Request.err)
+	}
+
+	for _, host := range []Matcher{String(""), String("http://api.example.com"), String("api.example.com/users"), nil} {
+		i = (&Interaction{}).WithRequest(Request{Method: "GET"}).WithHost(host)
+		if i.validate() == nil {
+			t.Fatalf("expected host '%v' to be invalid", host)
+		}
+	}
+}
+
+func TestHost_TLSServerName(t *testing.T) {
+	client := newMockClient()
+	pact := &Pact{
+		Host:          "localhost",
+		TLSServerName: "api.example.com",
+		pactClient:    client,
+	}
+
+	if err := pact.setupTLSServer(); err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	args := strings.Join(client.StartServerArgs[0], " ")
+	if !strings.Contains(args, "--ssl --sslcert "+pact.tlsCertDir) {
+		t.Fatalf("expected the TLS mock service to use the issued certificate, got '%s'", args)
+	}
+
+	data, err := ioutil.ReadFile(pact.tlsCertDir + "/cert.pem")
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	block, _ := pem.Decode(data)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	// The Mock Service only loads RSA keys
+	data, err = ioutil.ReadFile(pact.tlsCertDir + "/key.pem")
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	block, _ = pem.Decode(data)
+	if _, err = x509.ParsePKCS1PrivateKey(block.Bytes); err != nil || block.Type != "RSA PRIVATE KEY" {
+		t.Fatalf("expected an RSA key for the mock service but got '%s': %v", block.Type, err)
+	}
+
+	config, err := pact.ClientTLSConfig()
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err = cert.Verify(x509.VerifyOptions{DNSName: config.ServerName, Roots: config.RootCAs}); err != nil {
+		t.Fatalf("expected the certificate to be valid for '%s': %v", config.ServerName, err)
+	}
+
+	dir := pact.tlsCertDir
+	pact.Teardown()
+	if _, err = os.Stat(dir); !os.IsNotExist(err) {
+		t.Fatal("expected the certificate to be removed on teardown")
+	}
+}
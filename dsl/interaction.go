@@ -2,6 +2,7 @@ package dsl
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
 )
 
@@ -18,6 +19,37 @@ type Interaction struct {
 
 	// Provider state to be written into the Pact file
 	State string `json:"providerState,omitempty"`
+
+	// Transport the interaction is expected over e.g. TransportHTTPS
+	transport string
+
+	// Name of the Go test that defined the interaction
+	testName string
+
+	// Tags used to select the interaction, see WithTags
+	tags []string
+
+	// Scenario whose pact file the interaction is written to, see InScenario
+	scenario string
+
+	// Request headers holding secrets e.g. set by WithBasicAuth
+	secretHeaders []string
+
+	// How plain values in the request and response are matched
+	requestMatching  MatchingMode
+	responseMatching MatchingMode
+
+	// Responses to calls after the first, see ThenRespondWith
+	responses []sequenceResponse
+
+	// Number of the call in a response sequence this interaction is for
+	call int
+
+	// Interaction the response redirects to, see WillRedirectTo
+	redirectTo *Interaction
+
+	// InteractionSet the interaction was published in, if any
+	set *InteractionSet
 }
 
 // Given specifies a provider state. Optional.
@@ -61,6 +93,42 @@ func (i *Interaction) WillRespondWith(response Response) *Interaction {
 	return i
 }
 
+// validate returns any error that occurred while building the interaction
+func (i *Interaction) validate() error {
+	if !isKnownTransport(i.Transport()) {
+		return fmt.Errorf("unknown transport '%s' for interaction '%s', expected one of %v", i.transport, i.Description, transports)
+	}
+	if i.Request.err != nil {
+		return fmt.Errorf("invalid request for interaction '%s': %v", i.Description, i.Request.err)
+	}
+	if i.Response.err != nil {
+		return fmt.Errorf("invalid response for interaction '%s': %v", i.Description, i.Response.err)
+	}
+	if err := i.validateMethod(); err != nil {
+		return fmt.Errorf("invalid interaction '%s': %v", i.Description, err)
+	}
+	if err := i.validateHeadersAndQuery(); err != nil {
+		return fmt.Errorf("invalid interaction '%s': %v", i.Description, err)
+	}
+	if err := i.validateEncoding(); err != nil {
+		return fmt.Errorf("invalid interaction '%s': %v", i.Description, err)
+	}
+	if err := i.validateMatchers(); err != nil {
+		return fmt.Errorf("invalid matcher in interaction '%s': %v", i.Description, err)
+	}
+	if err := i.validateOptionalFields(); err != nil {
+		return fmt.Errorf("invalid optional field in interaction '%s': %v", i.Description, err)
+	}
+	if err := i.validateJSONStrings(); err != nil {
+		return fmt.Errorf("invalid JSON string in interaction '%s': %v", i.Description, err)
+	}
+	if err := i.validateSequence(); err != nil {
+		return fmt.Errorf("invalid interaction '%s': %v", i.Description, err)
+	}
+
+	return nil
+}
+
 // Checks to see if someone has tried to submit a JSON string
 // for an object, which is no longer supported
 func isJSONFormattedObject(stringOrObject interface{}) bool {
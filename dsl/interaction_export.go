@@ -0,0 +1,178 @@
+package dsl
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/ray-xu-deltatre/pact-go/gen"
+)
+
+// ExportInteractions writes Go source to path with a function that adds the
+// interactions registered so far, i.e. not yet verified, to a Pact. Consumer
+// tests in other packages that call the same provider endpoints can then share
+// the interactions by calling the function, e.g.
+//
+//	orders.AddInteractions(pact)
+//
+// The package and name of the function are given by options, as per
+// gen.Interactions.
+func (p *Pact) ExportInteractions(path string, options gen.Options) error {
+	pact := map[string]interface{}{
+		"consumer": map[string]string{"name": p.Consumer},
+		"provider": map[string]string{"name": p.Provider},
+	}
+
+	var interactions []interface{}
+	for _, i := range p.snapshotInteractions() {
+		if err := checkExportable(i); err != nil {
+			return fmt.Errorf("unable to export interaction '%s': %v", i.Description, err)
+		}
+		exported, err := exportInteraction(i)
+		if err != nil {
+			return fmt.Errorf("unable to export interaction '%s': %v", i.Description, err)
+		}
+		interactions = append(interactions, exported)
+	}
+	pact["interactions"] = interactions
+
+	doc, err := json.Marshal(pact)
+	if err != nil {
+		return fmt.Errorf("unable to export interactions: %v", err)
+	}
+
+	src, err := gen.Interactions(doc, options)
+	if err != nil {
+		return fmt.Errorf("unable to export interactions: %v", err)
+	}
+
+	return ioutil.WriteFile(path, src, 0644)
+}
+
+// exportInteraction returns the interaction as it would be written to a v2
+// pact file, with the examples of its matchers and their matching rules
+func exportInteraction(i *Interaction) (map[string]interface{}, error) {
+	expected, err := i.withMatchingModes()
+	if err != nil {
+		return nil, err
+	}
+	requestRules, err := i.RequestMatchingRules()
+	if err != nil {
+		return nil, err
+	}
+	responseRules, err := i.ResponseMatchingRules()
+	if err != nil {
+		return nil, err
+	}
+
+	request := map[string]interface{}{
+		"method":        expected.Request.Method,
+		"path":          exampleBody(expected.Request.Path),
+		"matchingRules": exportedRules(requestRules),
+	}
+	if query := exportedQuery(expected.Request.Query); len(query) > 0 {
+		request["query"] = query
+	}
+	if len(expected.Request.Headers) > 0 {
+		request["headers"] = exampleBody(expected.Request.Headers)
+	}
+	if expected.Request.Body != nil {
+		request["body"] = exampleBody(expected.Request.Body)
+	}
+
+	response := map[string]interface{}{
+		"status":        expected.Response.Status,
+		"matchingRules": exportedRules(responseRules),
+	}
+	if len(expected.Response.Headers) > 0 {
+		response["headers"] = exampleBody(expected.Response.Headers)
+	}
+	if expected.Response.Body != nil {
+		response["body"] = exampleBody(expected.Response.Body)
+	}
+
+	return map[string]interface{}{
+		"description":   i.Description,
+		"providerState": i.State,
+		"request":       request,
+		"response":      response,
+	}, nil
+}
+
+// exportedQuery returns the examples of the query values, by name
+func exportedQuery(query MapMatcher) map[string][]string {
+	values := make(map[string][]string)
+	for k, v := range query {
+		switch example := exampleBody(v).(type) {
+		case []interface{}:
+			for _, item := range example {
+				values[k] = append(values[k], fmt.Sprint(item))
+			}
+		default:
+			values[k] = []string{fmt.Sprint(example)}
+		}
+	}
+
+	return values
+}
+
+// exportedRules returns the matching rules by path
+func exportedRules(rules []MatchingRule) map[string]PactDocumentRule {
+	exported := make(map[string]PactDocumentRule, len(rules))
+	for _, r := range rules {
+		exported[r.Path] = PactDocumentRule{Match: r.Match, Regex: r.Regex, Min: r.Min, Max: r.Max}
+	}
+
+	return exported
+}
+
+// checkExportable returns an error if the interaction has matchers that the
+// exported interactions can't represent, which would leave the shared contract
+// looser than the one verified
+func checkExportable(i *Interaction) error {
+	_, optionalFields, err := i.withOptionalFields()
+	if err != nil {
+		return err
+	}
+	if len(optionalFields) > 0 {
+		return fmt.Errorf("Optional fields (%s) can't be exported", pathsOf(optionalFields))
+	}
+
+	_, jsonStrings, err := i.withJSONStrings()
+	if err != nil {
+		return err
+	}
+	for _, part := range []string{"request", "response"} {
+		if len(jsonStrings[part]) > 0 {
+			return fmt.Errorf("JSONString fields in the %s (%s) can't be exported", part, pathsOf(jsonStrings[part]))
+		}
+	}
+
+	if len(requestGenerators(i.Request.Body)) > 0 {
+		return fmt.Errorf("GeneratedUUID and GeneratedDateTime values can't be exported")
+	}
+	if len(urlGenerators([]*Interaction{i})) > 0 {
+		return fmt.Errorf("MockServerURL values can't be exported")
+	}
+
+	// The calls of a sequence are told apart by the recording proxy, so they
+	// can't be exported as interactions of their own either
+	if len(i.responses) > 0 {
+		return fmt.Errorf("response sequences (ThenRespondWith) can't be exported")
+	}
+
+	return nil
+}
+
+// pathsOf returns the sorted paths of fields, e.g. "$.body.nickname"
+func pathsOf(fields map[string]interface{}) string {
+	paths := make([]string, 0, len(fields))
+	for path := range fields {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	return strings.Join(paths, ", ")
+}
@@ -0,0 +1,116 @@
+package dsl
+
+import (
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ray-xu-deltatre/pact-go/gen"
+)
+
+func TestInteractionExport_ExportInteractions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pact-go-export")
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	defer os.RemoveAll(dir)
+
+	i := (&Interaction{}).
+		Given("invoices exist").
+		UponReceiving("a request for invoices").
+		WithRequest(Request{
+			Method:  "GET",
+			Path:    Term("/invoices/1", `^/invoices/[0-9]+$`),
+			Query:   MapMatcher{"status": String("paid")},
+			Headers: MapMatcher{"Accept": String("application/json")},
+		}).
+		WillRespondWith(Response{
+			Status: 200,
+			Body: map[string]interface{}{
+				"invoices": EachLike(map[string]interface{}{"id": Like(1)}, 1),
+				"lines":    EachLikeBetween("line", 1, 3),
+				"paid":     true,
+			},
+		})
+	pact := &Pact{Consumer: "billing-ui", Provider: "billing", Interactions: []*Interaction{i}}
+
+	path := filepath.Join(dir, "invoices.go")
+	if err = pact.ExportInteractions(path, gen.Options{Package: "invoices", Function: "AddInvoiceInteractions"}); err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err = parser.ParseFile(token.NewFileSet(), path, src, 0); err != nil {
+		t.Fatalf("expected valid Go but got %v:\n%s", err, src)
+	}
+
+	for _, expected := range []string{
+		"package invoices",
+		"func AddInvoiceInteractions(pact *dsl.Pact)",
+		`Given("invoices exist")`,
+		`UponReceiving("a request for invoices")`,
+		"dsl.Term(\"/invoices/1\", `^/invoices/[0-9]+$`)",
+		`"status": dsl.String("paid")`,
+		`"invoices": dsl.EachLike(map[string]interface{}{`,
+		`"id": 1`,
+		`"lines": dsl.EachLikeBetween("line", 1, 3)`,
+		`"paid":  true`,
+	} {
+		if !strings.Contains(string(src), expected) {
+			t.Fatalf("expected the exported interactions to contain '%s':\n%s", expected, src)
+		}
+	}
+}
+
+func TestInteractionExport_ExportInteractionsNone(t *testing.T) {
+	pact := &Pact{Consumer: "billing-ui", Provider: "billing"}
+
+	if err := pact.ExportInteractions(filepath.Join(os.TempDir(), "none.go"), gen.Options{}); err == nil {
+		t.Fatal("expected an error without interactions")
+	}
+}
+
+func TestInteractionExport_Unexportable(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		request  Request
+		response Response
+		want     string
+	}{
+		{"Optional", Request{}, Response{Body: map[string]interface{}{"nickname": Optional("billy")}}, "Optional fields ($.body.nickname) can't be exported"},
+		{"JSONString", Request{Body: map[string]interface{}{"payload": JSONString(map[string]interface{}{"id": Like(1)})}}, Response{}, "JSONString fields in the request ($.body.payload) can't be exported"},
+		{"GeneratedUUID", Request{Body: map[string]interface{}{"id": GeneratedUUID()}}, Response{}, "GeneratedUUID and GeneratedDateTime values can't be exported"},
+		{"MockServerURL", Request{}, Response{Headers: MapMatcher{"Location": MockServerURL("http://localhost/orders/1", `.*(/orders/\d+)$`)}}, "MockServerURL values can't be exported"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			i := (&Interaction{}).UponReceiving("a request").WithRequest(tt.request).WillRespondWith(tt.response)
+			pact := &Pact{Consumer: "billing-ui", Provider: "billing", Interactions: []*Interaction{i}}
+
+			err := pact.ExportInteractions(filepath.Join(os.TempDir(), "unexportable.go"), gen.Options{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("expected an error containing '%s' but got '%v'", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestInteractionExport_ResponseSequence(t *testing.T) {
+	i := (&Interaction{}).
+		UponReceiving("A request for the report").
+		WithRequest(Request{Method: "GET", Path: String("/report")}).
+		WillRespondWith(Response{Status: 202}).
+		ThenRespondWith("The report has been generated", Response{Status: 200})
+	pact := &Pact{Consumer: "billing-ui", Provider: "billing", Interactions: []*Interaction{i}}
+
+	err := pact.ExportInteractions(filepath.Join(os.TempDir(), "sequence.go"), gen.Options{})
+	if err == nil || !strings.Contains(err.Error(), "response sequences (ThenRespondWith) can't be exported") {
+		t.Fatalf("expected the response sequence to be rejected but got '%v'", err)
+	}
+}
@@ -0,0 +1,94 @@
+package dsl
+
+import (
+	"strings"
+)
+
+// InteractionSelector selects the interactions that Verify registers with the
+// Mock Service, see SelectDescriptions and SelectTags
+type InteractionSelector func(*Interaction) bool
+
+// SelectDescriptions selects the interactions with any of the descriptions
+func SelectDescriptions(descriptions ...string) InteractionSelector {
+	return func(i *Interaction) bool {
+		for _, description := range descriptions {
+			if i.Description == description {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// SelectTags selects the interactions with any of the tags (see WithTags)
+func SelectTags(tags ...string) InteractionSelector {
+	return func(i *Interaction) bool {
+		for _, tag := range tags {
+			for _, t := range i.tags {
+				if t == tag {
+					return true
+				}
+			}
+		}
+
+		return false
+	}
+}
+
+// selectSame selects the given interactions, and no others
+func selectSame(interactions []*Interaction) InteractionSelector {
+	same := make(map[*Interaction]bool, len(interactions))
+	for _, i := range interactions {
+		same[i] = true
+	}
+
+	return func(i *Interaction) bool {
+		return same[i]
+	}
+}
+
+// WithTags tags the interaction, so that it can be selected when verifying
+// with SelectTags. Tags are not written to the pact file.
+func (i *Interaction) WithTags(tags ...string) *Interaction {
+	i.tags = append(i.tags, tags...)
+
+	return i
+}
+
+// selectInteractions splits the interactions into those matching any of the
+// selectors, and the rest. All interactions are selected if there are no
+// selectors.
+func selectInteractions(interactions []*Interaction, selectors []InteractionSelector) (selected []*Interaction, remaining []*Interaction) {
+	remaining = make([]*Interaction, 0)
+
+	for _, interaction := range interactions {
+		if len(selectors) == 0 || anySelects(selectors, interaction) {
+			selected = append(selected, interaction)
+		} else {
+			remaining = append(remaining, interaction)
+		}
+	}
+
+	return selected, remaining
+}
+
+func anySelects(selectors []InteractionSelector, interaction *Interaction) bool {
+	for _, selector := range selectors {
+		if selector(interaction) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// descriptionsOf lists the descriptions of the interactions, for errors
+func descriptionsOf(interactions []*Interaction) string {
+	descriptions := make([]string, 0, len(interactions))
+	for _, i := range interactions {
+		descriptions = append(descriptions, "'"+i.Description+"'")
+	}
+
+	return strings.Join(descriptions, ", ")
+}
@@ -0,0 +1,102 @@
+package dsl
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+func TestInteractionSelector_Select(t *testing.T) {
+	login := (&Interaction{}).UponReceiving("login").WithTags("auth")
+	logout := (&Interaction{}).UponReceiving("logout").WithTags("auth", "session")
+	orders := (&Interaction{}).UponReceiving("orders")
+	interactions := []*Interaction{login, logout, orders}
+
+	tests := []struct {
+		name      string
+		selectors []InteractionSelector
+		selected  []*Interaction
+		remaining []*Interaction
+	}{
+		{"none", nil, interactions, []*Interaction{}},
+		{"description", []InteractionSelector{SelectDescriptions("orders")}, []*Interaction{orders}, []*Interaction{login, logout}},
+		{"tag", []InteractionSelector{SelectTags("session")}, []*Interaction{logout}, []*Interaction{login, orders}},
+		{"any", []InteractionSelector{SelectTags("auth"), SelectDescriptions("orders")}, interactions, []*Interaction{}},
+		{"no match", []InteractionSelector{SelectTags("missing")}, nil, interactions},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected, remaining := selectInteractions(interactions, tt.selectors)
+			if !reflect.DeepEqual(selected, tt.selected) {
+				t.Fatalf("expected %d interactions to be selected but got %d", len(tt.selected), len(selected))
+			}
+			if !reflect.DeepEqual(remaining, tt.remaining) {
+				t.Fatalf("expected %d interactions to remain but got %d", len(tt.remaining), len(remaining))
+			}
+		})
+	}
+}
+
+func TestInteractionSelector_Verify(t *testing.T) {
+	var mu sync.Mutex
+	var registered []string
+	ms := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.URL.Path == "/interactions" {
+			var i Interaction
+			json.NewDecoder(r.Body).Decode(&i)
+			mu.Lock()
+			registered = append(registered, i.Description)
+			mu.Unlock()
+		}
+	}))
+	defer ms.Close()
+
+	pact := &Pact{
+		Server: &types.MockServer{
+			Port: getPort(ms.URL),
+		},
+		Consumer: "My Consumer",
+		Provider: "My Provider",
+	}
+
+	for _, description := range []string{"login", "orders"} {
+		pact.
+			AddInteraction().
+			UponReceiving(description).
+			WithTags(description).
+			WithRequest(Request{Method: "GET", Path: String("/" + description)}).
+			WillRespondWith(Response{Status: 200})
+	}
+
+	if err := pact.Verify(func() error { return nil }, SelectTags("orders")); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if !reflect.DeepEqual(registered, []string{"orders"}) {
+		t.Fatalf("expected only the selected interaction to be registered but got %v", registered)
+	}
+	if len(pact.Interactions) != 1 || pact.Interactions[0].Description != "login" {
+		t.Fatalf("expected the other interaction to remain but got %d", len(pact.Interactions))
+	}
+
+	err := pact.Verify(func() error { return nil }, SelectDescriptions("orders"))
+	if !errors.Is(err, types.ErrInvalidRequest) {
+		t.Fatalf("expected an invalid request error when nothing is selected but got %v", err)
+	}
+
+	if err := pact.Verify(func() error { return nil }); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if !reflect.DeepEqual(registered, []string{"orders", "login"}) {
+		t.Fatalf("expected the remaining interaction to be registered but got %v", registered)
+	}
+	if len(pact.Interactions) != 0 {
+		t.Fatalf("expected no interactions to remain but got %d", len(pact.Interactions))
+	}
+}
@@ -0,0 +1,151 @@
+package dsl
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+// InteractionSet describes interactions published by a provider team for its
+// consumers to use, e.g. as typed constructors in a Go module alongside the
+// provider:
+//
+//	var Contract = dsl.InteractionSet{
+//		Name:     "github.com/acme/users/contract",
+//		Version:  "v1.4.0",
+//		Provider: "UserService",
+//	}
+//
+//	// GetUser is a request for an existing user
+//	func GetUser(id int) *dsl.Interaction {
+//		return Contract.Interaction().
+//			Given(fmt.Sprintf("user %d exists", id)).
+//			UponReceiving(fmt.Sprintf("A request for user %d", id)).
+//			...
+//	}
+//
+// Consumers add the interactions to their pacts with Pact.AddInteractions,
+// which checks that the set is compatible with the pact. The name and version
+// of the set are written to each of its interactions in the pact, so that
+// the provider knows which version of the set a consumer relies on.
+type InteractionSet struct {
+	// Name identifies the set, e.g. the path of the module publishing it
+	Name string
+
+	// Version of the set, e.g. the version of the module publishing it
+	Version string
+
+	// Provider the interactions are with, which must be the Provider of the
+	// pacts they are added to. Optional
+	Provider string
+
+	// SpecificationVersion is the lowest version of the pact specification
+	// the interactions need, e.g. 3 for interactions with provider state
+	// parameters. Optional
+	SpecificationVersion int
+
+	// ProviderVersions are the versions of the provider the interactions are
+	// known to be compatible with, e.g. ">= 2.3.0", for information. Optional
+	ProviderVersions string
+}
+
+// interactionSetField is how an InteractionSet is written to its interactions
+// in a pact
+type interactionSetField struct {
+	Name             string `json:"name"`
+	Version          string `json:"version,omitempty"`
+	ProviderVersions string `json:"providerVersions,omitempty"`
+}
+
+// Interaction returns a new interaction of the set, to be built by the
+// constructors publishing the set
+func (s InteractionSet) Interaction() *Interaction {
+	return &Interaction{set: &s}
+}
+
+// AddInteractions adds interactions built elsewhere, e.g. by the constructors
+// of an InteractionSet published by the provider, to be verified by the next
+// call to Verify, as per AddInteraction:
+//
+//	if err := pact.AddInteractions(contract.GetUser(10), contract.ListUsers()); err != nil {
+//		t.Fatal(err)
+//	}
+//
+// It is an error to add interactions of an InteractionSet for another
+// Provider, that needs a later SpecificationVersion than that of the Pact, or
+// whose version differs from that of interactions of the same set already
+// added. None of the interactions are added if any is incompatible.
+func (p *Pact) AddInteractions(interactions ...*Interaction) error {
+	p.Setup(true)
+	log.Println("[DEBUG] pact add interactions")
+
+	for _, i := range interactions {
+		if i == nil {
+			return types.NewError(types.ErrInvalidRequest, fmt.Errorf("unable to add a nil interaction"))
+		}
+		if err := p.checkInteractionSet(i, interactions); err != nil {
+			return types.NewError(types.ErrInvalidRequest, err)
+		}
+	}
+
+	testName := callerTestName()
+	for _, i := range interactions {
+		if i.testName == "" {
+			i.testName = testName
+		}
+	}
+	p.addInteractions(interactions...)
+
+	return nil
+}
+
+// checkInteractionSet checks that the InteractionSet of the interaction, if
+// any, is compatible with the pact and the other interactions
+func (p *Pact) checkInteractionSet(i *Interaction, added []*Interaction) error {
+	set := i.set
+	if set == nil {
+		return nil
+	}
+
+	if set.Provider != "" && p.Provider != "" && !strings.EqualFold(set.Provider, p.Provider) {
+		return fmt.Errorf("interaction '%s' of %s is with the provider '%s', not '%s'", i.Description, set, set.Provider, p.Provider)
+	}
+	if set.SpecificationVersion > p.SpecificationVersion {
+		return fmt.Errorf("interaction '%s' of %s needs version %d of the pact specification, but the pact is version %d", i.Description, set, set.SpecificationVersion, p.SpecificationVersion)
+	}
+
+	for _, other := range append(p.snapshotInteractions(), added...) {
+		if other.set != nil && other.set.Name == set.Name && other.set.Version != set.Version {
+			return fmt.Errorf("interaction '%s' of %s can't be verified alongside interaction '%s' of version %s of the set", i.Description, set, other.Description, other.set.Version)
+		}
+	}
+
+	return nil
+}
+
+func (s InteractionSet) String() string {
+	if s.Version == "" {
+		return fmt.Sprintf("interaction set '%s'", s.Name)
+	}
+
+	return fmt.Sprintf("interaction set '%s' %s", s.Name, s.Version)
+}
+
+// recordInteractionSet remembers the InteractionSet of an interaction, so that
+// it can be written to the pact file
+func (p *Pact) recordInteractionSet(key string, set *InteractionSet) {
+	if set == nil || set.Name == "" {
+		return
+	}
+
+	if p.interactionSets == nil {
+		p.interactionSets = make(map[string]interactionSetField)
+	}
+	p.interactionSets[key] = interactionSetField{
+		Name:             set.Name,
+		Version:          set.Version,
+		ProviderVersions: set.ProviderVersions,
+	}
+}
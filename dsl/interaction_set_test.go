@@ -0,0 +1,103 @@
+package dsl
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+var testInteractionSet = InteractionSet{
+	Name:             "github.com/acme/users/contract",
+	Version:          "v1.4.0",
+	Provider:         "UserService",
+	ProviderVersions: ">= 2.3.0",
+}
+
+func getUser(set InteractionSet, id string) *Interaction {
+	return set.Interaction().
+		Given("user " + id + " exists").
+		UponReceiving("A request for user " + id).
+		WithRequest(Request{Method: "GET", Path: String("/users/" + id)}).
+		WillRespondWith(Response{Status: 200})
+}
+
+func TestPact_AddInteractions(t *testing.T) {
+	pact := &Pact{Consumer: "billy", Provider: "userservice", pactClient: newMockClient()}
+	defer stubPorts()()
+
+	if err := pact.AddInteractions(getUser(testInteractionSet, "10"), getUser(testInteractionSet, "11")); err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	if len(pact.Interactions) != 2 {
+		t.Fatalf("expected 2 interactions to be added to Pact but got %d", len(pact.Interactions))
+	}
+	if name := pact.Interactions[0].testName; name != "TestPact_AddInteractions" {
+		t.Fatalf("expected the interactions to be added by the test but got '%s'", name)
+	}
+	if set := pact.Interactions[1].set; set == nil || set.Version != "v1.4.0" {
+		t.Fatalf("expected the interactions to be of the set but got %v", set)
+	}
+}
+
+func TestPact_AddInteractionsIncompatible(t *testing.T) {
+	defer stubPorts()()
+
+	later := testInteractionSet
+	later.Version = "v1.5.0"
+	v3 := testInteractionSet
+	v3.SpecificationVersion = 3
+	other := testInteractionSet
+	other.Provider = "OrderService"
+
+	for _, tt := range []struct {
+		interactions []*Interaction
+		want         string
+	}{
+		{[]*Interaction{getUser(other, "10")}, "is with the provider 'OrderService', not 'UserService'"},
+		{[]*Interaction{getUser(v3, "10")}, "needs version 3 of the pact specification, but the pact is version 2"},
+		{[]*Interaction{getUser(testInteractionSet, "10"), getUser(later, "11")}, "can't be verified alongside interaction 'A request for user 11' of version v1.5.0"},
+		{[]*Interaction{nil}, "unable to add a nil interaction"},
+	} {
+		pact := &Pact{Consumer: "billy", Provider: "UserService", pactClient: newMockClient()}
+		err := pact.AddInteractions(tt.interactions...)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Fatalf("expected an error containing '%s' but got '%v'", tt.want, err)
+		}
+		if len(pact.Interactions) != 0 {
+			t.Fatalf("expected no interactions to be added but got %d", len(pact.Interactions))
+		}
+	}
+
+	pact := &Pact{Consumer: "billy", Provider: "UserService", pactClient: newMockClient()}
+	pact.AddInteractions(getUser(testInteractionSet, "10"))
+	if err := pact.AddInteractions(getUser(later, "11")); err == nil {
+		t.Fatal("expected a later version of a set already added to return an error")
+	}
+}
+
+func TestPactFileRewrite_InteractionSets(t *testing.T) {
+	p := &Pact{}
+	p.recordInteractionSet(interactionKey("A request for user 10", "user 10 exists"), &testInteractionSet)
+	p.recordInteractionSet(interactionKey("A request without a set", ""), nil)
+
+	rewritten, err := p.pactRewriter().rewrite([]byte(`{"interactions":[{"description":"A request for user 10","providerState":"user 10 exists"},{"description":"A request without a set"}]}`))
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	var pact struct {
+		Interactions []struct {
+			InteractionSet *interactionSetField `json:"interactionSet"`
+		} `json:"interactions"`
+	}
+	json.Unmarshal(rewritten, &pact)
+
+	want := interactionSetField{Name: "github.com/acme/users/contract", Version: "v1.4.0", ProviderVersions: ">= 2.3.0"}
+	if set := pact.Interactions[0].InteractionSet; set == nil || *set != want {
+		t.Fatalf("expected the interaction set %v to be written but got %v", want, set)
+	}
+	if set := pact.Interactions[1].InteractionSet; set != nil {
+		t.Fatalf("expected no interaction set to be written but got %v", set)
+	}
+}
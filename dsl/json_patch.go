@@ -0,0 +1,107 @@
+package dsl
+
+import (
+	"fmt"
+)
+
+// jsonPatchContentType is the content type of RFC 6902 JSON Patch documents
+const jsonPatchContentType = "application/json-patch+json"
+
+// PatchOperation is an operation of a JSON Patch (RFC 6902) document, see
+// WithJSONPatchBody. The Path and From are JSON pointers, and may be matchers,
+// e.g. Term("/items/0", `^/items/\d+$`). The Value may contain matchers.
+type PatchOperation struct {
+	// Op is one of "add", "remove", "replace", "move", "copy" or "test"
+	Op string
+
+	// Path is the location the operation applies to
+	Path Matcher
+
+	// From is the location moved or copied, for "move" and "copy"
+	From Matcher
+
+	// Value is added, replaced or tested, for "add", "replace" and "test"
+	Value interface{}
+}
+
+// PatchAdd adds the value at the path
+func PatchAdd(path string, value interface{}) PatchOperation {
+	return PatchOperation{Op: "add", Path: String(path), Value: value}
+}
+
+// PatchRemove removes the value at the path
+func PatchRemove(path string) PatchOperation {
+	return PatchOperation{Op: "remove", Path: String(path)}
+}
+
+// PatchReplace replaces the value at the path
+func PatchReplace(path string, value interface{}) PatchOperation {
+	return PatchOperation{Op: "replace", Path: String(path), Value: value}
+}
+
+// PatchMove moves the value at from to the path
+func PatchMove(from string, path string) PatchOperation {
+	return PatchOperation{Op: "move", From: String(from), Path: String(path)}
+}
+
+// PatchCopy copies the value at from to the path
+func PatchCopy(from string, path string) PatchOperation {
+	return PatchOperation{Op: "copy", From: String(from), Path: String(path)}
+}
+
+// PatchTest tests that the value at the path is equal to value
+func PatchTest(path string, value interface{}) PatchOperation {
+	return PatchOperation{Op: "test", Path: String(path), Value: value}
+}
+
+// body returns the operation as it appears in a JSON Patch document
+func (o PatchOperation) body() (StructMatcher, error) {
+	if o.Path == nil {
+		return nil, fmt.Errorf("JSON Patch operation '%s' has no path", o.Op)
+	}
+	op := StructMatcher{"op": o.Op, "path": o.Path}
+
+	switch o.Op {
+	case "add", "replace", "test":
+		op["value"] = o.Value
+	case "move", "copy":
+		if o.From == nil {
+			return nil, fmt.Errorf("JSON Patch operation '%s' has no from", o.Op)
+		}
+		op["from"] = o.From
+	case "remove":
+	default:
+		return nil, fmt.Errorf("unknown JSON Patch operation '%s'", o.Op)
+	}
+
+	return op, nil
+}
+
+// WithJSONPatchBody sets the body of the request to a JSON Patch (RFC 6902)
+// document of the operations, e.g. for a PATCH request:
+//
+//	WithJSONPatchBody(
+//		dsl.PatchReplace("/name", dsl.Like("billy")),
+//		dsl.PatchAdd("/tags/-", dsl.Term("admin", "^(admin|user)$")),
+//	)
+//
+// The Content-Type header is set to application/json-patch+json if it has not
+// already been set, as per WithJSONBody. The operations are matched in order,
+// and there must be exactly as many, as v2 pacts can't match arrays that
+// contain an element regardless of its position.
+func (r *Request) WithJSONPatchBody(operations ...PatchOperation) *Request {
+	body := make([]interface{}, 0, len(operations))
+	for _, o := range operations {
+		op, err := o.body()
+		if err != nil {
+			r.err = err
+			return r
+		}
+		body = append(body, op)
+	}
+
+	r.Body = body
+	r.setBodyContentType(jsonPatchContentType, isJSONContentType)
+
+	return r
+}
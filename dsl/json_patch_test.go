@@ -0,0 +1,45 @@
+package dsl
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRequest_WithJSONPatchBody(t *testing.T) {
+	request := (&Request{Method: "PATCH", Path: String("/users/1")}).WithJSONPatchBody(
+		PatchReplace("/name", Like("billy")),
+		PatchMove("/nickname", "/alias"),
+		PatchOperation{Op: "remove", Path: Term("/tags/0", `^/tags/\d+$`)},
+	)
+	if request.err != nil || request.Headers["Content-Type"] != String("application/json-patch+json") {
+		t.Fatalf("expected a JSON Patch content type but got %v (%v)", request.Headers, request.err)
+	}
+
+	body, err := json.Marshal(exampleBody(request.Body))
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	want := `[{"op":"replace","path":"/name","value":"billy"},{"from":"/nickname","op":"move","path":"/alias"},{"op":"remove","path":"/tags/0"}]`
+	if string(body) != want {
+		t.Fatalf("want '%s', got '%s'", want, body)
+	}
+
+	rules, err := (&Interaction{Request: *request}).RequestMatchingRules()
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(rules) != 2 || rules[0].Path != "$.body[0].value" || rules[1].Path != "$.body[2].path" {
+		t.Fatalf("expected matching rules for each element but got %+v", rules)
+	}
+
+	for name, invalid := range map[string]*Request{
+		"unknown op": (&Request{}).WithJSONPatchBody(PatchOperation{Op: "merge", Path: String("/a")}),
+		"no path":    (&Request{}).WithJSONPatchBody(PatchOperation{Op: "remove"}),
+		"no from":    (&Request{}).WithJSONPatchBody(PatchOperation{Op: "copy", Path: String("/a")}),
+		"text":       (&Request{Headers: MapMatcher{"Content-Type": String("text/plain")}}).WithJSONPatchBody(PatchRemove("/a")),
+	} {
+		if invalid.err == nil {
+			t.Fatalf("expected an error for %s", name)
+		}
+	}
+}
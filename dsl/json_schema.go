@@ -0,0 +1,435 @@
+package dsl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	mathrand "math/rand"
+	"regexp"
+	"regexp/syntax"
+	"sort"
+	"strings"
+)
+
+// jsonSchema is the subset of a JSON Schema document that FromJSONSchema
+// understands
+type jsonSchema struct {
+	Ref         string                 `json:"$ref"`
+	Type        interface{}            `json:"type"`
+	Format      string                 `json:"format"`
+	Pattern     string                 `json:"pattern"`
+	Enum        []interface{}          `json:"enum"`
+	Const       interface{}            `json:"const"`
+	Examples    []interface{}          `json:"examples"`
+	Example     interface{}            `json:"example"`
+	Default     interface{}            `json:"default"`
+	Properties  map[string]*jsonSchema `json:"properties"`
+	Required    []string               `json:"required"`
+	Items       json.RawMessage        `json:"items"`
+	MinItems    int                    `json:"minItems"`
+	MaxItems    int                    `json:"maxItems"`
+	Minimum     *json.Number           `json:"minimum"`
+	OneOf       []*jsonSchema          `json:"oneOf"`
+	AnyOf       []*jsonSchema          `json:"anyOf"`
+	AllOf       []*jsonSchema          `json:"allOf"`
+	Definitions map[string]*jsonSchema `json:"definitions"`
+	Defs        map[string]*jsonSchema `json:"$defs"`
+
+	hasConst bool
+}
+
+func (s *jsonSchema) UnmarshalJSON(data []byte) error {
+	type schema jsonSchema
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode((*schema)(s)); err != nil {
+		return err
+	}
+
+	var keys map[string]json.RawMessage
+	json.Unmarshal(data, &keys)
+	_, s.hasConst = keys["const"]
+
+	return nil
+}
+
+// example returns the example given in the schema, if any
+func (s *jsonSchema) example() (interface{}, bool) {
+	switch {
+	case s.hasConst:
+		return s.Const, true
+	case len(s.Examples) > 0:
+		return s.Examples[0], true
+	case s.Example != nil:
+		return s.Example, true
+	case s.Default != nil:
+		return s.Default, true
+	}
+
+	return nil, false
+}
+
+// formatMatchers are the matchers of the string formats of JSON Schema
+var formatMatchers = map[string]func() Matcher{
+	"date-time": Timestamp,
+	"date":      Date,
+	"time":      Time,
+	"uuid":      UUID,
+	"email":     Email,
+	"ipv4":      StrictIPv4Address,
+	"ipv6":      IPv6Address,
+	"duration":  Duration,
+	"byte":      Base64,
+}
+
+// FromJSONSchema converts a JSON Schema document into the matchers of a body
+// that conforms to it, so that existing schemas can be used as the body of an
+// interaction:
+//
+//	body, err := dsl.FromJSONSchema(userSchema)
+//	...
+//	WillRespondWith(dsl.Response{Status: 200, Body: body})
+//
+// Values are matched by type, with the example given in the schema ("const",
+// "examples", "example" or "default") or a generated one. Strings with a
+// "pattern", a string "enum" or a well known "format" (e.g. "date-time",
+// "uuid" or "email") are matched by regex. Arrays are matched with EachLike,
+// within their "minItems" and "maxItems". Properties that are not "required"
+// are Optional, so the result is only suitable for response bodies (and
+// message content) unless every property is required.
+//
+// The first of "oneOf", "anyOf" or a list of types is used, the properties of
+// "allOf" are merged, and "$ref"s to the "definitions" (or "$defs") of the
+// document are followed. Other keywords, such as "additionalProperties", are
+// ignored, as v2 pacts can't express them.
+func FromJSONSchema(schema []byte) (Matcher, error) {
+	var root jsonSchema
+	if err := json.Unmarshal(schema, &root); err != nil {
+		return nil, fmt.Errorf("unable to parse JSON Schema: %v", err)
+	}
+
+	c := &schemaConverter{root: &root, resolving: make(map[string]bool)}
+	body, err := c.convert(&root, "$")
+	if err != nil {
+		return nil, err
+	}
+
+	switch b := body.(type) {
+	case Matcher:
+		return b, nil
+	case string:
+		return String(b), nil
+	}
+
+	return nil, fmt.Errorf("unable to match the JSON Schema: the document must describe an object, array or string, but it is the constant %v", body)
+}
+
+// schemaConverter converts the schemas of a JSON Schema document to matchers
+type schemaConverter struct {
+	root *jsonSchema
+
+	// resolving are the $refs being converted, to detect cycles
+	resolving map[string]bool
+}
+
+// convert returns the matcher of the schema at the (JSON) path, or a value if
+// it must be equal to a constant
+func (c *schemaConverter) convert(s *jsonSchema, path string) (interface{}, error) {
+	if s.Ref != "" {
+		if c.resolving[s.Ref] {
+			return nil, fmt.Errorf("%s: unable to match the recursive schema '%s'", path, s.Ref)
+		}
+		ref, err := c.resolve(s.Ref)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+
+		c.resolving[s.Ref] = true
+		defer delete(c.resolving, s.Ref)
+
+		return c.convert(ref, path)
+	}
+
+	switch {
+	case len(s.OneOf) > 0:
+		return c.convert(s.OneOf[0], path)
+	case len(s.AnyOf) > 0:
+		return c.convert(s.AnyOf[0], path)
+	case len(s.AllOf) > 0:
+		return c.convertAllOf(s, path)
+	}
+
+	if s.hasConst {
+		return s.Const, nil
+	}
+	if len(s.Enum) > 0 {
+		return enumMatcher(s.Enum), nil
+	}
+
+	example, hasExample := s.example()
+
+	switch schemaType(s) {
+	case "object":
+		return c.convertObject(s, path)
+	case "array":
+		return c.convertArray(s, path)
+	case "string":
+		return stringMatcher(s, example, hasExample, path)
+	case "integer":
+		if !hasExample {
+			example = json.Number("1")
+			if s.Minimum != nil {
+				example = *s.Minimum
+			}
+		}
+		n, err := json.Number(fmt.Sprint(example)).Int64()
+		if err != nil {
+			return nil, fmt.Errorf("%s: example %v is not an integer", path, example)
+		}
+		return Like(n), nil
+	case "number":
+		if !hasExample {
+			example = json.Number("1.5")
+		}
+		f, err := json.Number(fmt.Sprint(example)).Float64()
+		if err != nil {
+			return nil, fmt.Errorf("%s: example %v is not a number", path, example)
+		}
+		return StrictDecimal(f), nil
+	case "boolean":
+		if b, ok := example.(bool); ok {
+			return Like(b), nil
+		}
+		return Like(true), nil
+	case "null":
+		return nil, nil
+	case "":
+		if hasExample {
+			return Like(example), nil
+		}
+		return nil, fmt.Errorf("%s: the schema has no type", path)
+	}
+
+	return nil, fmt.Errorf("%s: unknown type '%v'", path, s.Type)
+}
+
+// schemaType returns the type of the schema, the first other than "null" if
+// there are several, or "object" or "array" if it has properties or items
+func schemaType(s *jsonSchema) string {
+	switch t := s.Type.(type) {
+	case string:
+		return t
+	case []interface{}:
+		for _, item := range t {
+			if name, ok := item.(string); ok && name != "null" {
+				return name
+			}
+		}
+		return "null"
+	}
+
+	if len(s.Properties) > 0 {
+		return "object"
+	}
+	if len(s.Items) > 0 {
+		return "array"
+	}
+
+	return ""
+}
+
+// resolve returns the schema of a $ref to the definitions of the document
+func (c *schemaConverter) resolve(ref string) (*jsonSchema, error) {
+	for prefix, definitions := range map[string]map[string]*jsonSchema{
+		"#/definitions/": c.root.Definitions,
+		"#/$defs/":       c.root.Defs,
+	} {
+		if strings.HasPrefix(ref, prefix) {
+			if s, ok := definitions[strings.TrimPrefix(ref, prefix)]; ok {
+				return s, nil
+			}
+		}
+	}
+	if ref == "#" {
+		return c.root, nil
+	}
+
+	return nil, fmt.Errorf("unable to resolve '$ref' '%s'", ref)
+}
+
+// convertObject returns a StructMatcher of the properties of the object
+func (c *schemaConverter) convertObject(s *jsonSchema, path string) (interface{}, error) {
+	required := make(map[string]bool, len(s.Required))
+	for _, name := range s.Required {
+		required[name] = true
+	}
+
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	obj := make(StructMatcher, len(s.Properties))
+	for _, name := range names {
+		value, err := c.convert(s.Properties[name], jsonPathField(path, name))
+		if err != nil {
+			return nil, err
+		}
+		if !required[name] {
+			value = Optional(value)
+		}
+		obj[name] = value
+	}
+
+	return obj, nil
+}
+
+// convertArray returns an EachLike of the items of the array, or the matcher
+// of each of its items if they are given as a list
+func (c *schemaConverter) convertArray(s *jsonSchema, path string) (interface{}, error) {
+	if len(s.Items) == 0 {
+		return nil, fmt.Errorf("%s: the array has no 'items'", path)
+	}
+
+	if bytes.HasPrefix(bytes.TrimSpace(s.Items), []byte("[")) {
+		var tuple []*jsonSchema
+		if err := json.Unmarshal(s.Items, &tuple); err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		items := make([]interface{}, len(tuple))
+		for i, item := range tuple {
+			value, err := c.convert(item, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			items[i] = value
+		}
+		return items, nil
+	}
+
+	var items jsonSchema
+	if err := json.Unmarshal(s.Items, &items); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	contents, err := c.convert(&items, path+"[*]")
+	if err != nil {
+		return nil, err
+	}
+
+	min := s.MinItems
+	if min < 1 {
+		min = 1
+	}
+	if s.MaxItems >= min {
+		return EachLikeBetween(contents, min, s.MaxItems), nil
+	}
+
+	return EachLike(contents, min), nil
+}
+
+// convertAllOf merges the properties of the schemas of allOf into an object
+func (c *schemaConverter) convertAllOf(s *jsonSchema, path string) (interface{}, error) {
+	merged := &jsonSchema{Type: "object", Properties: make(map[string]*jsonSchema)}
+
+	schemas := append([]*jsonSchema{}, s.AllOf...)
+	for len(schemas) > 0 {
+		schema := schemas[0]
+		schemas = schemas[1:]
+
+		if schema.Ref != "" {
+			ref, err := c.resolve(schema.Ref)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", path, err)
+			}
+			schema = ref
+		}
+		schemas = append(schemas, schema.AllOf...)
+
+		for name, property := range schema.Properties {
+			merged.Properties[name] = property
+		}
+		merged.Required = append(merged.Required, schema.Required...)
+	}
+
+	return c.convertObject(merged, path)
+}
+
+// enumMatcher matches any of the strings of the enum, or the first of its
+// values if they aren't all strings
+func enumMatcher(enum []interface{}) interface{} {
+	alternatives := make([]string, 0, len(enum))
+	for _, value := range enum {
+		s, ok := value.(string)
+		if !ok {
+			return enum[0]
+		}
+		alternatives = append(alternatives, regexp.QuoteMeta(s))
+	}
+
+	return Term(enum[0].(string), fmt.Sprintf("^(%s)$", strings.Join(alternatives, "|")))
+}
+
+// stringMatcher returns the matcher of a string, by its pattern or format if
+// it has one, or else its type
+func stringMatcher(s *jsonSchema, example interface{}, hasExample bool, path string) (interface{}, error) {
+	if hasExample {
+		if _, ok := example.(string); !ok {
+			return nil, fmt.Errorf("%s: example %v is not a string", path, example)
+		}
+	}
+
+	if s.Pattern != "" {
+		re, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid pattern '%s': %v", path, s.Pattern, err)
+		}
+		if !hasExample {
+			if example, hasExample = patternExample(s.Pattern, re); !hasExample {
+				return nil, fmt.Errorf("%s: unable to generate an example of the pattern '%s', give one in 'examples'", path, s.Pattern)
+			}
+		}
+		if !re.MatchString(example.(string)) {
+			return nil, fmt.Errorf("%s: example '%s' does not match the pattern '%s'", path, example, s.Pattern)
+		}
+		return Term(example.(string), s.Pattern), nil
+	}
+
+	if format, ok := formatMatchers[s.Format]; ok {
+		matcher := format()
+		if !hasExample {
+			return matcher, nil
+		}
+		regex := matcher.(term).Data.Matcher.Regex.(string)
+		if !regexp.MustCompile(regex).MatchString(example.(string)) {
+			return nil, fmt.Errorf("%s: example '%s' is not a valid %s", path, example, s.Format)
+		}
+		return Term(example.(string), regex), nil
+	}
+
+	if !hasExample {
+		example = "string"
+	}
+
+	return Like(example), nil
+}
+
+// patternExample generates a string matching the pattern
+func patternExample(pattern string, re *regexp.Regexp) (string, bool) {
+	parsed, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", false
+	}
+
+	r := &responseVariation{random: mathrand.New(mathrand.NewSource(1))}
+	for attempt := 0; attempt < 10; attempt++ {
+		var b strings.Builder
+		r.generate(&b, parsed.Simplify())
+		if re.MatchString(b.String()) {
+			return b.String(), true
+		}
+	}
+
+	return "", false
+}
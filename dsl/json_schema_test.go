@@ -0,0 +1,146 @@
+package dsl
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestFromJSONSchema(t *testing.T) {
+	schema := []byte(`{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "type": "object",
+  "required": ["id", "name", "email", "role", "tags", "createdAt", "address"],
+  "properties": {
+    "id": {"type": "integer", "minimum": 1},
+    "name": {"type": "string", "examples": ["billy"]},
+    "email": {"type": "string", "format": "email"},
+    "role": {"enum": ["admin", "user"]},
+    "tags": {"type": "array", "items": {"type": "string"}, "maxItems": 5},
+    "createdAt": {"type": "string", "format": "date-time"},
+    "code": {"type": "string", "pattern": "^[A-Z]{3}-\\d{4}$"},
+    "balance": {"type": ["number", "null"], "example": 10.5},
+    "active": {"type": "boolean", "default": false},
+    "kind": {"const": "user"},
+    "address": {"$ref": "#/definitions/address"}
+  },
+  "definitions": {
+    "address": {
+      "type": "object",
+      "required": ["city"],
+      "properties": {
+        "city": {"type": "string"},
+        "postcode": {"type": "string"}
+      }
+    }
+  }
+}`)
+
+	body, err := FromJSONSchema(schema)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	obj, ok := body.(StructMatcher)
+	if !ok {
+		t.Fatalf("expected a StructMatcher but got %T", body)
+	}
+
+	for name, want := range map[string]Matcher{
+		"id":        Like(int64(1)),
+		"name":      Like("billy"),
+		"email":     Email(),
+		"role":      Term("admin", "^(admin|user)$"),
+		"tags":      EachLikeBetween(Like("string"), 1, 5),
+		"createdAt": Timestamp(),
+		"code":      Optional(Term("", `^[A-Z]{3}-\d{4}$`)),
+		"balance":   Optional(StrictDecimal(10.5)),
+		"active":    Optional(Like(false)),
+		"address":   StructMatcher{"city": Like("string"), "postcode": Optional(Like("string"))},
+	} {
+		if name == "code" {
+			code, ok := obj[name].(optional)
+			if !ok {
+				t.Fatalf("expected the code to be optional but got %#v", obj[name])
+			}
+			example := code.Contents.(term).Data.Generate.(string)
+			if !regexp.MustCompile(`^[A-Z]{3}-\d{4}$`).MatchString(example) {
+				t.Fatalf("expected a generated example of the pattern but got '%s'", example)
+			}
+			continue
+		}
+
+		got, _ := json.Marshal(obj[name])
+		expected, _ := json.Marshal(want)
+		if string(got) != string(expected) {
+			t.Fatalf("expected %s for '%s' but got %s", expected, name, got)
+		}
+	}
+
+	if kind, ok := obj["kind"].(optional); !ok || kind.Contents != "user" {
+		t.Fatalf("expected the const kind to be matched by equality but got %#v", obj["kind"])
+	}
+}
+
+func TestFromJSONSchema_allOfAndTuples(t *testing.T) {
+	schema := []byte(`{
+  "$defs": {
+    "named": {"type": "object", "required": ["name"], "properties": {"name": {"type": "string"}}}
+  },
+  "type": "array",
+  "minItems": 2,
+  "items": {
+    "allOf": [
+      {"$ref": "#/$defs/named"},
+      {"required": ["point"], "properties": {"point": {"type": "array", "items": [{"type": "number"}, {"type": "number"}]}}}
+    ]
+  }
+}`)
+
+	body, err := FromJSONSchema(schema)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	got, _ := json.Marshal(body)
+	want, _ := json.Marshal(EachLike(StructMatcher{
+		"name":  Like("string"),
+		"point": []interface{}{StrictDecimal(1.5), StrictDecimal(1.5)},
+	}, 2))
+	if string(got) != string(want) {
+		t.Fatalf("expected %s but got %s", want, got)
+	}
+}
+
+func TestFromJSONSchema_errors(t *testing.T) {
+	for schema, want := range map[string]string{
+		`{`: "unable to parse JSON Schema",
+		`{"type": "object", "properties": {"a": {"$ref": "#/definitions/missing"}}}`:                             "$.a: unable to resolve '$ref' '#/definitions/missing'",
+		`{"type": "object", "properties": {"a": {"$ref": "#"}}}`:                                                 "unable to match the recursive schema '#'",
+		`{"type": "object", "properties": {"a": {"type": "string", "pattern": "^a$", "example": "b"}}}`:          "$.a: example 'b' does not match the pattern '^a$'",
+		`{"type": "object", "properties": {"a": {"type": "integer", "example": 1.5}}}`:                           "$.a: example 1.5 is not an integer",
+		`{"type": "object", "properties": {"a": {"type": "string", "format": "uuid", "example": "not-a-uuid"}}}`: "$.a: example 'not-a-uuid' is not a valid uuid",
+		`{"type": "array"}`:  "$: the array has no 'items'",
+		`{"type": "widget"}`: "$: unknown type 'widget'",
+		`{"const": 42}`:      "the document must describe an object, array or string",
+	} {
+		_, err := FromJSONSchema([]byte(schema))
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected an error containing '%s' for %s but got '%v'", want, schema, err)
+		}
+	}
+}
+
+func TestFromJSONSchema_example(t *testing.T) {
+	body, err := FromJSONSchema([]byte(`{"type": "object", "required": ["id"], "properties": {"id": {"type": "string", "format": "uuid"}}}`))
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	want := map[string]interface{}{"id": "fc763eba-0905-41c5-a27f-3934ab26786c"}
+	if got := exampleBody(body); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected the example body %v but got %v", want, got)
+	}
+}
@@ -0,0 +1,263 @@
+package dsl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// jsonStringClass identifies a JSONString in the serialised form of a body. It
+// is never sent to the Mock Service.
+const jsonStringClass = "PactGo::JSONString"
+
+// jsonStringMatcher is a string containing JSON
+type jsonStringMatcher struct {
+	ContentType string      `json:"contentType"`
+	Contents    interface{} `json:"contents"`
+}
+
+func (m jsonStringMatcher) GetValue() interface{} {
+	return jsonStringExample(m.Contents)
+}
+
+func (m jsonStringMatcher) isMatcher() {
+}
+
+func (m jsonStringMatcher) MarshalJSON() ([]byte, error) {
+	type marshaler jsonStringMatcher
+
+	return json.Marshal(struct {
+		Type string `json:"json_class"`
+		marshaler
+	}{jsonStringClass, marshaler(m)})
+}
+
+// JSONString matches a string in a body that contains JSON, such as a webhook
+// payload or a message envelope, whose content matches content (which may
+// contain matchers):
+//
+//	"payload": dsl.JSONString(map[string]interface{}{"id": dsl.Like(1)})
+//
+// The Mock Service matches any string, and responds with the example JSON.
+// The matchers in the JSON are checked in the requests made by the consumer,
+// and in the responses of the provider during verification (of pacts given as
+// local PactURLs). They are written to the "jsonStrings" of the interaction in
+// the pact, with a contentType of application/json.
+func JSONString(content interface{}) Matcher {
+	return jsonStringMatcher{
+		ContentType: "application/json",
+		Contents:    content,
+	}
+}
+
+// jsonStringExample returns the example JSON of the contents of a JSONString
+func jsonStringExample(contents interface{}) string {
+	var encoded bytes.Buffer
+	encoder := json.NewEncoder(&encoded)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(exampleBody(contents)); err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(encoded.String())
+}
+
+// hasJSONString returns true if the serialised value contains a JSONString
+func hasJSONString(v interface{}) bool {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if v["json_class"] == jsonStringClass {
+			return true
+		}
+		for _, field := range v {
+			if hasJSONString(field) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if hasJSONString(item) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// withoutJSONStrings returns the serialised body v at path with each JSONString
+// replaced by a Like of its example, as sent to the Mock Service, recording the
+// JSONStrings by their path
+func withoutJSONStrings(v interface{}, path string, fields map[string]interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		switch v["json_class"] {
+		case jsonStringClass:
+			fields[path] = v
+			return Like(jsonStringExample(v["contents"]))
+		case "Pact::SomethingLike", "Pact::ArrayLike":
+			contentsPath := path
+			if v["json_class"] == "Pact::ArrayLike" {
+				contentsPath += "[*]"
+			}
+			matcher := make(map[string]interface{}, len(v))
+			for k, field := range v {
+				matcher[k] = field
+			}
+			matcher["contents"] = withoutJSONStrings(v["contents"], contentsPath, fields)
+			return matcher
+		case "Pact::Term":
+			return v
+		}
+
+		object := make(map[string]interface{}, len(v))
+		for k, field := range v {
+			object[k] = withoutJSONStrings(field, jsonPathField(path, k), fields)
+		}
+		return object
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for n, item := range v {
+			items[n] = withoutJSONStrings(item, fmt.Sprintf("%s[%d]", path, n), fields)
+		}
+		return items
+	}
+
+	return v
+}
+
+// withJSONStrings returns a copy of the interaction whose request and response
+// bodies have no JSONStrings, as sent to the Mock Service, and the JSONStrings
+// of the "request" and "response" by their path e.g. "$.body.payload"
+func (i *Interaction) withJSONStrings() (*Interaction, map[string]map[string]interface{}, error) {
+	request, err := serialisedParts(nil, nil, nil, i.Request.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	response, err := serialisedParts(nil, nil, nil, i.Response.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	interaction := *i
+	fields := make(map[string]map[string]interface{})
+	if len(request) > 0 && hasJSONString(request[0].value) {
+		fields["request"] = make(map[string]interface{})
+		interaction.Request.Body = withoutJSONStrings(request[0].value, request[0].path, fields["request"])
+	}
+	if len(response) > 0 && hasJSONString(response[0].value) {
+		fields["response"] = make(map[string]interface{})
+		interaction.Response.Body = withoutJSONStrings(response[0].value, response[0].path, fields["response"])
+	}
+
+	if len(fields) == 0 {
+		return i, nil, nil
+	}
+
+	return &interaction, fields, nil
+}
+
+// validateJSONStrings checks that JSONStrings are only given in bodies
+func (i *Interaction) validateJSONStrings() error {
+	request, err := serialisedParts(i.Request.Path, i.Request.Query, i.Request.Headers, nil)
+	if err != nil {
+		return err
+	}
+	for _, part := range request {
+		if hasJSONString(part.value) {
+			return fmt.Errorf("request %s: JSONString is only supported in bodies", part.path)
+		}
+	}
+
+	for k, v := range i.Response.Headers {
+		if _, ok := v.(jsonStringMatcher); ok {
+			return fmt.Errorf("response $.headers.%s: JSONString is only supported in bodies", k)
+		}
+	}
+
+	return nil
+}
+
+// matchJSONString checks that the actual value at path is a string containing
+// JSON that matches the serialised contents of a JSONString
+func matchJSONString(contents interface{}, actual interface{}, path string) error {
+	s, ok := actual.(string)
+	if !ok {
+		return fmt.Errorf("%s: expected a string containing JSON but got %v", path, actual)
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(s))
+	decoder.UseNumber()
+
+	var content interface{}
+	if err := decoder.Decode(&content); err != nil {
+		return fmt.Errorf("%s: expected a string containing JSON but got '%s'", path, s)
+	}
+
+	return matchSerialised(contents, content, path+"(json)", false)
+}
+
+// recordJSONStrings remembers the JSONStrings in the request and response of
+// an interaction, so that they can be checked and written to the pact file
+func (p *Pact) recordJSONStrings(key string, fields map[string]map[string]interface{}) {
+	if len(fields) == 0 {
+		return
+	}
+
+	if p.jsonStrings == nil {
+		p.jsonStrings = make(map[string]map[string]map[string]interface{})
+	}
+	p.jsonStrings[key] = fields
+}
+
+// setupJSONStrings ensures that the requests of HTTP interactions with
+// JSONStrings in their body are recorded, so that the JSON can be checked.
+// This requires the recording proxy, which is started on demand.
+func (p *Pact) setupJSONStrings(interactions map[string][]*Interaction) {
+	for _, i := range interactions[TransportHTTPS] {
+		if parts, _ := serialisedParts(nil, nil, nil, i.Request.Body); len(parts) > 0 && hasJSONString(parts[0].value) {
+			log.Println("[WARN] JSONString is not checked in requests over TLS, any string is matched")
+			break
+		}
+	}
+
+	for _, i := range interactions[TransportHTTP] {
+		if parts, _ := serialisedParts(nil, nil, nil, i.Request.Body); len(parts) > 0 && hasJSONString(parts[0].value) {
+			p.startProxy()
+			return
+		}
+	}
+}
+
+// checkJSONStrings checks the JSONStrings in the bodies of the requests matched
+// by the Mock Service
+func (p *Pact) checkJSONStrings(interactions []*Interaction) error {
+	if p.proxy == nil {
+		return nil
+	}
+
+	var mismatches []string
+	for _, rec := range p.proxy.Requests() {
+		if rec.Unmatched {
+			continue
+		}
+
+		interaction := findInteraction(interactions, rec)
+		if interaction == nil {
+			continue
+		}
+
+		fields := p.jsonStrings[interactionKey(interaction.Description, interaction.State)]["request"]
+		for _, mismatch := range checkBodyFields(rec.Body, fields) {
+			mismatches = append(mismatches, fmt.Sprintf("%s %s: %s", rec.Method, rec.Path, mismatch))
+		}
+	}
+
+	if len(mismatches) > 0 {
+		return fmt.Errorf("JSON strings in requests did not match:\n\n%s", strings.Join(mismatches, "\n"))
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package dsl
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestJSONString_withJSONStrings(t *testing.T) {
+	i := (&Interaction{}).
+		UponReceiving("a webhook").
+		WithRequest(Request{
+			Method: "POST",
+			Path:   String("/hooks"),
+			Body: map[string]interface{}{
+				"event":   "created",
+				"payload": JSONString(map[string]interface{}{"id": Like(1), "name": "bob"}),
+			},
+		}).
+		WillRespondWith(Response{
+			Status: 200,
+			Body: EachLike(map[string]interface{}{
+				"envelope": JSONString(map[string]interface{}{"ok": Like(true)}),
+			}, 1),
+		})
+
+	if err := i.validate(); err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	expected, fields, err := i.withJSONStrings()
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	body, _ := json.Marshal(expected.Request.Body)
+	if strings.Contains(string(body), jsonStringClass) || !strings.Contains(string(body), `"contents":"{\"id\":1,\"name\":\"bob\"}"`) {
+		t.Fatalf("expected the JSONString to be replaced by a Like of its example: %s", body)
+	}
+	if fields["request"]["$.body.payload"] == nil || fields["response"]["$.body[*].envelope"] == nil {
+		t.Fatalf("expected the JSONStrings by path: %v", fields)
+	}
+
+	if unchanged, none, _ := (&Interaction{}).WillRespondWith(Response{Body: "plain"}).withJSONStrings(); none != nil || unchanged.Response.Body != "plain" {
+		t.Fatal("expected a body without JSONStrings to be unchanged")
+	}
+}
+
+func TestJSONString_Invalid(t *testing.T) {
+	for name, i := range map[string]*Interaction{
+		"request header": (&Interaction{}).WithRequest(Request{
+			Method:  "GET",
+			Path:    String("/users"),
+			Headers: MapMatcher{"X-Payload": JSONString(map[string]interface{}{"id": 1})},
+		}),
+		"response header": (&Interaction{}).WillRespondWith(Response{
+			Headers: MapMatcher{"X-Payload": JSONString(map[string]interface{}{"id": 1})},
+		}),
+	} {
+		if err := i.validateJSONStrings(); err == nil {
+			t.Fatalf("%s: expected JSONString to be rejected", name)
+		}
+	}
+}
+
+func TestJSONString_checkBodyFields(t *testing.T) {
+	var contents interface{}
+	serialised, _ := json.Marshal(JSONString(map[string]interface{}{"id": Like(1), "tags": EachLike("a", 1)}))
+	decoder := json.NewDecoder(bytes.NewReader(serialised))
+	decoder.UseNumber()
+	decoder.Decode(&contents)
+	fields := map[string]interface{}{"$.body.payload": contents}
+
+	for body, mismatches := range map[string]int{
+		`{"payload":"{\"id\":2,\"tags\":[\"b\",\"c\"]}"}`: 0,
+		`{"payload":"{\"id\":\"2\",\"tags\":[\"b\"]}"}`:   1,
+		`{"payload":"{\"id\":2,\"tags\":[1]}"}`:           1,
+		`{"payload":"not json"}`:                          1,
+		`{"payload":{"id":2,"tags":["b"]}}`:               1,
+	} {
+		if got := checkBodyFields([]byte(body), fields); len(got) != mismatches {
+			t.Fatalf("expected %d mismatches for %s but got %v", mismatches, body, got)
+		}
+	}
+}
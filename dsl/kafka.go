@@ -0,0 +1,169 @@
+package dsl
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+)
+
+// Metadata keys used to describe a Kafka record within a message pact
+const (
+	KafkaTopicMetadataKey = "kafka_topic"
+	KafkaKeyMetadataKey   = "kafka_key"
+)
+
+// KafkaMessage is a client agnostic representation of a Kafka record.
+//
+// It deliberately mirrors the fields of sarama.ConsumerMessage and
+// confluent-kafka-go's kafka.Message, so that either can be converted with
+// a small adapter without Pact Go depending on a specific Kafka client.
+// See the examples/kafka folder for a working example of each.
+type KafkaMessage struct {
+	// Topic the record is published to
+	Topic string
+
+	// Key of the record. Optional.
+	Key []byte
+
+	// Value is the serialised body of the record: JSON, or as serialised by
+	// the codec of the ContentType of the message (see WithContentType)
+	Value []byte
+
+	// Headers of the record. Optional.
+	Headers map[string][]byte
+}
+
+// KafkaMetadata creates the message metadata describing the topic, key and
+// headers of a Kafka record. Header names must not clash with the reserved
+// KafkaTopicMetadataKey and KafkaKeyMetadataKey keys.
+func KafkaMetadata(topic Matcher, key Matcher, headers MapMatcher) MapMatcher {
+	metadata := MapMatcher{}
+
+	for k, v := range headers {
+		metadata[k] = v
+	}
+
+	if topic != nil {
+		metadata[KafkaTopicMetadataKey] = topic
+	}
+
+	if key != nil {
+		metadata[KafkaKeyMetadataKey] = key
+	}
+
+	return metadata
+}
+
+// WithKafkaMetadata specifies the topic, key and headers of the Kafka record
+// carrying the message. It is a convenience wrapper around WithMetadata.
+func (p *Message) WithKafkaMetadata(topic Matcher, key Matcher, headers MapMatcher) *Message {
+	return p.WithMetadata(KafkaMetadata(topic, key, headers))
+}
+
+// KafkaMessageFromPact converts the message Pact sends to a message consumer
+// back into the Kafka record it represents, so that it can be passed through
+// to a consumer handler that operates on records.
+func KafkaMessageFromPact(m Message) (KafkaMessage, error) {
+	value, err := kafkaRecordValue(m)
+	if err != nil {
+		return KafkaMessage{}, fmt.Errorf("unable to convert message content to a Kafka record value: %v", err)
+	}
+
+	record := KafkaMessage{
+		Value:   value,
+		Headers: make(map[string][]byte),
+	}
+
+	for k, v := range m.Metadata {
+		value := metadataValueString(v)
+
+		switch k {
+		case KafkaTopicMetadataKey:
+			record.Topic = value
+		case KafkaKeyMetadataKey:
+			record.Key = []byte(value)
+		default:
+			record.Headers[k] = []byte(value)
+		}
+	}
+
+	return record, nil
+}
+
+// kafkaRecordValue returns the serialised content of the message: the raw
+// content for a ContentType with a codec other than JSON, as the Content has
+// then been decoded to the Type of the message, or else the content as JSON
+func kafkaRecordValue(m Message) ([]byte, error) {
+	if m.ContentType != "" {
+		codec, err := contentCodecFor(m.ContentType)
+		if err != nil {
+			return nil, err
+		}
+
+		if !isJSONCodec(codec) {
+			if raw, ok := m.ContentRaw.([]byte); ok {
+				return raw, nil
+			}
+			return codec.Encode(m.Content)
+		}
+	}
+
+	return json.Marshal(m.Content)
+}
+
+// KafkaMessageConsumer adapts a function accepting a KafkaMessage to a
+// MessageConsumer that can be passed to VerifyMessageConsumer.
+func KafkaMessageConsumer(handler func(KafkaMessage) error) MessageConsumer {
+	return func(m Message) error {
+		record, err := KafkaMessageFromPact(m)
+		if err != nil {
+			return err
+		}
+
+		return handler(record)
+	}
+}
+
+// KafkaMessageHandler adapts a function producing a KafkaMessage to a
+// MessageHandler that can be used during message provider verification.
+// The record value must be valid JSON.
+//
+// NOTE: only the record value is compared with the Pact during verification,
+// the topic, key and headers of the record are not currently verified.
+func KafkaMessageHandler(producer func(Message) (KafkaMessage, error)) MessageHandler {
+	return func(m Message) (interface{}, error) {
+		record, err := producer(m)
+		if err != nil {
+			return nil, err
+		}
+
+		if topic, ok := m.Metadata[KafkaTopicMetadataKey]; ok && record.Topic != metadataValueString(topic) {
+			log.Printf("[WARN] kafka record topic '%s' differs from the expected topic '%s'", record.Topic, metadataValueString(topic))
+		}
+
+		var content interface{}
+		if err = decodeJSON(record.Value, &content, true); err != nil {
+			return nil, fmt.Errorf("unable to parse Kafka record value as JSON: %v", err)
+		}
+
+		return content, nil
+	}
+}
+
+// metadataValueString returns the example value of a metadata entry as a string
+func metadataValueString(m Matcher) string {
+	if m == nil {
+		return ""
+	}
+
+	switch v := m.GetValue().(type) {
+	case string:
+		return v
+	case String:
+		return string(v)
+	case S:
+		return string(v)
+	default:
+		return objectToString(v)
+	}
+}
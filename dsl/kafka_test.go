@@ -0,0 +1,145 @@
+package dsl
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestKafka_KafkaMetadata(t *testing.T) {
+	metadata := KafkaMetadata(String("orders"), Term("order-1", "order-\\d+"), MapMatcher{
+		"content-type": String("application/json"),
+	})
+
+	if metadataValueString(metadata[KafkaTopicMetadataKey]) != "orders" {
+		t.Fatalf("expected topic 'orders' but got '%v'", metadata[KafkaTopicMetadataKey])
+	}
+	if metadataValueString(metadata[KafkaKeyMetadataKey]) != "order-1" {
+		t.Fatalf("expected key 'order-1' but got '%v'", metadata[KafkaKeyMetadataKey])
+	}
+	if metadataValueString(metadata["content-type"]) != "application/json" {
+		t.Fatalf("expected header 'content-type' to be preserved but got '%v'", metadata["content-type"])
+	}
+}
+
+func TestKafka_KafkaMetadataOptionalKey(t *testing.T) {
+	metadata := KafkaMetadata(String("orders"), nil, nil)
+
+	if _, ok := metadata[KafkaKeyMetadataKey]; ok {
+		t.Fatal("expected no key metadata to be set")
+	}
+	if len(metadata) != 1 {
+		t.Fatalf("expected 1 metadata entry but got %d", len(metadata))
+	}
+}
+
+func TestKafka_KafkaMessageFromPact(t *testing.T) {
+	m := (&Message{}).
+		ExpectsToReceive("an order").
+		WithKafkaMetadata(String("orders"), String("order-1"), MapMatcher{
+			"trace-id": String("abc"),
+		}).
+		WithContent(map[string]interface{}{
+			"id": 1,
+		})
+
+	record, err := KafkaMessageFromPact(*m)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	if record.Topic != "orders" {
+		t.Fatalf("expected topic 'orders' but got '%s'", record.Topic)
+	}
+	if string(record.Key) != "order-1" {
+		t.Fatalf("expected key 'order-1' but got '%s'", record.Key)
+	}
+	if string(record.Headers["trace-id"]) != "abc" {
+		t.Fatalf("expected header 'trace-id' to be 'abc' but got '%s'", record.Headers["trace-id"])
+	}
+	if string(record.Value) != `{"id":1}` {
+		t.Fatalf("expected value '{\"id\":1}' but got '%s'", record.Value)
+	}
+}
+
+func TestKafka_KafkaMessageFromPactCodec(t *testing.T) {
+	m := Message{
+		Content:     []string{"a", "b"},
+		ContentRaw:  []byte{0xff, 0xfe, 'a'},
+		ContentType: "application/x-binary",
+	}
+
+	record, err := KafkaMessageFromPact(m)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if string(record.Value) != string([]byte{0xff, 0xfe, 'a'}) {
+		t.Fatalf("expected the raw content as the value but got '%s'", record.Value)
+	}
+
+	m.ContentRaw = nil
+	m.ContentType = "text/csv"
+	if record, err = KafkaMessageFromPact(m); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if string(record.Value) != "a,b" {
+		t.Fatalf("expected the content to be encoded by the codec but got '%s'", record.Value)
+	}
+}
+
+func TestKafka_KafkaMessageConsumer(t *testing.T) {
+	var received KafkaMessage
+	consumer := KafkaMessageConsumer(func(k KafkaMessage) error {
+		received = k
+		return nil
+	})
+
+	err := consumer(Message{
+		Content:  map[string]string{"foo": "bar"},
+		Metadata: MapMatcher{KafkaTopicMetadataKey: String("foos")},
+	})
+
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if received.Topic != "foos" {
+		t.Fatalf("expected topic 'foos' but got '%s'", received.Topic)
+	}
+}
+
+func TestKafka_KafkaMessageHandler(t *testing.T) {
+	handler := KafkaMessageHandler(func(m Message) (KafkaMessage, error) {
+		return KafkaMessage{
+			Topic: "orders",
+			Value: []byte(`{"id":1}`),
+		}, nil
+	})
+
+	res, err := handler(Message{})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	content, ok := res.(map[string]interface{})
+	if !ok || content["id"] != json.Number("1") {
+		t.Fatalf("expected record value to be decoded but got '%v'", res)
+	}
+}
+
+func TestKafka_KafkaMessageHandlerFail(t *testing.T) {
+	handler := KafkaMessageHandler(func(m Message) (KafkaMessage, error) {
+		return KafkaMessage{Value: []byte(`not json`)}, nil
+	})
+
+	if _, err := handler(Message{}); err == nil {
+		t.Fatal("expected error for a non JSON record value")
+	}
+
+	handler = KafkaMessageHandler(func(m Message) (KafkaMessage, error) {
+		return KafkaMessage{}, errors.New("unable to produce record")
+	})
+
+	if _, err := handler(Message{}); err == nil {
+		t.Fatal("expected producer error to be returned")
+	}
+}
@@ -0,0 +1,102 @@
+package dsl
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+// checkBodySizes returns an error if the request or response body of the
+// interaction is larger than MaxBodySize
+func (p *Pact) checkBodySizes(i *Interaction) error {
+	if err := p.checkBodySize(i.Request.Body); err != nil {
+		return fmt.Errorf("request body of interaction '%s' %w", i.Description, err)
+	}
+	if err := p.checkBodySize(i.Response.Body); err != nil {
+		return fmt.Errorf("response body of interaction '%s' %w", i.Description, err)
+	}
+
+	return nil
+}
+
+// checkBodySize returns an error if body, as serialised to the pact file, is
+// larger than MaxBodySize
+func (p *Pact) checkBodySize(body interface{}) error {
+	if p.MaxBodySize <= 0 || body == nil {
+		return nil
+	}
+
+	size, err := bodySize(body)
+	if err != nil {
+		return err
+	}
+	if size > p.MaxBodySize {
+		return types.NewError(types.ErrLimitExceeded, fmt.Errorf("is %d bytes, more than the MaxBodySize of %d bytes. Consider matching on a smaller, representative example", size, p.MaxBodySize))
+	}
+
+	return nil
+}
+
+// bodySize is the size of body when serialised, including any matchers.
+// String bodies (e.g. from fixtures) are counted as is.
+func bodySize(body interface{}) (int, error) {
+	switch b := body.(type) {
+	case string:
+		return len(b), nil
+	case []byte:
+		return len(b), nil
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return 0, fmt.Errorf("could not be serialised: %v", err)
+	}
+
+	return len(data), nil
+}
+
+// checkInteractionCount returns an error if a pact file has more than max
+// interactions (or messages)
+func checkInteractionCount(count int, max int) error {
+	if max > 0 && count > max {
+		return types.NewError(types.ErrLimitExceeded, fmt.Errorf("the pact has %d interactions, more than the MaxInteractions of %d", count, max))
+	}
+
+	return nil
+}
+
+// restoreOverLimit puts the pact file at path back as it was before it was
+// written, or removes it if there was none, if err is that the written pact
+// exceeds a limit, so that it isn't published by mistake
+func restoreOverLimit(path string, previous []byte, existed bool, err error) error {
+	if !errors.Is(err, types.ErrLimitExceeded) {
+		return err
+	}
+
+	if existed {
+		if restoreErr := writeFileAtomic(path, previous, 0644); restoreErr != nil {
+			return fmt.Errorf("%w (and the pact file could not be restored: %v)", err, restoreErr)
+		}
+		return err
+	}
+
+	if removeErr := os.Remove(path); removeErr != nil && !os.IsNotExist(removeErr) {
+		return fmt.Errorf("%w (and the pact file could not be removed: %v)", err, removeErr)
+	}
+
+	return err
+}
+
+// readPactFile returns the content of the pact file at path, if it exists
+func readPactFile(path string) ([]byte, bool, error) {
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+
+	return content, err == nil, err
+}
@@ -0,0 +1,107 @@
+package dsl
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+func TestLimits_checkBodySizes(t *testing.T) {
+	p := &Pact{MaxBodySize: 20}
+
+	small := (&Interaction{Description: "small"}).
+		WithRequest(Request{Body: map[string]string{"name": "billy"}}).
+		WillRespondWith(Response{Body: "hello"})
+	if err := p.checkBodySizes(small); err != nil {
+		t.Fatalf("expected the bodies to be within the limit: %v", err)
+	}
+
+	large := (&Interaction{Description: "large"}).
+		WithRequest(Request{}).
+		WillRespondWith(Response{Body: map[string]interface{}{"users": EachLike(map[string]string{"name": "billy"}, 1)}})
+	err := p.checkBodySizes(large)
+	if err == nil || !strings.Contains(err.Error(), "response body of interaction 'large' is") || !strings.Contains(err.Error(), "MaxBodySize of 20 bytes") {
+		t.Fatalf("expected the response body to exceed the limit, got '%v'", err)
+	}
+
+	if err = (&Pact{}).checkBodySizes(large); err != nil {
+		t.Fatalf("expected no limit by default: %v", err)
+	}
+}
+
+func TestLimits_VerifyBodySize(t *testing.T) {
+	ms := setupMockServer(true, t)
+	defer ms.Close()
+
+	pact := &Pact{Server: &types.MockServer{Port: getPort(ms.URL)}, MaxBodySize: 10}
+	pact.Interactions = []*Interaction{
+		(&Interaction{Description: "large"}).WithRequest(Request{Method: "POST", Body: strings.Repeat("a", 11)}),
+	}
+
+	if err := pact.Verify(func() error { return nil }); !errors.Is(err, types.ErrLimitExceeded) || !errors.Is(err, types.ErrInvalidRequest) {
+		t.Fatalf("expected a limit exceeded error but got '%v'", err)
+	}
+	if len(pact.Interactions) != 0 {
+		t.Fatalf("expected the interactions to be cleaned up but got %d", len(pact.Interactions))
+	}
+
+	pact.Interactions = []*Interaction{
+		(&Interaction{Description: "small"}).WithRequest(Request{Method: "POST", Body: "a"}),
+	}
+	if err := pact.Verify(func() error { return nil }); err != nil {
+		t.Fatalf("expected the next Verify to be unaffected but got '%v'", err)
+	}
+}
+
+func TestLimits_MaxInteractions(t *testing.T) {
+	pact := []byte(`{"interactions":[{"description":"a"},{"description":"b"}]}`)
+
+	if _, err := (&pactRewriter{maxInteractions: 2}).rewrite(pact); err != nil {
+		t.Fatalf("expected the interactions to be within the limit: %v", err)
+	}
+
+	_, err := (&pactRewriter{maxInteractions: 1}).rewrite(pact)
+	if !errors.Is(err, types.ErrLimitExceeded) || err.Error() != "the pact has 2 interactions, more than the MaxInteractions of 1" {
+		t.Fatalf("expected the interactions to exceed the limit, got '%v'", err)
+	}
+}
+
+func TestLimits_WritePactMaxInteractions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pact-go-limits")
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "consumer-provider.json")
+	pact := &Pact{Consumer: "consumer", Provider: "provider", PactDir: dir, MaxInteractions: 1}
+	write := func(interactions string) func() error {
+		return func() error {
+			return ioutil.WriteFile(path, []byte(`{"consumer":{"name":"consumer"},"interactions":`+interactions+`}`), 0644)
+		}
+	}
+
+	if err = pact.writePactFile(write(`[{"description":"a"},{"description":"b"}]`)); !errors.Is(err, types.ErrLimitExceeded) {
+		t.Fatalf("expected the pact to exceed the limit but got '%v'", err)
+	}
+	if _, err = os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected the pact over the limit to be removed but got %v", err)
+	}
+
+	if err = pact.writePactFile(write(`[{"description":"a"}]`)); err != nil {
+		t.Fatal("Error:", err)
+	}
+	previous, _ := ioutil.ReadFile(path)
+
+	if err = pact.writePactFile(write(`[{"description":"a"},{"description":"b"}]`)); !errors.Is(err, types.ErrLimitExceeded) {
+		t.Fatalf("expected the pact to exceed the limit but got '%v'", err)
+	}
+	if restored, _ := ioutil.ReadFile(path); string(restored) != string(previous) {
+		t.Fatalf("expected the previous pact to be restored but got %s", restored)
+	}
+}
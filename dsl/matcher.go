@@ -4,8 +4,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"math"
 	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -19,6 +21,15 @@ const (
 	timestamp   = `^([\+-]?\d{4}(?!\d{2}\b))((-?)((0[1-9]|1[0-2])(\3([12]\d|0[1-9]|3[01]))?|W([0-4]\d|5[0-2])(-?[1-7])?|(00[1-9]|0[1-9]\d|[12]\d{2}|3([0-5]\d|6[1-6])))([T\s]((([01]\d|2[0-3])((:?)[0-5]\d)?|24\:?00)([\.,]\d+(?!:))?)?(\17[0-5]\d([\.,]\d+)?)?([zZ]|([\+-])([01]\d|2[0-3]):?([0-5]\d)?)?)?)?$`
 	date        = `^([\+-]?\d{4}(?!\d{2}\b))((-?)((0[1-9]|1[0-2])(\3([12]\d|0[1-9]|3[01]))?|W([0-4]\d|5[0-2])(-?[1-7])?|(00[1-9]|0[1-9]\d|[12]\d{2}|3([0-5]\d|6[1-6])))?)`
 	timeRegex   = `^(T\d\d:\d\d(:\d\d)?(\.\d+)?(([+-]\d\d:\d\d)|Z)?)?$`
+	ipv4Address = `^(25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)(\.(25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)){3}$`
+	duration    = `^P(` + durationDate + `(` + durationTime + `)?|` + durationTime + `)$`
+	semver      = `^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(-((0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(\.(0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?(\+([0-9a-zA-Z-]+(\.[0-9a-zA-Z-]+)*))?$`
+	email       = `^[a-zA-Z0-9.!#$%&'*+/=?^_{|}~-]+@[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(\.[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$`
+	base64Regex = `^([A-Za-z0-9+/]{4})*([A-Za-z0-9+/]{4}|[A-Za-z0-9+/]{2}==|[A-Za-z0-9+/]{3}=)$`
+
+	// The date and time parts of a duration, each with at least one component
+	durationDate = `(\d+Y(\d+M)?(\d+W)?(\d+D)?|\d+M(\d+W)?(\d+D)?|\d+W(\d+D)?|\d+D)`
+	durationTime = `T(\d+H(\d+M)?(\d+([.,]\d+)?S)?|\d+M(\d+([.,]\d+)?S)?|\d+([.,]\d+)?S)`
 )
 
 var timeExample = time.Date(2000, 2, 1, 12, 30, 0, 0, time.UTC)
@@ -29,6 +40,7 @@ var exampleRegex = regexp.MustCompile(`^example=(.*)`)
 type eachLike struct {
 	Contents interface{} `json:"contents"`
 	Min      int         `json:"min"`
+	Max      int         `json:"max,omitempty"`
 }
 
 func (m eachLike) GetValue() interface{} {
@@ -107,6 +119,30 @@ func EachLike(content interface{}, minRequired int) Matcher {
 	}
 }
 
+// EachLikeBetween specifies that a given element in a JSON body can be repeated
+// between "minRequired" and "maxAllowed" times (inclusive). "minRequired"
+// needs to be 1 or greater, and may not exceed "maxAllowed".
+//
+// NOTE: the maximum is written to the contract alongside the minimum, and is
+// only enforced by versions of the Pact CLI tools that support it.
+func EachLikeBetween(content interface{}, minRequired int, maxAllowed int) Matcher {
+	if minRequired < 1 || maxAllowed < minRequired {
+		panic(fmt.Sprintf("EachLikeBetween: invalid bounds min=%d, max=%d. min must be >= 1 and <= max", minRequired, maxAllowed))
+	}
+
+	return eachLike{
+		Contents: content,
+		Min:      minRequired,
+		Max:      maxAllowed,
+	}
+}
+
+// EachLikeExactly specifies that a given element in a JSON body must be
+// repeated exactly "length" times. "length" needs to be 1 or greater.
+func EachLikeExactly(content interface{}, length int) Matcher {
+	return EachLikeBetween(content, length, length)
+}
+
 // Like specifies that the given content type should be matched based
 // on type (int, string etc.) instead of a verbatim match.
 func Like(content interface{}) Matcher {
@@ -151,14 +187,73 @@ func IPAddress() Matcher {
 // IPv4Address matches valid IPv4 addresses.
 var IPv4Address = IPAddress
 
-// IPv6Address defines a matcher that accepts IP addresses.
+// StrictIPv4Address defines a matcher that accepts IPv4 addresses in dotted
+// decimal notation, with each octet in the range 0-255.
+// Regex: ^(25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)(\.(25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)){3}$
+func StrictIPv4Address() Matcher {
+	return Regex("127.0.0.1", ipv4Address)
+}
+
+// IPv6Address defines a matcher that accepts IPv6 addresses, including
+// IPv4-mapped addresses.
 func IPv6Address() Matcher {
-	return Regex("::ffff:192.0.2.128", ipAddress)
+	return Regex("::ffff:192.0.2.128", ipv6Address)
 }
 
 // Decimal defines a matcher that accepts any decimal value.
 func Decimal() Matcher {
-	return Like(42.0)
+	return Like(decimal(42.0))
+}
+
+// StrictDecimal defines a matcher that matches decimal values by type, using
+// "example" in mock responses. The example is always written with a decimal
+// point (e.g. 2.0), so mock responses never return an integer, and numeric
+// strings (e.g. "2.0") will not match. The pact only has a type rule, though,
+// so whether an integer (e.g. 2) matches is up to the verifier: there is no
+// rule to accept decimals only.
+func StrictDecimal(example float64) Matcher {
+	return Like(decimal(example))
+}
+
+// DecimalString defines a matcher that accepts decimals encoded as strings
+// (e.g. monetary amounts) with at most "precision" significant digits and
+// exactly "scale" digits after the decimal point, e.g. a precision of 5 and a
+// scale of 2 accepts "123.45" and "-1.00", but not "1.5" or "1234.56".
+func DecimalString(precision int, scale int) Matcher {
+	if precision < 1 || scale < 0 || scale > precision {
+		panic(fmt.Sprintf("DecimalString: invalid precision=%d, scale=%d. precision must be >= 1 and scale between 0 and precision", precision, scale))
+	}
+
+	integerDigits := precision - scale
+	example := "0"
+	integerPart := "0"
+	if integerDigits > 0 {
+		example = "1"
+		integerPart = fmt.Sprintf(`\d{1,%d}`, integerDigits)
+	}
+
+	if scale == 0 {
+		return Regex(example, fmt.Sprintf(`^-?%s$`, integerPart))
+	}
+
+	return Regex(example+"."+strings.Repeat("0", scale), fmt.Sprintf(`^-?%s\.\d{%d}$`, integerPart, scale))
+}
+
+// decimal is a float that always serialises with a decimal point, so that the
+// CLI tools treat the example as a decimal rather than an integer when matching
+type decimal float64
+
+func (d decimal) MarshalJSON() ([]byte, error) {
+	if math.IsNaN(float64(d)) || math.IsInf(float64(d), 0) {
+		return nil, fmt.Errorf("unsupported decimal value: %v", float64(d))
+	}
+
+	s := strconv.FormatFloat(float64(d), 'f', -1, 64)
+	if !strings.Contains(s, ".") {
+		s = s + ".0"
+	}
+
+	return []byte(s), nil
 }
 
 // Timestamp matches a pattern corresponding to the ISO_DATETIME_FORMAT, which
@@ -184,6 +279,32 @@ func UUID() Matcher {
 	return Regex("fc763eba-0905-41c5-a27f-3934ab26786c", uuid)
 }
 
+// Duration defines a matcher that accepts ISO8601 durations e.g. "P1DT2H30M".
+// Only the last (seconds) component may be fractional, and there must be at
+// least one component, and at least one after the "T" if given.
+func Duration() Matcher {
+	return Regex("P1DT2H30M", duration)
+}
+
+// SemVer defines a matcher that accepts semantic versions (see https://semver.org)
+// including pre-release and build metadata e.g. "1.2.3-beta.1+build.42".
+func SemVer() Matcher {
+	return Regex("1.2.3", semver)
+}
+
+// Email defines a matcher that accepts email addresses, as per the HTML5
+// definition of a valid email address.
+func Email() Matcher {
+	return Regex("jane.doe@example.com", email)
+}
+
+// Base64 defines a matcher that accepts standard (padded) base64 encoded strings.
+// The empty string is not accepted.
+// Regex: ^([A-Za-z0-9+/]{4})*([A-Za-z0-9+/]{4}|[A-Za-z0-9+/]{2}==|[A-Za-z0-9+/]{3}=)$
+func Base64() Matcher {
+	return Regex("cGFjdA==", base64Regex)
+}
+
 // Regex is a more appropriately named alias for the "Term" matcher
 var Regex = Term
 
@@ -284,6 +405,7 @@ func objectToString(obj interface{}) string {
 //
 // Supported Tag Formats
 // Minimum Slice Size: `pact:"min=2"`
+// Bounded Slice Size: `pact:"min=1,max=10"`
 // String RegEx:       `pact:"example=2000-01-01,regex=^\\d{4}-\\d{2}-\\d{2}$"`
 func Match(src interface{}) Matcher {
 	return match(reflect.TypeOf(src), getDefaults())
@@ -296,6 +418,9 @@ func match(srcType reflect.Type, params params) Matcher {
 	case reflect.Ptr:
 		return match(srcType.Elem(), params)
 	case reflect.Slice, reflect.Array:
+		if params.slice.max > 0 {
+			return EachLikeBetween(match(srcType.Elem(), getDefaults()), params.slice.min, params.slice.max)
+		}
 		return EachLike(match(srcType.Elem(), getDefaults()), params.slice.min)
 	case reflect.Struct:
 		result := StructMatcher{}
@@ -378,6 +503,7 @@ type boolParams struct {
 
 type sliceParams struct {
 	min int
+	max int
 }
 
 type stringParams struct {
@@ -397,6 +523,7 @@ func getDefaults() params {
 // pluckParams converts a 'pact' tag into a pactParams struct
 // Supported Tag Formats
 // Minimum Slice Size: `pact:"min=2"`
+// Bounded Slice Size: `pact:"min=1,max=10"`
 // String RegEx:       `pact:"example=2000-01-01,regex=^\\d{4}-\\d{2}-\\d{2}$"`
 func pluckParams(srcType reflect.Type, pactTag string) params {
 	params := getDefaults()
@@ -420,8 +547,19 @@ func pluckParams(srcType reflect.Type, pactTag string) params {
 			triggerInvalidPactTagPanic(pactTag, err)
 		}
 	case reflect.Slice:
-		if _, err := fmt.Sscanf(pactTag, "min=%d", &params.slice.min); err != nil {
-			triggerInvalidPactTagPanic(pactTag, err)
+		for _, option := range strings.Split(pactTag, ",") {
+			var err error
+			if strings.HasPrefix(option, "max=") {
+				_, err = fmt.Sscanf(option, "max=%d", &params.slice.max)
+			} else {
+				_, err = fmt.Sscanf(option, "min=%d", &params.slice.min)
+			}
+			if err != nil {
+				triggerInvalidPactTagPanic(pactTag, err)
+			}
+		}
+		if params.slice.max > 0 && params.slice.max < params.slice.min {
+			triggerInvalidPactTagPanic(pactTag, fmt.Errorf("invalid format: max must not be less than min"))
 		}
 	case reflect.String:
 		if fullRegex.Match([]byte(pactTag)) {
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"math"
 	"reflect"
 	"regexp"
 	"testing"
@@ -187,6 +188,49 @@ func TestMatcher_EachLikeArray(t *testing.T) {
 	}
 }
 
+func TestMatcher_EachLikeBetween(t *testing.T) {
+	expected := formatJSON(`
+		{
+		  "json_class": "Pact::ArrayLike",
+		  "contents": "someword",
+		  "min": 2,
+		  "max": 5
+		}`)
+
+	match := formatJSON(EachLikeBetween("someword", 2, 5))
+	if expected != match {
+		t.Fatalf("Expected Term to match. '%s' != '%s'", expected, match)
+	}
+}
+
+func TestMatcher_EachLikeExactly(t *testing.T) {
+	expected := formatJSON(`
+		{
+		  "json_class": "Pact::ArrayLike",
+		  "contents": 42,
+		  "min": 3,
+		  "max": 3
+		}`)
+
+	match := formatJSON(EachLikeExactly(42, 3))
+	if expected != match {
+		t.Fatalf("Expected Term to match. '%s' != '%s'", expected, match)
+	}
+}
+
+func TestMatcher_EachLikeBetweenInvalidBounds(t *testing.T) {
+	for _, bounds := range [][]int{{0, 1}, {3, 2}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("Expected EachLikeBetween to panic with bounds %v", bounds)
+				}
+			}()
+			EachLikeBetween(42, bounds[0], bounds[1])
+		}()
+	}
+}
+
 func TestMatcher_EachLikeGetValue(t *testing.T) {
 	expected := "42"
 	match := EachLike("42", 1).GetValue()
@@ -489,6 +533,69 @@ func TestMatcher_SugarMatchers(t *testing.T) {
 	}
 }
 
+func TestMatcher_StrictNumbers(t *testing.T) {
+	expected := formatJSON(`
+		{
+		  "json_class": "Pact::SomethingLike",
+		  "contents": 2.0
+		}`)
+
+	if match := formatJSON(StrictDecimal(2)); expected != match {
+		t.Fatalf("Expected StrictDecimal to keep its decimal point. '%s' != '%s'", expected, match)
+	}
+
+	if _, err := json.Marshal(StrictDecimal(math.NaN())); err == nil {
+		t.Fatal("Expected NaN decimal to fail to serialise")
+	}
+}
+
+func TestMatcher_DecimalString(t *testing.T) {
+	tests := []struct {
+		precision int
+		scale     int
+		example   string
+		valid     []string
+		invalid   []string
+	}{
+		{5, 2, "1.00", []string{"123.45", "-1.00", "0.99"}, []string{"1.5", "1234.56", "12", "1.234"}},
+		{3, 0, "1", []string{"123", "-7"}, []string{"1234", "1.0"}},
+		{2, 2, "0.00", []string{"0.25", "-0.10"}, []string{"1.25", "0.1"}},
+	}
+
+	for _, tt := range tests {
+		m := DecimalString(tt.precision, tt.scale).(term)
+
+		if m.Data.Generate != tt.example {
+			t.Fatalf("Expected example '%s' but got '%v'", tt.example, m.Data.Generate)
+		}
+
+		r := regexp.MustCompile(m.Data.Matcher.Regex.(string))
+		if !r.MatchString(tt.example) {
+			t.Fatalf("Expected example '%s' to match '%s'", tt.example, r)
+		}
+		for _, v := range tt.valid {
+			if !r.MatchString(v) {
+				t.Fatalf("Expected '%s' to match '%s'", v, r)
+			}
+		}
+		for _, v := range tt.invalid {
+			if r.MatchString(v) {
+				t.Fatalf("Expected '%s' not to match '%s'", v, r)
+			}
+		}
+	}
+}
+
+func TestMatcher_DecimalStringInvalid(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Expected DecimalString to panic when scale exceeds precision")
+		}
+	}()
+
+	DecimalString(2, 3)
+}
+
 func ExampleLike_string() {
 	match := Like("myspecialvalue")
 	fmt.Println(formatJSON(match))
@@ -563,6 +670,9 @@ func TestMatch(t *testing.T) {
 	type wordsDTO struct {
 		Words []string `json:"words" pact:"min=2"`
 	}
+	type boundedWordsDTO struct {
+		Words []string `json:"words" pact:"min=1,max=3"`
+	}
 	type boolDTO struct {
 		Boolean bool `json:"boolean" pact:"example=true"`
 	}
@@ -643,6 +753,15 @@ func TestMatch(t *testing.T) {
 				"words": EachLike(Like("string"), 2),
 			},
 		},
+		{
+			name: "recursive case - struct with bounded slice tag",
+			args: args{
+				src: boundedWordsDTO{},
+			},
+			want: StructMatcher{
+				"words": EachLikeBetween(Like("string"), 1, 3),
+			},
+		},
 		{
 			name: "recursive case - struct with bool",
 			args: args{
@@ -862,6 +981,39 @@ func Test_pluckParams(t *testing.T) {
 			},
 			want: getDefaults(),
 		},
+		{
+			name: "expected use - bounded slice tag",
+			args: args{
+				srcType: reflect.TypeOf([]string{}),
+				pactTag: "min=1,max=10",
+			},
+			want: params{
+				slice: sliceParams{
+					min: 1,
+					max: 10,
+				},
+				str: stringParams{
+					example: getDefaults().str.example,
+					regEx:   getDefaults().str.regEx,
+				},
+			},
+		},
+		{
+			name: "invalid slice tag - max less than min",
+			args: args{
+				srcType: reflect.TypeOf([]string{}),
+				pactTag: "min=3,max=2",
+			},
+			wantPanic: true,
+		},
+		{
+			name: "invalid slice tag - max typo non-number",
+			args: args{
+				srcType: reflect.TypeOf([]string{}),
+				pactTag: "min=1,max=a",
+			},
+			wantPanic: true,
+		},
 		{
 			name: "invalid slice tag - no min",
 			args: args{
@@ -1024,3 +1176,84 @@ func Test_pluckParams(t *testing.T) {
 		})
 	}
 }
+
+func TestMatcher_SemanticMatchers(t *testing.T) {
+	tests := []struct {
+		name    string
+		matcher Matcher
+		regex   string
+		valid   []string
+		invalid []string
+	}{
+		{
+			name:    "Duration",
+			matcher: Duration(),
+			regex:   duration,
+			valid:   []string{"P1Y2M3DT4H5M6S", "PT0.5S", "P3W", "PT36H", "P1M", "PT1M", "P1DT1S", "P2W3D"},
+			invalid: []string{"1DT2H", "P1H", "PT1.5M", "P-1D", "P", "PT", "P1DT", ""},
+		},
+		{
+			name:    "SemVer",
+			matcher: SemVer(),
+			regex:   semver,
+			valid:   []string{"0.0.1", "10.20.30", "1.0.0-alpha.1", "1.0.0+20130313144700", "1.0.0-beta+exp.sha.5114f85"},
+			invalid: []string{"1.2", "01.2.3", "1.2.3-", "v1.2.3", "1.2.3.4"},
+		},
+		{
+			name:    "StrictIPv4Address",
+			matcher: StrictIPv4Address(),
+			regex:   ipv4Address,
+			valid:   []string{"0.0.0.0", "192.168.1.254", "255.255.255.255"},
+			invalid: []string{"256.1.1.1", "1.2.3", "01.2.3.4", "1.2.3.4.5"},
+		},
+		{
+			name:    "Email",
+			matcher: Email(),
+			regex:   email,
+			valid:   []string{"a@b", "first.last+tag@sub.example.co.uk"},
+			invalid: []string{"plainaddress", "@example.com", "a@-example.com", "a b@example.com"},
+		},
+		{
+			name:    "Base64",
+			matcher: Base64(),
+			regex:   base64Regex,
+			valid:   []string{"Zg==", "Zm8=", "Zm9v", "aGVsbG8gd29ybGQ="},
+			invalid: []string{"", "Zg", "Zm9v=", "Z===", "Zm9v!"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := regexp.MustCompile(tt.regex)
+
+			example := getMatcherValue(tt.matcher).(string)
+			if !r.MatchString(example) {
+				t.Errorf("expected example '%s' to match its own regex", example)
+			}
+
+			for _, v := range tt.valid {
+				if !r.MatchString(v) {
+					t.Errorf("expected '%s' to match", v)
+				}
+			}
+
+			for _, v := range tt.invalid {
+				if r.MatchString(v) {
+					t.Errorf("expected '%s' not to match", v)
+				}
+			}
+		})
+	}
+}
+
+func TestMatcher_IPv6Address(t *testing.T) {
+	var m term
+	err := json.Unmarshal([]byte(objectToString(IPv6Address())), &m)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	if m.Data.Matcher.Regex != ipv6Address {
+		t.Fatalf("expected IPv6Address to use the IPv6 regex, got '%v'", m.Data.Matcher.Regex)
+	}
+}
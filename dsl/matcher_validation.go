@@ -0,0 +1,166 @@
+package dsl
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+)
+
+// validateMatchers returns an error if the example of any matcher in the
+// interaction does not satisfy the matcher itself, e.g. a Term whose example
+// does not match its regex, which would otherwise produce a pact that fails
+// confusingly during provider verification
+func (i *Interaction) validateMatchers() error {
+	request, err := serialisedParts(i.Request.Path, i.Request.Query, i.Request.Headers, i.Request.Body)
+	if err != nil {
+		return err
+	}
+	response, err := serialisedParts(nil, nil, i.Response.Headers, i.Response.Body)
+	if err != nil {
+		return err
+	}
+
+	for _, part := range request {
+		if err = validateMatcherExamples(part.value, "request "+part.path); err != nil {
+			return err
+		}
+	}
+	for _, part := range response {
+		if err = validateMatcherExamples(part.value, "response "+part.path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateMatcherExamples checks the examples of any serialised matchers in v
+func validateMatcherExamples(v interface{}, path string) error {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		switch v["json_class"] {
+		case "Pact::SomethingLike":
+			return validateMatcherExamples(v["contents"], path)
+		case "Pact::ArrayLike":
+			if v["contents"] == nil {
+				return fmt.Errorf("%s: EachLike requires an example of the items", path)
+			}
+			minRequired, _ := v["min"].(json.Number)
+			min, _ := minRequired.Int64()
+			if max, ok := v["max"].(json.Number); ok {
+				if n, _ := max.Int64(); n < min {
+					return fmt.Errorf("%s: EachLike requires at least %d items, but allows at most %d", path, min, n)
+				}
+			}
+			return validateMatcherExamples(v["contents"], path+"[*]")
+		case "Pact::Term":
+			return validateTermExample(v, path)
+		case optionalClass:
+			return validateMatcherExamples(v["contents"], path)
+		case jsonStringClass:
+			return validateMatcherExamples(v["contents"], path+"(json)")
+		}
+
+		for k, field := range v {
+			if err := validateMatcherExamples(field, jsonPathField(path, k)); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for n, item := range v {
+			if err := validateMatcherExamples(item, fmt.Sprintf("%s[%d]", path, n)); err != nil {
+				return err
+			}
+		}
+		return validateSiblingMatchers(v, path)
+	}
+
+	return nil
+}
+
+// validateSiblingMatchers checks that the matchers among the items of an array
+// agree on the JSON type of their examples, e.g. not Like(1) alongside
+// Like("1"), as one of them is then bound to fail against the provider. Items
+// without a matcher, or with a null example, are not checked.
+func validateSiblingMatchers(items []interface{}, path string) error {
+	first, firstType := -1, ""
+	for n, item := range items {
+		matcher, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		class, _ := matcher["json_class"].(string)
+		if class == "" {
+			continue
+		}
+		itemType := matcherExampleType(matcher)
+		if itemType == "" {
+			continue
+		}
+
+		if first < 0 {
+			first, firstType = n, itemType
+			continue
+		}
+		if itemType != firstType {
+			return fmt.Errorf("%s[%d]: the example of the matcher is %s, but the matcher of its sibling item %d expects %s", path, n, itemType, first, firstType)
+		}
+	}
+
+	return nil
+}
+
+// matcherExampleType returns the JSON type of the example of a serialised
+// matcher e.g. "a number", or "" if it has no type
+func matcherExampleType(v interface{}) string {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		switch v["json_class"] {
+		case "Pact::SomethingLike", optionalClass:
+			return matcherExampleType(v["contents"])
+		case "Pact::ArrayLike":
+			return "an array"
+		case "Pact::Term", jsonStringClass:
+			return "a string"
+		}
+		return "an object"
+	case []interface{}:
+		return "an array"
+	case string:
+		return "a string"
+	case json.Number, float64:
+		return "a number"
+	case bool:
+		return "a boolean"
+	}
+
+	return ""
+}
+
+// validateTermExample checks that the example of a serialised Term matches its
+// regex. Regexes that are not valid in Go (e.g. Ruby specific syntax) are left
+// to the Mock Service. The regex is compiled in multi-line mode, as in Ruby ^
+// and $ match at the start and end of each line rather than of the string.
+func validateTermExample(v map[string]interface{}, path string) error {
+	data, _ := v["data"].(map[string]interface{})
+	matcher, _ := data["matcher"].(map[string]interface{})
+	regex, ok := matcher["s"].(string)
+	if !ok {
+		return nil
+	}
+
+	r, err := regexp.Compile("(?m)" + regex)
+	if err != nil {
+		return nil
+	}
+
+	example, ok := data["generate"].(string)
+	if !ok {
+		return fmt.Errorf("%s: the example %v of the regex '%s' must be a string", path, data["generate"], regex)
+	}
+	if !r.MatchString(example) {
+		return fmt.Errorf("%s: the example '%s' does not match the regex '%s'", path, example, regex)
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package dsl
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMatcherValidation_Valid(t *testing.T) {
+	i := (&Interaction{}).
+		UponReceiving("A request for users").
+		WithRequest(Request{
+			Method: "GET",
+			Path:   Term("/users/1", `/users/\d+`),
+			Query:  MapMatcher{"since": Date()},
+			Headers: MapMatcher{
+				"Accept": Term("application/json", `application/.*json`),
+			},
+		}).
+		WillRespondWith(Response{
+			Status: 200,
+			Body: map[string]interface{}{
+				"users": EachLikeBetween(map[string]interface{}{
+					"id":      UUID(),
+					"ip":      IPAddress(),
+					"created": Timestamp(),
+					"amount":  DecimalString(5, 2),
+					"email":   Email(),
+				}, 1, 10),
+				"ruby":      Term("abc", `\Aabc\Z`),
+				"multiline": Term("Dear Billy,\n42", `^\d+$`),
+				"pair":      []interface{}{Like(1), Integer()},
+				"tuple":     []interface{}{Like(1), "one", nil},
+			},
+		})
+
+	if err := i.validate(); err != nil {
+		t.Fatal("Error:", err)
+	}
+}
+
+func TestMatcherValidation_Invalid(t *testing.T) {
+	tests := map[string]struct {
+		request  Request
+		response Response
+		want     string
+	}{
+		"path": {
+			request: Request{Method: "GET", Path: Term("/users/abc", `/users/\d+`)},
+			want:    `request $.path: the example '/users/abc' does not match the regex '/users/\d+'`,
+		},
+		"header": {
+			request: Request{Method: "GET", Path: String("/"), Headers: MapMatcher{"Accept": Term("text/html", "json")}},
+			want:    "request $.headers.Accept: the example 'text/html'",
+		},
+		"body": {
+			request: Request{Method: "GET", Path: String("/")},
+			response: Response{Body: map[string]interface{}{
+				"users": EachLike(map[string]interface{}{"id": Like(Term("abc", `^\d+$`))}, 1),
+			}},
+			want: "response $.body.users[*].id: the example 'abc'",
+		},
+		"siblings": {
+			request:  Request{Method: "GET", Path: String("/")},
+			response: Response{Body: map[string]interface{}{"ids": []interface{}{Like(1), Like("2")}}},
+			want:     "response $.body.ids[1]: the example of the matcher is a string, but the matcher of its sibling item 0 expects a number",
+		},
+		"sibling term": {
+			request:  Request{Method: "GET", Path: String("/")},
+			response: Response{Body: EachLike([]interface{}{Term("1", `\d+`), Like(2)}, 1)},
+			want:     "response $.body[*][1]: the example of the matcher is a number, but the matcher of its sibling item 0 expects a string",
+		},
+		"array": {
+			request:  Request{Method: "GET", Path: String("/")},
+			response: Response{Body: EachLike(nil, 1)},
+			want:     "response $.body: EachLike requires an example of the items",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			i := (&Interaction{}).
+				UponReceiving("A request").
+				WithRequest(tt.request).
+				WillRespondWith(tt.response)
+
+			err := i.validate()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("expected an error containing '%s' but got '%v'", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package dsl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// MatchingMode controls how the plain values (those not wrapped in a matcher)
+// in the headers and body of a request or response are matched
+type MatchingMode string
+
+const (
+	// MatchStrict matches plain values exactly, and arrays must contain exactly
+	// the elements given. This is the default.
+	MatchStrict MatchingMode = "strict"
+
+	// MatchLenient matches plain values by type, as per Like, and arrays may
+	// contain any number (of at least one) of elements like the first.
+	MatchLenient MatchingMode = "lenient"
+)
+
+func isKnownMatchingMode(mode MatchingMode) bool {
+	return mode == MatchStrict || mode == MatchLenient
+}
+
+// WithRequestMatching specifies how the plain values in the request headers
+// and body are matched, overriding the default of MatchStrict for this
+// interaction. Matchers in the request are unaffected.
+func (i *Interaction) WithRequestMatching(mode MatchingMode) *Interaction {
+	if !isKnownMatchingMode(mode) {
+		i.Request.err = fmt.Errorf("unknown matching mode '%s', expected '%s' or '%s'", mode, MatchStrict, MatchLenient)
+		return i
+	}
+	i.requestMatching = mode
+
+	return i
+}
+
+// WithResponseMatching specifies how the plain values in the response headers
+// and body are matched, overriding the default of MatchStrict for this
+// interaction. Matchers in the response are unaffected.
+//
+// NOTE: unexpected keys in a response body are always allowed, and are never
+// allowed in a request body, as per the Pact specification.
+func (i *Interaction) WithResponseMatching(mode MatchingMode) *Interaction {
+	if !isKnownMatchingMode(mode) {
+		i.Response.err = fmt.Errorf("unknown matching mode '%s', expected '%s' or '%s'", mode, MatchStrict, MatchLenient)
+		return i
+	}
+	i.responseMatching = mode
+
+	return i
+}
+
+// withMatchingModes returns a copy of the interaction, with any lenient request
+// or response converted to the equivalent matchers
+func (i *Interaction) withMatchingModes() (*Interaction, error) {
+	if i.requestMatching != MatchLenient && i.responseMatching != MatchLenient {
+		return i, nil
+	}
+
+	interaction := *i
+	var err error
+
+	if i.requestMatching == MatchLenient {
+		interaction.Request.Headers = lenientHeaders(i.Request.Headers)
+		if interaction.Request.Body, err = lenientBody(i.Request.Body); err != nil {
+			return nil, fmt.Errorf("unable to match the request body of interaction '%s' leniently: %v", i.Description, err)
+		}
+	}
+
+	if i.responseMatching == MatchLenient {
+		interaction.Response.Headers = lenientHeaders(i.Response.Headers)
+		if interaction.Response.Body, err = lenientBody(i.Response.Body); err != nil {
+			return nil, fmt.Errorf("unable to match the response body of interaction '%s' leniently: %v", i.Description, err)
+		}
+	}
+
+	return &interaction, nil
+}
+
+// lenientHeaders matches any plain header values by type
+func lenientHeaders(headers MapMatcher) MapMatcher {
+	if headers == nil {
+		return nil
+	}
+
+	lenient := make(MapMatcher, len(headers))
+	for name, value := range headers {
+		switch value.(type) {
+		case String, S:
+			lenient[name] = Like(value.GetValue())
+		default:
+			lenient[name] = value
+		}
+	}
+
+	return lenient
+}
+
+// lenientBody matches any plain values in a body by type, and any plain arrays
+// by their first element. The body is converted to its JSON representation,
+// in which matchers are identified by their "json_class".
+func lenientBody(body interface{}) (interface{}, error) {
+	if body == nil {
+		return nil, nil
+	}
+
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+
+	var content interface{}
+	if err = decoder.Decode(&content); err != nil {
+		return nil, err
+	}
+
+	return lenientValue(content), nil
+}
+
+// lenientValue converts a decoded JSON value, leaving any matchers in place
+func lenientValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		if _, ok := value["json_class"]; ok {
+			return value
+		}
+
+		lenient := make(map[string]interface{}, len(value))
+		for k, item := range value {
+			lenient[k] = lenientValue(item)
+		}
+		return lenient
+	case []interface{}:
+		if len(value) == 0 {
+			return value
+		}
+		return EachLike(lenientValue(value[0]), 1)
+	case nil:
+		return nil
+	default:
+		return Like(value)
+	}
+}
@@ -0,0 +1,109 @@
+package dsl
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMatchingMode_Lenient(t *testing.T) {
+	i := (&Interaction{}).
+		WithRequest(Request{
+			Method:  "POST",
+			Path:    String("/users"),
+			Headers: MapMatcher{"Content-Type": String("application/json")},
+			Body:    map[string]interface{}{"name": "billy"},
+		}).
+		WillRespondWith(Response{
+			Status:  200,
+			Headers: MapMatcher{"X-Id": Term("1", `\d+`)},
+			Body: map[string]interface{}{
+				"id":    Term("1", `\d+`),
+				"tags":  []string{"a", "b"},
+				"empty": []string{},
+				"admin": false,
+				"phone": nil,
+			},
+		}).
+		WithResponseMatching(MatchLenient)
+
+	if err := i.validate(); err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	lenient, err := i.withMatchingModes()
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	expected := map[string]interface{}{
+		"id":    Term("1", `\d+`),
+		"tags":  EachLike(Like("a"), 1),
+		"empty": []string{},
+		"admin": Like(false),
+		"phone": nil,
+	}
+	assertSameJSON(t, lenient.Response.Body, expected)
+	assertSameJSON(t, lenient.Response.Headers, MapMatcher{"X-Id": Term("1", `\d+`)})
+
+	// The request is strict, and the original interaction untouched
+	assertSameJSON(t, lenient.Request.Body, map[string]interface{}{"name": "billy"})
+	assertSameJSON(t, lenient.Request.Headers, MapMatcher{"Content-Type": String("application/json")})
+	assertSameJSON(t, i.Response.Body.(map[string]interface{})["admin"], false)
+}
+
+func TestMatchingMode_LenientRequest(t *testing.T) {
+	i := (&Interaction{}).
+		WithRequest(Request{
+			Method:  "POST",
+			Path:    String("/users"),
+			Headers: MapMatcher{"Content-Type": String("application/json")},
+			Body: struct {
+				Name  string        `json:"name"`
+				Roles []interface{} `json:"roles"`
+			}{"billy", []interface{}{map[string]interface{}{"id": 1}}},
+		}).
+		WithRequestMatching(MatchLenient)
+
+	lenient, err := i.withMatchingModes()
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	assertSameJSON(t, lenient.Request.Body, map[string]interface{}{
+		"name":  Like("billy"),
+		"roles": EachLike(map[string]interface{}{"id": Like(1)}, 1),
+	})
+	assertSameJSON(t, lenient.Request.Headers, MapMatcher{"Content-Type": Like("application/json")})
+}
+
+func TestMatchingMode_Strict(t *testing.T) {
+	i := (&Interaction{}).WithRequestMatching(MatchStrict).WithResponseMatching(MatchStrict)
+
+	if lenient, _ := i.withMatchingModes(); lenient != i {
+		t.Fatal("expected a strict interaction to be used as is")
+	}
+}
+
+func TestMatchingMode_Unknown(t *testing.T) {
+	i := (&Interaction{}).WithResponseMatching("loose")
+
+	if err := i.validate(); err == nil || !strings.Contains(err.Error(), "unknown matching mode 'loose'") {
+		t.Fatalf("expected an unknown matching mode error but got '%v'", err)
+	}
+}
+
+func assertSameJSON(t *testing.T, actual interface{}, expected interface{}) {
+	t.Helper()
+
+	a, _ := json.Marshal(actual)
+	e, _ := json.Marshal(expected)
+
+	var av, ev interface{}
+	json.Unmarshal(a, &av)
+	json.Unmarshal(e, &ev)
+	if !reflect.DeepEqual(av, ev) {
+		t.Fatalf("expected %s but got %s", e, a)
+	}
+}
@@ -0,0 +1,173 @@
+package dsl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// MatchingRule is a matching rule produced by a matcher in an interaction,
+// e.g. "$.body.items[*].id" matched by "type"
+type MatchingRule struct {
+	// Path of the matched value, e.g. "$.path", "$.query.page",
+	// "$.headers.Content-Type" or "$.body.items[*].id"
+	Path string
+
+	// Match is "type" (Like, EachLike) or "regex" (Term)
+	Match string
+
+	// Regex for a "regex" match
+	Regex string
+
+	// Min and Max number of items for an EachLike. Max is 0 if unbounded.
+	Min int
+	Max int
+}
+
+func (r MatchingRule) String() string {
+	switch {
+	case r.Match == "regex":
+		return fmt.Sprintf("%s → regex %s", r.Path, r.Regex)
+	case r.Max > 0:
+		return fmt.Sprintf("%s → type (min %d, max %d)", r.Path, r.Min, r.Max)
+	case r.Min > 0:
+		return fmt.Sprintf("%s → type (min %d)", r.Path, r.Min)
+	}
+
+	return fmt.Sprintf("%s → type", r.Path)
+}
+
+// RequestMatchingRules lists the matching rules produced by the matchers in the
+// request of the interaction, in order of path, taking into account any
+// matching mode. Useful to check which matchers the DSL (e.g. Match) produced.
+func (i *Interaction) RequestMatchingRules() ([]MatchingRule, error) {
+	expected, err := i.withMatchingModes()
+	if err != nil {
+		return nil, err
+	}
+
+	return matchingRules(expected.Request.Path, expected.Request.Query, expected.Request.Headers, expected.Request.Body)
+}
+
+// ResponseMatchingRules lists the matching rules produced by the matchers in
+// the response of the interaction, as per RequestMatchingRules.
+func (i *Interaction) ResponseMatchingRules() ([]MatchingRule, error) {
+	expected, err := i.withMatchingModes()
+	if err != nil {
+		return nil, err
+	}
+
+	return matchingRules(nil, nil, expected.Response.Headers, expected.Response.Body)
+}
+
+// matchingRules collects the rules from the serialised form of the matchers,
+// as received by the Mock Service
+func matchingRules(path Matcher, query MapMatcher, headers MapMatcher, body interface{}) ([]MatchingRule, error) {
+	parts, err := serialisedParts(path, query, headers, body)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []MatchingRule
+	for _, part := range parts {
+		rules = collectMatchingRules(part.value, part.path, rules)
+	}
+
+	sort.SliceStable(rules, func(a, b int) bool {
+		return rules[a].Path < rules[b].Path
+	})
+
+	return rules, nil
+}
+
+// serialisedPart is part of a request or response, as received by the Mock
+// Service
+type serialisedPart struct {
+	path  string
+	value interface{}
+}
+
+// serialisedParts serialises the parts of a request or response that may
+// contain matchers, by their path e.g. "$.headers.Accept"
+func serialisedParts(path Matcher, query MapMatcher, headers MapMatcher, body interface{}) ([]serialisedPart, error) {
+	parts := []serialisedPart{{"$.path", path}}
+	for k, v := range query {
+		parts = append(parts, serialisedPart{"$.query." + k, v})
+	}
+	for k, v := range headers {
+		parts = append(parts, serialisedPart{"$.headers." + k, v})
+	}
+	if _, isString := body.(string); !isString {
+		parts = append(parts, serialisedPart{"$.body", body})
+	}
+
+	serialised := make([]serialisedPart, 0, len(parts))
+	for _, part := range parts {
+		if part.value == nil {
+			continue
+		}
+
+		data, err := json.Marshal(part.value)
+		if err != nil {
+			return nil, fmt.Errorf("unable to serialise '%s': %v", part.path, err)
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		var v interface{}
+		if err = decoder.Decode(&v); err != nil {
+			return nil, err
+		}
+
+		serialised = append(serialised, serialisedPart{part.path, v})
+	}
+
+	return serialised, nil
+}
+
+// collectMatchingRules appends the rules of any serialised matchers in v
+func collectMatchingRules(v interface{}, path string, rules []MatchingRule) []MatchingRule {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		switch v["json_class"] {
+		case "Pact::SomethingLike":
+			rules = append(rules, MatchingRule{Path: path, Match: "type"})
+			return collectMatchingRules(v["contents"], path, rules)
+		case "Pact::ArrayLike":
+			rule := MatchingRule{Path: path, Match: "type"}
+			if min, ok := v["min"].(json.Number); ok {
+				n, _ := min.Int64()
+				rule.Min = int(n)
+			}
+			if max, ok := v["max"].(json.Number); ok {
+				n, _ := max.Int64()
+				rule.Max = int(n)
+			}
+			rules = append(rules, rule)
+			return collectMatchingRules(v["contents"], path+"[*]", rules)
+		case "Pact::Term":
+			rule := MatchingRule{Path: path, Match: "regex"}
+			if data, ok := v["data"].(map[string]interface{}); ok {
+				if matcher, ok := data["matcher"].(map[string]interface{}); ok {
+					rule.Regex = fmt.Sprint(matcher["s"])
+				}
+			}
+			return append(rules, rule)
+		case optionalClass:
+			return collectMatchingRules(v["contents"], path, rules)
+		case jsonStringClass:
+			return append(rules, MatchingRule{Path: path, Match: "type"})
+		}
+
+		for k, field := range v {
+			rules = collectMatchingRules(field, jsonPathField(path, k), rules)
+		}
+	case []interface{}:
+		for n, item := range v {
+			rules = collectMatchingRules(item, fmt.Sprintf("%s[%d]", path, n), rules)
+		}
+	}
+
+	return rules
+}
@@ -0,0 +1,92 @@
+package dsl
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMatchingRules_Request(t *testing.T) {
+	i := (&Interaction{}).WithRequest(Request{
+		Method:  "GET",
+		Path:    Term("/users/1", `^/users/\d+$`),
+		Query:   MapMatcher{"page": Like("1"), "sort": String("name")},
+		Headers: MapMatcher{"Content-Type": String("application/json")},
+		Body: map[string]interface{}{
+			"items": EachLikeBetween(map[string]interface{}{
+				"id":   Like(1),
+				"date": Date(),
+			}, 1, 10),
+			"user name": Like("billy"),
+			"plain":     true,
+		},
+	})
+
+	rules, err := i.RequestMatchingRules()
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	var got []string
+	for _, r := range rules {
+		got = append(got, r.String())
+	}
+
+	expected := []string{
+		"$.body.items → type (min 1, max 10)",
+		"$.body.items[*].date → regex " + date,
+		"$.body.items[*].id → type",
+		"$.body['user name'] → type",
+		`$.path → regex ^/users/\d+$`,
+		"$.query.page → type",
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("expected rules\n%s\nbut got\n%s", strings.Join(expected, "\n"), strings.Join(got, "\n"))
+	}
+}
+
+func TestMatchingRules_Response(t *testing.T) {
+	type User struct {
+		Name string   `json:"name" pact:"example=billy"`
+		Tags []string `json:"tags"`
+	}
+
+	i := (&Interaction{}).WillRespondWith(Response{
+		Status:  200,
+		Headers: MapMatcher{"X-Id": UUID()},
+		Body:    Match(&User{}),
+	})
+
+	rules, err := i.ResponseMatchingRules()
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	expected := []MatchingRule{
+		{Path: "$.body.name", Match: "type"},
+		{Path: "$.body.tags", Match: "type", Min: 1},
+		{Path: "$.body.tags[*]", Match: "type"},
+		{Path: "$.headers.X-Id", Match: "regex", Regex: uuid},
+	}
+	if len(rules) != len(expected) {
+		t.Fatalf("expected %v but got %v", expected, rules)
+	}
+	for n := range expected {
+		if rules[n] != expected[n] {
+			t.Fatalf("expected rule %v but got %v", expected[n], rules[n])
+		}
+	}
+}
+
+func TestMatchingRules_Lenient(t *testing.T) {
+	i := (&Interaction{}).
+		WillRespondWith(Response{Status: 200, Body: map[string]interface{}{"id": 1}}).
+		WithResponseMatching(MatchLenient)
+
+	rules, err := i.ResponseMatchingRules()
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(rules) != 1 || rules[0].String() != "$.body.id → type" {
+		t.Fatalf("expected the lenient body to be matched by type, got %v", rules)
+	}
+}
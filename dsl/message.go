@@ -30,9 +30,20 @@ type Message struct {
 	// Message Body
 	Content interface{} `json:"contents,omitempty"`
 
-	// Message Body as a Raw JSON string
+	// Message Body as a Raw JSON string. The serialised content ([]byte) of
+	// the message given to a MessageConsumer, see RawMessageConsumer.
 	ContentRaw interface{} `json:"-"`
 
+	// ContentType of the message body, set via WithContentType.
+	// Defaults to JSON.
+	ContentType string `json:"-"`
+
+	// contentEncoding is the encoding (if any) applied to non-JSON content
+	contentEncoding string
+
+	// err records any problems building the message, reported on verification
+	err error
+
 	// Provider state to be written into the Pact file
 	States []State `json:"providerStates,omitempty"`
 
@@ -47,6 +58,9 @@ type Message struct {
 	Type interface{}
 
 	Args []string `json:"-"`
+
+	// Name of the Go test that defined the message
+	testName string
 }
 
 // State specifies how the system should be configured when
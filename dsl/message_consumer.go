@@ -0,0 +1,116 @@
+package dsl
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+var (
+	errorType    = reflect.TypeOf((*error)(nil)).Elem()
+	metadataType = reflect.TypeOf(map[string]interface{}(nil))
+)
+
+// RawMessageConsumer adapts the function that handles the serialised content of
+// a message, e.g. the handler of a queue subscription, to a MessageConsumer, so
+// that the actual handler is verified against the message:
+//
+//	pact.VerifyMessageConsumer(t, message, dsl.RawMessageConsumer(orders.HandleDelivery))
+//
+// The content is the example JSON of the message, or the content encoded by the
+// codec of its ContentType, and the metadata the examples of its metadata.
+func RawMessageConsumer(handler func(content []byte, metadata map[string]interface{}) error) MessageConsumer {
+	return func(m Message) error {
+		content, err := messageContent(m)
+		if err != nil {
+			return err
+		}
+
+		return handler(content, messageMetadata(m))
+	}
+}
+
+// TypedMessageConsumer adapts a function taking the content of a message as a
+// Go type, and optionally its metadata, to a MessageConsumer, e.g. any of
+//
+//	func(order Order) error
+//	func(order *Order) error
+//	func(order Order, metadata map[string]interface{}) error
+//
+// The content is decoded into the type as JSON, or by the codec of the
+// ContentType of the message. A function of any other signature is an error
+// when the message is verified.
+func TypedMessageConsumer(handler interface{}) MessageConsumer {
+	return func(m Message) error {
+		fn := reflect.ValueOf(handler)
+		if err := checkTypedMessageConsumer(reflect.TypeOf(handler)); err != nil {
+			return types.NewError(types.ErrInvalidRequest, err)
+		}
+
+		content, err := messageContent(m)
+		if err != nil {
+			return err
+		}
+
+		contentType := fn.Type().In(0)
+		target := reflect.New(contentType)
+		if contentType.Kind() == reflect.Ptr {
+			target.Elem().Set(reflect.New(contentType.Elem()))
+		}
+
+		decode := json.Unmarshal
+		if m.ContentType != "" {
+			codec, err := contentCodecFor(m.ContentType)
+			if err != nil {
+				return err
+			}
+			decode = codec.Decode
+		}
+		if err = decode(content, target.Interface()); err != nil {
+			return fmt.Errorf("unable to decode message '%s' to %v: %v", m.Description, contentType, err)
+		}
+
+		args := []reflect.Value{target.Elem()}
+		if fn.Type().NumIn() == 2 {
+			args = append(args, reflect.ValueOf(messageMetadata(m)))
+		}
+
+		if err, _ := fn.Call(args)[0].Interface().(error); err != nil {
+			return err
+		}
+
+		return nil
+	}
+}
+
+// checkTypedMessageConsumer checks the signature of a TypedMessageConsumer
+func checkTypedMessageConsumer(t reflect.Type) error {
+	if t == nil || t.Kind() != reflect.Func ||
+		t.NumIn() < 1 || t.NumIn() > 2 || (t.NumIn() == 2 && t.In(1) != metadataType) ||
+		t.NumOut() != 1 || t.Out(0) != errorType {
+		return fmt.Errorf("message consumer must be a func(T) error or func(T, map[string]interface{}) error, but got %v", t)
+	}
+
+	return nil
+}
+
+// messageContent returns the serialised content of the generated message
+func messageContent(m Message) ([]byte, error) {
+	if raw, ok := m.ContentRaw.([]byte); ok {
+		return raw, nil
+	}
+
+	return json.Marshal(m.Content)
+}
+
+// messageMetadata returns the examples of the metadata of the message
+func messageMetadata(m Message) map[string]interface{} {
+	metadata := make(map[string]interface{}, len(m.Metadata))
+	for k, v := range m.Metadata {
+		metadata[k] = exampleOf(v, false)
+	}
+
+	return metadata
+}
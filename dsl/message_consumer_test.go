@@ -0,0 +1,80 @@
+package dsl
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+func TestRawMessageConsumer(t *testing.T) {
+	message := Message{
+		ContentRaw: []byte(`{"id":1}`),
+		Metadata:   MapMatcher{"topic": Term("orders", `^orders$`)},
+	}
+
+	var content string
+	var topic interface{}
+	err := RawMessageConsumer(func(c []byte, metadata map[string]interface{}) error {
+		content = string(c)
+		topic = metadata["topic"]
+		return nil
+	})(message)
+
+	if err != nil || content != `{"id":1}` || topic != "orders" {
+		t.Fatalf("unexpected content '%s' or topic '%v' (%v)", content, topic, err)
+	}
+
+	// Without the serialised content, e.g. when called directly
+	err = RawMessageConsumer(func(c []byte, metadata map[string]interface{}) error {
+		content = string(c)
+		return nil
+	})(Message{Content: map[string]int{"id": 2}})
+	if err != nil || content != `{"id":2}` {
+		t.Fatalf("unexpected content '%s' (%v)", content, err)
+	}
+}
+
+func TestTypedMessageConsumer(t *testing.T) {
+	type order struct {
+		ID int `json:"id"`
+	}
+	message := Message{
+		Description: "an order",
+		ContentRaw:  []byte(`{"id":1}`),
+		Metadata:    MapMatcher{"topic": String("orders")},
+	}
+
+	var received order
+	if err := TypedMessageConsumer(func(o order) error {
+		received = o
+		return nil
+	})(message); err != nil || received.ID != 1 {
+		t.Fatalf("unexpected order %+v (%v)", received, err)
+	}
+
+	var pointer *order
+	var topic interface{}
+	if err := TypedMessageConsumer(func(o *order, metadata map[string]interface{}) error {
+		pointer = o
+		topic = metadata["topic"]
+		return nil
+	})(message); err != nil || pointer == nil || pointer.ID != 1 || topic != "orders" {
+		t.Fatalf("unexpected order %+v or topic '%v' (%v)", pointer, topic, err)
+	}
+
+	failure := errors.New("unable to ship order")
+	if err := TypedMessageConsumer(func(o order) error { return failure })(message); err != failure {
+		t.Fatalf("expected the error of the handler but got '%v'", err)
+	}
+
+	if err := TypedMessageConsumer(func(o order) error { return nil })(Message{ContentRaw: []byte(`[]`)}); err == nil {
+		t.Fatal("expected an error decoding the content")
+	}
+
+	for _, invalid := range []interface{}{nil, "handler", func() error { return nil }, func(o order) {}, func(o order, topic string) error { return nil }} {
+		if err := TypedMessageConsumer(invalid)(message); !errors.Is(err, types.ErrInvalidRequest) {
+			t.Fatalf("expected %T to be an invalid consumer but got '%v'", invalid, err)
+		}
+	}
+}
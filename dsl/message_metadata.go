@@ -0,0 +1,220 @@
+package dsl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"sort"
+	"strings"
+)
+
+// MessageWithMetadata is the result of a MessageHandler that produces message
+// metadata, e.g. the topic or content type used to route the message, along
+// with its content. The metadata is checked against the metadata of the
+// message in the pact, including any matchers, during verification (of pacts
+// given as local PactURLs). Metadata is not checked for handlers that return
+// the content alone.
+type MessageWithMetadata struct {
+	Content  interface{}
+	Metadata map[string]interface{}
+}
+
+// withoutMetadataMatchers returns a copy of the message whose metadata has the
+// examples of any matchers, as sent to the Pact CLI tools, and the serialised
+// matchers by metadata key
+func (p *Message) withoutMetadataMatchers() (*Message, map[string]interface{}, error) {
+	fields := make(map[string]interface{})
+	metadata := make(MapMatcher, len(p.Metadata))
+
+	for k, v := range p.Metadata {
+		switch v.(type) {
+		case String, S, nil:
+			metadata[k] = v
+			continue
+		}
+
+		serialised, err := serialise(v)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid matcher for metadata '%s': %v", k, err)
+		}
+		fields[k] = serialised
+		metadata[k] = String(metadataValueString(v))
+	}
+
+	if len(fields) == 0 {
+		return p, nil, nil
+	}
+
+	message := *p
+	message.Metadata = metadata
+
+	return &message, fields, nil
+}
+
+// serialise returns the JSON form of v, as written to a pact
+func serialise(v interface{}) (interface{}, error) {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+
+	var serialised interface{}
+	err = decoder.Decode(&serialised)
+
+	return serialised, err
+}
+
+// decodeJSON decodes the JSON data into v as per json.Unmarshal, but with its
+// numbers as json.Number if useNumber, so that large integers such as IDs
+// don't lose their precision as float64
+func decodeJSON(data []byte, v interface{}, useNumber bool) error {
+	if !useNumber {
+		return json.Unmarshal(data, v)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return fmt.Errorf("invalid JSON after the top-level value")
+	}
+
+	return nil
+}
+
+// recordMetadataMatchers remembers the matchers in the metadata of a message,
+// so that they can be written to the pact file
+func (p *Pact) recordMetadataMatchers(key string, fields map[string]interface{}) {
+	if len(fields) == 0 {
+		return
+	}
+
+	if p.metadataMatchers == nil {
+		p.metadataMatchers = make(map[string]map[string]interface{})
+	}
+	p.metadataMatchers[key] = fields
+}
+
+// loadMessageMetadata returns the expected metadata of each message in the
+// given pact files by description, with any "metadataMatchers" in place of
+// the examples. Pacts fetched from a remote URL are skipped.
+func loadMessageMetadata(pactURLs []string) map[string]map[string]interface{} {
+	expected := make(map[string]map[string]interface{})
+
+	for _, pactURL := range pactURLs {
+		if strings.HasPrefix(pactURL, "http://") || strings.HasPrefix(pactURL, "https://") {
+			continue
+		}
+
+		content, err := ioutil.ReadFile(pactURL)
+		if err != nil {
+			continue
+		}
+
+		var pact struct {
+			Messages []struct {
+				Description      string                 `json:"description"`
+				Metadata         map[string]interface{} `json:"metadata"`
+				MetadataMatchers map[string]interface{} `json:"metadataMatchers"`
+			} `json:"messages"`
+		}
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.UseNumber()
+		if err = decoder.Decode(&pact); err != nil {
+			log.Printf("[WARN] unable to read the message metadata from pact '%s': %v", pactURL, err)
+			continue
+		}
+
+		for _, m := range pact.Messages {
+			metadata := make(map[string]interface{}, len(m.Metadata))
+			for k, v := range m.Metadata {
+				metadata[k] = v
+			}
+			for k, v := range m.MetadataMatchers {
+				metadata[k] = v
+			}
+			if len(metadata) > 0 {
+				expected[m.Description] = metadata
+			}
+		}
+	}
+
+	return expected
+}
+
+// checkMessageMetadata wraps the handlers such that the metadata of each
+// MessageWithMetadata is checked against the expected metadata of the message,
+// adding any that doesn't match to mismatches, and only its content is
+// returned to the verifier
+func checkMessageMetadata(handlers MessageHandlers, expected map[string]map[string]interface{}, mismatches *responseMismatches) MessageHandlers {
+	checked := make(MessageHandlers, len(handlers))
+
+	for description, handler := range handlers {
+		description, handler := description, handler
+		checked[description] = func(m Message) (interface{}, error) {
+			res, err := handler(m)
+			if err != nil {
+				return res, err
+			}
+
+			produced, ok := res.(MessageWithMetadata)
+			if !ok {
+				if pointer, isPointer := res.(*MessageWithMetadata); isPointer && pointer != nil {
+					produced, ok = *pointer, true
+				}
+			}
+			if !ok {
+				return res, nil
+			}
+
+			for _, mismatch := range metadataMismatches(expected[description], produced.Metadata) {
+				mismatches.add(fmt.Sprintf("message '%s': %s", description, mismatch))
+			}
+
+			return produced.Content, nil
+		}
+	}
+
+	return checked
+}
+
+// metadataMismatches returns a mismatch for each of the expected metadata that
+// is missing from, or doesn't match, the actual metadata
+func metadataMismatches(expected map[string]interface{}, actual map[string]interface{}) []string {
+	keys := make([]string, 0, len(expected))
+	for k := range expected {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var mismatches []string
+	for _, k := range keys {
+		path := jsonPathField("$.metadata", k)
+
+		value, ok := actual[k]
+		if !ok {
+			mismatches = append(mismatches, fmt.Sprintf("%s: expected metadata but it was missing", path))
+			continue
+		}
+
+		serialised, err := serialise(value)
+		if err != nil {
+			mismatches = append(mismatches, fmt.Sprintf("%s: %v", path, err))
+			continue
+		}
+
+		if err = matchSerialised(expected[k], serialised, path, false); err != nil {
+			mismatches = append(mismatches, err.Error())
+		}
+	}
+
+	return mismatches
+}
@@ -0,0 +1,113 @@
+package dsl
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMessage_withoutMetadataMatchers(t *testing.T) {
+	message := (&Message{}).WithMetadata(MapMatcher{
+		"contentType": Term("application/json", `^application/json`),
+		"topic":       Like("orders"),
+		"source":      String("shop"),
+	})
+
+	written, fields, err := message.withoutMetadataMatchers()
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	if written.Metadata["contentType"] != String("application/json") || written.Metadata["topic"] != String("orders") || written.Metadata["source"] != String("shop") {
+		t.Fatalf("expected the examples of the metadata but got %v", written.Metadata)
+	}
+	if _, ok := message.Metadata["topic"].(like); !ok {
+		t.Fatal("expected the metadata of the message to be unchanged")
+	}
+	if len(fields) != 2 || fields["source"] != nil {
+		t.Fatalf("expected only the matchers to be recorded but got %v", fields)
+	}
+
+	plain := (&Message{}).WithMetadata(MapMatcher{"topic": String("orders")})
+	if written, fields, _ = plain.withoutMetadataMatchers(); written != plain || fields != nil {
+		t.Fatal("expected a message without matchers to be unchanged")
+	}
+}
+
+func TestCheckMessageMetadata(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pact-go-metadata")
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	defer os.RemoveAll(dir)
+
+	pactFile := filepath.Join(dir, "billy-bobby.json")
+	content := `{
+  "messages": [
+    {
+      "description": "an order",
+      "contents": {"id": 1},
+      "metadata": {"contentType": "application/json", "topic": "orders", "source": "shop"},
+      "metadataMatchers": {
+        "contentType": {"json_class": "Pact::Term", "data": {"generate": "application/json", "matcher": {"json_class": "Regexp", "o": 0, "s": "^application/json"}}},
+        "topic": {"json_class": "Pact::SomethingLike", "contents": "orders"}
+      }
+    }
+  ]
+}`
+	if err = ioutil.WriteFile(pactFile, []byte(content), 0644); err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	expected := loadMessageMetadata([]string{pactFile, "http://localhost/pacts/remote.json"})
+	if len(expected) != 1 || len(expected["an order"]) != 3 {
+		t.Fatalf("unexpected metadata loaded %v", expected)
+	}
+
+	var metadata map[string]interface{}
+	handlers := MessageHandlers{
+		"an order": func(m Message) (interface{}, error) {
+			return MessageWithMetadata{Content: map[string]int{"id": 1}, Metadata: metadata}, nil
+		},
+		"a refund": func(m Message) (interface{}, error) {
+			return "refund", nil
+		},
+		"a failure": func(m Message) (interface{}, error) {
+			return nil, errors.New("unable to produce")
+		},
+	}
+
+	mismatches := &responseMismatches{}
+	checked := checkMessageMetadata(handlers, expected, mismatches)
+
+	metadata = map[string]interface{}{"contentType": "application/json; charset=utf-8", "topic": "payments", "source": "shop"}
+	res, err := checked["an order"](Message{})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, ok := res.(map[string]int); !ok {
+		t.Fatalf("expected only the content to be returned but got %v", res)
+	}
+	if err = mismatches.err(); err != nil {
+		t.Fatalf("expected the metadata to match but got '%v'", err)
+	}
+
+	metadata = map[string]interface{}{"contentType": "text/plain", "topic": 1}
+	checked["an order"](Message{})
+	err = mismatches.err()
+	for _, want := range []string{"$.metadata.contentType: expected a string matching", "$.metadata.source: expected metadata but it was missing", "$.metadata.topic: expected a value like orders"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected a mismatch '%s' but got '%v'", want, err)
+		}
+	}
+
+	if res, _ = checked["a refund"](Message{}); res != "refund" {
+		t.Fatalf("expected the content of a handler without metadata but got %v", res)
+	}
+	if _, err = checked["a failure"](Message{}); err == nil {
+		t.Fatal("expected the error of the handler")
+	}
+}
@@ -0,0 +1,58 @@
+package dsl
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// httpTokenChars are the characters allowed in an HTTP method, besides letters
+// and digits (see RFC 7230, section 3.2.6)
+const httpTokenChars = "!#$%&'*+-.^_`|~"
+
+// isValidMethod checks that method is a valid HTTP token, allowing custom
+// methods such as PROPFIND
+func isValidMethod(method string) bool {
+	if method == "" {
+		return false
+	}
+
+	for _, c := range method {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || strings.ContainsRune(httpTokenChars, c)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// validateMethod checks the method of the request, and that neither the
+// request nor the response has a body that could not be sent
+func (i *Interaction) validateMethod() error {
+	method := i.Request.Method
+	if method == "" {
+		return nil
+	}
+
+	if !isValidMethod(method) {
+		return fmt.Errorf("invalid request method '%s'", method)
+	}
+
+	if i.Request.Body != nil && strings.EqualFold(method, http.MethodTrace) {
+		return fmt.Errorf("a %s request cannot have a body", strings.ToUpper(method))
+	}
+
+	if i.Response.Body == nil {
+		return nil
+	}
+
+	if strings.EqualFold(method, http.MethodHead) {
+		return fmt.Errorf("the response to a %s request cannot have a body", http.MethodHead)
+	}
+
+	if status := i.Response.Status; (status >= 100 && status < 200) || status == http.StatusNoContent || status == http.StatusNotModified {
+		return fmt.Errorf("a response with status %d cannot have a body", status)
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package dsl
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestMethod_isValidMethod(t *testing.T) {
+	tests := map[string]bool{
+		"GET":      true,
+		"patch":    true,
+		"PROPFIND": true,
+		"M-SEARCH": true,
+		"":         false,
+		"GET /":    false,
+		"GET\n":    false,
+		"(GET)":    false,
+	}
+
+	for method, valid := range tests {
+		if isValidMethod(method) != valid {
+			t.Fatalf("expected isValidMethod('%s') to be %v", method, valid)
+		}
+	}
+}
+
+func TestMethod_Validate(t *testing.T) {
+	valid := []*Interaction{
+		(&Interaction{}).WithRequest(Request{Method: "HEAD", Path: String("/users")}).WillRespondWith(Response{Status: 200, Headers: MapMatcher{"Content-Length": String("42")}}),
+		(&Interaction{}).WithRequest(Request{Method: "OPTIONS", Path: String("/users")}).WillRespondWith(Response{Status: 204}),
+		(&Interaction{}).WithRequest(Request{Method: "PATCH", Path: String("/users/1"), Body: map[string]string{"name": "billy"}}).WillRespondWith(Response{Status: 200, Body: map[string]string{"name": "billy"}}),
+		(&Interaction{}).WithRequest(Request{Method: "PROPFIND", Path: String("/files"), Body: "<propfind/>"}).WillRespondWith(Response{Status: 207, Body: "<multistatus/>"}),
+	}
+
+	for _, i := range valid {
+		if err := i.validate(); err != nil {
+			t.Fatalf("expected %s interaction to be valid: %v", i.Request.Method, err)
+		}
+	}
+
+	invalid := map[string]*Interaction{
+		"invalid request method 'GET /'":             (&Interaction{}).WithRequest(Request{Method: "GET /"}),
+		"a TRACE request cannot have a body":         (&Interaction{}).WithRequest(Request{Method: "trace", Body: "hello"}),
+		"the response to a HEAD request cannot have": (&Interaction{}).WithRequest(Request{Method: "HEAD"}).WillRespondWith(Response{Status: 200, Body: "hello"}),
+		"a response with status 204 cannot have":     (&Interaction{}).WithRequest(Request{Method: "DELETE"}).WillRespondWith(Response{Status: 204, Body: "hello"}),
+		"a response with status 304 cannot have":     (&Interaction{}).WithRequest(Request{Method: "GET"}).WillRespondWith(Response{Status: 304, Body: "hello"}),
+	}
+
+	for want, i := range invalid {
+		if err := i.validate(); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected an error containing '%s' but got '%v'", want, err)
+		}
+	}
+}
+
+func TestMethod_CustomMethodMismatch(t *testing.T) {
+	ms := setupMismatchMockServer()
+	defer ms.Close()
+
+	proxy, err := startMockServerProxy("tcp", "localhost", 0, ms.URL, mockServerTimeouts{})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	defer proxy.Stop()
+
+	req, _ := http.NewRequest("PROPFIND", fmt.Sprintf("http://localhost:%d/files", proxy.Port), strings.NewReader("<propfind/>"))
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	res.Body.Close()
+
+	requests := proxy.Requests()
+	if len(requests) != 1 || requests[0].Method != "PROPFIND" || !requests[0].Unmatched {
+		t.Fatalf("expected the PROPFIND request to be recorded as unmatched, got %+v", requests)
+	}
+
+	i := (&Interaction{}).WithRequest(Request{Method: "propfind", Path: String("/files")})
+	if findInteraction([]*Interaction{i}, requests[0]) != i {
+		t.Fatal("expected the interaction to be found regardless of the case of the method")
+	}
+}
@@ -0,0 +1,39 @@
+package dsl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// RawMismatches returns the JSON responses of the Mock Service listening on
+// port to each request it could not match, since the start of the last Verify,
+// as a JSON array. The responses are included verbatim, so that tools such as
+// custom reporters can consume fields (e.g. "interaction_diffs") that are not
+// modelled by this package.
+//
+// Requests are only recorded if RecordMismatches (or UpdateFixtures) is set
+// before the Mock Service is started. Interactions over TLS are not supported.
+func (p *Pact) RawMismatches(port int) (json.RawMessage, error) {
+	if p.Server == nil || p.Server.Port != port {
+		return nil, fmt.Errorf("no mock server with recorded requests is running on port %d", port)
+	}
+	if p.proxy == nil {
+		return nil, fmt.Errorf("requests to the mock server on port %d were not recorded, set RecordMismatches to record them", port)
+	}
+
+	return rawMismatches(p.proxy.Requests()), nil
+}
+
+// rawMismatches joins the Mock Service responses to the unmatched requests into
+// a JSON array, without re-encoding them
+func rawMismatches(requests []*recordedRequest) json.RawMessage {
+	mismatches := [][]byte{}
+	for _, rec := range requests {
+		if rec.Unmatched {
+			mismatches = append(mismatches, bytes.TrimSpace(rec.Mismatch))
+		}
+	}
+
+	return json.RawMessage(append(append([]byte("["), bytes.Join(mismatches, []byte(","))...), ']'))
+}
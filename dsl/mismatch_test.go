@@ -0,0 +1,64 @@
+package dsl
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+func TestMismatch_RawMismatches(t *testing.T) {
+	ms := setupMismatchMockServer()
+	defer ms.Close()
+
+	proxy, err := startMockServerProxy("tcp", "localhost", 0, ms.URL, mockServerTimeouts{})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	defer proxy.Stop()
+
+	p := &Pact{Server: &types.MockServer{Port: proxy.Port}, proxy: proxy}
+	url := fmt.Sprintf("http://localhost:%d", proxy.Port)
+
+	http.Get(url + "/users")
+	http.Get(url + "/error")
+	http.Post(url+"/orders", "application/json", strings.NewReader(`{}`))
+
+	raw, err := p.RawMismatches(proxy.Port)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	var mismatches []map[string]interface{}
+	if err = json.Unmarshal(raw, &mismatches); err != nil {
+		t.Fatalf("expected a JSON array but got '%s': %v", raw, err)
+	}
+	if len(mismatches) != 2 {
+		t.Fatalf("expected 2 mismatches but got %d", len(mismatches))
+	}
+	if mismatches[1]["message"] != "No interaction found for POST /orders" {
+		t.Fatalf("unexpected mismatch %v", mismatches[1])
+	}
+	if _, ok := mismatches[0]["interaction_diffs"]; !ok {
+		t.Fatalf("expected the full response of the mock service, got %v", mismatches[0])
+	}
+
+	proxy.Reset()
+	if raw, _ = p.RawMismatches(proxy.Port); string(raw) != "[]" {
+		t.Fatalf("expected no mismatches but got '%s'", raw)
+	}
+}
+
+func TestMismatch_RawMismatchesNotRecorded(t *testing.T) {
+	p := &Pact{Server: &types.MockServer{Port: 1234}}
+
+	if _, err := p.RawMismatches(1234); err == nil || !strings.Contains(err.Error(), "RecordMismatches") {
+		t.Fatalf("expected an error suggesting RecordMismatches but got '%v'", err)
+	}
+	if _, err := p.RawMismatches(4321); err == nil {
+		t.Fatal("expected an error for an unknown port")
+	}
+}
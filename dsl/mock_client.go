@@ -17,6 +17,8 @@ type mockClient struct {
 	ReifyMessageError        error
 	UpdateMessagePactError   error
 	PublishPactsError        error
+	StartServerArgs          [][]string
+	VerifyProviderRequests   []types.VerifyRequest
 }
 
 func newMockClient() *mockClient {
@@ -35,6 +37,7 @@ func newMockClient() *mockClient {
 
 // StartServer starts a remote Pact Mock Server.
 func (p *mockClient) StartServer(args []string, port int) *types.MockServer {
+	p.StartServerArgs = append(p.StartServerArgs, args)
 	return p.MockServer
 }
 
@@ -55,6 +58,7 @@ func (p *mockClient) RemoveAllServers(server *types.MockServer) []*types.MockSer
 
 // VerifyProvider runs the verification process against a running Provider.
 func (p *mockClient) VerifyProvider(request types.VerifyRequest) ([]types.ProviderVerifierResponse, error) {
+	p.VerifyProviderRequests = append(p.VerifyProviderRequests, request)
 	return p.VerifyProviderResponse, p.VerifyProviderError
 }
 
@@ -0,0 +1,439 @@
+package dsl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// recordedRequest is a request made to the Mock Service by the code under test
+type recordedRequest struct {
+	Method string
+	Path   string
+	Query  string
+	Header http.Header
+	Body   []byte
+
+	// Status of the Mock Service response
+	Status int
+
+	// Received is when the request was made, and Latency how long the Mock
+	// Service took to respond
+	Received time.Time
+	Latency  time.Duration
+
+	// Unmatched is true if the Mock Service could not match the request to an
+	// interaction, in which case Mismatch contains its (JSON) explanation
+	Unmatched bool
+	Mismatch  []byte
+
+	// Passthrough is true if the request matched no interaction and was
+	// forwarded to the PassthroughURL
+	Passthrough bool
+
+	// ResponseHeader and ResponseBody are the response to the request, if
+	// responses are captured
+	ResponseHeader http.Header
+	ResponseBody   []byte
+}
+
+type recordedRequestKey struct{}
+
+// mockServerProxy sits in front of the Pact Mock Service, recording the
+// requests made by the code under test. Administrative requests (those sent
+// with the X-Pact-Mock-Service header) are passed through without being recorded.
+type mockServerProxy struct {
+	// Port the proxy is listening on
+	Port int
+
+	// target is the Mock Service requests are forwarded to
+	target *url.URL
+
+	listener net.Listener
+	server   *http.Server
+
+	// onResponse is called once the Mock Service has responded to a recorded
+	// request, if set
+	onResponse func(*recordedRequest)
+
+	// captureResponses records the headers and body of each response
+	captureResponses bool
+
+	// variation varies the responses of the Mock Service, if set
+	variation *responseVariation
+
+	mu         sync.Mutex
+	requests   []*recordedRequest
+	generators []urlGenerator
+	sequences  []*responseSequence
+
+	// passthrough forwards requests matching none of the routes to a real
+	// provider, if set
+	passthrough http.Handler
+	routes      []Request
+
+	// interactions are those registered with the Mock Service, for the
+	// request hooks
+	interactions []*Interaction
+
+	// inFlight is the number of recorded requests yet to be responded to, and
+	// lastActive when a recorded request was last made or responded to
+	inFlight   int
+	lastActive time.Time
+
+	// wrongScheme is the number of connections that started a TLS handshake
+	wrongScheme int64
+}
+
+// mockServerTimeouts are the timeouts of the connections to the proxy, none if 0
+type mockServerTimeouts struct {
+	// read is the longest to wait to read a request, including its body
+	read time.Duration
+
+	// idle is the longest to keep an idle keep-alive connection open
+	idle time.Duration
+}
+
+// startMockServerProxy starts a proxy to the Mock Service at target, listening
+// on the given port (or a random port if 0) on the given host
+func startMockServerProxy(network string, host string, port int, target string, timeouts mockServerTimeouts) (*mockServerProxy, error) {
+	targetURL, err := url.Parse(target)
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen(network, net.JoinHostPort(strings.Trim(host, "[]"), strconv.Itoa(port)))
+	if err != nil {
+		return nil, fmt.Errorf("unable to start mock server proxy: %v", err)
+	}
+
+	p := &mockServerProxy{
+		Port:     listener.Addr().(*net.TCPAddr).Port,
+		target:   targetURL,
+		listener: listener,
+	}
+
+	reverseProxy := httputil.NewSingleHostReverseProxy(targetURL)
+	reverseProxy.ModifyResponse = p.recordResponse
+
+	p.server = &http.Server{
+		Handler:     p.handler(reverseProxy),
+		ReadTimeout: timeouts.read,
+		IdleTimeout: timeouts.idle,
+	}
+
+	go func() {
+		if err := p.server.Serve(schemeListener{Listener: listener, wrong: &p.wrongScheme}); err != nil && err != http.ErrServerClosed {
+			log.Println("[ERROR] mock server proxy:", err)
+		}
+	}()
+
+	log.Println("[DEBUG] started mock server proxy on port:", p.Port)
+
+	return p, nil
+}
+
+func (p *mockServerProxy) handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Pact-Mock-Service") != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := ioutil.ReadAll(r.Body)
+		r.Body.Close()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		rec := &recordedRequest{
+			Method:   r.Method,
+			Path:     r.URL.Path,
+			Query:    r.URL.RawQuery,
+			Header:   r.Header,
+			Body:     body,
+			Received: time.Now(),
+		}
+
+		p.mu.Lock()
+		p.requests = append(p.requests, rec)
+		p.inFlight++
+		p.lastActive = time.Now()
+		p.mu.Unlock()
+
+		defer func() {
+			p.mu.Lock()
+			p.inFlight--
+			p.lastActive = time.Now()
+			p.mu.Unlock()
+		}()
+
+		if passthrough := p.passthroughHandler(r); passthrough != nil {
+			rec.Passthrough = true
+			passthrough.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), recordedRequestKey{}, rec)))
+			return
+		}
+
+		// The call to a response sequence is told apart by a header, which is
+		// not recorded
+		if call := p.nextCall(r); call > 0 {
+			header := make(http.Header, len(r.Header)+1)
+			for k, v := range r.Header {
+				header[k] = v
+			}
+			header.Set(sequenceCallHeader, strconv.Itoa(call))
+			r.Header = header
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), recordedRequestKey{}, rec)))
+	})
+}
+
+// recordResponse captures whether the Mock Service matched the request
+func (p *mockServerProxy) recordResponse(res *http.Response) error {
+	rec, ok := res.Request.Context().Value(recordedRequestKey{}).(*recordedRequest)
+	if !ok {
+		return nil
+	}
+
+	if err := p.recordResult(rec, res); err != nil {
+		return err
+	}
+
+	if p.onResponse != nil {
+		p.onResponse(rec)
+	}
+
+	return nil
+}
+
+// recordResult records the response of the Mock Service to the request
+func (p *mockServerProxy) recordResult(rec *recordedRequest, res *http.Response) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.varyResponse(rec, res); err != nil {
+		return err
+	}
+	if err := p.generateURLs(res); err != nil {
+		return err
+	}
+
+	rec.Status = res.StatusCode
+	rec.Latency = time.Since(rec.Received)
+	if err := p.captureResponse(rec, res); err != nil {
+		return err
+	}
+	if res.StatusCode != http.StatusInternalServerError {
+		return nil
+	}
+
+	body, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return err
+	}
+	res.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	if isMismatchResponse(body) {
+		rec.Unmatched = true
+		rec.Mismatch = body
+	}
+
+	return nil
+}
+
+// captureResponse records the headers and body of the response to the request,
+// if responses are captured
+func (p *mockServerProxy) captureResponse(rec *recordedRequest, res *http.Response) error {
+	if !p.captureResponses {
+		return nil
+	}
+
+	body, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return err
+	}
+	res.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	rec.ResponseHeader = res.Header.Clone()
+	rec.ResponseBody = body
+
+	return nil
+}
+
+// generateURLs replaces example URLs in the response headers and body with
+// URLs on the proxy, as it is advertised as the Mock Server
+func (p *mockServerProxy) generateURLs(res *http.Response) error {
+	if len(p.generators) == 0 {
+		return nil
+	}
+
+	// The Host header is not rewritten on the way to the Mock Service
+	baseURL := "http://" + res.Request.Host
+
+	for _, values := range res.Header {
+		for i, value := range values {
+			for _, g := range p.generators {
+				if value == g.example {
+					values[i] = g.generate(baseURL)
+					break
+				}
+			}
+		}
+	}
+
+	body, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return err
+	}
+
+	// Only whole JSON strings are replaced, as per the matchers in the body
+	for _, g := range p.generators {
+		body = bytes.Replace(body, jsonString(g.example), jsonString(g.generate(baseURL)), -1)
+	}
+
+	res.Body = ioutil.NopCloser(bytes.NewReader(body))
+	res.ContentLength = int64(len(body))
+	if res.Header.Get("Content-Length") != "" {
+		res.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	}
+
+	return nil
+}
+
+// jsonString encodes s as a JSON string, as the Mock Service would
+func jsonString(s string) []byte {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(s)
+
+	return bytes.TrimSpace(buf.Bytes())
+}
+
+// isMismatchResponse detects the error returned by the Mock Service when a
+// request does not match any (or matches several) interactions
+func isMismatchResponse(body []byte) bool {
+	var res struct {
+		Message string `json:"message"`
+	}
+
+	if err := json.Unmarshal(body, &res); err != nil {
+		return false
+	}
+
+	return strings.HasPrefix(res.Message, "No interaction found") ||
+		strings.HasPrefix(res.Message, "Multiple interaction")
+}
+
+// Requests returns the requests recorded since the last reset
+func (p *mockServerProxy) Requests() []*recordedRequest {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	requests := make([]*recordedRequest, len(p.requests))
+	copy(requests, p.requests)
+
+	return requests
+}
+
+// SetGenerators sets the example URLs to be replaced in responses
+func (p *mockServerProxy) SetGenerators(generators []urlGenerator) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.generators = generators
+}
+
+// WaitForInFlight waits up to timeout for the requests in flight to be
+// responded to, returning false if any are still in flight
+func (p *mockServerProxy) WaitForInFlight(timeout time.Duration) bool {
+	return p.WaitForQuiet(0, timeout)
+}
+
+// WaitForQuiet waits up to timeout for there to be no requests in flight, and
+// none made for the given window, returning false if there were
+func (p *mockServerProxy) WaitForQuiet(window time.Duration, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		p.mu.Lock()
+		quiet := p.inFlight == 0 && time.Since(p.lastActive) >= window
+		p.mu.Unlock()
+
+		if quiet {
+			return true
+		}
+		if time.Now().After(deadline) {
+			return false
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// Matched returns the number of recorded requests the Mock Service matched to
+// an interaction
+func (p *mockServerProxy) Matched() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	matched := 0
+	for _, rec := range p.requests {
+		if rec.Status != 0 && !rec.Unmatched && !rec.Passthrough {
+			matched++
+		}
+	}
+
+	return matched
+}
+
+// Reset clears the recorded requests
+func (p *mockServerProxy) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.requests = nil
+}
+
+// Stop shuts down the proxy
+func (p *mockServerProxy) Stop() error {
+	log.Println("[DEBUG] stopping mock server proxy")
+
+	return p.server.Close()
+}
+
+// Shutdown stops the proxy once the requests in flight have completed, or
+// closes it once ctx is done
+func (p *mockServerProxy) Shutdown(ctx context.Context) error {
+	log.Println("[DEBUG] shutting down mock server proxy")
+
+	return shutdownServer(ctx, p.server)
+}
+
+// shutdownServer shuts the server down gracefully, closing it if ctx is done
+// first
+func shutdownServer(ctx context.Context, server *http.Server) error {
+	err := server.Shutdown(ctx)
+	if err != nil {
+		server.Close()
+	}
+
+	return err
+}
@@ -0,0 +1,145 @@
+package dsl
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// setupMismatchMockServer behaves like the Mock Service, failing to match any
+// request made by the code under test, and hence verification
+func setupMismatchMockServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ioutil.ReadAll(r.Body)
+		r.Body.Close()
+
+		if r.Header.Get("X-Pact-Mock-Service") != "" {
+			if r.URL.Path == "/interactions/verification" {
+				http.Error(w, "Actual interactions do not match expected interactions", http.StatusInternalServerError)
+				return
+			}
+			fmt.Fprintln(w, "OK")
+			return
+		}
+
+		if r.URL.Path == "/error" {
+			http.Error(w, `{"message": "provider error"}`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, `{"message": "No interaction found for %s %s", "interaction_diffs": []}`, r.Method, r.URL.Path)
+	}))
+}
+
+func TestMockServerProxy_RecordsRequests(t *testing.T) {
+	ms := setupMismatchMockServer()
+	defer ms.Close()
+
+	proxy, err := startMockServerProxy("tcp", "localhost", 0, ms.URL, mockServerTimeouts{})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	defer proxy.Stop()
+
+	url := fmt.Sprintf("http://localhost:%d", proxy.Port)
+
+	req, _ := http.NewRequest("DELETE", url+"/interactions", nil)
+	req.Header.Set("X-Pact-Mock-Service", "true")
+	if _, err = http.DefaultClient.Do(req); err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	res, err := http.Post(url+"/users?name=billy", "application/json", strings.NewReader(`{"name":"billy"}`))
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	body, _ := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+
+	if !strings.Contains(string(body), "No interaction found for POST /users") {
+		t.Fatalf("expected the mock service response to be proxied but got '%s'", body)
+	}
+
+	if _, err = http.Get(url + "/error"); err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	requests := proxy.Requests()
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 recorded requests but got %d", len(requests))
+	}
+
+	rec := requests[0]
+	if rec.Method != "POST" || rec.Path != "/users" || rec.Query != "name=billy" || string(rec.Body) != `{"name":"billy"}` {
+		t.Fatalf("unexpected recorded request: %+v", rec)
+	}
+	if !rec.Unmatched || rec.Status != http.StatusInternalServerError || len(rec.Mismatch) == 0 {
+		t.Fatalf("expected the request to be recorded as unmatched: %+v", rec)
+	}
+	if requests[1].Unmatched {
+		t.Fatal("expected a provider error not to be treated as a mismatch")
+	}
+
+	proxy.Reset()
+	if len(proxy.Requests()) != 0 {
+		t.Fatal("expected recorded requests to be cleared")
+	}
+}
+
+func TestMockServerProxy_isMismatchResponse(t *testing.T) {
+	tests := map[string]bool{
+		`{"message": "No interaction found for GET /foo"}`:       true,
+		`{"message": "Multiple interaction found for GET /foo"}`: true,
+		`{"message": "something else"}`:                          false,
+		`No interaction found`:                                   false,
+	}
+
+	for body, want := range tests {
+		if got := isMismatchResponse([]byte(body)); got != want {
+			t.Fatalf("expected isMismatchResponse('%s') to be %v", body, want)
+		}
+	}
+}
+
+func TestMockServerProxy_WaitForInFlight(t *testing.T) {
+	release := make(chan struct{})
+	ms := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer ms.Close()
+
+	proxy, err := startMockServerProxy("tcp", "localhost", 0, ms.URL, mockServerTimeouts{read: time.Second, idle: 2 * time.Second})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	defer proxy.Stop()
+
+	if proxy.server.ReadTimeout != time.Second || proxy.server.IdleTimeout != 2*time.Second {
+		t.Fatal("expected the timeouts to be applied to the proxy")
+	}
+
+	done := make(chan struct{})
+	go func() {
+		http.Get(fmt.Sprintf("http://localhost:%d/retry", proxy.Port))
+		close(done)
+	}()
+	for len(proxy.Requests()) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	if proxy.WaitForInFlight(20 * time.Millisecond) {
+		t.Fatal("expected the request to still be in flight")
+	}
+
+	close(release)
+	if !proxy.WaitForInFlight(5 * time.Second) {
+		t.Fatal("expected the request to settle")
+	}
+	<-done
+}
@@ -0,0 +1,56 @@
+package dsl
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Stop stops the Mock Servers as per Teardown, once the requests to them still
+// in flight have completed, or ctx is done, e.g. so that a client with
+// keep-alive connections open doesn't see them reset mid-request. New requests
+// are refused, and idle connections closed, while waiting. Returns the error of
+// ctx if it was done before all requests completed.
+//
+// Only requests made through the proxy in front of the Mock Service, started
+// e.g. when MockServerShutdownTimeout is set, can be waited for.
+func (p *Pact) Stop(ctx context.Context) error {
+	start := time.Now()
+	if p.proxy == nil && p.socketProxy == nil && p.mtlsProxy == nil {
+		log.Println("[DEBUG] no proxy in front of the mock server, not waiting for requests in flight")
+	}
+
+	err := p.shutdownProxies(ctx)
+	p.Teardown()
+	log.Printf("[DEBUG] mock servers stopped in %s\n", time.Since(start))
+
+	return err
+}
+
+// shutdownProxies stops the proxies in front of the Mock Services once the
+// requests in flight have completed, or else closes them once ctx is done
+func (p *Pact) shutdownProxies(ctx context.Context) error {
+	var err error
+	shutdown := func(stop func(context.Context) error) {
+		if stopErr := stop(ctx); stopErr != nil && err == nil {
+			err = stopErr
+		}
+	}
+
+	// The socket proxy forwards to the recording proxy, if any, so that is
+	// stopped last for the requests in flight through the socket to complete
+	if p.socketProxy != nil {
+		shutdown(p.socketProxy.Shutdown)
+		p.socketProxy = nil
+	}
+	if p.mtlsProxy != nil {
+		shutdown(p.mtlsProxy.Shutdown)
+		p.mtlsProxy = nil
+	}
+	if p.proxy != nil {
+		shutdown(p.proxy.Shutdown)
+		p.proxy = nil
+	}
+
+	return err
+}
@@ -0,0 +1,193 @@
+package dsl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+// startSlowProxy starts a proxy in front of a Mock Service that takes delay to
+// respond, returning a channel closed once a request is in flight
+func startSlowProxy(t *testing.T, delay time.Duration) (*mockServerProxy, chan struct{}, func()) {
+	inFlight := make(chan struct{})
+	ms := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(inFlight)
+		time.Sleep(delay)
+		fmt.Fprint(w, "done")
+	}))
+
+	proxy, err := startMockServerProxy("tcp", "localhost", 0, ms.URL, mockServerTimeouts{})
+	if err != nil {
+		ms.Close()
+		t.Fatal("Error:", err)
+	}
+
+	return proxy, inFlight, ms.Close
+}
+
+// getAsync makes a request in the background, returning its result
+func getAsync(url string) chan error {
+	result := make(chan error, 1)
+	go func() {
+		res, err := http.Get(url)
+		if err != nil {
+			result <- err
+			return
+		}
+		body, err := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		if err == nil && string(body) != "done" {
+			err = fmt.Errorf("unexpected response '%s'", body)
+		}
+		result <- err
+	}()
+
+	return result
+}
+
+func TestPact_Stop(t *testing.T) {
+	proxy, inFlight, closeMockService := startSlowProxy(t, 200*time.Millisecond)
+	defer closeMockService()
+
+	pact := &Pact{pactClient: newMockClient(), proxy: proxy}
+	result := getAsync(fmt.Sprintf("http://localhost:%d/users", proxy.Port))
+	<-inFlight
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := pact.Stop(ctx); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := <-result; err != nil {
+		t.Fatal("expected the request in flight to complete but got:", err)
+	}
+	if pact.proxy != nil {
+		t.Fatal("expected the proxy to be stopped")
+	}
+
+	if _, err := http.Get(fmt.Sprintf("http://localhost:%d/users", proxy.Port)); err == nil {
+		t.Fatal("expected new requests to be refused once stopped")
+	}
+}
+
+func TestPact_StopTimeout(t *testing.T) {
+	proxy, inFlight, closeMockService := startSlowProxy(t, 500*time.Millisecond)
+	defer closeMockService()
+
+	pact := &Pact{pactClient: newMockClient(), proxy: proxy}
+	result := getAsync(fmt.Sprintf("http://localhost:%d/users", proxy.Port))
+	<-inFlight
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := pact.Stop(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the deadline to be exceeded but got '%v'", err)
+	}
+	if err := <-result; err == nil {
+		t.Fatal("expected the request in flight to fail once the proxy was closed")
+	}
+}
+
+func TestPact_TeardownShutdownTimeout(t *testing.T) {
+	proxy, inFlight, closeMockService := startSlowProxy(t, 200*time.Millisecond)
+	defer closeMockService()
+
+	pact := &Pact{pactClient: newMockClient(), proxy: proxy, MockServerShutdownTimeout: 5 * time.Second}
+	result := getAsync(fmt.Sprintf("http://localhost:%d/users", proxy.Port))
+	<-inFlight
+
+	pact.Teardown()
+	if err := <-result; err != nil {
+		t.Fatal("expected Teardown to wait for the request in flight but got:", err)
+	}
+}
+
+func TestPact_StopSocketProxy(t *testing.T) {
+	inFlight := make(chan struct{})
+	release := make(chan struct{})
+	ms := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			close(inFlight)
+			<-release
+		}
+		fmt.Fprint(w, "done")
+	}))
+	defer ms.Close()
+	var releaseOnce sync.Once
+	defer releaseOnce.Do(func() { close(release) })
+
+	dir, err := ioutil.TempDir("", "pact-go-socket")
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	defer os.RemoveAll(dir)
+
+	proxy, err := startMockServerProxy("tcp", "localhost", 0, ms.URL, mockServerTimeouts{})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	pact := &Pact{
+		pactClient: newMockClient(),
+		Server:     &types.MockServer{Port: proxy.Port},
+		Host:       "localhost",
+		UnixSocket: filepath.Join(dir, "mock.sock"),
+		proxy:      proxy,
+	}
+	if err = pact.startSocketProxy(); err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", pact.UnixSocket)
+		},
+	}}
+	result := make(chan error, 1)
+	go func() {
+		res, err := client.Get("http://docker/slow")
+		if err == nil {
+			body, _ := ioutil.ReadAll(res.Body)
+			res.Body.Close()
+			if string(body) != "done" {
+				err = fmt.Errorf("unexpected response %d '%s'", res.StatusCode, body)
+			}
+		}
+		result <- err
+	}()
+	<-inFlight
+
+	stopped := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		stopped <- pact.Stop(ctx)
+	}()
+
+	// The recording proxy must keep serving until the socket proxy has stopped
+	time.Sleep(50 * time.Millisecond)
+	if _, err := http.Get(fmt.Sprintf("http://localhost:%d/users", proxy.Port)); err != nil {
+		t.Fatal("expected the recording proxy to be stopped after the socket proxy but got:", err)
+	}
+
+	releaseOnce.Do(func() { close(release) })
+	if err := <-result; err != nil {
+		t.Fatal("expected the request in flight through the socket to complete but got:", err)
+	}
+	if err := <-stopped; err != nil {
+		t.Fatal("Error:", err)
+	}
+	if pact.proxy != nil || pact.socketProxy != nil {
+		t.Fatal("expected the proxies to be stopped")
+	}
+}
@@ -0,0 +1,156 @@
+package dsl
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+// MockServerStatus describes a Mock Server started by a Pact in this process
+type MockServerStatus struct {
+	// Port the Mock Server is listening on
+	Port int
+
+	// Transport of the Mock Server e.g. TransportHTTP
+	Transport string
+
+	// Consumer and Provider of the Pact the Mock Server was started by
+	Consumer string
+	Provider string
+
+	// Interactions is the number of interactions registered with the Mock
+	// Server by the current (or last) Verify
+	Interactions int
+
+	// Matched is the number of requests the Mock Server matched to an
+	// interaction during the current (or last) Verify, or -1 if requests are
+	// not recorded (see RecordMismatches)
+	Matched int
+
+	// StartedAt is when the Mock Server was started, and Uptime how long ago
+	StartedAt time.Time
+	Uptime    time.Duration
+}
+
+// runningMockServer is a Mock Server registered by a Pact
+type runningMockServer struct {
+	pact         *Pact
+	consumer     string
+	provider     string
+	transport    string
+	port         int
+	proxy        *mockServerProxy
+	startedAt    time.Time
+	interactions int
+}
+
+// runningMockServers are the Mock Servers started by Pacts in this process,
+// until they are torn down
+var runningMockServers = struct {
+	sync.Mutex
+	servers []*runningMockServer
+}{}
+
+// ListMockServers describes the Mock Servers started by Pacts in this process
+// that have not been torn down, in order of port. Useful when debugging a
+// stuck test suite.
+func ListMockServers() []MockServerStatus {
+	runningMockServers.Lock()
+	defer runningMockServers.Unlock()
+
+	statuses := make([]MockServerStatus, 0, len(runningMockServers.servers))
+	for _, s := range runningMockServers.servers {
+		status := MockServerStatus{
+			Port:         s.port,
+			Transport:    s.transport,
+			Consumer:     s.consumer,
+			Provider:     s.provider,
+			Interactions: s.interactions,
+			Matched:      -1,
+			StartedAt:    s.startedAt,
+			Uptime:       time.Since(s.startedAt),
+		}
+
+		if s.proxy != nil {
+			status.Matched = 0
+			for _, rec := range s.proxy.Requests() {
+				if !rec.Unmatched {
+					status.Matched++
+				}
+			}
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	sort.SliceStable(statuses, func(a, b int) bool {
+		return statuses[a].Port < statuses[b].Port
+	})
+
+	return statuses
+}
+
+// registerMockServer records a Mock Server started by the Pact, and the proxy
+// recording its requests, if any
+func (p *Pact) registerMockServer(transport string, server *types.MockServer, proxy *mockServerProxy) {
+	if server == nil || server.Error != nil {
+		return
+	}
+
+	runningMockServers.Lock()
+	defer runningMockServers.Unlock()
+
+	runningMockServers.servers = append(runningMockServers.servers, &runningMockServer{
+		pact:      p,
+		consumer:  p.Consumer,
+		provider:  p.Provider,
+		transport: transport,
+		port:      server.Port,
+		proxy:     proxy,
+		startedAt: time.Now(),
+	})
+}
+
+// proxiedMockServer records that the Mock Server for the transport, if
+// registered, is now reached through the proxy on the given port, as the proxy
+// may be started on demand after Setup
+func (p *Pact) proxiedMockServer(transport string, port int, proxy *mockServerProxy) {
+	runningMockServers.Lock()
+	defer runningMockServers.Unlock()
+
+	for _, s := range runningMockServers.servers {
+		if s.pact == p && s.transport == transport {
+			s.port = port
+			s.proxy = proxy
+		}
+	}
+}
+
+// registeredInteractions records the number of interactions registered with
+// the Mock Server for the transport
+func (p *Pact) registeredInteractions(transport string, count int) {
+	runningMockServers.Lock()
+	defer runningMockServers.Unlock()
+
+	for _, s := range runningMockServers.servers {
+		if s.pact == p && s.transport == transport {
+			s.interactions = count
+		}
+	}
+}
+
+// unregisterMockServers forgets the Mock Servers started by the Pact
+func (p *Pact) unregisterMockServers() {
+	runningMockServers.Lock()
+	defer runningMockServers.Unlock()
+
+	servers := runningMockServers.servers[:0]
+	for _, s := range runningMockServers.servers {
+		if s.pact != p {
+			servers = append(servers, s)
+		}
+	}
+	runningMockServers.servers = servers
+}
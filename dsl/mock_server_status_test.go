@@ -0,0 +1,107 @@
+package dsl
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+func TestMockServerStatus_ListMockServers(t *testing.T) {
+	ms := setupMockServer(true, t)
+	defer ms.Close()
+
+	client := newMockClient()
+	client.MockServer = &types.MockServer{Port: getPort(ms.URL)}
+	pact := &Pact{
+		Consumer:         "Status Consumer",
+		Provider:         "Status Provider",
+		RecordMismatches: true,
+		pactClient:       client,
+	}
+	pact.Setup(true)
+
+	status := func() *MockServerStatus {
+		for _, s := range ListMockServers() {
+			if s.Consumer == pact.Consumer {
+				return &s
+			}
+		}
+		return nil
+	}
+
+	s := status()
+	if s == nil {
+		t.Fatal("expected the mock server to be listed")
+	}
+	if s.Port != pact.Server.Port || s.Transport != TransportHTTP || s.Provider != "Status Provider" || s.Interactions != 0 || s.Matched != 0 {
+		t.Fatalf("unexpected status of a new mock server: %+v", s)
+	}
+	if time.Since(s.StartedAt) < s.Uptime {
+		t.Fatalf("expected the uptime to be since the mock server started: %+v", s)
+	}
+
+	pact.
+		AddInteraction().
+		UponReceiving("A request for the status").
+		WithRequest(Request{Method: "GET", Path: String("/status")}).
+		WillRespondWith(Response{Status: 200})
+
+	err := pact.Verify(func() error {
+		_, err := http.Get(fmt.Sprintf("http://%s:%d/status", pact.Host, pact.Server.Port))
+		return err
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	if s = status(); s == nil || s.Interactions != 1 || s.Matched != 1 {
+		t.Fatalf("expected the interaction and request to be counted: %+v", s)
+	}
+
+	pact.Teardown()
+	if s = status(); s != nil {
+		t.Fatalf("expected the mock server not to be listed once torn down: %+v", s)
+	}
+}
+
+func TestMockServerStatus_ProxyStartedOnDemand(t *testing.T) {
+	ms := setupMockServer(true, t)
+	defer ms.Close()
+
+	client := newMockClient()
+	client.MockServer = &types.MockServer{Port: getPort(ms.URL)}
+	pact := &Pact{
+		Consumer:   "On Demand Consumer",
+		Provider:   "Status Provider",
+		pactClient: client,
+	}
+	pact.Setup(true)
+	defer pact.Teardown()
+
+	pact.
+		AddInteraction().
+		UponReceiving("A request for the status").
+		WithRequest(Request{Method: "GET", Path: String("/status")}).
+		WillRespondWith(Response{Status: 200, Body: map[string]interface{}{"self": MockServerURL("http://localhost:8080/status", `.*(/status)$`)}})
+
+	err := pact.Verify(func() error {
+		_, err := http.Get(fmt.Sprintf("http://%s:%d/status", pact.Host, pact.Server.Port))
+		return err
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	for _, s := range ListMockServers() {
+		if s.Consumer == pact.Consumer {
+			if s.Port != pact.Server.Port || s.Matched != 1 {
+				t.Fatalf("expected the mock server to be listed on the port of the proxy %d: %+v", pact.Server.Port, s)
+			}
+			return
+		}
+	}
+	t.Fatal("expected the mock server to be listed")
+}
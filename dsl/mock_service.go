@@ -2,6 +2,7 @@ package dsl
 
 import (
 	"bytes"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -30,6 +31,13 @@ type MockService struct {
 	// are split over multiple files and instantiations of a Mock Server
 	// See https://github.com/pact-foundation/pact-ruby/blob/master/documentation/configuration.md#pactfile_write_mode
 	PactFileWriteMode string
+
+	// PactDir overrides the directory the Mock Service writes the pact to,
+	// given by --pact-dir when it was started.
+	PactDir string
+
+	// TLSConfig is used to connect to a Mock Service running over HTTPS
+	TLSConfig *tls.Config
 }
 
 // call sends a message to the Pact service
@@ -41,6 +49,9 @@ func (m *MockService) call(method string, url string, content interface{}) error
 	}
 
 	client := &http.Client{}
+	if m.TLSConfig != nil {
+		client.Transport = &http.Transport{TLSClientConfig: m.TLSConfig}
+	}
 	var req *http.Request
 	if method == "POST" {
 		req, err = http.NewRequest(method, url, bytes.NewReader(body))
@@ -74,6 +85,14 @@ func (m *MockService) DeleteInteractions() error {
 	return m.call("DELETE", url, nil)
 }
 
+// DeleteSession removes all Mock Service Interactions, including those that
+// have been verified but not yet written to a pact.
+func (m *MockService) DeleteSession() error {
+	log.Println("[DEBUG] mock service delete session")
+	url := fmt.Sprintf("%s/session", m.BaseURL)
+	return m.call("DELETE", url, nil)
+}
+
 // AddInteraction adds a new Pact Mock Service interaction.
 func (m *MockService) AddInteraction(interaction *Interaction) error {
 	log.Println("[DEBUG] mock service add interaction")
@@ -108,6 +127,9 @@ func (m *MockService) WritePact() error {
 		},
 		"pactFileWriteMode": m.PactFileWriteMode,
 	}
+	if m.PactDir != "" {
+		pact["pact_dir"] = m.PactDir
+	}
 
 	url := fmt.Sprintf("%s/pact", m.BaseURL)
 	return m.call("POST", url, pact)
@@ -0,0 +1,91 @@
+package dsl
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+// logExcerptLines is the most lines of a Mock Service log included in errors
+const logExcerptLines = 40
+
+// mockServiceLogFile returns the name of the log file for a Mock Service on the
+// given port, in the LogDir
+func (p *Pact) mockServiceLogFile(name string, port int) string {
+	if !p.LogPerMockServer {
+		return name
+	}
+
+	return fmt.Sprintf("%s-%d.log", strings.TrimSuffix(name, ".log"), port)
+}
+
+// mockServiceLog returns the path of the log of a Mock Service, if known
+func mockServiceLog(server *types.MockServer) string {
+	if server == nil {
+		return ""
+	}
+
+	for i, arg := range server.Args {
+		if arg == "--log" && i+1 < len(server.Args) {
+			return server.Args[i+1]
+		}
+	}
+
+	return ""
+}
+
+// logOffsets records the size of the log of each Mock Service, so that what
+// they log afterwards can be included in errors
+func logOffsets(servers map[string]*types.MockServer) map[string]int64 {
+	offsets := make(map[string]int64, len(servers))
+
+	for transport, server := range servers {
+		if info, err := os.Stat(mockServiceLog(server)); err == nil {
+			offsets[transport] = info.Size()
+		}
+	}
+
+	return offsets
+}
+
+// logExcerpt returns the last lines written to the log at path since offset
+func logExcerpt(path string, offset int64) string {
+	content := readLogSince(path, offset)
+	if content == "" {
+		return ""
+	}
+
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	if len(lines) > logExcerptLines {
+		lines = lines[len(lines)-logExcerptLines:]
+	}
+
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// readLogSince returns all that was written to the log at path since offset
+func readLogSince(path string, offset int64) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	// The log may have been truncated in the meantime
+	if info, err := f.Stat(); err != nil || info.Size() < offset {
+		offset = 0
+	}
+	if _, err = f.Seek(offset, 0); err != nil {
+		return ""
+	}
+
+	content, err := ioutil.ReadAll(f)
+	if err != nil {
+		return ""
+	}
+
+	return string(content)
+}
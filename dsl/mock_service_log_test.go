@@ -0,0 +1,93 @@
+package dsl
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+func TestMockServiceLog_FileName(t *testing.T) {
+	pact := &Pact{}
+	if got := pact.mockServiceLogFile("pact.log", 1234); got != "pact.log" {
+		t.Fatalf("expected the shared log file but got '%s'", got)
+	}
+
+	pact.LogPerMockServer = true
+	if got := pact.mockServiceLogFile("pact-tls.log", 1234); got != "pact-tls-1234.log" {
+		t.Fatalf("expected a log file per port but got '%s'", got)
+	}
+}
+
+func TestMockServiceLog_Excerpt(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pact-go-log")
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "pact.log")
+	ioutil.WriteFile(path, []byte("before\n"), 0644)
+	offsets := logOffsets(map[string]*types.MockServer{TransportHTTP: {Args: []string{"--log", path}}})
+
+	var lines []string
+	for i := 0; i < logExcerptLines+5; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	f.WriteString(strings.Join(lines, "\n") + "\n")
+	f.Close()
+
+	excerpt := logExcerpt(path, offsets[TransportHTTP])
+	if want := strings.Join(lines[5:], "\n"); excerpt != want {
+		t.Fatalf("expected the last %d lines since the offset but got '%s'", logExcerptLines, excerpt)
+	}
+
+	if excerpt = logExcerpt(filepath.Join(dir, "missing.log"), 0); excerpt != "" {
+		t.Fatalf("expected no excerpt of a missing log but got '%s'", excerpt)
+	}
+}
+
+func TestMockServiceLog_VerifyMismatch(t *testing.T) {
+	ms := setupMismatchMockServer()
+	defer ms.Close()
+
+	dir, err := ioutil.TempDir("", "pact-go-log")
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "pact.log")
+	ioutil.WriteFile(path, []byte("an earlier test\n"), 0644)
+
+	pact := &Pact{
+		Server: &types.MockServer{
+			Port: getPort(ms.URL),
+			Args: []string{"--log", path},
+		},
+	}
+
+	pact.
+		AddInteraction().
+		UponReceiving("Some name for the test").
+		WithRequest(Request{}).
+		WillRespondWith(Response{})
+
+	err = pact.Verify(func() error {
+		f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+		defer f.Close()
+		_, err := f.WriteString("No interaction found for GET /foo\n")
+		return err
+	})
+
+	if err == nil || !strings.Contains(err.Error(), "No interaction found for GET /foo") {
+		t.Fatalf("expected the log of the test in the error but got '%v'", err)
+	}
+	if strings.Contains(err.Error(), "an earlier test") {
+		t.Fatalf("expected only the log of the test in the error but got '%v'", err)
+	}
+}
@@ -1,6 +1,7 @@
 package dsl
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"log"
@@ -83,6 +84,26 @@ func TestMockService_DeleteInteractions(t *testing.T) {
 	}
 }
 
+func TestMockService_DeleteSession(t *testing.T) {
+	var path string
+	ms := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.Method + " " + r.URL.Path
+	}))
+	defer ms.Close()
+
+	mockService := &MockService{
+		BaseURL: ms.URL,
+	}
+	err := mockService.DeleteSession()
+
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if path != "DELETE /session" {
+		t.Fatalf("expected the session to be deleted but got '%s'", path)
+	}
+}
+
 func TestMockService_WritePact(t *testing.T) {
 	ms := setupMockServer(true, t)
 	defer ms.Close()
@@ -100,6 +121,28 @@ func TestMockService_WritePact(t *testing.T) {
 	}
 }
 
+func TestMockService_WritePactDir(t *testing.T) {
+	var body map[string]interface{}
+	ms := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+	}))
+	defer ms.Close()
+
+	mockService := &MockService{
+		BaseURL:  ms.URL,
+		Consumer: "Foo Consumer",
+		Provider: "Bar Provider",
+		PactDir:  "/tmp/pacts/case-1",
+	}
+
+	if err := mockService.WritePact(); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if body["pact_dir"] != "/tmp/pacts/case-1" {
+		t.Fatalf("expected the pact directory to be overridden but got %v", body)
+	}
+}
+
 func TestMockService_WritePactFail(t *testing.T) {
 	ms := setupMockServer(true, t)
 	defer ms.Close()
@@ -0,0 +1,312 @@
+package dsl
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+// testCA issues the certificates used for mTLS interactions, unless the
+// ClientCACert (and SSLCert) are given
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+// newTestCA generates a CA valid for a day, which is plenty for a test run
+func newTestCA() (*testCA, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "pact-go test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+
+	return &testCA{cert: cert, key: key}, nil
+}
+
+// issue returns a PEM encoded certificate and ECDSA key signed by the CA
+func (ca *testCA) issue(commonName string, usage x509.ExtKeyUsage, hosts ...string) ([]byte, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return ca.sign(&key.PublicKey, &pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}, commonName, usage, hosts)
+}
+
+// issueRSA is as per issue, with an RSA key, for the TLS Mock Service, which
+// only loads RSA keys
+func (ca *testCA) issueRSA(commonName string, usage x509.ExtKeyUsage, hosts ...string) ([]byte, []byte, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return ca.sign(&key.PublicKey, &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}, commonName, usage, hosts)
+}
+
+// sign returns a PEM encoded certificate for publicKey signed by the CA, along
+// with the PEM encoded private key
+func (ca *testCA) sign(publicKey interface{}, key *pem.Block, commonName string, usage x509.ExtKeyUsage, hosts []string) ([]byte, []byte, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 62))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     ca.cert.NotAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	for _, host := range hosts {
+		if ip := net.ParseIP(strings.Trim(host, "[]")); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else if host != "" {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, publicKey, ca.key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(key), nil
+}
+
+// testCA returns the CA for this Pact, generating it on first use
+func (p *Pact) testCA() (*testCA, error) {
+	if p.ca == nil {
+		ca, err := newTestCA()
+		if err != nil {
+			return nil, fmt.Errorf("unable to generate test CA: %v", err)
+		}
+		p.ca = ca
+	}
+
+	return p.ca, nil
+}
+
+// ClientCertificatePEM issues a PEM encoded client certificate and key trusted
+// by the Mock Server when RequireClientCert is set, e.g. to be written to
+// files for an API client configured by path. Not available if ClientCACert
+// is given, as the certificates must then be issued by your own CA.
+func (p *Pact) ClientCertificatePEM() (cert []byte, key []byte, err error) {
+	if p.ClientCACert != "" {
+		return nil, nil, types.NewError(types.ErrInvalidRequest, errors.New("client certificates must be issued by the ClientCACert"))
+	}
+
+	ca, err := p.testCA()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return ca.issue("pact-go client", x509.ExtKeyUsageClientAuth)
+}
+
+// ClientCertificate issues a client certificate trusted by the Mock Server when
+// RequireClientCert is set, as per ClientCertificatePEM
+func (p *Pact) ClientCertificate() (tls.Certificate, error) {
+	cert, key, err := p.ClientCertificatePEM()
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	return tls.X509KeyPair(cert, key)
+}
+
+// ClientTLSConfig returns a TLS config presenting a new client certificate (see
+// ClientCertificate), for API clients calling the Mock Server over TLS. The
+// certificate of the Mock Server is trusted when issued by the test CA, i.e.
+// with RequireClientCert or a TLSServerName, unless SSLCert is given.
+func (p *Pact) ClientTLSConfig() (*tls.Config, error) {
+	cert, err := p.ClientCertificate()
+	if err != nil {
+		return nil, err
+	}
+
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ServerName:   p.TLSServerName,
+	}
+	if p.SSLCert == "" {
+		config.RootCAs = x509.NewCertPool()
+		config.RootCAs.AddCert(p.ca.cert)
+	}
+
+	return config, nil
+}
+
+// clientCAs returns the CAs trusted to issue client certificates
+func (p *Pact) clientCAs() (*x509.CertPool, error) {
+	pool := x509.NewCertPool()
+
+	if p.ClientCACert != "" {
+		data, err := ioutil.ReadFile(p.ClientCACert)
+		if err != nil {
+			return nil, err
+		}
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no certificates found in ClientCACert '%s'", p.ClientCACert)
+		}
+
+		return pool, nil
+	}
+
+	ca, err := p.testCA()
+	if err != nil {
+		return nil, err
+	}
+	pool.AddCert(ca.cert)
+
+	return pool, nil
+}
+
+// serverCertificate returns the certificate presented by the mTLS proxy
+func (p *Pact) serverCertificate() (tls.Certificate, error) {
+	if p.SSLCert != "" || p.SSLKey != "" {
+		return tls.LoadX509KeyPair(p.SSLCert, p.SSLKey)
+	}
+
+	ca, err := p.testCA()
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	cert, key, err := ca.issue("pact-go mock server", x509.ExtKeyUsageServerAuth, p.TLSServerName, p.Host, "localhost", "127.0.0.1", "::1")
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	return tls.X509KeyPair(cert, key)
+}
+
+// mtlsProxy terminates TLS in front of the TLS Mock Service, which is unable
+// to require client certificates itself
+type mtlsProxy struct {
+	// Port the proxy is listening on
+	Port int
+
+	server *http.Server
+
+	// wrongScheme is the number of connections that sent plain HTTP
+	wrongScheme int64
+}
+
+// setupMTLSProxy starts the proxy requiring client certificates for
+// interactions over TLS, if required
+func (p *Pact) setupMTLSProxy() error {
+	if p.mtlsProxy != nil {
+		return nil
+	}
+
+	if p.SSLCert != "" && p.ClientCACert == "" {
+		return types.NewError(types.ErrInvalidRequest, errors.New("ClientCACert must be provided with SSLCert when RequireClientCert is set"))
+	}
+
+	cert, err := p.serverCertificate()
+	if err != nil {
+		return types.NewError(types.ErrInvalidRequest, fmt.Errorf("unable to load the mock server certificate: %v", err))
+	}
+	clientCAs, err := p.clientCAs()
+	if err != nil {
+		return types.NewError(types.ErrInvalidRequest, fmt.Errorf("unable to load client CAs: %v", err))
+	}
+
+	port, err := p.allocatePort()
+	if err != nil {
+		return types.NewError(types.ErrServiceStartup, err)
+	}
+
+	listener, err := net.Listen(p.Network, net.JoinHostPort(strings.Trim(p.Host, "[]"), strconv.Itoa(port)))
+	if err != nil {
+		return types.NewError(types.ErrServiceStartup, fmt.Errorf("unable to start mTLS proxy: %v", err))
+	}
+
+	target, _ := url.Parse(fmt.Sprintf("https://%s:%d", p.Host, p.tlsServer.Port))
+	reverseProxy := httputil.NewSingleHostReverseProxy(target)
+	reverseProxy.Transport = &http.Transport{
+		// The Mock Service is running locally, usually with a self-signed certificate
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+
+	proxy := &mtlsProxy{
+		Port:   listener.Addr().(*net.TCPAddr).Port,
+		server: &http.Server{Handler: reverseProxy},
+	}
+
+	go func() {
+		err := proxy.server.Serve(tls.NewListener(schemeListener{Listener: listener, tls: true, wrong: &proxy.wrongScheme}, &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			ClientAuth:   tls.RequireAndVerifyClientCert,
+			ClientCAs:    clientCAs,
+		}))
+		if err != nil && err != http.ErrServerClosed {
+			log.Println("[ERROR] mTLS proxy:", err)
+		}
+	}()
+
+	log.Println("[DEBUG] started mTLS proxy on port:", proxy.Port)
+	p.mtlsProxy = proxy
+
+	return nil
+}
+
+// Stop shuts the proxy down
+func (m *mtlsProxy) Stop() error {
+	log.Println("[DEBUG] stopping mTLS proxy")
+
+	return m.server.Close()
+}
+
+// Shutdown is as per mockServerProxy.Shutdown, for the mTLS proxy
+func (m *mtlsProxy) Shutdown(ctx context.Context) error {
+	log.Println("[DEBUG] shutting down mTLS proxy")
+
+	return shutdownServer(ctx, m.server)
+}
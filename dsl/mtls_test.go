@@ -0,0 +1,138 @@
+package dsl
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+func TestMTLS_RequireClientCert(t *testing.T) {
+	ms, _ := setupRecordingMockServer(false)
+	defer ms.Close()
+	tlsMs, tlsRequests := setupRecordingMockServer(true)
+	defer tlsMs.Close()
+
+	client := newMockClient()
+	client.MockServer = &types.MockServer{Port: getPort(tlsMs.URL)}
+
+	pact := &Pact{
+		Server:            &types.MockServer{Port: getPort(ms.URL)},
+		Host:              "localhost",
+		Network:           "tcp",
+		RequireClientCert: true,
+		pactClient:        client,
+	}
+	defer pact.Teardown()
+
+	pact.
+		AddInteraction().
+		UponReceiving("A mutual TLS request").
+		WithTransport(TransportHTTPS).
+		WithRequest(Request{Method: "GET", Path: String("/secure")}).
+		WillRespondWith(Response{Status: 200})
+
+	err := pact.VerifyWithTransports(func(servers map[string]*types.MockServer) error {
+		url := fmt.Sprintf("https://localhost:%d/secure", servers[TransportHTTPS].Port)
+		if servers[TransportHTTPS].Port == getPort(tlsMs.URL) {
+			return errors.New("expected requests over TLS to be made via the mTLS proxy")
+		}
+
+		config, err := pact.ClientTLSConfig()
+		if err != nil {
+			return err
+		}
+
+		anonymous := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: config.RootCAs}}}
+		if _, err = anonymous.Get(url); err == nil {
+			return errors.New("expected a request without a client certificate to fail")
+		}
+
+		authenticated := &http.Client{Transport: &http.Transport{TLSClientConfig: config}}
+		res, err := authenticated.Get(url)
+		if err != nil {
+			return err
+		}
+		res.Body.Close()
+
+		return nil
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	if !strings.Contains(strings.Join(tlsRequests(), ","), "GET /secure") {
+		t.Fatalf("expected the request to reach the TLS mock service, got %v", tlsRequests())
+	}
+}
+
+func TestMTLS_ClientCertificate(t *testing.T) {
+	pact := &Pact{}
+
+	cert, err := pact.ClientCertificate()
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if cert.PrivateKey == nil || len(cert.Certificate) != 1 {
+		t.Fatal("expected a client certificate and key")
+	}
+
+	pact.ClientCACert = "ca.pem"
+	if _, err = pact.ClientCertificate(); !errors.Is(err, types.ErrInvalidRequest) {
+		t.Fatalf("expected an invalid request error with a ClientCACert but got '%v'", err)
+	}
+}
+
+func TestMTLS_setupMTLSProxyInvalid(t *testing.T) {
+	pact := &Pact{SSLCert: "cert.pem", SSLKey: "key.pem", RequireClientCert: true}
+	if err := pact.setupMTLSProxy(); !errors.Is(err, types.ErrInvalidRequest) {
+		t.Fatalf("expected an invalid request error without a ClientCACert but got '%v'", err)
+	}
+
+	pact = &Pact{ClientCACert: "missing.pem", RequireClientCert: true}
+	if err := pact.setupMTLSProxy(); !errors.Is(err, types.ErrInvalidRequest) {
+		t.Fatalf("expected an invalid request error for a missing ClientCACert but got '%v'", err)
+	}
+}
+
+func TestMTLS_VerifySetupFailure(t *testing.T) {
+	ms := setupMockServer(true, t)
+	defer ms.Close()
+	tlsMS := httptest.NewTLSServer(ms.Config.Handler)
+	defer tlsMS.Close()
+
+	pact := &Pact{
+		Server:            &types.MockServer{Port: getPort(ms.URL)},
+		tlsServer:         &types.MockServer{Port: getPort(tlsMS.URL)},
+		RequireClientCert: true,
+		ClientCACert:      "missing.pem",
+	}
+	pact.
+		AddInteraction().
+		UponReceiving("A request over TLS").
+		WithTransport(TransportHTTPS).
+		WithRequest(Request{}).
+		WillRespondWith(Response{})
+
+	if err := pact.Verify(func() error { return nil }); !errors.Is(err, types.ErrInvalidRequest) {
+		t.Fatalf("expected an invalid request error for a missing ClientCACert but got '%v'", err)
+	}
+	if len(pact.Interactions) != 0 {
+		t.Fatalf("expected the interactions to be cleaned up but got %d", len(pact.Interactions))
+	}
+
+	pact.RequireClientCert = false
+	pact.
+		AddInteraction().
+		UponReceiving("A plain request").
+		WithRequest(Request{}).
+		WillRespondWith(Response{})
+	if err := pact.Verify(func() error { return nil }); err != nil {
+		t.Fatalf("expected the next Verify to be unaffected but got '%v'", err)
+	}
+}
@@ -0,0 +1,233 @@
+package dsl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// optionalClass identifies an Optional field in the serialised form of a body.
+// It is never sent to the Mock Service.
+const optionalClass = "PactGo::Optional"
+
+// optional is a field of a response body that may be absent
+type optional struct {
+	Contents interface{} `json:"contents"`
+}
+
+func (m optional) GetValue() interface{} {
+	return m.Contents
+}
+
+func (m optional) isMatcher() {
+}
+
+func (m optional) MarshalJSON() ([]byte, error) {
+	type marshaler optional
+
+	return json.Marshal(struct {
+		Type string `json:"json_class"`
+		marshaler
+	}{optionalClass, marshaler(m)})
+}
+
+// Optional marks a field of an object in a response body as one that may be
+// absent, but must match content (which may be a matcher) if present:
+//
+//	"nickname": dsl.Optional(dsl.Like("bob"))
+//
+// The Mock Service responds without the field, so that the consumer is tested
+// against its absence. The field is written to the "optionalFields" of the
+// interaction in the pact, and checked in any response of the provider that
+// includes it during verification (of pacts given as local PactURLs).
+//
+// Optional is not supported in requests, as unexpected keys are never allowed
+// in a request body.
+func Optional(content interface{}) Matcher {
+	return optional{
+		Contents: content,
+	}
+}
+
+// isOptional returns true for a serialised Optional field
+func isOptional(v interface{}) bool {
+	m, ok := v.(map[string]interface{})
+
+	return ok && m["json_class"] == optionalClass
+}
+
+// hasOptional returns true if the serialised value contains an Optional field
+func hasOptional(v interface{}) bool {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if isOptional(v) {
+			return true
+		}
+		// Optional fields in a JSONString are checked along with it
+		if v["json_class"] == jsonStringClass {
+			return false
+		}
+		for _, field := range v {
+			if hasOptional(field) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if hasOptional(item) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// withoutOptionalFields returns the serialised body v at path without its
+// Optional fields, and the serialised contents of each by its path
+func withoutOptionalFields(v interface{}, path string, fields map[string]interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		switch v["json_class"] {
+		case optionalClass:
+			return nil, fmt.Errorf("%s: Optional must be the value of a field of an object", path)
+		case "Pact::SomethingLike":
+			return withMatcherContents(v, path, fields)
+		case "Pact::ArrayLike":
+			return withMatcherContents(v, path+"[*]", fields)
+		case "Pact::Term", jsonStringClass:
+			return v, nil
+		}
+
+		object := make(map[string]interface{}, len(v))
+		for k, field := range v {
+			if isOptional(field) {
+				contents := field.(map[string]interface{})["contents"]
+				if hasOptional(contents) {
+					return nil, fmt.Errorf("%s: Optional fields may not be nested in one another", jsonPathField(path, k))
+				}
+				fields[jsonPathField(path, k)] = contents
+				continue
+			}
+
+			value, err := withoutOptionalFields(field, jsonPathField(path, k), fields)
+			if err != nil {
+				return nil, err
+			}
+			object[k] = value
+		}
+		return object, nil
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for n, item := range v {
+			value, err := withoutOptionalFields(item, fmt.Sprintf("%s[%d]", path, n), fields)
+			if err != nil {
+				return nil, err
+			}
+			items[n] = value
+		}
+		return items, nil
+	}
+
+	return v, nil
+}
+
+// withMatcherContents returns the serialised Like or EachLike matcher without
+// the Optional fields of its contents
+func withMatcherContents(m map[string]interface{}, path string, fields map[string]interface{}) (interface{}, error) {
+	contents, err := withoutOptionalFields(m["contents"], path, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	matcher := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		matcher[k] = v
+	}
+	matcher["contents"] = contents
+
+	return matcher, nil
+}
+
+// withOptionalFields returns a copy of the interaction whose response body has
+// no Optional fields, as sent to the Mock Service, and the Optional fields by
+// their path e.g. "$.body.nickname"
+func (i *Interaction) withOptionalFields() (*Interaction, map[string]interface{}, error) {
+	parts, err := serialisedParts(nil, nil, nil, i.Response.Body)
+	if err != nil || len(parts) == 0 || !hasOptional(parts[0].value) {
+		return i, nil, err
+	}
+
+	fields := make(map[string]interface{})
+	body, err := withoutOptionalFields(parts[0].value, parts[0].path, fields)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	interaction := *i
+	interaction.Response.Body = body
+
+	return &interaction, fields, nil
+}
+
+// validateOptionalFields checks that Optional fields are only given in the
+// response body
+func (i *Interaction) validateOptionalFields() error {
+	request, err := serialisedParts(i.Request.Path, i.Request.Query, i.Request.Headers, i.Request.Body)
+	if err != nil {
+		return err
+	}
+	for _, part := range request {
+		if hasOptional(part.value) {
+			return fmt.Errorf("request %s: Optional is only supported in response bodies", part.path)
+		}
+	}
+
+	for k, v := range i.Response.Headers {
+		if _, ok := v.(optional); ok {
+			return fmt.Errorf("response $.headers.%s: Optional is only supported in response bodies", k)
+		}
+	}
+
+	_, _, err = i.withOptionalFields()
+
+	return err
+}
+
+// recordOptionalFields remembers the Optional fields in the response body of an
+// interaction, so that they can be written to the pact file
+func (p *Pact) recordOptionalFields(key string, fields map[string]interface{}) {
+	if len(fields) == 0 {
+		return
+	}
+
+	if p.optionalFields == nil {
+		p.optionalFields = make(map[string]map[string]interface{})
+	}
+	p.optionalFields[key] = fields
+}
+
+// withInteractionField adds a field to an interaction in a compact pact,
+// unless it already has one by that name
+func withInteractionField(raw json.RawMessage, name string, value interface{}) json.RawMessage {
+	var interaction map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &interaction); err != nil || interaction[name] != nil || !bytes.HasSuffix(raw, []byte("}")) {
+		return raw
+	}
+
+	var encoded bytes.Buffer
+	encoder := json.NewEncoder(&encoded)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return raw
+	}
+
+	separator := ","
+	if bytes.Equal(raw, []byte("{}")) {
+		separator = ""
+	}
+
+	return json.RawMessage(strings.TrimSuffix(string(raw), "}") + separator + strconv.Quote(name) + ":" + string(bytes.TrimSpace(encoded.Bytes())) + "}")
+}
@@ -0,0 +1,100 @@
+package dsl
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestOptional_withOptionalFields(t *testing.T) {
+	i := (&Interaction{}).
+		UponReceiving("a request for a user").
+		WillRespondWith(Response{
+			Status: 200,
+			Body: map[string]interface{}{
+				"name":     "bob",
+				"nickname": Optional(Like("bobby")),
+				"friends": EachLike(map[string]interface{}{
+					"name":  Like("alice"),
+					"email": Optional(Term("alice@example.com", `^\S+@\S+$`)),
+				}, 1),
+			},
+		})
+
+	if err := i.validate(); err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	expected, fields, err := i.withOptionalFields()
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	body, _ := json.Marshal(expected.Response.Body)
+	if strings.Contains(string(body), "nickname") || strings.Contains(string(body), "email") || !strings.Contains(string(body), "alice") {
+		t.Fatalf("expected the optional fields to be removed from the body: %s", body)
+	}
+	if len(fields) != 2 || fields["$.body.nickname"] == nil || fields["$.body.friends[*].email"] == nil {
+		t.Fatalf("expected the optional fields by path: %v", fields)
+	}
+
+	if unchanged, none, _ := (&Interaction{}).WillRespondWith(Response{Body: "plain"}).withOptionalFields(); none != nil || unchanged.Response.Body != "plain" {
+		t.Fatal("expected a body without optional fields to be unchanged")
+	}
+}
+
+func TestOptional_Invalid(t *testing.T) {
+	for name, i := range map[string]*Interaction{
+		"request body": (&Interaction{}).WithRequest(Request{
+			Method: "POST",
+			Path:   String("/users"),
+			Body:   map[string]interface{}{"nickname": Optional("bobby")},
+		}),
+		"response header": (&Interaction{}).WillRespondWith(Response{
+			Headers: MapMatcher{"X-Nickname": Optional("bobby")},
+		}),
+		"whole body": (&Interaction{}).WillRespondWith(Response{
+			Body: Optional(map[string]interface{}{"name": "bob"}),
+		}),
+		"array item": (&Interaction{}).WillRespondWith(Response{
+			Body: []interface{}{Optional("bob")},
+		}),
+	} {
+		if err := i.validateOptionalFields(); err == nil {
+			t.Fatalf("%s: expected Optional to be rejected", name)
+		}
+	}
+}
+
+func TestOptional_withInteractionField(t *testing.T) {
+	raw := json.RawMessage(`{"description":"a","response":{"status":200}}`)
+	rewritten := withInteractionField(raw, "optionalFields", map[string]interface{}{"$.body.nickname": "bob"})
+
+	want := `{"description":"a","response":{"status":200},"optionalFields":{"$.body.nickname":"bob"}}`
+	if string(rewritten) != want {
+		t.Fatalf("expected '%s' but got '%s'", want, rewritten)
+	}
+	if string(withInteractionField(rewritten, "optionalFields", map[string]interface{}{"$.body.other": "x"})) != want {
+		t.Fatal("expected existing optional fields to be kept")
+	}
+}
+
+func TestOptional_checkBodyFields(t *testing.T) {
+	fields := map[string]interface{}{
+		"$.body.nickname":         map[string]interface{}{"json_class": "Pact::SomethingLike", "contents": "bobby"},
+		"$.body.friends[*].email": map[string]interface{}{"json_class": "Pact::Term", "data": map[string]interface{}{"generate": "a@b", "matcher": map[string]interface{}{"s": `^\S+@\S+$`}}},
+	}
+
+	for body, mismatches := range map[string]int{
+		`{"name":"bob"}`: 0,
+		`{"nickname":"b","friends":[{"email":"c@d"}]}`:   0,
+		`{"nickname":1,"friends":[{}]}`:                  1,
+		`{"friends":[{"email":"c@d"},{"email":"nope"}]}`: 1,
+		`{"nickname":null,"friends":[{"email":"nope"}]}`: 2,
+		`not json`: 0,
+	} {
+		if got := checkBodyFields([]byte(body), fields); len(got) != mismatches {
+			t.Fatalf("expected %d mismatches for %s but got %v", mismatches, body, got)
+		}
+	}
+}
@@ -5,9 +5,11 @@ collaboration test cases, and Provider contract test verification.
 package dsl
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"log"
 	"net"
@@ -16,7 +18,9 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"runtime/debug"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -57,10 +61,84 @@ type Pact struct {
 	// Defaults to `<cwd>/logs`.
 	LogDir string
 
+	// LogPerMockServer writes the log of each Mock Service to its own file in
+	// the LogDir, named after its port e.g. "pact-1234.log", rather than
+	// sharing "pact.log" with other Mock Services e.g. of parallel tests.
+	LogPerMockServer bool
+
 	// Pact files will be saved in this folder.
 	// Defaults to `<cwd>/pacts`.
 	PactDir string
 
+	// FileNameStrategy names the pact files written to the PactDir.
+	// Defaults to DefaultPactFileName, e.g. "myconsumer-myprovider.json".
+	// See also PactFileNameWithBranch.
+	FileNameStrategy PactFileNameStrategy
+
+	// Scenarios names the consumer and provider of the pact file of each
+	// scenario (see InScenario), by scenario name, so that the pact files of
+	// scenarios can be published without colliding with the pact between the
+	// Consumer and Provider. Optional, the Consumer and Provider by default.
+	Scenarios map[string]Scenario
+
+	// PactFileSortKeys sorts all keys in written pact files, as well as the
+	// interactions, so that pact files are stable byte for byte.
+	PactFileSortKeys bool
+
+	// RedactSecrets replaces the example credentials of interactions using
+	// WithBasicAuth or WithBearerToken in written pact files, whilst keeping
+	// their matchers, so that pact files can be shared without leaking them.
+	RedactSecrets bool
+
+	// BlockExternalRequests fails a test that makes requests to hosts other
+	// than the Mock Server (or AllowedHosts), e.g. due to a misconfigured base
+	// URL. Requests via http.DefaultTransport are guarded, as are those via a
+	// transport wrapped with GuardTransport. As http.DefaultTransport is shared
+	// by the whole process, tests with BlockExternalRequests run one at a time,
+	// even with t.Parallel, and requests made by other tests running in
+	// parallel meanwhile are guarded too.
+	BlockExternalRequests bool
+
+	// AllowedHosts may be called by the test when BlockExternalRequests is
+	// set, given as a host ("example.com") or host and port ("example.com:443")
+	AllowedHosts []string
+
+	// TestEnv sets environment variables whilst Verify runs the test, restoring
+	// them afterwards, e.g. for clients configured by the environment. In the
+	// values, ${PACT_MOCK_SERVER_URL} and ${PACT_MOCK_SERVER_PORT} are replaced
+	// by the URL and port of the Mock Server, and ${PACT_MOCK_SERVER_TLS_URL}
+	// and ${PACT_MOCK_SERVER_TLS_PORT} by those of the TLS Mock Server, if any.
+	// The environment is shared by the process, so tests using TestEnv must not
+	// be run in parallel.
+	TestEnv map[string]string
+
+	// MaxBodySize is the largest request or response body (or message content),
+	// in bytes, that an interaction may have. Guards against accidentally
+	// recording large payloads in pact files. Optional.
+	MaxBodySize int
+
+	// MaxInteractions is the most interactions (or messages) a pact file may
+	// have, once written. A pact with more fails with a types.ErrLimitExceeded
+	// error, and the pact file is left as it was. Optional.
+	MaxInteractions int
+
+	// UseJSONNumbers decodes the numbers of the JSON bodies that pact-go gives
+	// back to tests, i.e. of a PactDocument and of message content decoded to
+	// an AsType of maps or interfaces, as json.Number rather than float64, so
+	// that large integers such as snowflake IDs keep their precision. Numbers
+	// written to pacts always keep their precision. Optional.
+	UseJSONNumbers bool
+
+	// ApprovedPactDir is the directory of the approved pact files, e.g. as
+	// committed to the repository. When set, WritePact fails with a diff if a
+	// pact written to the PactDir differs from the approved pact of the same
+	// name, giving a chance to review changes to the contract. Optional.
+	ApprovedPactDir string
+
+	// ApprovePactChanges updates the pacts in the ApprovedPactDir with those
+	// written, rather than failing. Can also be enabled by setting PACT_APPROVE.
+	ApprovePactChanges bool
+
 	// PactFileWriteMode specifies how to write to the Pact file, for the life
 	// of a Mock Service.
 	// "overwrite" will always truncate and replace the pact after each run
@@ -87,6 +165,29 @@ type Pact struct {
 	// Example "1234", "12324,5667", "1234-5667"
 	AllowedMockServerPorts string
 
+	// PortAllocator finds ports for the Mock Service (unless
+	// AllowedMockServerPorts is given), and the proxies used by this package.
+	// Defaults to utils.EphemeralPorts. See also utils.PortRange and
+	// utils.PortsFromEnv.
+	PortAllocator utils.PortAllocator
+
+	// Clock is the source of the current time for generated values, such as
+	// GeneratedDateTime, and the time recorded in the VerificationCacheDir.
+	// Set it to return a fixed time for reproducible output e.g. in snapshot
+	// tests. Defaults to time.Now
+	Clock func() time.Time
+
+	// Random is the source of randomness for generated values, such as
+	// GeneratedUUID. Set it to a seeded source for reproducible output.
+	// Defaults to a source seeded with the Seed
+	Random io.Reader
+
+	// Seed seeds the source of randomness for generated values, if Random is
+	// not set, so that each verification generates the same values on every
+	// run e.g. to reproduce a failure. Can also be set with PACT_SEED. If
+	// neither is set, a random seed is used and logged at INFO
+	Seed int64
+
 	// DisableToolValidityCheck prevents CLI version checking - use this carefully!
 	// The ideal situation is to check the tool installation with  before running
 	// the tests, which should speed up large test suites significantly
@@ -97,8 +198,191 @@ type Pact struct {
 	// Defaults to 10s
 	ClientTimeout time.Duration
 
+	// MockServerReadTimeout is the longest the Mock Server waits to read a
+	// request, including its body, and MockServerIdleTimeout the longest it
+	// keeps an idle keep-alive connection open, e.g. to test the retries of a
+	// client. They are applied by a proxy in front of the Mock Service, which
+	// is started when either is set. Interactions over TLS are not supported.
+	// Default to no timeout.
+	MockServerReadTimeout time.Duration
+	MockServerIdleTimeout time.Duration
+
+	// MockServerShutdownTimeout is the longest Teardown waits for requests to
+	// the Mock Server still in flight to complete, before stopping it, as per
+	// Stop. It is applied by a proxy in front of the Mock Service, which is
+	// started when it is set. Defaults to stopping the Mock Server at once.
+	MockServerShutdownTimeout time.Duration
+
+	// SettleTimeout is how long Verify waits, once the test has returned, for
+	// requests to the Mock Server still in flight (e.g. retries made by the
+	// client in the background) to be responded to, before checking that the
+	// interactions were matched. Requests are tracked by a proxy in front of the
+	// Mock Service, as per MockServerReadTimeout. Defaults to not waiting.
+	SettleTimeout time.Duration
+
+	// QuiesceWindow makes Verify wait, once the test has returned, until no
+	// requests have been made to the Mock Server for the window, e.g. for
+	// asynchronous clients, giving up after the SettleTimeout (or 10s if not
+	// set). Requests are tracked as per SettleTimeout. See also
+	// AwaitInteractions. Defaults to not waiting.
+	QuiesceWindow time.Duration
+
+	// UpdateFixtures writes the actual body of any request the Mock Service was
+	// unable to match as a suggested fixture update, alongside a diff against the
+	// expected body. Can also be enabled by setting PACT_UPDATE_FIXTURES.
+	UpdateFixtures bool
+
+	// IgnoreUnexpectedRequests logs, rather than fails Verify for, requests to
+	// the Mock Server that match no interaction, e.g. benign extra calls made
+	// by exploratory tests or shared fixtures. Requests that match an
+	// interaction incorrectly, and interactions not requested, still fail.
+	IgnoreUnexpectedRequests bool
+
+	// RecordMismatches records the responses of the Mock Service to requests it
+	// was unable to match, so that they can be retrieved with RawMismatches.
+	RecordMismatches bool
+
+	// OnInteractionMatched is called as each request made by the code under
+	// test is matched to an interaction by the Mock Service, e.g. to collect
+	// metrics. It is called from the goroutine serving the request. Interactions
+	// over TLS are not supported.
+	OnInteractionMatched func(RequestEvent)
+
+	// OnUnmatchedRequest is called as each request made by the code under test
+	// is not matched to an interaction by the Mock Service, e.g. to fail the
+	// test early, as per OnInteractionMatched.
+	OnUnmatchedRequest func(RequestEvent)
+
+	// AccessLog writes a line for each request made to the Mock Server by the
+	// code under test (its method, path, status, latency and the interaction
+	// it matched, or "unmatched") to a log in the LogDir named after the test,
+	// e.g. "access-TestClient_GetUser.log". The unmatched requests are included
+	// when Verify fails, see UnmatchedRequests. Interactions over TLS are not
+	// supported.
+	AccessLog bool
+
+	// WireLog writes a dump of each request made to the Mock Server by the code
+	// under test, and of its response, to a log in the LogDir named after the
+	// test, e.g. "wire-TestClient_GetUser.log", to debug mismatches that the
+	// diff doesn't explain. Bodies are truncated to WireLogMaxBodySize, and
+	// headers redacted by WireLogRedact. Interactions over TLS are not
+	// supported.
+	WireLog bool
+
+	// WireLogMaxBodySize is the most of each body, in bytes, written to the
+	// WireLog. Defaults to 4096.
+	WireLogMaxBodySize int
+
+	// WireLogRedact returns the value of a header as written to the WireLog.
+	// Defaults to RedactAuthorization.
+	WireLogRedact func(name string, value string) string
+
+	// ResponseVariations is the number of times Verify runs the test again,
+	// once it has passed, with the JSON bodies of the responses to the
+	// interactions over HTTP replaced by random values that still match their
+	// matchers, e.g. strings of other lengths, other numbers and arrays of
+	// other sizes, and without Optional fields, to flush out assumptions about
+	// the example values. Only the examples are written to the pact. A failed
+	// variation can be repeated by setting the PACT_SEED it reports.
+	ResponseVariations int
+
+	// PassthroughURL is the base URL of a real provider that requests to the
+	// Mock Server are forwarded to when their method and path match none of
+	// the interactions being verified, rather than failing the test, e.g. to
+	// adopt contract tests gradually. These requests are logged as
+	// "passthrough" in the AccessLog. Interactions over TLS are not supported.
+	PassthroughURL string
+
+	// UnixSocket is the path of a Unix domain socket the Mock Server also
+	// listens on, e.g. for clients of local daemons such as the Docker API. It
+	// is given to the test as the SocketPath of the Mock Server. Interactions
+	// over TLS are not supported.
+	UnixSocket string
+
 	// Check if CLI tools are up to date
 	toolValidityCheck bool
+
+	// SSLCert and SSLKey are the paths to the certificate and key used by the
+	// Mock Service for interactions over TLS. A self-signed certificate is
+	// used if not provided.
+	SSLCert string
+	SSLKey  string
+
+	// TLSServerName is the host name (SNI) the TLS Mock Service presents a
+	// certificate for, issued by a test CA, unless SSLCert is given. Use
+	// ClientTLSConfig to trust it.
+	TLSServerName string
+
+	// RequireClientCert requires the code under test to present a client
+	// certificate, signed by the ClientCACert, for interactions over TLS. A
+	// test CA is used if ClientCACert is not given, see ClientCertificate.
+	RequireClientCert bool
+
+	// ClientCACert is the path to the PEM encoded CA certificate(s) trusted to
+	// issue client certificates when RequireClientCert is set.
+	ClientCACert string
+
+	// Records requests made to the Mock Service, when required
+	proxy *mockServerProxy
+
+	// accessLogFile is the access log written by the last Verify, and
+	// unmatched the lines of the requests in it that were not matched
+	accessLogFile string
+	unmatched     []string
+
+	// Serves the Mock Server on the UnixSocket, if any
+	socketProxy *socketProxy
+
+	// Mock Service for interactions over TLS, started on demand
+	tlsServer *types.MockServer
+
+	// Proxy requiring client certificates for interactions over TLS, and the
+	// CA issuing them, started on demand
+	mtlsProxy *mtlsProxy
+	ca        *testCA
+
+	// Directory of the certificate issued for the TLSServerName
+	tlsCertDir string
+
+	// Names of the Go tests that defined each interaction, by interactionKey
+	testNames map[string]string
+
+	// Request headers to redact from each interaction, by interactionKey
+	secretHeaders map[string][]string
+
+	// Generators in the request body of each interaction, by interactionKey
+	requestGenerators map[string]map[string]requestGenerator
+
+	// Interactions verified successfully, by interactionKey
+	verified map[string]*Interaction
+
+	// Scenario of each interaction in one, by interactionKey
+	scenarios map[string]string
+
+	// Optional fields in the response body of each interaction, by
+	// interactionKey
+	optionalFields map[string]map[string]interface{}
+
+	// JSONStrings in the request and response of each interaction, by
+	// interactionKey
+	jsonStrings map[string]map[string]map[string]interface{}
+
+	// Matchers in the metadata of each message, by interactionKey
+	metadataMatchers map[string]map[string]interface{}
+
+	// InteractionSet of each interaction published in one, by interactionKey
+	interactionSets map[string]interactionSetField
+
+	// interactionsMu guards the Interactions, which parallel subtests may add
+	// to whilst another is verified
+	interactionsMu sync.Mutex
+
+	// verifyMu serialises Verify, as the Mock Service holds the interactions
+	// of one test at a time
+	verifyMu sync.Mutex
+
+	// setupMu serialises Setup, which is called on first use by each test
+	setupMu sync.Mutex
 }
 
 // AddMessage creates a new asynchronous consumer expectation
@@ -106,7 +390,7 @@ func (p *Pact) AddMessage() *Message {
 	p.setupLogging()
 	log.Println("[DEBUG] pact add message")
 
-	m := &Message{}
+	m := &Message{testName: callerTestName()}
 	p.MessageInteractions = append(p.MessageInteractions, m)
 	return m
 }
@@ -117,18 +401,65 @@ func (p *Pact) WithClient(c Client) {
 
 // AddInteraction creates a new Pact interaction, initialising all
 // required things. Will automatically start a Mock Service if none running.
+//
+// Interactions may be added, and verified with selectors (e.g. SelectTags), by
+// parallel subtests sharing the Pact, and Verify calls then run one at a time.
+// The interaction is visible to the Verify of other subtests as soon as it is
+// added though, so in parallel subtests build it first and add it with
+// AddInteractions instead.
 func (p *Pact) AddInteraction() *Interaction {
 	p.Setup(true)
 	log.Println("[DEBUG] pact add interaction")
-	i := &Interaction{}
-	p.Interactions = append(p.Interactions, i)
+	i := &Interaction{testName: callerTestName()}
+	p.addInteractions(i)
 	return i
 }
 
+// addInteractions adds interactions to be verified, safe for use by parallel
+// subtests
+func (p *Pact) addInteractions(interactions ...*Interaction) {
+	p.interactionsMu.Lock()
+	defer p.interactionsMu.Unlock()
+
+	p.Interactions = append(p.Interactions, interactions...)
+}
+
+// snapshotInteractions returns a copy of the Interactions, so that those
+// added by parallel subtests whilst they are verified are left alone
+func (p *Pact) snapshotInteractions() []*Interaction {
+	p.interactionsMu.Lock()
+	defer p.interactionsMu.Unlock()
+
+	return append([]*Interaction{}, p.Interactions...)
+}
+
+// removeInteractions removes the verified interactions, keeping any added
+// since they were selected
+func (p *Pact) removeInteractions(verified []*Interaction) {
+	p.interactionsMu.Lock()
+	defer p.interactionsMu.Unlock()
+
+	removed := make(map[*Interaction]bool, len(verified))
+	for _, i := range verified {
+		removed[i] = true
+	}
+
+	var kept []*Interaction
+	for _, i := range p.Interactions {
+		if !removed[i] {
+			kept = append(kept, i)
+		}
+	}
+	p.Interactions = kept
+}
+
 // Setup starts the Pact Mock Server. This is usually called before each test
 // suite begins. AddInteraction() will automatically call this if no Mock Server
 // has been started.
 func (p *Pact) Setup(startMockServer bool) *Pact {
+	p.setupMu.Lock()
+	defer p.setupMu.Unlock()
+
 	p.setupLogging()
 	log.Println("[DEBUG] pact setup")
 	dir, _ := os.Getwd()
@@ -172,39 +503,92 @@ func (p *Pact) Setup(startMockServer bool) *Pact {
 		p.PactFileWriteMode = "overwrite"
 	}
 
+	if p.Server == nil && startMockServer {
+		p.Server = p.startMockServer("pact.log", p.PactFileWriteMode)
+
+		if p.updateFixtures() || p.RecordMismatches || p.hasRequestHooks() || p.AccessLog || p.WireLog || p.PassthroughURL != "" || p.ResponseVariations > 0 {
+			p.startProxy()
+		}
+
+		if err := p.setupPassthrough(); err != nil {
+			log.Println("[ERROR]", err)
+		}
+
+		if err := p.startSocketProxy(); err != nil {
+			log.Println("[ERROR]", err)
+		}
+
+		p.registerMockServer(TransportHTTP, p.Server, p.proxy)
+	}
+
+	return p
+}
+
+// startMockServer starts a Mock Service on a free port, logging to the given
+// file in the LogDir
+func (p *Pact) startMockServer(logFile string, pactFileWriteMode string, extraArgs ...string) *types.MockServer {
 	// Need to predefine due to scoping
 	var port int
 	var perr error
 	if p.AllowedMockServerPorts != "" {
 		port, perr = utils.FindPortInRange(p.AllowedMockServerPorts)
 	} else {
-		port, perr = utils.GetFreePort()
+		port, perr = p.allocatePort()
 	}
 	if perr != nil {
 		log.Println("[ERROR] unable to find free port, mockserver will fail to start")
 	}
 
-	if p.Server == nil && startMockServer {
-		log.Println("[DEBUG] starting mock service on port:", port)
-		args := []string{
-			"--pact-specification-version",
-			fmt.Sprintf("%d", p.SpecificationVersion),
-			"--pact-dir",
-			filepath.FromSlash(p.PactDir),
-			"--log",
-			filepath.FromSlash(p.LogDir + "/" + "pact.log"),
-			"--consumer",
-			p.Consumer,
-			"--provider",
-			p.Provider,
-			"--pact-file-write-mode",
-			p.PactFileWriteMode,
-		}
+	log.Println("[DEBUG] starting mock service on port:", port)
+	args := []string{
+		"--pact-specification-version",
+		fmt.Sprintf("%d", p.SpecificationVersion),
+		"--pact-dir",
+		filepath.FromSlash(p.cliPactDir()),
+		"--log",
+		filepath.FromSlash(p.LogDir + "/" + p.mockServiceLogFile(logFile, port)),
+		"--consumer",
+		p.Consumer,
+		"--provider",
+		p.Provider,
+		"--pact-file-write-mode",
+		pactFileWriteMode,
+	}
 
-		p.Server = p.pactClient.StartServer(args, port)
+	return p.pactClient.StartServer(append(args, extraArgs...), port)
+}
+
+// startProxy places a recording proxy in front of the Mock Service. The proxy
+// port is advertised as the Mock Service port, so that it receives all requests.
+func (p *Pact) startProxy() {
+	if p.proxy != nil {
+		return
 	}
 
-	return p
+	port, err := p.allocatePort()
+	if err != nil {
+		log.Println("[ERROR] unable to find a free port to record requests to the mock server:", err)
+		return
+	}
+
+	timeouts := mockServerTimeouts{
+		read: p.MockServerReadTimeout,
+		idle: p.MockServerIdleTimeout,
+	}
+	proxy, err := startMockServerProxy(p.Network, p.Host, port, fmt.Sprintf("http://%s:%d", p.Host, p.Server.Port), timeouts)
+	if err != nil {
+		log.Println("[ERROR] unable to record requests to the mock server:", err)
+		return
+	}
+
+	if p.hasRequestHooks() {
+		proxy.onResponse = p.requestMade
+	}
+	proxy.captureResponses = p.WireLog
+
+	p.proxy = proxy
+	p.Server.Port = proxy.Port
+	p.proxiedMockServer(TransportHTTP, proxy.Port, proxy)
 }
 
 // Configure logging
@@ -227,6 +611,39 @@ func (p *Pact) setupLogging() {
 // of each test suite.
 func (p *Pact) Teardown() *Pact {
 	log.Println("[DEBUG] teardown")
+	if p.MockServerShutdownTimeout > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), p.MockServerShutdownTimeout)
+		if err := p.shutdownProxies(ctx); err != nil {
+			log.Println("[WARN] stopped the mock server before all requests completed:", err)
+		}
+		cancel()
+	}
+	if p.proxy != nil {
+		if err := p.proxy.Stop(); err != nil {
+			log.Println("error:", err)
+		}
+		p.proxy = nil
+	}
+	if p.socketProxy != nil {
+		if err := p.socketProxy.Stop(); err != nil {
+			log.Println("error:", err)
+		}
+		p.socketProxy = nil
+	}
+	if p.mtlsProxy != nil {
+		if err := p.mtlsProxy.Stop(); err != nil {
+			log.Println("error:", err)
+		}
+		p.mtlsProxy = nil
+	}
+	if p.tlsServer != nil {
+		if _, err := p.pactClient.StopServer(p.tlsServer); err != nil {
+			log.Println("error:", err)
+		}
+		p.tlsServer = nil
+	}
+	p.removeTLSServerName()
+	p.unregisterMockServers()
 	if p.Server != nil {
 		server, err := p.pactClient.StopServer(p.Server)
 
@@ -240,70 +657,252 @@ func (p *Pact) Teardown() *Pact {
 
 // Verify runs the current test case against a Mock Service.
 // Will cleanup interactions between tests within a suite.
-func (p *Pact) Verify(integrationTest func() error) error {
+//
+// If selectors are given (e.g. SelectTags), only the interactions matching any
+// of them are registered with the Mock Service and cleaned up, leaving the
+// rest to be verified by other tests.
+func (p *Pact) Verify(integrationTest func() error, selectors ...InteractionSelector) error {
+	return p.VerifyWithTransports(func(map[string]*types.MockServer) error {
+		return integrationTest()
+	}, selectors...)
+}
+
+// VerifyWithTransports is as per Verify, but supports interactions over more
+// than one transport (see Interaction.WithTransport) in a single test. The
+// test is passed the Mock Server for each transport, keyed by TransportHTTP
+// or TransportHTTPS.
+func (p *Pact) VerifyWithTransports(integrationTest func(servers map[string]*types.MockServer) error, selectors ...InteractionSelector) error {
+	p.verifyMu.Lock()
+	defer p.verifyMu.Unlock()
+
+	if p.ResponseVariations > 0 {
+		return p.verifyWithVariations(integrationTest, selectors...)
+	}
+
+	return p.verifyWithTransports(integrationTest, selectors...)
+}
+
+// verifyWithTransports verifies the interactions once, as per VerifyWithTransports
+func (p *Pact) verifyWithTransports(integrationTest func(servers map[string]*types.MockServer) error, selectors ...InteractionSelector) error {
 	p.Setup(true)
 	log.Println("[DEBUG] pact verify")
 	var err error
 
 	// Check if we are verifying messages or if we actually have interactions
-	if len(p.Interactions) == 0 {
-		return errors.New("there are no interactions to be verified")
+	all := p.snapshotInteractions()
+	if len(all) == 0 {
+		return types.NewError(types.ErrInvalidRequest, errors.New("there are no interactions to be verified"))
 	}
 
-	mockServer := &MockService{
-		BaseURL:  fmt.Sprintf("http://%s:%d", p.Host, p.Server.Port),
-		Consumer: p.Consumer,
-		Provider: p.Provider,
+	selected, _ := selectInteractions(all, selectors)
+	if len(selected) == 0 {
+		return types.NewError(types.ErrInvalidRequest, fmt.Errorf("none of the interactions (%s) were selected to be verified", descriptionsOf(all)))
 	}
 
-	// Cleanup all interactions
-	defer func(mockServer *MockService) {
+	p.setupSequences(selected)
+
+	// Cleanup all interactions, including when they fail to be set up
+	mockServers := make(map[string]*MockService)
+	defer func() {
 		log.Println("[DEBUG] clearing interactions")
 
-		p.Interactions = make([]*Interaction, 0)
-		err = mockServer.DeleteInteractions()
-	}(mockServer)
+		p.removeInteractions(selected)
+		for _, mockServer := range mockServers {
+			err = mockServer.DeleteInteractions()
+		}
+	}()
+
+	interactions := make(map[string][]*Interaction)
+	for _, interaction := range expandSequences(selected) {
+		if err = interaction.validate(); err != nil {
+			return types.NewError(types.ErrInvalidRequest, err)
+		}
+		if err = p.checkBodySizes(interaction); err != nil {
+			return types.NewError(types.ErrInvalidRequest, err)
+		}
+		interactions[interaction.Transport()] = append(interactions[interaction.Transport()], interaction)
+		p.recordTestName(interactionKey(interaction.Description, interaction.State), interaction.testName)
+		p.recordSecretHeaders(interactionKey(interaction.Description, interaction.State), interaction.secretHeaders)
+		p.recordRequestGenerators(interactionKey(interaction.Description, interaction.State), interaction.Request.Body)
+		p.recordScenario(interactionKey(interaction.Description, interaction.State), interaction.scenario)
+		p.recordInteractionSet(interactionKey(interaction.Description, interaction.State), interaction.set)
+	}
 
-	for _, interaction := range p.Interactions {
-		err = mockServer.AddInteraction(interaction)
-		if err != nil {
+	if len(interactions[TransportHTTPS]) > 0 {
+		if err = p.setupTLSServer(); err != nil {
 			return err
 		}
+		if p.RequireClientCert {
+			if err = p.setupMTLSProxy(); err != nil {
+				return err
+			}
+		}
+	}
+
+	p.setupGenerators(interactions)
+	p.setupJSONStrings(interactions)
+	if p.MockServerReadTimeout > 0 || p.MockServerIdleTimeout > 0 || p.MockServerShutdownTimeout > 0 || p.SettleTimeout > 0 || p.QuiesceWindow > 0 {
+		p.startProxy()
+	}
+
+	servers := p.mockServers()
+	offsets := logOffsets(servers)
+	wrongSchemes := p.wrongSchemeConnections()
+	for transport := range servers {
+		mockServers[transport] = p.mockService(transport)
+	}
+
+	for _, transport := range transports {
+		if _, ok := servers[transport]; ok {
+			p.registeredInteractions(transport, len(interactions[transport]))
+		}
+
+		for _, interaction := range interactions[transport] {
+			expected, err := interaction.withMatchingModes()
+			if err != nil {
+				return types.NewError(types.ErrInvalidRequest, err)
+			}
+			expected, fields, err := expected.withOptionalFields()
+			if err != nil {
+				return types.NewError(types.ErrInvalidRequest, err)
+			}
+			p.recordOptionalFields(interactionKey(interaction.Description, interaction.State), fields)
+			expected, jsonStrings, err := expected.withJSONStrings()
+			if err != nil {
+				return types.NewError(types.ErrInvalidRequest, err)
+			}
+			p.recordJSONStrings(interactionKey(interaction.Description, interaction.State), jsonStrings)
+
+			err = mockServers[transport].AddInteraction(expected)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if p.proxy != nil {
+		p.proxy.Reset()
+		p.proxy.SetRoutes(requestsOf(interactions[TransportHTTP]))
+		p.proxy.SetInteractions(interactions[TransportHTTP])
+	}
+
+	// Interactions over TLS are made via the proxy requiring client certificates
+	if p.mtlsProxy != nil {
+		servers[TransportHTTPS] = &types.MockServer{Port: p.mtlsProxy.Port}
+	}
+
+	var guard *requestGuard
+	if p.BlockExternalRequests {
+		guard = p.newRequestGuard(servers)
+		guard.install()
+		defer guard.uninstall()
 	}
 
 	// Run the integration test
-	err = integrationTest()
-	if err != nil {
-		return err
+	restoreEnv := p.setTestEnv(servers)
+	err = runIntegrationTest(integrationTest, servers)
+	restoreEnv()
+
+	p.settle()
+	p.writeAccessLog(interactions[TransportHTTP])
+	p.writeWireLog(interactions[TransportHTTP])
+
+	if guard != nil {
+		guard.uninstall()
+		if guardErr := guard.err(); guardErr != nil {
+			err = types.NewError(types.ErrMismatch, guardErr)
+		}
+	}
+
+	if err == nil {
+		if jsonErr := p.checkJSONStrings(interactions[TransportHTTP]); jsonErr != nil {
+			err = types.NewError(types.ErrMismatch, jsonErr)
+		}
+	}
+	if err == nil {
+		if redirectErr := p.checkRedirects(interactions[TransportHTTP]); redirectErr != nil {
+			err = types.NewError(types.ErrMismatch, redirectErr)
+		}
 	}
 
 	// Run Verification Process
-	err = mockServer.Verify()
-	if err != nil {
-		return err
+	for _, transport := range transports {
+		if mockServer, ok := mockServers[transport]; ok && err == nil {
+			err = mockServer.Verify()
+			if p.IgnoreUnexpectedRequests {
+				err = ignoreUnexpectedRequests(err)
+			}
+			if err != nil {
+				if mismatches := p.schemeMismatches(transport, servers, interactions, wrongSchemes, offsets); len(mismatches) > 0 {
+					err = fmt.Errorf("%w\n\n%s", err, strings.Join(mismatches, "\n"))
+				}
+				if names := testNamesOf(interactions[transport]); len(names) > 0 {
+					err = fmt.Errorf("%w\n\nInteractions were defined by: %s", err, strings.Join(names, ", "))
+				}
+				if unmatched := p.unmatchedRequests(transport); len(unmatched) > 0 {
+					err = fmt.Errorf("%w\n\nUnmatched requests (%s):\n%s", err, p.accessLogFile, strings.Join(unmatched, "\n"))
+				}
+				if logFile := mockServiceLog(p.mockServers()[transport]); logFile != "" {
+					if excerpt := logExcerpt(logFile, offsets[transport]); excerpt != "" {
+						err = fmt.Errorf("%w\n\nMock Service log (%s):\n%s", err, logFile, excerpt)
+					}
+				}
+				err = types.NewError(types.ErrMismatch, withFieldPaths(err, interactions[transport]))
+			}
+		}
+	}
+
+	if err == nil {
+		p.recordVerified(expandSequences(selected))
+	}
+	if err != nil && p.updateFixtures() {
+		p.writeFixtureUpdates()
 	}
 
 	return err
 }
 
+// runIntegrationTest runs the test, returning any panic as an error so that
+// the interactions are still cleaned up, rather than left registered with the
+// Mock Server to fail subsequent tests
+func runIntegrationTest(integrationTest func(servers map[string]*types.MockServer) error, servers map[string]*types.MockServer) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("the test panicked: %v\n\n%s", r, debug.Stack())
+		}
+	}()
+
+	return integrationTest(servers)
+}
+
 // WritePact should be called writes when all tests have been performed for a
 // given Consumer <-> Provider pair. It will write out the Pact to the
 // configured file.
 func (p *Pact) WritePact() error {
 	p.Setup(true)
 	log.Println("[DEBUG] pact write Pact file")
-	mockServer := MockService{
-		BaseURL:           fmt.Sprintf("http://%s:%d", p.Host, p.Server.Port),
-		Consumer:          p.Consumer,
-		Provider:          p.Provider,
-		PactFileWriteMode: p.PactFileWriteMode,
-	}
-	err := mockServer.WritePact()
+
+	// Pact files may be shared with tests running in parallel
+	err := p.writePactFile(func() error {
+		// Interactions over TLS are merged into the pact written by the HTTP Mock Service
+		for _, transport := range transports {
+			if _, ok := p.mockServers()[transport]; !ok {
+				continue
+			}
+
+			err := p.mockService(transport).WritePact()
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return p.checkApprovedPacts()
 }
 
 // VerifyProviderRaw reads the provided pact files and runs verification against
@@ -338,8 +937,32 @@ func (p *Pact) VerifyProviderRaw(request types.VerifyRequest) ([]types.ProviderV
 		m = append(m, request.RequestFilter)
 	}
 
+	if request.ProgressHandler != nil {
+		m = append(m, progressMiddleware(request.ProgressHandler))
+	}
+
+	if requests := loadRequestGenerators(request.PactURLs); len(requests) > 0 {
+		m = append(m, requestGeneratorMiddleware(requests, p.generatorSource()))
+	}
+
+	responseMismatches := &responseMismatches{}
+	if requests := loadCheckedRequests(request.PactURLs); len(requests) > 0 {
+		m = append(m, responseCheckMiddleware(requests, responseMismatches))
+	}
+
+	deprecations := &deprecations{}
+	if request.ReportDeprecations {
+		m = append(m, deprecationMiddleware(deprecations))
+	}
+
+	proxyPort, err := p.allocatePort()
+	if err != nil {
+		return res, fmt.Errorf("unable to allocate a port for verification: %v", err)
+	}
+
 	// Configure HTTP Verification Proxy
 	opts := proxy.Options{
+		ProxyPort:                 proxyPort,
 		TargetAddress:             fmt.Sprintf("%s:%s", u.Hostname(), u.Port()),
 		TargetScheme:              u.Scheme,
 		TargetPath:                u.Path,
@@ -370,6 +993,7 @@ func (p *Pact) VerifyProviderRaw(request types.VerifyRequest) ([]types.ProviderV
 		BrokerUsername:             request.BrokerUsername,
 		BrokerPassword:             request.BrokerPassword,
 		BrokerToken:                request.BrokerToken,
+		BrokerTokenSource:          request.BrokerTokenSource,
 		PublishVerificationResults: request.PublishVerificationResults,
 		ProviderVersion:            request.ProviderVersion,
 		Provider:                   request.Provider,
@@ -383,6 +1007,15 @@ func (p *Pact) VerifyProviderRaw(request types.VerifyRequest) ([]types.ProviderV
 		IncludeWIPPactsSince:       request.IncludeWIPPactsSince,
 		PactLogDir:                 request.PactLogDir,
 		PactLogLevel:               request.PactLogLevel,
+		VerificationCacheDir:       request.VerificationCacheDir,
+		OnlyChangedPacts:           request.OnlyChangedPacts,
+		Concurrency:                request.Concurrency,
+		Retries:                    request.Retries,
+		RetryDelay:                 request.RetryDelay,
+		FailureReportFile:          request.FailureReportFile,
+		RerunFailed:                request.RerunFailed,
+		ReportDeprecations:         request.ReportDeprecations,
+		ProgressHandler:            request.ProgressHandler,
 	}
 
 	if request.Provider == "" {
@@ -399,7 +1032,15 @@ func (p *Pact) VerifyProviderRaw(request types.VerifyRequest) ([]types.ProviderV
 
 	log.Println("[DEBUG] pact provider verification")
 
-	return p.pactClient.VerifyProvider(verificationRequest)
+	res, err = p.verifyWithFailureReport(verificationRequest)
+	deprecations.report(res)
+	if err == nil {
+		if mismatchErr := responseMismatches.err(); mismatchErr != nil {
+			err = types.NewError(types.ErrVerification, mismatchErr)
+		}
+	}
+
+	return res, err
 }
 
 // VerifyProvider accepts an instance of `*testing.T`
@@ -430,6 +1071,15 @@ func (p *Pact) VerifyProvider(t *testing.T, request types.VerifyRequest) ([]type
 	return res, err
 }
 
+// allocatePort finds a port for a service to listen on
+func (p *Pact) allocatePort() (int, error) {
+	if p.PortAllocator == nil {
+		return utils.EphemeralPorts.AllocatePort()
+	}
+
+	return p.PortAllocator.AllocatePort()
+}
+
 var installer = install.NewInstaller()
 
 var checkCliCompatibility = func() {
@@ -633,6 +1283,12 @@ func runTestCases(t *testing.T, res []types.ProviderVerifierResponse) {
 
 				t.Run(testCase, func(st *testing.T) {
 					st.Log(example.FullDescription)
+					if example.Flaky {
+						st.Log("flaky: passed only when retried")
+					}
+					if example.Deprecation != nil {
+						st.Logf("deprecated: %s", example.Deprecation)
+					}
 
 					if example.Status != "passed" {
 						if example.Status == "pending" {
@@ -648,6 +1304,10 @@ func runTestCases(t *testing.T, res []types.ProviderVerifierResponse) {
 					t.Logf("notice: %s", notice.Text)
 				}
 			}
+
+			if test.Output != "" && (pactTest.Failed() || testing.Verbose()) {
+				pactTest.Logf("verifier output:\n%s", test.Output)
+			}
 		})
 	}
 }
@@ -667,7 +1327,7 @@ func (p *Pact) VerifyMessageProviderRaw(request VerifyMessageRequest) ([]types.P
 	// and error. The object will be marshalled to JSON for comparison.
 	mux := http.NewServeMux()
 
-	port, err := utils.GetFreePort()
+	port, err := p.allocatePort()
 	if err != nil {
 		return response, fmt.Errorf("unable to allocate a port for verification: %v", err)
 	}
@@ -681,13 +1341,19 @@ func (p *Pact) VerifyMessageProviderRaw(request VerifyMessageRequest) ([]types.P
 		BrokerUsername:             request.BrokerUsername,
 		BrokerPassword:             request.BrokerPassword,
 		BrokerToken:                request.BrokerToken,
+		BrokerTokenSource:          request.BrokerTokenSource,
 		PublishVerificationResults: request.PublishVerificationResults,
 		ProviderVersion:            request.ProviderVersion,
 		ProviderTags:               request.ProviderTags,
 		Provider:                   p.Provider,
+		VerificationCacheDir:       request.VerificationCacheDir,
+		Concurrency:                request.Concurrency,
+		ProgressHandler:            request.ProgressHandler,
 	}
 
-	mux.HandleFunc("/", messageVerificationHandler(request.MessageHandlers, request.StateHandlers))
+	metadataMismatches := &responseMismatches{}
+	handlers := checkMessageMetadata(request.MessageHandlers, loadMessageMetadata(request.PactURLs), metadataMismatches)
+	mux.HandleFunc("/", messageVerificationHandler(handlers, request.StateHandlers))
 
 	ln, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
 	if err != nil {
@@ -707,7 +1373,14 @@ func (p *Pact) VerifyMessageProviderRaw(request VerifyMessageRequest) ([]types.P
 	}
 
 	log.Println("[DEBUG] pact provider verification")
-	return p.pactClient.VerifyProvider(verificationRequest)
+	response, err = p.verifyProvider(verificationRequest)
+	if err == nil {
+		if mismatchErr := metadataMismatches.err(); mismatchErr != nil {
+			err = types.NewError(types.ErrVerification, mismatchErr)
+		}
+	}
+
+	return response, err
 }
 
 // VerifyMessageConsumerRaw creates a new Pact _message_ interaction to build a testable
@@ -721,6 +1394,18 @@ func (p *Pact) VerifyMessageConsumerRaw(message *Message, handler MessageConsume
 	log.Printf("[DEBUG] verify message")
 	p.Setup(false)
 
+	if message.err != nil {
+		return types.NewError(types.ErrInvalidRequest, fmt.Errorf("invalid message '%s': %v", message.Description, message.err))
+	}
+	if err := p.checkBodySize(message.Content); err != nil {
+		return types.NewError(types.ErrInvalidRequest, fmt.Errorf("content of message '%s' %w", message.Description, err))
+	}
+	message.contentMetadata()
+	written, metadataMatchers, err := message.withoutMetadataMatchers()
+	if err != nil {
+		return types.NewError(types.ErrInvalidRequest, fmt.Errorf("invalid message '%s': %v", message.Description, err))
+	}
+
 	// Reify the message back to its "example/generated" form
 	reified, err := p.pactClient.ReifyMessage(&types.PactReificationRequest{
 		Message: message.Content,
@@ -730,10 +1415,31 @@ func (p *Pact) VerifyMessageConsumerRaw(message *Message, handler MessageConsume
 		return fmt.Errorf("unable to convert consumer test to a valid JSON representation: %v", err)
 	}
 
+	var codec ContentCodec
+	if message.ContentType != "" {
+		if codec, err = contentCodecFor(message.ContentType); err != nil {
+			return err
+		}
+	}
+
+	raw := reified.ResponseRaw
+	if codec != nil && !isJSONCodec(codec) {
+		if raw, err = encodedContentBytes(message.Content, message.contentEncoding); err != nil {
+			return err
+		}
+	}
+
 	t := reflect.TypeOf(message.Type)
-	if t != nil && t.Name() != "interface" {
+	if codec != nil && !isJSONCodec(codec) {
+		if t != nil && t.Name() != "interface" {
+			log.Println("[DEBUG] decoding", message.ContentType, "content to type", t.Name())
+			if err = decodeContent(codec, message.Content, message.contentEncoding, message.Type); err != nil {
+				return fmt.Errorf("unable to decode '%s' content to %v: %v", message.ContentType, t.Name(), err)
+			}
+		}
+	} else if t != nil && t.Name() != "interface" {
 		log.Println("[DEBUG] narrowing type to", t.Name())
-		err = json.Unmarshal(reified.ResponseRaw, &message.Type)
+		err = decodeJSON(reified.ResponseRaw, &message.Type, p.UseJSONNumbers)
 
 		if err != nil {
 			return fmt.Errorf("unable to narrow type to %v: %v", t.Name(), err)
@@ -744,6 +1450,8 @@ func (p *Pact) VerifyMessageConsumerRaw(message *Message, handler MessageConsume
 	generatedMessage :=
 		Message{
 			Content:     message.Type,
+			ContentRaw:  raw,
+			ContentType: message.ContentType,
 			States:      message.States,
 			Description: message.Description,
 			Metadata:    message.Metadata,
@@ -754,12 +1462,21 @@ func (p *Pact) VerifyMessageConsumerRaw(message *Message, handler MessageConsume
 		return err
 	}
 
+	states := make([]string, len(message.States))
+	for i, s := range message.States {
+		states[i] = s.Name
+	}
+	p.recordTestName(interactionKey(message.Description, states...), message.testName)
+	p.recordMetadataMatchers(interactionKey(message.Description, states...), metadataMatchers)
+
 	// If no errors, update Message Pact
-	return p.pactClient.UpdateMessagePact(types.PactMessageRequest{
-		Message:  message,
-		Consumer: p.Consumer,
-		Provider: p.Provider,
-		PactDir:  p.PactDir,
+	return p.writePactFile(func() error {
+		return p.pactClient.UpdateMessagePact(types.PactMessageRequest{
+			Message:  written,
+			Consumer: p.Consumer,
+			Provider: p.Provider,
+			PactDir:  p.cliPactDir(),
+		})
 	})
 }
 
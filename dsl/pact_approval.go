@@ -0,0 +1,86 @@
+package dsl
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+// approvePactChanges returns true if changes to the approved pacts should be
+// accepted
+func (p *Pact) approvePactChanges() bool {
+	return p.ApprovePactChanges || os.Getenv("PACT_APPROVE") != ""
+}
+
+// writtenPactFiles returns the names of the pact files written by this Pact,
+// including those of any scenarios
+func (p *Pact) writtenPactFiles() []string {
+	names := []string{p.pactFileName()}
+
+	seen := make(map[string]bool)
+	for _, scenario := range p.scenarios {
+		name := scenarioFileName(p.pactFileName(), scenario)
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names[1:])
+
+	return names
+}
+
+// checkApprovedPacts compares the pacts written to the PactDir with those in
+// the ApprovedPactDir, returning an error with a diff of any changes. If the
+// changes are approved, the approved pacts are updated instead.
+func (p *Pact) checkApprovedPacts() error {
+	if p.ApprovedPactDir == "" {
+		return nil
+	}
+
+	var changes []string
+	for _, name := range p.writtenPactFiles() {
+		written, err := ioutil.ReadFile(filepath.Join(p.PactDir, name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		approvedPath := filepath.Join(p.ApprovedPactDir, name)
+		approved, err := ioutil.ReadFile(approvedPath)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if err == nil && bytes.Equal(written, approved) {
+			continue
+		}
+
+		if p.approvePactChanges() {
+			if err = os.MkdirAll(p.ApprovedPactDir, os.ModePerm); err != nil {
+				return err
+			}
+			if err = writeFileAtomic(approvedPath, written, 0644); err != nil {
+				return err
+			}
+			log.Printf("[INFO] approved the changes to pact '%s'", approvedPath)
+			continue
+		}
+
+		changes = append(changes, fmt.Sprintf("%s:\n%s", approvedPath, diffLines(string(approved), string(written))))
+	}
+
+	if len(changes) > 0 {
+		return types.NewError(types.ErrMismatch, fmt.Errorf("the pact differs from the approved pact. Review the changes below, then approve them by setting ApprovePactChanges (or PACT_APPROVE)\n\n%s", strings.Join(changes, "\n")))
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package dsl
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+func TestPactApproval_checkApprovedPacts(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pact-go-approval")
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	defer os.RemoveAll(dir)
+
+	pact := &Pact{
+		Consumer:        "consumer",
+		Provider:        "provider",
+		PactDir:         filepath.Join(dir, "pacts"),
+		ApprovedPactDir: filepath.Join(dir, "approved"),
+	}
+	written := filepath.Join(pact.PactDir, "consumer-provider.json")
+	approved := filepath.Join(pact.ApprovedPactDir, "consumer-provider.json")
+	os.MkdirAll(pact.PactDir, os.ModePerm)
+	ioutil.WriteFile(written, []byte("{\n  \"interactions\": [\"login\"]\n}\n"), 0644)
+
+	// A new pact must be approved
+	err = pact.checkApprovedPacts()
+	if !errors.Is(err, types.ErrMismatch) || !strings.Contains(err.Error(), `+  "interactions": ["login"]`) {
+		t.Fatalf("expected an error with a diff against no approved pact but got '%v'", err)
+	}
+
+	pact.ApprovePactChanges = true
+	if err = pact.checkApprovedPacts(); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if content, _ := ioutil.ReadFile(approved); !strings.Contains(string(content), "login") {
+		t.Fatalf("expected the approved pact to be updated but got '%s'", content)
+	}
+
+	pact.ApprovePactChanges = false
+	if err = pact.checkApprovedPacts(); err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	ioutil.WriteFile(written, []byte("{\n  \"interactions\": [\"logout\"]\n}\n"), 0644)
+	err = pact.checkApprovedPacts()
+	if err == nil || !strings.Contains(err.Error(), `-  "interactions": ["login"]`) || !strings.Contains(err.Error(), `+  "interactions": ["logout"]`) {
+		t.Fatalf("expected an error with a diff against the approved pact but got '%v'", err)
+	}
+}
+
+func TestPactApproval_writtenPactFiles(t *testing.T) {
+	pact := &Pact{
+		Consumer:  "consumer",
+		Provider:  "provider",
+		scenarios: map[string]string{"a": "refunds", "b": "checkout", "c": "checkout"},
+	}
+
+	want := []string{"consumer-provider.json", "consumer-provider-checkout.json", "consumer-provider-refunds.json"}
+	if got := pact.writtenPactFiles(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected %v but got %v", want, got)
+	}
+}
@@ -0,0 +1,176 @@
+package dsl
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"path/filepath"
+	"sort"
+)
+
+// PactDocument is a v2 pact, as written by WritePact, for tests to assert on
+// the interactions, matching rules and metadata of the pact they produce
+type PactDocument struct {
+	Consumer     PactName                  `json:"consumer"`
+	Provider     PactName                  `json:"provider"`
+	Interactions []PactDocumentInteraction `json:"interactions"`
+	Metadata     map[string]interface{}    `json:"metadata,omitempty"`
+}
+
+// PactDocumentInteraction is an interaction in a PactDocument
+type PactDocumentInteraction struct {
+	Description   string               `json:"description"`
+	ProviderState string               `json:"providerState,omitempty"`
+	Request       PactDocumentRequest  `json:"request"`
+	Response      PactDocumentResponse `json:"response"`
+}
+
+// PactDocumentRequest is the request of an interaction in a PactDocument. The
+// values of the headers, query and body are the examples of any matchers, and
+// the matching rules are keyed by path e.g. "$.body.id".
+type PactDocumentRequest struct {
+	Method        string                      `json:"method"`
+	Path          string                      `json:"path"`
+	Query         PactDocumentQuery           `json:"query,omitempty"`
+	Headers       map[string]string           `json:"headers,omitempty"`
+	Body          interface{}                 `json:"body,omitempty"`
+	MatchingRules map[string]PactDocumentRule `json:"matchingRules,omitempty"`
+}
+
+// PactDocumentResponse is the response of an interaction in a PactDocument
+type PactDocumentResponse struct {
+	Status        int                         `json:"status"`
+	Headers       map[string]string           `json:"headers,omitempty"`
+	Body          interface{}                 `json:"body,omitempty"`
+	MatchingRules map[string]PactDocumentRule `json:"matchingRules,omitempty"`
+}
+
+// PactDocumentRule is a matching rule in a PactDocument
+type PactDocumentRule struct {
+	Match string `json:"match,omitempty"`
+	Regex string `json:"regex,omitempty"`
+	Min   int    `json:"min,omitempty"`
+	Max   int    `json:"max,omitempty"`
+}
+
+// PactDocumentQuery is the query of a request in a PactDocument, by name. The
+// Pact CLI tools write the query of v2 pacts as a string, e.g. "id=1&id=2",
+// which is parsed.
+type PactDocumentQuery map[string][]string
+
+// UnmarshalJSON reads the query from either a string or an object
+func (q *PactDocumentQuery) UnmarshalJSON(data []byte) error {
+	var query string
+	if err := json.Unmarshal(data, &query); err != nil {
+		return json.Unmarshal(data, (*map[string][]string)(q))
+	}
+
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return err
+	}
+	*q = PactDocumentQuery(values)
+
+	return nil
+}
+
+// Interaction returns the interaction with the description, and whether there
+// is one
+func (d *PactDocument) Interaction(description string) (PactDocumentInteraction, bool) {
+	for _, i := range d.Interactions {
+		if i.Description == description {
+			return i, true
+		}
+	}
+
+	return PactDocumentInteraction{}, false
+}
+
+// PactDocument returns the pact of the interactions verified successfully by
+// this Pact so far, as WritePact would write it, without reading the pact file.
+// Interactions are in the order of their description and provider state, as
+// in the pact file, and one verified more than once appears once.
+func (p *Pact) PactDocument() (*PactDocument, error) {
+	doc := &PactDocument{
+		Consumer: PactName{Name: p.Consumer},
+		Provider: PactName{Name: p.Provider},
+		Metadata: map[string]interface{}{
+			"pactSpecification": map[string]interface{}{
+				"version": fmt.Sprintf("%d.0.0", p.SpecificationVersion),
+			},
+		},
+	}
+
+	keys := make([]string, 0, len(p.verified))
+	for key := range p.verified {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		interaction, err := documentInteraction(p.verified[key], p.UseJSONNumbers)
+		if err != nil {
+			return nil, fmt.Errorf("unable to describe interaction '%s': %v", p.verified[key].Description, err)
+		}
+		doc.Interactions = append(doc.Interactions, interaction)
+	}
+
+	return doc, nil
+}
+
+// ReadPactDocument reads the pact file written by WritePact
+func (p *Pact) ReadPactDocument() (*PactDocument, error) {
+	path := filepath.Join(p.PactDir, p.pactFileName())
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &PactDocument{}
+	if err = decodeJSON(content, doc, p.UseJSONNumbers); err != nil {
+		return nil, fmt.Errorf("unable to read pact file '%s': %v", path, err)
+	}
+
+	return doc, nil
+}
+
+// recordVerified records the interactions verified by a test, by
+// interactionKey, for PactDocument
+func (p *Pact) recordVerified(interactions []*Interaction) {
+	if p.verified == nil {
+		p.verified = make(map[string]*Interaction)
+	}
+
+	for _, i := range interactions {
+		p.verified[interactionKey(i.Description, i.State)] = i
+	}
+}
+
+// documentInteraction returns the interaction as it appears in a PactDocument,
+// with the numbers of its bodies as json.Number if useNumber
+func documentInteraction(i *Interaction, useNumber bool) (PactDocumentInteraction, error) {
+	var interaction PactDocumentInteraction
+
+	exported, err := exportInteraction(i)
+	if err != nil {
+		return interaction, err
+	}
+
+	content, err := json.Marshal(exported)
+	if err != nil {
+		return interaction, err
+	}
+	if err = decodeJSON(content, &interaction, useNumber); err != nil {
+		return interaction, err
+	}
+
+	// The call of a response sequence is not written to the pact
+	delete(interaction.Request.Headers, sequenceCallHeader)
+	if len(interaction.Request.Headers) == 0 {
+		interaction.Request.Headers = nil
+	}
+
+	return interaction, nil
+}
@@ -0,0 +1,139 @@
+package dsl
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestPactDocument(t *testing.T) {
+	pact := &Pact{Consumer: "Billy", Provider: "Bobby", SpecificationVersion: 2}
+
+	sequence := (&Interaction{}).
+		UponReceiving("A request to poll").
+		WithRequest(Request{Method: "GET", Path: String("/jobs/1")}).
+		WillRespondWith(Response{Status: 202}).
+		ThenRespondWith("The job is done", Response{Status: 200})
+	user := (&Interaction{}).
+		Given("User billy exists").
+		UponReceiving("A request for billy").
+		WithRequest(Request{Method: "GET", Path: String("/users"), Query: MapMatcher{"name": String("billy")}}).
+		WillRespondWith(Response{Status: 200, Body: Match(struct {
+			Name string `json:"name"`
+		}{"billy"})})
+	pact.recordVerified(expandSequences([]*Interaction{user, sequence}))
+	pact.recordVerified([]*Interaction{user})
+
+	doc, err := pact.PactDocument()
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	if doc.Consumer.Name != "Billy" || doc.Provider.Name != "Bobby" || !reflect.DeepEqual(doc.Metadata["pactSpecification"], map[string]interface{}{"version": "2.0.0"}) {
+		t.Fatalf("unexpected pacticipants or metadata: %+v", doc)
+	}
+	var descriptions []string
+	for _, i := range doc.Interactions {
+		descriptions = append(descriptions, i.Description)
+	}
+	if !reflect.DeepEqual(descriptions, []string{"A request for billy", "A request to poll (call 1)", "A request to poll (call 2)"}) {
+		t.Fatalf("unexpected interactions %q", descriptions)
+	}
+
+	interaction, ok := doc.Interaction("A request for billy")
+	if !ok {
+		t.Fatal("expected the interaction for billy")
+	}
+	if interaction.ProviderState != "User billy exists" || !reflect.DeepEqual(interaction.Request.Query, PactDocumentQuery{"name": {"billy"}}) {
+		t.Fatalf("unexpected request %+v", interaction)
+	}
+	if rule := interaction.Response.MatchingRules["$.body.name"]; rule.Match != "type" {
+		t.Fatalf("expected a type rule for the name but got %+v", interaction.Response.MatchingRules)
+	}
+	if poll, _ := doc.Interaction("A request to poll (call 2)"); poll.Request.Headers != nil || poll.Response.Status != 200 {
+		t.Fatalf("unexpected sequence call %+v", poll)
+	}
+}
+
+func TestPactDocument_Optional(t *testing.T) {
+	pact := &Pact{Consumer: "Billy", Provider: "Bobby"}
+	user := (&Interaction{}).
+		UponReceiving("A request for billy").
+		WithRequest(Request{Method: "GET", Path: String("/users/billy")}).
+		WillRespondWith(Response{Status: 200, Body: map[string]interface{}{"name": "billy", "nickname": Optional(Like("b"))}})
+	pact.recordVerified([]*Interaction{user})
+
+	doc, err := pact.PactDocument()
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if interaction, ok := doc.Interaction("A request for billy"); !ok || interaction.Response.Status != 200 {
+		t.Fatalf("expected the interaction with an Optional field but got %+v", doc.Interactions)
+	}
+}
+
+func TestPactDocument_read(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pact-go-document")
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	defer os.RemoveAll(dir)
+
+	content := `{
+  "consumer": {"name": "Billy"},
+  "provider": {"name": "Bobby"},
+  "interactions": [
+    {
+      "description": "A request for billy",
+      "request": {"method": "GET", "path": "/users", "query": "name=billy&name=bob"},
+      "response": {"status": 200, "matchingRules": {"$.body.name": {"match": "type"}}}
+    }
+  ],
+  "metadata": {"pactSpecification": {"version": "2.0.0"}}
+}`
+	if err = ioutil.WriteFile(filepath.Join(dir, "billy-bobby.json"), []byte(content), 0644); err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	pact := &Pact{Consumer: "Billy", Provider: "Bobby", PactDir: dir}
+	doc, err := pact.ReadPactDocument()
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	interaction, _ := doc.Interaction("A request for billy")
+	if !reflect.DeepEqual(interaction.Request.Query, PactDocumentQuery{"name": {"billy", "bob"}}) {
+		t.Fatalf("unexpected query %v", interaction.Request.Query)
+	}
+	if interaction.Response.MatchingRules["$.body.name"].Match != "type" {
+		t.Fatalf("unexpected matching rules %v", interaction.Response.MatchingRules)
+	}
+}
+
+func TestPactDocument_UseJSONNumbers(t *testing.T) {
+	order := (&Interaction{}).
+		UponReceiving("A request for an order").
+		WithRequest(Request{Method: "GET", Path: String("/orders/1234567890123456789")}).
+		WillRespondWith(Response{Status: 200, Body: map[string]interface{}{"id": Like(int64(1234567890123456789))}})
+
+	pact := &Pact{Consumer: "Billy", Provider: "Bobby", SpecificationVersion: 2}
+	pact.recordVerified([]*Interaction{order})
+	doc, err := pact.PactDocument()
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if id := doc.Interactions[0].Response.Body.(map[string]interface{})["id"]; id != float64(1234567890123456789) {
+		t.Fatalf("expected a float64 id by default but got %#v", id)
+	}
+
+	pact.UseJSONNumbers = true
+	if doc, err = pact.PactDocument(); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if id := doc.Interactions[0].Response.Body.(map[string]interface{})["id"]; id != json.Number("1234567890123456789") {
+		t.Fatalf("expected the id to keep its precision but got %#v", id)
+	}
+}
@@ -0,0 +1,105 @@
+package dsl
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// pactDirLockFile is created in the PactDir while pact files are written, so
+// that test packages run in parallel (e.g. go test ./... -p 4) sharing the same
+// PactDir do not corrupt each other's pact files
+const pactDirLockFile = ".pact-go.lock"
+
+var (
+	// pactDirLockTimeout is how long to wait to acquire the lock
+	pactDirLockTimeout = 30 * time.Second
+
+	// pactDirLockStale is the age at which a lock is assumed to have been left
+	// behind by a process that has died, and is removed
+	pactDirLockStale = 2 * time.Minute
+
+	pactDirLockRetry = 20 * time.Millisecond
+)
+
+// lockPactDir acquires an advisory lock on the pact directory, returning a
+// function to release it
+func lockPactDir(dir string) (func(), error) {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, fmt.Errorf("unable to create pact directory: %v", err)
+	}
+
+	path := filepath.Join(dir, pactDirLockFile)
+	timeout := time.After(pactDirLockTimeout)
+
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			f.WriteString(strconv.Itoa(os.Getpid()))
+			f.Close()
+
+			return func() {
+				if err := os.Remove(path); err != nil {
+					log.Println("[WARN] unable to release pact directory lock:", err)
+				}
+			}, nil
+		}
+
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("unable to lock pact directory: %v", err)
+		}
+
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > pactDirLockStale {
+			log.Println("[WARN] removing stale pact directory lock:", path)
+			os.Remove(path)
+			continue
+		}
+
+		select {
+		case <-timeout:
+			return nil, fmt.Errorf("timed out after %s waiting for the pact directory lock '%s'. If no other tests are running, remove the file and try again", pactDirLockTimeout, path)
+		case <-time.After(pactDirLockRetry):
+		}
+	}
+}
+
+// withPactDirLock runs f whilst holding the pact directory lock
+func withPactDirLock(dir string, f func() error) error {
+	unlock, err := lockPactDir(dir)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return f()
+}
+
+// writeFileAtomic writes data to a temporary file in the same directory as
+// path, then renames it over path, so that readers never see a partial file
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	f, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+
+	tmp := f.Name()
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp, perm)
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		os.Remove(tmp)
+	}
+
+	return err
+}
@@ -0,0 +1,103 @@
+package dsl
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPactFileLock_Concurrent(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pact-go-lock")
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "consumer-provider.json")
+	if err = writeFileAtomic(path, []byte("0"), 0644); err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	// Each writer increments the count, which is only safe under the lock
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- withPactDirLock(dir, func() error {
+				content, err := ioutil.ReadFile(path)
+				if err != nil {
+					return err
+				}
+				count, err := strconv.Atoi(string(content))
+				if err != nil {
+					return err
+				}
+				return writeFileAtomic(path, []byte(strconv.Itoa(count+1)), 0644)
+			})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+	}
+
+	content, _ := ioutil.ReadFile(path)
+	if string(content) != "20" {
+		t.Fatalf("expected 20 serialised writes but got '%s'", content)
+	}
+
+	files, _ := ioutil.ReadDir(dir)
+	if len(files) != 1 {
+		t.Fatalf("expected the lock and temporary files to be removed but found %d files", len(files))
+	}
+}
+
+func TestPactFileLock_Timeout(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pact-go-lock")
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	defer os.RemoveAll(dir)
+
+	defer func(timeout time.Duration) { pactDirLockTimeout = timeout }(pactDirLockTimeout)
+	pactDirLockTimeout = 100 * time.Millisecond
+
+	unlock, err := lockPactDir(dir)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	defer unlock()
+
+	if _, err = lockPactDir(dir); err == nil {
+		t.Fatal("expected to time out waiting for the lock")
+	}
+}
+
+func TestPactFileLock_Stale(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pact-go-lock")
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, pactDirLockFile)
+	ioutil.WriteFile(path, []byte("1"), 0644)
+	old := time.Now().Add(-pactDirLockStale - time.Minute)
+	os.Chtimes(path, old, old)
+
+	unlock, err := lockPactDir(dir)
+	if err != nil {
+		t.Fatal("expected a stale lock to be removed:", err)
+	}
+	unlock()
+}
@@ -0,0 +1,127 @@
+package dsl
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// PactFileNameStrategy returns the file name of the pact between a consumer and
+// provider, allowing different variants of the same pair (e.g. per branch) to be
+// written to the same PactDir.
+type PactFileNameStrategy func(consumer string, provider string) string
+
+var (
+	whitespace        = regexp.MustCompile(`\s`)
+	unsafeBranchChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+)
+
+// DefaultPactFileName is the name used by the Pact CLI tools, e.g.
+// "myconsumer-myprovider.json"
+func DefaultPactFileName(consumer string, provider string) string {
+	return fmt.Sprintf("%s-%s.json", filenamify(consumer), filenamify(provider))
+}
+
+// PactFileNameWithBranch includes the branch in the pact file name, e.g.
+// "myconsumer-myprovider-feat_new-thing.json" for the branch "feat/new-thing"
+func PactFileNameWithBranch(branch string) PactFileNameStrategy {
+	return func(consumer string, provider string) string {
+		if branch == "" {
+			return DefaultPactFileName(consumer, provider)
+		}
+
+		return fmt.Sprintf("%s-%s-%s.json", filenamify(consumer), filenamify(provider), unsafeBranchChars.ReplaceAllString(branch, "_"))
+	}
+}
+
+// filenamify mirrors the Pact CLI tools' conversion of a pacticipant name
+func filenamify(name string) string {
+	return strings.ToLower(whitespace.ReplaceAllString(name, "_"))
+}
+
+// pactFileName returns the name of the pact file written by this Pact
+func (p *Pact) pactFileName() string {
+	if p.FileNameStrategy == nil {
+		return DefaultPactFileName(p.Consumer, p.Provider)
+	}
+
+	return p.FileNameStrategy(p.Consumer, p.Provider)
+}
+
+// cliPactDir is the directory the Pact CLI tools write pacts to. When a
+// FileNameStrategy is used, pacts are written to a staging directory unique to
+// the file name, and then moved into the PactDir.
+func (p *Pact) cliPactDir() string {
+	if p.FileNameStrategy == nil {
+		return p.PactDir
+	}
+
+	return filepath.Join(p.PactDir, ".pact-go", strings.TrimSuffix(p.pactFileName(), filepath.Ext(p.pactFileName())))
+}
+
+// writePactFile runs write, which instructs the Pact CLI tools to write the
+// pact, whilst holding the pact directory lock. When a FileNameStrategy is
+// used, any existing pact is staged beforehand so that it can be merged with,
+// and the result is then atomically moved to its final name. The interactions
+// in the pact are then sorted, and those in a scenario moved to its pact file.
+func (p *Pact) writePactFile(write func() error) error {
+	return withPactDirLock(p.PactDir, func() error {
+		target := filepath.Join(p.PactDir, p.pactFileName())
+		previous, existed, err := readPactFile(target)
+		if err != nil {
+			return err
+		}
+
+		if p.FileNameStrategy == nil {
+			if err := write(); err != nil {
+				return err
+			}
+
+			return restoreOverLimit(target, previous, existed, p.rewritePactFile(target))
+		}
+		staged := filepath.Join(p.cliPactDir(), DefaultPactFileName(p.Consumer, p.Provider))
+
+		if err := os.MkdirAll(p.cliPactDir(), os.ModePerm); err != nil {
+			return err
+		}
+		os.Remove(staged)
+
+		if existed {
+			if err = writeFileAtomic(staged, previous, 0644); err != nil {
+				return err
+			}
+		}
+
+		if err := write(); err != nil {
+			return err
+		}
+
+		pact, err := ioutil.ReadFile(staged)
+		if err != nil {
+			return fmt.Errorf("unable to find the pact written by the Pact CLI tools: %v", err)
+		}
+
+		if err = writeFileAtomic(target, pact, 0644); err != nil {
+			return err
+		}
+
+		if err = os.Remove(staged); err != nil {
+			return err
+		}
+
+		return restoreOverLimit(target, previous, existed, p.rewritePactFile(target))
+	})
+}
+
+// rewritePactFile tidies the pact file written to path, and then moves the
+// interactions in any scenarios to their own pact files
+func (p *Pact) rewritePactFile(path string) error {
+	if err := p.pactRewriter().rewriteFile(path); err != nil {
+		return err
+	}
+
+	return p.writeScenarios(path)
+}
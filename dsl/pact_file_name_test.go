@@ -0,0 +1,91 @@
+package dsl
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPactFileName_Strategies(t *testing.T) {
+	tests := []struct {
+		strategy PactFileNameStrategy
+		want     string
+	}{
+		{DefaultPactFileName, "my_consumer-myprovider.json"},
+		{PactFileNameWithBranch("feat/new thing"), "my_consumer-myprovider-feat_new_thing.json"},
+		{PactFileNameWithBranch(""), "my_consumer-myprovider.json"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.strategy("My Consumer", "MyProvider"); got != tt.want {
+			t.Fatalf("expected '%s' but got '%s'", tt.want, got)
+		}
+	}
+}
+
+func TestPactFileName_writePactFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pact-go-name")
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	defer os.RemoveAll(dir)
+
+	pact := &Pact{
+		Consumer:         "consumer",
+		Provider:         "provider",
+		PactDir:          dir,
+		FileNameStrategy: PactFileNameWithBranch("main"),
+	}
+
+	// Behave like the CLI tools in merge mode, appending to any existing pact
+	// in the directory they were told to write to
+	write := func(interaction string) func() error {
+		return func() error {
+			path := filepath.Join(pact.cliPactDir(), "consumer-provider.json")
+
+			var pact struct {
+				Interactions []string `json:"interactions"`
+			}
+			if existing, err := ioutil.ReadFile(path); err == nil {
+				json.Unmarshal(existing, &pact)
+			}
+			pact.Interactions = append(pact.Interactions, interaction)
+
+			content, _ := json.Marshal(pact)
+			return ioutil.WriteFile(path, content, 0644)
+		}
+	}
+
+	if err = pact.writePactFile(write("a")); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err = pact.writePactFile(write("b")); err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	content, err := ioutil.ReadFile(filepath.Join(dir, "consumer-provider-main.json"))
+	if err != nil {
+		t.Fatal("expected the pact to be written with the strategy's name:", err)
+	}
+	if !strings.Contains(string(content), `"a",`) || !strings.Contains(string(content), `"b"`) {
+		t.Fatalf("expected the pact to be merged but got '%s'", content)
+	}
+
+	if _, err = os.Stat(filepath.Join(dir, "consumer-provider.json")); !os.IsNotExist(err) {
+		t.Fatal("expected no pact to be written with the default name")
+	}
+	if _, err = os.Stat(filepath.Join(pact.cliPactDir(), "consumer-provider.json")); !os.IsNotExist(err) {
+		t.Fatal("expected the staged pact to be removed")
+	}
+}
+
+func TestPactFileName_writePactFileDefault(t *testing.T) {
+	pact := &Pact{PactDir: "pacts"}
+
+	if pact.cliPactDir() != "pacts" {
+		t.Fatalf("expected pacts to be written directly to the PactDir but got '%s'", pact.cliPactDir())
+	}
+}
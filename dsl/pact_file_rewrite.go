@@ -0,0 +1,255 @@
+package dsl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+)
+
+// pactRewriter tidies pact files written by the Pact CLI tools, preserving
+// their layout and key order unless sortKeys is set
+type pactRewriter struct {
+	// sortKeys sorts all keys in the pact, not just the interactions
+	sortKeys bool
+
+	// testNames are the names of the Go tests that defined each interaction,
+	// by interactionKey
+	testNames map[string]string
+
+	// secretHeaders are the request headers to redact from each interaction,
+	// by interactionKey
+	secretHeaders map[string][]string
+
+	// requestGenerators are the generators in the request body of each
+	// interaction, by interactionKey
+	requestGenerators map[string]map[string]requestGenerator
+
+	// optionalFields are the Optional fields in the response body of each
+	// interaction, by interactionKey
+	optionalFields map[string]map[string]interface{}
+
+	// jsonStrings are the JSONStrings in the request and response of each
+	// interaction, by interactionKey
+	jsonStrings map[string]map[string]map[string]interface{}
+
+	// metadataMatchers are the matchers in the metadata of each message, by
+	// interactionKey
+	metadataMatchers map[string]map[string]interface{}
+
+	// interactionSets are the InteractionSets of the interactions published
+	// in one, by interactionKey
+	interactionSets map[string]interactionSetField
+
+	// maxInteractions is the most interactions a pact may have, if set
+	maxInteractions int
+}
+
+// pactRewriter returns the rewriter for the pacts written by this Pact
+func (p *Pact) pactRewriter() *pactRewriter {
+	return &pactRewriter{
+		sortKeys:          p.PactFileSortKeys,
+		testNames:         p.testNames,
+		secretHeaders:     p.secretHeaders,
+		requestGenerators: p.requestGenerators,
+		optionalFields:    p.optionalFields,
+		jsonStrings:       p.jsonStrings,
+		metadataMatchers:  p.metadataMatchers,
+		interactionSets:   p.interactionSets,
+		maxInteractions:   p.MaxInteractions,
+	}
+}
+
+// rewriteFile rewrites the pact file at path, if it exists
+func (r *pactRewriter) rewriteFile(path string) error {
+	original, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	rewritten, err := r.rewrite(original)
+	if err != nil {
+		return fmt.Errorf("unable to rewrite pact file '%s': %w", path, err)
+	}
+
+	if bytes.Equal(rewritten, original) {
+		return nil
+	}
+
+	return writeFileAtomic(path, rewritten, 0644)
+}
+
+// rewrite orders the interactions (or messages) in a pact by their description
+// and provider state, so that re-running tests in a different order doesn't
+// change the pact, records the test that defined each interaction and any
+// request generators, and redacts any secrets.
+func (r *pactRewriter) rewrite(original []byte) ([]byte, error) {
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, original); err != nil {
+		return nil, err
+	}
+	doc := compact.Bytes()
+
+	var pact map[string]json.RawMessage
+	if err := json.Unmarshal(doc, &pact); err != nil {
+		return nil, err
+	}
+
+	for _, key := range []string{"interactions", "messages"} {
+		raw, ok := pact[key]
+		if !ok {
+			continue
+		}
+
+		interactions, err := r.rewriteInteractions(raw)
+		if err != nil {
+			return nil, err
+		}
+
+		// The document is compact, so the array appears verbatim and only once
+		doc = bytes.Replace(doc, raw, interactions, 1)
+	}
+
+	if r.sortKeys {
+		decoder := json.NewDecoder(bytes.NewReader(doc))
+		decoder.UseNumber()
+
+		var content interface{}
+		if err := decoder.Decode(&content); err != nil {
+			return nil, err
+		}
+
+		// Maps are marshalled with sorted keys
+		var buf bytes.Buffer
+		encoder := json.NewEncoder(&buf)
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(content); err != nil {
+			return nil, err
+		}
+		doc = bytes.TrimSpace(buf.Bytes())
+	}
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, doc, "", "  "); err != nil {
+		return nil, err
+	}
+	if bytes.HasSuffix(original, []byte("\n")) {
+		out.WriteString("\n")
+	}
+
+	return out.Bytes(), nil
+}
+
+// rewriteInteractions returns the array of interactions (or messages) raw, in
+// order
+func (r *pactRewriter) rewriteInteractions(raw json.RawMessage) ([]byte, error) {
+	var interactions []json.RawMessage
+	if err := json.Unmarshal(raw, &interactions); err != nil {
+		return nil, err
+	}
+	if err := checkInteractionCount(len(interactions), r.maxInteractions); err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, len(interactions))
+	for i, interaction := range interactions {
+		keys[i] = rawInteractionKey(interaction)
+
+		if name, ok := r.testNames[keys[i]]; ok {
+			interactions[i] = withTestName(interactions[i], name)
+		}
+		if headers, ok := r.secretHeaders[keys[i]]; ok {
+			interactions[i] = redactHeaders(interactions[i], headers)
+		}
+		if generators, ok := r.requestGenerators[keys[i]]; ok {
+			interactions[i] = withInteractionField(interactions[i], "requestGenerators", generators)
+		}
+		if fields, ok := r.optionalFields[keys[i]]; ok {
+			interactions[i] = withInteractionField(interactions[i], "optionalFields", fields)
+		}
+		if fields, ok := r.jsonStrings[keys[i]]; ok {
+			interactions[i] = withInteractionField(interactions[i], "jsonStrings", fields)
+		}
+		if fields, ok := r.metadataMatchers[keys[i]]; ok {
+			interactions[i] = withInteractionField(interactions[i], "metadataMatchers", fields)
+		}
+		if set, ok := r.interactionSets[keys[i]]; ok {
+			interactions[i] = withInteractionField(interactions[i], "interactionSet", set)
+		}
+		interactions[i] = withoutSequenceCallHeader(interactions[i])
+	}
+
+	order := make([]int, len(interactions))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return keys[order[a]] < keys[order[b]]
+	})
+
+	// Join the interactions verbatim, as json.Marshal would escape HTML
+	sorted := make([][]byte, len(interactions))
+	for i, j := range order {
+		sorted[i] = interactions[j]
+	}
+
+	return append(append([]byte("["), bytes.Join(sorted, []byte(","))...), ']'), nil
+}
+
+// interactionKey identifies an interaction by its description and provider
+// state(s)
+func interactionKey(description string, states ...string) string {
+	key := description
+	for _, s := range states {
+		if s != "" {
+			key += "\x00" + s
+		}
+	}
+
+	return key
+}
+
+// rawInteractionKey is the interactionKey of an interaction in a pact file
+func rawInteractionKey(raw json.RawMessage) string {
+	var interaction struct {
+		Description         string `json:"description"`
+		ProviderState       string `json:"providerState"`
+		LegacyProviderState string `json:"provider_state"`
+		ProviderStates      []struct {
+			Name string `json:"name"`
+		} `json:"providerStates"`
+	}
+	json.Unmarshal(raw, &interaction)
+
+	states := []string{interaction.ProviderState, interaction.LegacyProviderState}
+	for _, s := range interaction.ProviderStates {
+		states = append(states, s.Name)
+	}
+
+	return interactionKey(interaction.Description, states...)
+}
+
+// withTestName adds a V4 style "testname" comment to a compact interaction,
+// unless it already has comments
+func withTestName(raw json.RawMessage, name string) json.RawMessage {
+	var interaction struct {
+		Comments json.RawMessage `json:"comments"`
+	}
+	if err := json.Unmarshal(raw, &interaction); err != nil || interaction.Comments != nil || !bytes.HasSuffix(raw, []byte("}")) {
+		return raw
+	}
+
+	comments, _ := json.Marshal(map[string]string{"testname": name})
+	separator := ","
+	if bytes.Equal(raw, []byte("{}")) {
+		separator = ""
+	}
+
+	return json.RawMessage(strings.TrimSuffix(string(raw), "}") + separator + `"comments":` + string(comments) + "}")
+}
@@ -0,0 +1,196 @@
+package dsl
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var unsortedPact = `{
+  "consumer": {
+    "name": "billy"
+  },
+  "provider": {
+    "name": "bobby"
+  },
+  "interactions": [
+    {
+      "description": "b request",
+      "providerState": "state",
+      "request": {
+        "method": "GET",
+        "path": "/<b>"
+      }
+    },
+    {
+      "description": "a request",
+      "providerState": "state 2",
+      "request": {
+        "path": "/a2",
+        "method": "GET"
+      }
+    },
+    {
+      "description": "a request",
+      "providerState": "state 1",
+      "request": {
+        "method": "GET",
+        "path": "/a1"
+      }
+    }
+  ],
+  "metadata": {
+    "pactSpecification": {
+      "version": "2.0.0"
+    }
+  }
+}`
+
+func TestPactFileRewrite_Sort(t *testing.T) {
+	sorted, err := (&pactRewriter{}).rewrite([]byte(unsortedPact))
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	expected := `{
+  "consumer": {
+    "name": "billy"
+  },
+  "provider": {
+    "name": "bobby"
+  },
+  "interactions": [
+    {
+      "description": "a request",
+      "providerState": "state 1",
+      "request": {
+        "method": "GET",
+        "path": "/a1"
+      }
+    },
+    {
+      "description": "a request",
+      "providerState": "state 2",
+      "request": {
+        "path": "/a2",
+        "method": "GET"
+      }
+    },
+    {
+      "description": "b request",
+      "providerState": "state",
+      "request": {
+        "method": "GET",
+        "path": "/<b>"
+      }
+    }
+  ],
+  "metadata": {
+    "pactSpecification": {
+      "version": "2.0.0"
+    }
+  }
+}`
+
+	if string(sorted) != expected {
+		t.Fatalf("expected the interactions to be sorted, preserving the layout, but got\n%s", sorted)
+	}
+
+	// Sorting is idempotent
+	again, _ := (&pactRewriter{}).rewrite(sorted)
+	if string(again) != expected {
+		t.Fatalf("expected a sorted pact to be unchanged, but got\n%s", again)
+	}
+}
+
+func TestPactFileRewrite_SortKeys(t *testing.T) {
+	r := &pactRewriter{sortKeys: true}
+	sorted, err := r.rewrite([]byte(`{"provider":{"name":"bobby"},"messages":[{"metadata":{"b":1,"a":1.50},"description":"b","providerStates":[{"name":"x"}]},{"description":"a"}],"consumer":{"name":"billy"}}` + "\n"))
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	expected := `{
+  "consumer": {
+    "name": "billy"
+  },
+  "messages": [
+    {
+      "description": "a"
+    },
+    {
+      "description": "b",
+      "metadata": {
+        "a": 1.50,
+        "b": 1
+      },
+      "providerStates": [
+        {
+          "name": "x"
+        }
+      ]
+    }
+  ],
+  "provider": {
+    "name": "bobby"
+  }
+}
+`
+
+	if string(sorted) != expected {
+		t.Fatalf("expected all keys to be sorted, but got\n%s", sorted)
+	}
+}
+
+func TestPactFileRewrite_rewriteFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pact-go-sort")
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "billy-bobby.json")
+	if err = (&pactRewriter{}).rewriteFile(path); err != nil {
+		t.Fatal("expected a missing pact file to be ignored:", err)
+	}
+
+	ioutil.WriteFile(path, []byte("not json"), 0644)
+	if err = (&pactRewriter{}).rewriteFile(path); err == nil {
+		t.Fatal("expected an invalid pact file to return an error")
+	}
+}
+
+func TestPactFileRewrite_TestNames(t *testing.T) {
+	r := &pactRewriter{
+		testNames: map[string]string{
+			interactionKey("a request", "state 1"): "TestA",
+			interactionKey("b request", "state"):   "TestB",
+		},
+	}
+
+	rewritten, err := r.rewrite([]byte(`{"interactions":[{"description":"b request","providerState":"state","comments":{"testname":"TestOld"}},{"description":"a request","providerState":"state 1"},{"description":"a request","providerState":"state 2"}]}`))
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	var pact struct {
+		Interactions []struct {
+			Comments struct {
+				TestName string `json:"testname"`
+			} `json:"comments"`
+		} `json:"interactions"`
+	}
+	json.Unmarshal(rewritten, &pact)
+
+	names := []string{}
+	for _, i := range pact.Interactions {
+		names = append(names, i.Comments.TestName)
+	}
+
+	if strings.Join(names, ",") != "TestA,,TestOld" {
+		t.Fatalf("expected test names to be added to interactions without comments, but got %v", names)
+	}
+}
@@ -2,12 +2,14 @@ package dsl
 
 import (
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"log"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -159,6 +161,98 @@ func TestPact_VerifyFail(t *testing.T) {
 	}
 }
 
+func TestPact_VerifyMismatch(t *testing.T) {
+	ms := setupMismatchMockServer()
+	defer ms.Close()
+
+	pact := &Pact{
+		Server: &types.MockServer{
+			Port: getPort(ms.URL),
+		},
+	}
+
+	pact.
+		AddInteraction().
+		UponReceiving("Some name for the test").
+		WithRequest(Request{}).
+		WillRespondWith(Response{})
+
+	err := pact.Verify(func() error { return nil })
+
+	if !errors.Is(err, types.ErrMismatch) {
+		t.Fatalf("want ErrMismatch but got '%v'", err)
+	}
+
+	if types.ExitCode(err) != types.ExitCodeMismatch {
+		t.Fatalf("want exit code %d but got %d", types.ExitCodeMismatch, types.ExitCode(err))
+	}
+}
+
+func TestPact_VerifyPanic(t *testing.T) {
+	cleared := false
+	ms := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			cleared = true
+		}
+	}))
+	defer ms.Close()
+
+	pact := &Pact{
+		Server: &types.MockServer{
+			Port: getPort(ms.URL),
+		},
+	}
+
+	pact.
+		AddInteraction().
+		UponReceiving("Some name for the test").
+		WithRequest(Request{}).
+		WillRespondWith(Response{})
+
+	err := pact.Verify(func() error { panic("boom") })
+
+	if err == nil || !strings.Contains(err.Error(), "the test panicked: boom") {
+		t.Fatalf("want the panic as an error but got '%v'", err)
+	}
+	if !cleared || len(pact.Interactions) != 0 {
+		t.Fatal("want the interactions to be cleaned up")
+	}
+}
+
+func TestPact_VerifyInvalidInteraction(t *testing.T) {
+	ms := setupMockServer(true, t)
+	defer ms.Close()
+
+	pact := &Pact{
+		Server: &types.MockServer{
+			Port: getPort(ms.URL),
+		},
+	}
+
+	pact.
+		AddInteraction().
+		UponReceiving("A request with a missing fixture").
+		WithRequest(*(&Request{}).WithBodyFromFile("testdata/missing.json")).
+		WillRespondWith(Response{})
+
+	if err := pact.Verify(func() error { return nil }); err == nil {
+		t.Fatal("want an error for the invalid interaction but got none")
+	}
+	if len(pact.Interactions) != 0 {
+		t.Fatalf("want the interactions to be cleaned up but got %d", len(pact.Interactions))
+	}
+
+	pact.
+		AddInteraction().
+		UponReceiving("Some name for the test").
+		WithRequest(Request{}).
+		WillRespondWith(Response{})
+
+	if err := pact.Verify(func() error { return nil }); err != nil {
+		t.Fatalf("want the next Verify to be unaffected but got '%v'", err)
+	}
+}
+
 func TestPact_Setup(t *testing.T) {
 	defer stubPorts()()
 
@@ -964,3 +1058,52 @@ func stubPorts() func() {
 	}
 	return func() { waitForPort = old }
 }
+
+func TestPact_VerifyParallel(t *testing.T) {
+	ms := setupMockServer(true, t)
+	defer ms.Close()
+
+	pact := &Pact{
+		Server:   &types.MockServer{Port: getPort(ms.URL)},
+		Consumer: "My Consumer",
+		Provider: "My Provider",
+	}
+
+	// As per parallel subtests, each adding and verifying its own interaction
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for n := 0; n < 10; n++ {
+		wg.Add(1)
+		go func(description string) {
+			defer wg.Done()
+
+			i := (&Interaction{}).
+				UponReceiving(description).
+				WithRequest(Request{}).
+				WillRespondWith(Response{}).
+				WithTags(description)
+			if err := pact.AddInteractions(i); err != nil {
+				errs <- err
+				return
+			}
+
+			// Other subtests add interactions whilst this one is verified
+			test := func() error {
+				time.Sleep(10 * time.Millisecond)
+				return nil
+			}
+			if err := pact.Verify(test, SelectTags(description)); err != nil {
+				errs <- err
+			}
+		}(fmt.Sprintf("Request %d", n))
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Fatalf("Error: %v", err)
+	}
+	if len(pact.Interactions) != 0 {
+		t.Fatalf("expected all of the interactions to be verified but %d remain", len(pact.Interactions))
+	}
+}
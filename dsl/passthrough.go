@@ -0,0 +1,99 @@
+package dsl
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+// setupPassthrough forwards the requests to the Mock Server that match no
+// interaction to the PassthroughURL, if any
+func (p *Pact) setupPassthrough() error {
+	if p.PassthroughURL == "" || p.proxy == nil {
+		return nil
+	}
+
+	target, err := url.Parse(p.PassthroughURL)
+	if err != nil || target.Scheme == "" || target.Host == "" {
+		return types.NewError(types.ErrInvalidRequest, fmt.Errorf("invalid PassthroughURL '%s'", p.PassthroughURL))
+	}
+
+	p.proxy.setPassthrough(target)
+
+	return nil
+}
+
+// setPassthrough forwards requests matching none of the routes to target
+func (p *mockServerProxy) setPassthrough(target *url.URL) {
+	reverseProxy := httputil.NewSingleHostReverseProxy(target)
+	director := reverseProxy.Director
+	reverseProxy.Director = func(r *http.Request) {
+		director(r)
+		r.Host = target.Host
+	}
+	reverseProxy.ModifyResponse = func(res *http.Response) error {
+		rec, ok := res.Request.Context().Value(recordedRequestKey{}).(*recordedRequest)
+		if !ok {
+			return nil
+		}
+
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		rec.Status = res.StatusCode
+		rec.Latency = time.Since(rec.Received)
+
+		return p.captureResponse(rec, res)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.passthrough = reverseProxy
+}
+
+// SetRoutes sets the requests of the interactions registered with the Mock
+// Service, which are not passed through
+func (p *mockServerProxy) SetRoutes(routes []Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.routes = routes
+}
+
+// passthroughHandler returns the handler for requests to be passed through to
+// the real provider, or nil if the request is for the Mock Service
+func (p *mockServerProxy) passthroughHandler(r *http.Request) http.Handler {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.passthrough == nil {
+		return nil
+	}
+
+	for _, route := range p.routes {
+		if strings.EqualFold(route.Method, r.Method) && pathMatches(route.Path, r.URL.Path) {
+			return nil
+		}
+	}
+
+	log.Println("[DEBUG] passing through request to", r.Method, r.URL.Path)
+
+	return p.passthrough
+}
+
+// requestsOf returns the requests of the interactions
+func requestsOf(interactions []*Interaction) []Request {
+	requests := make([]Request, 0, len(interactions))
+	for _, i := range interactions {
+		requests = append(requests, i.Request)
+	}
+
+	return requests
+}
@@ -0,0 +1,81 @@
+package dsl
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+func TestPassthrough(t *testing.T) {
+	mockService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "mock")
+	}))
+	defer mockService.Close()
+
+	var host string
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host = r.Host
+		w.WriteHeader(http.StatusAccepted)
+		fmt.Fprint(w, "backend "+r.URL.Path)
+	}))
+	defer backend.Close()
+
+	pact := &Pact{
+		Server:         &types.MockServer{Port: getPort(mockService.URL)},
+		Host:           "localhost",
+		Network:        "tcp",
+		PassthroughURL: backend.URL,
+	}
+	pact.startProxy()
+	defer pact.proxy.Stop()
+	if err := pact.setupPassthrough(); err != nil {
+		t.Fatal("Error:", err)
+	}
+	pact.proxy.SetRoutes(requestsOf([]*Interaction{
+		(&Interaction{}).WithRequest(Request{Method: "GET", Path: Term("/users/1", `/users/\d+`)}),
+	}))
+
+	get := func(path string) string {
+		res, err := http.Get(fmt.Sprintf("http://localhost:%d%s", pact.Server.Port, path))
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		defer res.Body.Close()
+		body, _ := ioutil.ReadAll(res.Body)
+		return string(body)
+	}
+
+	if body := get("/users/2"); body != "mock" {
+		t.Fatalf("expected the interaction to be served by the Mock Service but got '%s'", body)
+	}
+	if body := get("/orders"); body != "backend /orders" {
+		t.Fatalf("expected the request to be passed through but got '%s'", body)
+	}
+	if host != strings.TrimPrefix(backend.URL, "http://") {
+		t.Fatalf("expected the Host of the backend but got '%s'", host)
+	}
+
+	requests := pact.proxy.Requests()
+	if len(requests) != 2 || requests[0].Passthrough || !requests[1].Passthrough || requests[1].Status != http.StatusAccepted {
+		t.Fatalf("unexpected requests recorded %+v", requests)
+	}
+	if line := accessLogLine(requests[1], nil); !strings.HasSuffix(line, "passthrough") {
+		t.Fatalf("expected the request to be logged as passed through but got '%s'", line)
+	}
+	if pact.proxy.Matched() != 1 {
+		t.Fatalf("expected requests passed through not to be counted as matched")
+	}
+}
+
+func TestPassthrough_invalidURL(t *testing.T) {
+	pact := &Pact{PassthroughURL: "localhost:8080", proxy: &mockServerProxy{}}
+
+	if err := pact.setupPassthrough(); err == nil {
+		t.Fatal("expected an error for a URL without a scheme")
+	}
+}
@@ -0,0 +1,166 @@
+package dsl
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+// defaultContainerWaitTimeout is how long to wait for a provider container to
+// be ready, if not given
+const defaultContainerWaitTimeout = time.Minute
+
+// ProviderContainer describes a provider to be run in a Docker container for
+// verification, see VerifyProviderContainer
+type ProviderContainer struct {
+	// Image of the provider e.g. "myorg/myprovider:1.2.3"
+	Image string
+
+	// Port the provider listens on within the container
+	Port int
+
+	// Env is the environment of the container
+	Env map[string]string
+
+	// WaitPath is polled over HTTP until the provider responds with a status
+	// below 500, e.g. "/health". It is required: the published port accepts
+	// connections as soon as the container starts, before the provider does.
+	WaitPath string
+
+	// WaitTimeout is how long to wait for the provider to be ready. Defaults
+	// to a minute
+	WaitTimeout time.Duration
+
+	// Args are any other arguments to "docker run" e.g. "--network", "ci"
+	Args []string
+}
+
+// runDocker runs the Docker CLI, returning its output
+var runDocker = func(args ...string) ([]byte, error) {
+	output, err := exec.Command("docker", args...).Output()
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return output, fmt.Errorf("docker %s: %v: %s", args[0], err, strings.TrimSpace(string(exitErr.Stderr)))
+	}
+
+	return output, err
+}
+
+// VerifyProviderContainer starts the provider in a Docker container, verifies
+// it as per VerifyProvider (with the ProviderBaseURL of the request set to the
+// port the container is published on), and then removes the container:
+//
+//	pact.VerifyProviderContainer(t, dsl.ProviderContainer{
+//		Image:    "myorg/myprovider:1.2.3",
+//		Port:     8080,
+//		WaitPath: "/health",
+//	}, types.VerifyRequest{PactURLs: []string{"pacts/myconsumer-myprovider.json"}})
+//
+// The Docker CLI must be on the PATH. The provider states are set up by the
+// state handlers in this process, as usual. Services on the host, e.g. stubs of
+// the dependencies of the provider, can be reached from within the container
+// as host.docker.internal.
+func (p *Pact) VerifyProviderContainer(t *testing.T, container ProviderContainer, request types.VerifyRequest) ([]types.ProviderVerifierResponse, error) {
+	id, baseURL, err := startProviderContainer(container)
+	if id != "" {
+		defer stopProviderContainer(id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	request.ProviderBaseURL = baseURL
+
+	return p.VerifyProvider(t, request)
+}
+
+// startProviderContainer runs the container, returning its ID and the base URL
+// of the provider once it is ready
+func startProviderContainer(container ProviderContainer) (string, string, error) {
+	if container.Image == "" || container.Port == 0 || container.WaitPath == "" {
+		return "", "", types.NewError(types.ErrInvalidRequest, fmt.Errorf("an image, port and wait path are required to run a provider container"))
+	}
+
+	port := strconv.Itoa(container.Port) + "/tcp"
+	args := []string{"run", "--detach", "--publish", "127.0.0.1::" + port, "--add-host", "host.docker.internal:host-gateway"}
+
+	names := make([]string, 0, len(container.Env))
+	for name := range container.Env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		args = append(args, "--env", name+"="+container.Env[name])
+	}
+	args = append(append(args, container.Args...), container.Image)
+
+	log.Println("[INFO] starting provider container", container.Image)
+	output, err := runDocker(args...)
+	if err != nil {
+		return "", "", types.NewError(types.ErrServiceStartup, fmt.Errorf("unable to start provider container: %v", err))
+	}
+	id := strings.TrimSpace(string(output))
+
+	output, err = runDocker("port", id, port)
+	if err != nil {
+		return id, "", types.NewError(types.ErrServiceStartup, fmt.Errorf("unable to find the port of provider container: %v", err))
+	}
+	address := strings.TrimSpace(strings.SplitN(string(output), "\n", 2)[0])
+	if _, _, err = net.SplitHostPort(address); err != nil {
+		return id, "", types.NewError(types.ErrServiceStartup, fmt.Errorf("unexpected port of provider container '%s'", address))
+	}
+	baseURL := "http://" + address
+
+	timeout := container.WaitTimeout
+	if timeout == 0 {
+		timeout = defaultContainerWaitTimeout
+	}
+	if err = waitForProvider(baseURL+container.WaitPath, timeout); err != nil {
+		return id, "", types.NewError(types.ErrServiceStartup, err)
+	}
+
+	return id, baseURL, nil
+}
+
+// waitForProvider polls the URL until the provider is ready, or the timeout
+// elapses
+func waitForProvider(url string, timeout time.Duration) error {
+	client := &http.Client{Timeout: time.Second}
+	deadline := time.Now().Add(timeout)
+
+	for {
+		res, err := client.Get(url)
+		if err == nil {
+			res.Body.Close()
+			if res.StatusCode < 500 {
+				return nil
+			}
+			err = fmt.Errorf("status %d", res.StatusCode)
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("provider container was not ready after %v: %v", timeout, err)
+		}
+		time.Sleep(250 * time.Millisecond)
+	}
+}
+
+// stopProviderContainer removes the container
+func stopProviderContainer(id string) {
+	log.Println("[INFO] removing provider container", id)
+
+	if _, err := runDocker("rm", "--force", id); err != nil {
+		log.Println("[WARN] unable to remove provider container:", err)
+	}
+}
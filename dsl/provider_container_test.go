@@ -0,0 +1,84 @@
+package dsl
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+func TestProviderContainer_start(t *testing.T) {
+	ready := false
+	provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" || !ready {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			ready = true
+			return
+		}
+	}))
+	defer provider.Close()
+	address := strings.TrimPrefix(provider.URL, "http://")
+
+	var calls []string
+	defer func(original func(...string) ([]byte, error)) { runDocker = original }(runDocker)
+	runDocker = func(args ...string) ([]byte, error) {
+		calls = append(calls, strings.Join(args, " "))
+		switch args[0] {
+		case "run":
+			return []byte("abc123\n"), nil
+		case "port":
+			return []byte(address + "\n[::1]:1234\n"), nil
+		}
+		return nil, nil
+	}
+
+	id, baseURL, err := startProviderContainer(ProviderContainer{
+		Image:       "myprovider:1.0.0",
+		Port:        8080,
+		Env:         map[string]string{"B": "2", "A": "1"},
+		WaitPath:    "/health",
+		WaitTimeout: 5 * time.Second,
+		Args:        []string{"--network", "ci"},
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if id != "abc123" || baseURL != provider.URL {
+		t.Fatalf("expected the container and its URL but got '%s' and '%s'", id, baseURL)
+	}
+
+	want := []string{
+		"run --detach --publish 127.0.0.1::8080/tcp --add-host host.docker.internal:host-gateway --env A=1 --env B=2 --network ci myprovider:1.0.0",
+		"port abc123 8080/tcp",
+	}
+	if fmt.Sprint(calls) != fmt.Sprint(want) {
+		t.Fatalf("expected docker to be run with %q but got %q", want, calls)
+	}
+
+	stopProviderContainer(id)
+	if calls[2] != "rm --force abc123" {
+		t.Fatalf("expected the container to be removed but got %q", calls[2])
+	}
+}
+
+func TestProviderContainer_startFailure(t *testing.T) {
+	defer func(original func(...string) ([]byte, error)) { runDocker = original }(runDocker)
+	runDocker = func(args ...string) ([]byte, error) {
+		return nil, errors.New("docker: command not found")
+	}
+
+	if _, _, err := startProviderContainer(ProviderContainer{Image: "myprovider", WaitPath: "/health"}); !errors.Is(err, types.ErrInvalidRequest) {
+		t.Fatalf("expected a port to be required but got '%v'", err)
+	}
+	if _, _, err := startProviderContainer(ProviderContainer{Image: "myprovider", Port: 8080}); !errors.Is(err, types.ErrInvalidRequest) {
+		t.Fatalf("expected a wait path to be required but got '%v'", err)
+	}
+	if _, _, err := startProviderContainer(ProviderContainer{Image: "myprovider", Port: 8080, WaitPath: "/health"}); !errors.Is(err, types.ErrServiceStartup) {
+		t.Fatalf("expected a startup error but got '%v'", err)
+	}
+}
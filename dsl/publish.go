@@ -50,7 +50,17 @@ func (p *Publisher) Publish(request types.PublishRequest) error {
 		return err
 	}
 
-	return p.pactClient.PublishPacts(request)
+	// The Pact CLI tools neither retry nor take a proxy other than the environment's
+	if request.MaxRetries > 0 || request.ProxyURL != "" {
+		err = publishWithBroker(request)
+	} else {
+		err = p.pactClient.PublishPacts(request)
+	}
+	if err != nil {
+		return err
+	}
+
+	return describeConsumers(request)
 }
 
 // Configure logging
@@ -0,0 +1,141 @@
+package dsl
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/ray-xu-deltatre/pact-go/broker"
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+// describeConsumers creates or updates the consumers of the published pacts in
+// the broker with the repository URL, main branch and labels of the request,
+// if any are given
+func describeConsumers(request types.PublishRequest) error {
+	if request.ConsumerRepositoryURL == "" && request.ConsumerMainBranch == "" && len(request.ConsumerLabels) == 0 {
+		return nil
+	}
+
+	consumers, err := pactConsumers(request.PactURLs)
+	if err != nil {
+		return types.NewError(types.ErrInvalidRequest, err)
+	}
+
+	client := publishRequestBroker(request)
+
+	for _, consumer := range consumers {
+		log.Println("[DEBUG] pact publisher: describe consumer", consumer)
+
+		_, err := client.CreateOrUpdatePacticipant(broker.Pacticipant{
+			Name:          consumer,
+			RepositoryURL: request.ConsumerRepositoryURL,
+			MainBranch:    request.ConsumerMainBranch,
+			Labels:        request.ConsumerLabels,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// pactConsumers returns the names of the consumers of the pact files, or the
+// JSON files within directories, at paths
+func pactConsumers(paths []string) ([]string, error) {
+	seen := map[string]bool{}
+	var consumers []string
+
+	files, err := pactFiles(paths)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		var pact PactFile
+		if err = json.Unmarshal(data, &pact); err != nil || pact.Consumer.Name == "" {
+			return nil, fmt.Errorf("unable to find the consumer of pact file '%s'", file)
+		}
+
+		if !seen[pact.Consumer.Name] {
+			seen[pact.Consumer.Name] = true
+			consumers = append(consumers, pact.Consumer.Name)
+		}
+	}
+	sort.Strings(consumers)
+
+	return consumers, nil
+}
+
+// pactFiles returns the pact files, or the JSON files within directories, at
+// paths
+func pactFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		matches, err := filepath.Glob(filepath.Join(path, "*.json"))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matches...)
+	}
+
+	return files, nil
+}
+
+// publishRequestBroker returns a client for the broker of the request
+func publishRequestBroker(request types.PublishRequest) *broker.Client {
+	return &broker.Client{
+		BrokerURL:       request.PactBroker,
+		BrokerUsername:  request.BrokerUsername,
+		BrokerPassword:  request.BrokerPassword,
+		BrokerToken:     request.BrokerToken,
+		TokenSource:     request.BrokerTokenSource,
+		MaxRetries:      request.MaxRetries,
+		RetryBackoff:    request.RetryBackoff,
+		MaxRetryBackoff: request.MaxRetryBackoff,
+		ProxyURL:        request.ProxyURL,
+	}
+}
+
+// publishWithBroker publishes the pacts of the request with the broker
+// package, so that failed requests are retried and sent via ProxyURL
+func publishWithBroker(request types.PublishRequest) error {
+	files, err := pactFiles(request.PactURLs)
+	if err != nil {
+		return types.NewError(types.ErrInvalidRequest, err)
+	}
+
+	client := publishRequestBroker(request)
+	for _, file := range files {
+		log.Println("[DEBUG] pact publisher: publish", file)
+
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return types.NewError(types.ErrInvalidRequest, err)
+		}
+		if err = client.PublishPact(content, request.ConsumerVersion, request.Tags...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
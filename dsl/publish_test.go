@@ -2,6 +2,7 @@ package dsl
 
 import (
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"log"
@@ -144,3 +145,88 @@ func TestPublish_PublishFailIntegration(t *testing.T) {
 		t.Fatal("want error, got none")
 	}
 }
+
+func TestPublish_PublishDescribesConsumers(t *testing.T) {
+	var requests []string
+	broker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.EscapedPath())
+		if r.Method == "GET" {
+			fmt.Fprint(w, `{"name":"Some Consumer"}`)
+		}
+	}))
+	defer broker.Close()
+
+	file := createSimplePact(true)
+	defer os.Remove(file.Name())
+
+	p := Publisher{
+		pactClient: newMockClient(),
+	}
+	err := p.Publish(types.PublishRequest{
+		PactURLs:              []string{file.Name()},
+		PactBroker:            broker.URL,
+		ConsumerVersion:       "1.0.0",
+		ConsumerRepositoryURL: "https://example.com/consumer",
+		ConsumerLabels:        []string{"frontend"},
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	want := "[PATCH /pacticipants/Some%20Consumer PUT /pacticipants/Some%20Consumer/labels/frontend GET /pacticipants/Some%20Consumer]"
+	if fmt.Sprint(requests) != want {
+		t.Fatalf("expected requests %s but got %v", want, requests)
+	}
+}
+
+func TestPublish_PublishRetries(t *testing.T) {
+	var requests []string
+	attempts := 0
+	broker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.EscapedPath())
+		if strings.HasPrefix(r.URL.Path, "/pacts/") {
+			if attempts++; attempts == 1 {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+		}
+	}))
+	defer broker.Close()
+
+	file := createSimplePact(true)
+	defer os.Remove(file.Name())
+
+	c := newMockClient()
+	c.PublishPactsError = fmt.Errorf("the Pact CLI tools should not be used")
+	p := Publisher{
+		pactClient: c,
+	}
+	err := p.Publish(types.PublishRequest{
+		PactURLs:        []string{file.Name()},
+		PactBroker:      broker.URL,
+		ConsumerVersion: "1.0.0",
+		Tags:            []string{"master"},
+		MaxRetries:      2,
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	want := "[PUT /pacticipants/Some%20Consumer/versions/1.0.0/tags/master PUT /pacts/provider/Some%20Provider/consumer/Some%20Consumer/version/1.0.0 PUT /pacts/provider/Some%20Provider/consumer/Some%20Consumer/version/1.0.0]"
+	if fmt.Sprint(requests) != want {
+		t.Fatalf("expected requests %s but got %v", want, requests)
+	}
+
+	// The broker doubles as the proxy, failing the publish as it isn't retried
+	attempts = 0
+	err = p.Publish(types.PublishRequest{
+		PactURLs:        []string{file.Name()},
+		PactBroker:      broker.URL,
+		ConsumerVersion: "1.0.0",
+		ProxyURL:        broker.URL,
+	})
+	if !errors.Is(err, types.ErrBroker) {
+		t.Fatalf("expected a broker error once the retries are exhausted but got '%v'", err)
+	}
+}
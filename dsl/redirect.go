@@ -0,0 +1,118 @@
+package dsl
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// WillRedirectTo responds to the request with a redirect, e.g.
+// http.StatusFound, to the request of the target interaction on the Mock
+// Server, in place of WillRespondWith:
+//
+//	login := pact.AddInteraction().
+//		UponReceiving("A request to login").
+//		WithRequest(dsl.Request{Method: "GET", Path: dsl.String("/login")})
+//	pact.AddInteraction().
+//		UponReceiving("A request for the account").
+//		WithRequest(dsl.Request{Method: "GET", Path: dsl.String("/account")}).
+//		WillRedirectTo(http.StatusFound, login)
+//
+// The Location header is a MockServerURL of the path and query of the target
+// request, so that a client following the redirect hits the Mock Server.
+// Verify fails unless the target request is made after the redirect, so that
+// chains of redirects are followed in order.
+func (i *Interaction) WillRedirectTo(status int, target *Interaction) *Interaction {
+	i.Response.Status = status
+	if status < 300 || status > 399 {
+		i.Response.err = fmt.Errorf("redirect status must be 3xx but got %d", status)
+		return i
+	}
+
+	location, err := requestURI(target.Request)
+	if err != nil {
+		i.Response.err = fmt.Errorf("unable to redirect to '%s': %v", target.Description, err)
+		return i
+	}
+
+	headers := MapMatcher{}
+	for k, v := range i.Response.Headers {
+		headers[k] = v
+	}
+	headers["Location"] = MockServerURL("http://localhost:8080"+location, ".*("+regexp.QuoteMeta(location)+")$")
+	i.Response.Headers = headers
+	i.redirectTo = target
+
+	return i
+}
+
+// requestURI returns the example path and query of the request
+func requestURI(request Request) (string, error) {
+	path := metadataValueString(request.Path)
+	if !strings.HasPrefix(path, "/") {
+		return "", fmt.Errorf("the request has no path")
+	}
+
+	if len(request.Query) == 0 {
+		return path, nil
+	}
+
+	query := url.Values{}
+	for k, v := range request.Query {
+		query.Set(k, metadataValueString(v))
+	}
+
+	return path + "?" + query.Encode(), nil
+}
+
+// checkRedirects checks that the target of each redirect was requested after
+// the redirect
+func (p *Pact) checkRedirects(interactions []*Interaction) error {
+	if p.proxy == nil {
+		return nil
+	}
+
+	requests := p.proxy.Requests()
+
+	var mismatches []string
+	for _, i := range interactions {
+		if i.redirectTo == nil {
+			continue
+		}
+
+		redirected := -1
+		for n, rec := range requests {
+			if requestMatches(i.Request, rec) {
+				redirected = n
+				break
+			}
+		}
+		if redirected < 0 {
+			continue
+		}
+
+		followed := false
+		for _, rec := range requests[redirected+1:] {
+			if requestMatches(i.redirectTo.Request, rec) {
+				followed = true
+				break
+			}
+		}
+		if !followed {
+			mismatches = append(mismatches, fmt.Sprintf("%s %s: the redirect to '%s' was not followed", requests[redirected].Method, requests[redirected].Path, i.redirectTo.Description))
+		}
+	}
+
+	if len(mismatches) > 0 {
+		return fmt.Errorf("redirects were not followed:\n\n%s", strings.Join(mismatches, "\n"))
+	}
+
+	return nil
+}
+
+// requestMatches returns true if the recorded request has the method and path
+// of the request
+func requestMatches(request Request, rec *recordedRequest) bool {
+	return strings.EqualFold(request.Method, rec.Method) && pathMatches(request.Path, rec.Path)
+}
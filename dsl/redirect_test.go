@@ -0,0 +1,74 @@
+package dsl
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRedirect_WillRedirectTo(t *testing.T) {
+	next := (&Interaction{}).
+		UponReceiving("A request for the second page").
+		WithRequest(Request{
+			Method: "GET",
+			Path:   String("/orders"),
+			Query:  MapMatcher{"page": String("2")},
+		})
+	i := (&Interaction{}).
+		UponReceiving("A request for the orders").
+		WithRequest(Request{Method: "GET", Path: String("/orders/all")}).
+		WillRedirectTo(http.StatusFound, next)
+
+	if err := i.validate(); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if i.Response.Status != http.StatusFound {
+		t.Fatalf("expected a redirect status but got %d", i.Response.Status)
+	}
+
+	location, ok := i.Response.Headers["Location"].(mockServerURL)
+	if !ok || location.path != "/orders?page=2" || location.Data.Generate != "http://localhost:8080/orders?page=2" {
+		t.Fatalf("expected the location to be the target request on the mock server but got %v", i.Response.Headers["Location"])
+	}
+
+	for name, invalid := range map[string]*Interaction{
+		"status":  (&Interaction{}).WillRedirectTo(http.StatusOK, next),
+		"no path": (&Interaction{}).WillRedirectTo(http.StatusFound, &Interaction{}),
+	} {
+		if err := invalid.validate(); err == nil {
+			t.Fatalf("%s: expected the redirect to be rejected", name)
+		}
+	}
+}
+
+func TestRedirect_checkRedirects(t *testing.T) {
+	login := (&Interaction{}).
+		UponReceiving("A request to login").
+		WithRequest(Request{Method: "GET", Path: String("/login")})
+	account := (&Interaction{}).
+		UponReceiving("A request for the account").
+		WithRequest(Request{Method: "GET", Path: String("/account")}).
+		WillRedirectTo(http.StatusFound, login)
+	interactions := []*Interaction{account, login}
+
+	for requests, followed := range map[string]bool{
+		"GET /account,GET /login": true,
+		"GET /login,GET /account": false,
+		"GET /account":            false,
+		"GET /login":              true,
+	} {
+		p := &Pact{proxy: &mockServerProxy{}}
+		for _, request := range strings.Split(requests, ",") {
+			parts := strings.Split(request, " ")
+			p.proxy.requests = append(p.proxy.requests, &recordedRequest{Method: parts[0], Path: parts[1]})
+		}
+
+		err := p.checkRedirects(interactions)
+		if followed && err != nil {
+			t.Fatalf("%s: expected the redirect to be followed but got %v", requests, err)
+		}
+		if !followed && (err == nil || !strings.Contains(err.Error(), "'A request to login' was not followed")) {
+			t.Fatalf("%s: expected the redirect not to be followed but got %v", requests, err)
+		}
+	}
+}
@@ -1,5 +1,7 @@
 package dsl
 
+import "reflect"
+
 // Request is the default implementation of the Request interface.
 type Request struct {
 	Method  string      `json:"method"`
@@ -7,4 +9,13 @@ type Request struct {
 	Query   MapMatcher  `json:"query,omitempty"`
 	Headers MapMatcher  `json:"headers,omitempty"`
 	Body    interface{} `json:"body,omitempty"`
+
+	// err records any failure to load the body e.g. from a fixture file
+	err error
+
+	// fixture is the file the body was loaded from, if any
+	fixture string
+
+	// bodyType is the Go type the body was built from, if known
+	bodyType reflect.Type
 }
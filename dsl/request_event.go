@@ -0,0 +1,92 @@
+package dsl
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// RequestEvent describes a request made to the Mock Server by the code under
+// test, and whether the Mock Service matched it to an interaction. See
+// Pact.OnInteractionMatched and Pact.OnUnmatchedRequest.
+type RequestEvent struct {
+	Method string
+	Path   string
+	Query  string
+	Header http.Header
+	Body   []byte
+
+	// Status of the Mock Service response
+	Status int
+
+	// Description and ProviderState of the interaction the request matched,
+	// taken to be the first registered interaction with the method and path
+	// of the request. Empty for an unmatched request
+	Description   string
+	ProviderState string
+
+	// Mismatch is the (JSON) explanation of the Mock Service for an unmatched
+	// request
+	Mismatch json.RawMessage
+}
+
+// hasRequestHooks returns true if any hooks are to be called as requests are
+// made to the Mock Server
+func (p *Pact) hasRequestHooks() bool {
+	return p.OnInteractionMatched != nil || p.OnUnmatchedRequest != nil
+}
+
+// requestMade calls the hook for the request recorded by the proxy, if any
+func (p *Pact) requestMade(rec *recordedRequest) {
+	event := RequestEvent{
+		Method: rec.Method,
+		Path:   rec.Path,
+		Query:  rec.Query,
+		Header: rec.Header,
+		Body:   rec.Body,
+		Status: rec.Status,
+	}
+
+	if rec.Unmatched {
+		if p.OnUnmatchedRequest != nil {
+			event.Mismatch = json.RawMessage(rec.Mismatch)
+			p.OnUnmatchedRequest(event)
+		}
+		return
+	}
+
+	if p.OnInteractionMatched != nil {
+		if i := p.proxy.matchedInteraction(rec); i != nil {
+			event.Description = i.Description
+			event.ProviderState = i.State
+		}
+		p.OnInteractionMatched(event)
+	}
+}
+
+// SetInteractions sets the interactions registered with the Mock Service,
+// which matched requests are attributed to
+func (p *mockServerProxy) SetInteractions(interactions []*Interaction) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.interactions = interactions
+}
+
+// matchedInteraction returns the registered interaction with the method and
+// path of the recorded request, if any
+func (p *mockServerProxy) matchedInteraction(rec *recordedRequest) *Interaction {
+	if p == nil {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, i := range p.interactions {
+		if requestMatches(i.Request, rec) {
+			return i
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package dsl
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+func TestRequestEvent_Hooks(t *testing.T) {
+	ms := setupMismatchMockServer()
+	defer ms.Close()
+
+	var mu sync.Mutex
+	var matched, unmatched []RequestEvent
+	pact := &Pact{
+		Server:  &types.MockServer{Port: getPort(ms.URL)},
+		Host:    "localhost",
+		Network: "tcp",
+		OnInteractionMatched: func(event RequestEvent) {
+			mu.Lock()
+			defer mu.Unlock()
+			matched = append(matched, event)
+		},
+		OnUnmatchedRequest: func(event RequestEvent) {
+			mu.Lock()
+			defer mu.Unlock()
+			unmatched = append(unmatched, event)
+		},
+	}
+	pact.startProxy()
+	defer pact.proxy.Stop()
+	pact.proxy.SetInteractions([]*Interaction{
+		(&Interaction{}).
+			Given("User billy exists").
+			UponReceiving("A request for a user").
+			WithRequest(Request{Method: "GET", Path: String("/users/billy")}),
+		(&Interaction{}).
+			Given("The service is down").
+			UponReceiving("A request that errors").
+			WithRequest(Request{Method: "GET", Path: String("/error")}),
+	})
+
+	url := fmt.Sprintf("http://localhost:%d", pact.Server.Port)
+	if _, err := http.Post(url+"/users?name=billy", "application/json", strings.NewReader(`{"name":"billy"}`)); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err := http.Get(url + "/error"); err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(unmatched) != 1 || len(matched) != 1 {
+		t.Fatalf("expected 1 matched and 1 unmatched request but got %d and %d", len(matched), len(unmatched))
+	}
+
+	event := unmatched[0]
+	if event.Method != "POST" || event.Path != "/users" || event.Query != "name=billy" || string(event.Body) != `{"name":"billy"}` {
+		t.Fatalf("unexpected unmatched request: %+v", event)
+	}
+	if !strings.Contains(string(event.Mismatch), "No interaction found for POST /users") {
+		t.Fatalf("expected the mismatch of the Mock Service but got '%s'", event.Mismatch)
+	}
+
+	if event.Description != "" || event.ProviderState != "" {
+		t.Fatalf("expected no interaction for an unmatched request: %+v", event)
+	}
+
+	if matched[0].Path != "/error" || matched[0].Status != http.StatusInternalServerError || matched[0].Mismatch != nil {
+		t.Fatalf("unexpected matched request: %+v", matched[0])
+	}
+	if matched[0].Description != "A request that errors" || matched[0].ProviderState != "The service is down" {
+		t.Fatalf("expected the matched interaction to be described: %+v", matched[0])
+	}
+}
@@ -0,0 +1,427 @@
+package dsl
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	mathrand "math/rand"
+	"net/http"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ray-xu-deltatre/pact-go/proxy"
+)
+
+// Request generator types, as per the V3 specification
+const (
+	generatorUUID     = "Uuid"
+	generatorDateTime = "DateTime"
+)
+
+// requestGenerator describes how to generate a value in a request body when it
+// is replayed against the provider. Format is the Go time layout of a DateTime,
+// which is why the generators are written to the pact as "requestGenerators"
+// rather than the "generators" of the V3 specification, whose formats are Java
+// date-time patterns.
+type requestGenerator struct {
+	Type   string `json:"type"`
+	Format string `json:"format,omitempty"`
+}
+
+// generatorSource is the clock and source of randomness of generated values
+type generatorSource struct {
+	now    func() time.Time
+	random io.Reader
+}
+
+// generatorSource returns the Clock and Random source of the pact, defaulting
+// to the current time and a source seeded with the Seed (or PACT_SEED, or else
+// a random seed, which is logged). A fresh seeded source is returned each time,
+// so that each verification generates the same values regardless of what ran
+// before it.
+func (p *Pact) generatorSource() generatorSource {
+	source := generatorSource{now: p.now(), random: p.Random}
+	if source.random == nil {
+		seed, ok := p.seed()
+		if ok {
+			log.Printf("[DEBUG] generating values with seed %d\n", seed)
+		} else {
+			seed = randomSeed()
+			log.Printf("[INFO] generating values with seed %d, set PACT_SEED=%d to generate the same values again\n", seed, seed)
+		}
+		source.random = &seededReader{random: mathrand.New(mathrand.NewSource(seed))}
+	}
+
+	return source
+}
+
+// now returns the Clock of the pact, defaulting to time.Now
+func (p *Pact) now() func() time.Time {
+	if p.Clock == nil {
+		return time.Now
+	}
+
+	return p.Clock
+}
+
+// seed returns the Seed of the pact, or else the PACT_SEED, if either is set
+func (p *Pact) seed() (int64, bool) {
+	if p.Seed != 0 {
+		return p.Seed, true
+	}
+
+	if env := os.Getenv("PACT_SEED"); env != "" {
+		seed, err := strconv.ParseInt(env, 10, 64)
+		if err == nil {
+			return seed, true
+		}
+		log.Printf("[WARN] ignoring invalid PACT_SEED '%s': %v\n", env, err)
+	}
+
+	return 0, false
+}
+
+// randomSeed returns a non-negative seed from crypto/rand
+func randomSeed() int64 {
+	var b [8]byte
+	rand.Read(b[:])
+
+	return int64(binary.LittleEndian.Uint64(b[:]) >> 1)
+}
+
+// seededReader is a seeded source of randomness that is safe for concurrent
+// use, as requests may be verified in parallel
+type seededReader struct {
+	mu     sync.Mutex
+	random *mathrand.Rand
+}
+
+func (r *seededReader) Read(b []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.random.Read(b)
+}
+
+// generate returns a fresh value
+func (g requestGenerator) generate(source generatorSource) interface{} {
+	switch g.Type {
+	case generatorUUID:
+		return randomUUID(source.random)
+	case generatorDateTime:
+		return source.now().Format(g.Format)
+	}
+
+	return nil
+}
+
+// generatedValue is a Term whose example is replaced by a fresh value when the
+// request is replayed against the provider
+type generatedValue struct {
+	term
+
+	generator requestGenerator
+}
+
+// GeneratedUUID matches a UUID in a request body. The example is used in the
+// consumer test, and a random UUID is generated each time the request is
+// replayed against the provider.
+func GeneratedUUID() Matcher {
+	return generatedValue{
+		term:      UUID().(term),
+		generator: requestGenerator{Type: generatorUUID},
+	}
+}
+
+// GeneratedDateTime matches a timestamp with the given Go time layout in a
+// request body, as per DateTime. The example is used in the consumer test, and
+// the current time is generated each time the request is replayed against the
+// provider, so that it isn't rejected as stale.
+func GeneratedDateTime(layout string, example string) Matcher {
+	return generatedValue{
+		term:      DateTime(layout, example).(term),
+		generator: requestGenerator{Type: generatorDateTime, Format: layout},
+	}
+}
+
+// randomUUID returns a random (version 4) UUID
+func randomUUID(random io.Reader) string {
+	b := make([]byte, 16)
+	io.ReadFull(random, b)
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// requestGenerators returns the generators in the body of a request, by the
+// JSON path of the generated value
+func requestGenerators(body interface{}) map[string]requestGenerator {
+	generators := make(map[string]requestGenerator)
+	collectRequestGenerators(reflect.ValueOf(body), "$", generators)
+
+	if len(generators) == 0 {
+		return nil
+	}
+
+	return generators
+}
+
+// collectRequestGenerators finds any generated values in v, which may be a
+// matcher, map, slice or struct, at the given JSON path
+func collectRequestGenerators(v reflect.Value, path string, generators map[string]requestGenerator) {
+	if !v.IsValid() {
+		return
+	}
+
+	if v.CanInterface() {
+		switch m := v.Interface().(type) {
+		case generatedValue:
+			generators[path] = m.generator
+			return
+		case like:
+			collectRequestGenerators(reflect.ValueOf(m.Contents), path, generators)
+			return
+		case eachLike:
+			collectRequestGenerators(reflect.ValueOf(m.Contents), path+"[*]", generators)
+			return
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Interface, reflect.Ptr:
+		collectRequestGenerators(v.Elem(), path, generators)
+	case reflect.Map:
+		for _, key := range v.MapKeys() {
+			collectRequestGenerators(v.MapIndex(key), jsonPathField(path, fmt.Sprintf("%v", key.Interface())), generators)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			collectRequestGenerators(v.Index(i), fmt.Sprintf("%s[%d]", path, i), generators)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.Tag.Get("json") == "-" || !v.Field(i).CanInterface() {
+				continue
+			}
+			name := getJsonFieldName(field)
+			if name == "" {
+				name = field.Name
+			}
+			collectRequestGenerators(v.Field(i), jsonPathField(path, name), generators)
+		}
+	}
+}
+
+var jsonPathIdentifier = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// jsonPathField appends a field name to a JSON path, quoting it if need be
+func jsonPathField(path string, name string) string {
+	if jsonPathIdentifier.MatchString(name) {
+		return path + "." + name
+	}
+
+	return path + "['" + name + "']"
+}
+
+// recordRequestGenerators remembers the generators in the request body of an
+// interaction, so that they can be written to the pact file
+func (p *Pact) recordRequestGenerators(key string, body interface{}) {
+	generators := requestGenerators(body)
+	if generators == nil {
+		return
+	}
+
+	if p.requestGenerators == nil {
+		p.requestGenerators = make(map[string]map[string]requestGenerator)
+	}
+	p.requestGenerators[key] = generators
+}
+
+// generatedRequest is the method and path of an interaction request in a pact
+// file, and the generators of its body
+type generatedRequest struct {
+	Method     string
+	Path       string
+	Generators map[string]requestGenerator
+}
+
+// loadRequestGenerators returns the requests with body generators in the given
+// pact files. Pacts fetched from a remote URL are skipped.
+func loadRequestGenerators(pactURLs []string) []generatedRequest {
+	var requests []generatedRequest
+
+	for _, pactURL := range pactURLs {
+		if strings.HasPrefix(pactURL, "http://") || strings.HasPrefix(pactURL, "https://") {
+			continue
+		}
+
+		content, err := ioutil.ReadFile(pactURL)
+		if err != nil {
+			continue
+		}
+
+		var pact struct {
+			Interactions []struct {
+				Request struct {
+					Method string `json:"method"`
+					Path   string `json:"path"`
+				} `json:"request"`
+				RequestGenerators map[string]requestGenerator `json:"requestGenerators"`
+			} `json:"interactions"`
+		}
+		if err = json.Unmarshal(content, &pact); err != nil {
+			log.Printf("[WARN] unable to read request generators from pact '%s': %v", pactURL, err)
+			continue
+		}
+
+		for _, i := range pact.Interactions {
+			if len(i.RequestGenerators) > 0 {
+				requests = append(requests, generatedRequest{Method: i.Request.Method, Path: i.Request.Path, Generators: i.RequestGenerators})
+			}
+		}
+	}
+
+	return requests
+}
+
+// requestGeneratorMiddleware replaces the values in the JSON body of requests
+// replayed against the provider with freshly generated ones, as per the
+// generators of the request in the pact with the same method and path
+func requestGeneratorMiddleware(requests []generatedRequest, source generatorSource) proxy.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == providerStatesSetupPath || r.Body == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			for _, request := range requests {
+				if !strings.EqualFold(request.Method, r.Method) || request.Path != r.URL.Path {
+					continue
+				}
+
+				body, err := ioutil.ReadAll(r.Body)
+				r.Body.Close()
+				if err == nil {
+					body = generateRequestBody(body, request.Generators, source)
+				}
+
+				r.Body = ioutil.NopCloser(bytes.NewReader(body))
+				r.ContentLength = int64(len(body))
+				r.Header.Set("Content-Length", strconv.Itoa(len(body)))
+				break
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// generateRequestBody applies generators to a JSON body, returning it as is if
+// it isn't JSON
+func generateRequestBody(body []byte, generators map[string]requestGenerator, source generatorSource) []byte {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var content interface{}
+	if err := decoder.Decode(&content); err != nil {
+		return body
+	}
+
+	for path, g := range generators {
+		tokens, err := parseJSONPath(path)
+		if err != nil {
+			log.Printf("[WARN] unable to apply request generator at '%s': %v", path, err)
+			continue
+		}
+		content = setJSONPath(content, tokens, g, source)
+	}
+
+	var generated bytes.Buffer
+	encoder := json.NewEncoder(&generated)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(content); err != nil {
+		return body
+	}
+
+	return bytes.TrimSpace(generated.Bytes())
+}
+
+// parseJSONPath splits a JSON path such as "$.items[*].id" or "$['a b'][0]"
+// into its field names, indexes and wildcards
+func parseJSONPath(path string) ([]string, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("JSON path must start with '$'")
+	}
+
+	var tokens []string
+	for rest := path[1:]; rest != ""; {
+		switch {
+		case strings.HasPrefix(rest, "['"):
+			end := strings.Index(rest, "']")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated field name in '%s'", path)
+			}
+			tokens = append(tokens, "."+rest[2:end])
+			rest = rest[end+2:]
+		case strings.HasPrefix(rest, "["):
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated index in '%s'", path)
+			}
+			tokens = append(tokens, rest[:end+1])
+			rest = rest[end+1:]
+		case strings.HasPrefix(rest, "."):
+			end := strings.IndexAny(rest[1:], ".[")
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			tokens = append(tokens, rest[:end+1])
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("unexpected '%s' in '%s'", rest, path)
+		}
+	}
+
+	return tokens, nil
+}
+
+// setJSONPath replaces the values at the path given by tokens with generated
+// ones, ignoring any that don't exist
+func setJSONPath(content interface{}, tokens []string, g requestGenerator, source generatorSource) interface{} {
+	if len(tokens) == 0 {
+		return g.generate(source)
+	}
+
+	token := tokens[0]
+	switch c := content.(type) {
+	case map[string]interface{}:
+		if strings.HasPrefix(token, ".") {
+			if v, ok := c[token[1:]]; ok {
+				c[token[1:]] = setJSONPath(v, tokens[1:], g, source)
+			}
+		}
+	case []interface{}:
+		if token == "[*]" {
+			for i := range c {
+				c[i] = setJSONPath(c[i], tokens[1:], g, source)
+			}
+		} else if i, err := strconv.Atoi(strings.Trim(token, "[]")); err == nil && i >= 0 && i < len(c) {
+			c[i] = setJSONPath(c[i], tokens[1:], g, source)
+		}
+	}
+
+	return content
+}
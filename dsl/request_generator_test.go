@@ -0,0 +1,235 @@
+package dsl
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRequestGenerator_requestGenerators(t *testing.T) {
+	body := map[string]interface{}{
+		"id":         GeneratedUUID(),
+		"created at": GeneratedDateTime(time.RFC3339, ""),
+		"items": EachLike(StructMatcher{
+			"ref": GeneratedUUID(),
+		}, 1),
+		"name": Like("billy"),
+	}
+
+	generators := requestGenerators(body)
+	expected := map[string]requestGenerator{
+		"$.id":            {Type: generatorUUID},
+		"$['created at']": {Type: generatorDateTime, Format: time.RFC3339},
+		"$.items[*].ref":  {Type: generatorUUID},
+	}
+
+	if len(generators) != len(expected) {
+		t.Fatalf("expected generators %v but got %v", expected, generators)
+	}
+	for path, g := range expected {
+		if generators[path] != g {
+			t.Fatalf("expected generator %v at '%s' but got %v", g, path, generators[path])
+		}
+	}
+
+	if requestGenerators(map[string]interface{}{"name": "billy"}) != nil {
+		t.Fatal("expected no generators")
+	}
+}
+
+func TestRequestGenerator_GeneratedValueIsTerm(t *testing.T) {
+	got, _ := json.Marshal(GeneratedDateTime("2006-01-02", "2020-01-02"))
+	want, _ := json.Marshal(DateTime("2006-01-02", "2020-01-02"))
+	if string(got) != string(want) {
+		t.Fatalf("expected generated value to be written as a Term, got %s", got)
+	}
+}
+
+func TestRequestGenerator_generateRequestBody(t *testing.T) {
+	body := []byte(`{"id":"00000000-0000-4000-8000-000000000000","sent":"2000-02-01","items":[{"ref":"a"},{"ref":"b"}],"count":12345678901234567890}`)
+
+	generated := generateRequestBody(body, map[string]requestGenerator{
+		"$.id":           {Type: generatorUUID},
+		"$['sent']":      {Type: generatorDateTime, Format: "2006-01-02"},
+		"$.items[*].ref": {Type: generatorUUID},
+		"$.missing":      {Type: generatorUUID},
+	}, (&Pact{}).generatorSource())
+
+	var content struct {
+		ID    string `json:"id"`
+		Sent  string `json:"sent"`
+		Items []struct {
+			Ref string `json:"ref"`
+		} `json:"items"`
+		Missing *string `json:"missing"`
+	}
+	if err := json.Unmarshal(generated, &content); err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	generatedUUID := regexp.MustCompile("^" + uuid + "$")
+	if !generatedUUID.MatchString(content.ID) || content.ID == "00000000-0000-4000-8000-000000000000" {
+		t.Fatalf("expected a fresh UUID but got '%s'", content.ID)
+	}
+	if content.Sent != time.Now().Format("2006-01-02") {
+		t.Fatalf("expected the current date but got '%s'", content.Sent)
+	}
+	for _, item := range content.Items {
+		if !generatedUUID.MatchString(item.Ref) {
+			t.Fatalf("expected a fresh UUID but got '%s'", item.Ref)
+		}
+	}
+	if content.Missing != nil {
+		t.Fatal("expected missing values not to be generated")
+	}
+	if !strings.Contains(string(generated), "12345678901234567890") {
+		t.Fatalf("expected numbers to be preserved, got %s", generated)
+	}
+
+	if string(generateRequestBody([]byte("not json"), nil, generatorSource{})) != "not json" {
+		t.Fatal("expected a non-JSON body to be returned as is")
+	}
+}
+
+func TestRequestGenerator_Middleware(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "pact-generators")
+	defer os.RemoveAll(dir)
+
+	pactFile := filepath.Join(dir, "pact.json")
+	ioutil.WriteFile(pactFile, []byte(`{"interactions":[
+		{"description":"a","request":{"method":"POST","path":"/orders"},"requestGenerators":{"$.id":{"type":"Uuid"}}},
+		{"description":"c","request":{"method":"PUT","path":"/orders","generators":{"body":{"$.id":{"type":"Uuid"}}}}},
+		{"description":"b","request":{"method":"GET","path":"/orders"}}
+	]}`), 0644)
+
+	requests := loadRequestGenerators([]string{pactFile, "http://localhost/pact.json"})
+	if len(requests) != 1 {
+		t.Fatalf("expected one request with generators but got %d", len(requests))
+	}
+
+	var received string
+	handler := requestGeneratorMiddleware(requests, (&Pact{}).generatorSource())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		received = string(body)
+		if r.ContentLength != int64(len(body)) {
+			t.Fatalf("expected the content length to be updated to %d but got %d", len(body), r.ContentLength)
+		}
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/orders", strings.NewReader(`{"id":"x"}`)))
+	if received == `{"id":"x"}` || !strings.HasPrefix(received, `{"id":"`) {
+		t.Fatalf("expected a generated id but got '%s'", received)
+	}
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("PUT", "/orders", strings.NewReader(`{"id":"x"}`)))
+	if received != `{"id":"x"}` {
+		t.Fatalf("expected an unmatched request to be passed as is but got '%s'", received)
+	}
+}
+
+func TestRequestGenerator_generatorSource(t *testing.T) {
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	pact := &Pact{
+		Clock:  func() time.Time { return now },
+		Random: bytes.NewReader(bytes.Repeat([]byte{0xab}, 16)),
+	}
+
+	generated := generateRequestBody([]byte(`{"id":"x","sent":"x"}`), map[string]requestGenerator{
+		"$.id":   {Type: generatorUUID},
+		"$.sent": {Type: generatorDateTime, Format: time.RFC3339},
+	}, pact.generatorSource())
+
+	want := `{"id":"abababab-abab-4bab-abab-abababababab","sent":"2020-01-02T03:04:05Z"}`
+	if string(generated) != want {
+		t.Fatalf("expected '%s' but got '%s'", want, generated)
+	}
+}
+
+func TestRequestGenerator_seed(t *testing.T) {
+	generators := map[string]requestGenerator{"$.id": {Type: generatorUUID}}
+	generate := func(pact *Pact) string {
+		return string(generateRequestBody([]byte(`{"id":"x"}`), generators, pact.generatorSource()))
+	}
+
+	pact := &Pact{Seed: 42}
+	first := generate(pact)
+	if first != generate(pact) {
+		t.Fatal("expected each verification to generate the same values with a seed")
+	}
+	if first == generate(&Pact{Seed: 43}) {
+		t.Fatal("expected another seed to generate other values")
+	}
+
+	os.Setenv("PACT_SEED", "42")
+	defer os.Unsetenv("PACT_SEED")
+	if generate(&Pact{}) != first {
+		t.Fatal("expected the seed to be read from PACT_SEED")
+	}
+
+	os.Setenv("PACT_SEED", "not a number")
+	if generate(&Pact{}) == generate(&Pact{}) {
+		t.Fatal("expected an invalid PACT_SEED to be ignored")
+	}
+}
+
+func TestRequestGenerator_randomSeed(t *testing.T) {
+	var logged bytes.Buffer
+	log.SetOutput(&logged)
+	defer log.SetOutput(os.Stderr)
+
+	generators := map[string]requestGenerator{"$.id": {Type: generatorUUID}}
+	generated := generateRequestBody([]byte(`{"id":"x"}`), generators, (&Pact{}).generatorSource())
+
+	match := regexp.MustCompile(`\[INFO\] generating values with seed (\d+), set PACT_SEED=`).FindStringSubmatch(logged.String())
+	if match == nil {
+		t.Fatalf("expected the seed to be logged but got '%s'", logged.String())
+	}
+	seed, _ := strconv.ParseInt(match[1], 10, 64)
+	if again := generateRequestBody([]byte(`{"id":"x"}`), generators, (&Pact{Seed: seed}).generatorSource()); string(again) != string(generated) {
+		t.Fatalf("expected the logged seed to generate '%s' again but got '%s'", generated, again)
+	}
+}
+
+func TestRequestGenerator_rewrite(t *testing.T) {
+	r := &pactRewriter{
+		requestGenerators: map[string]map[string]requestGenerator{
+			interactionKey("a request", ""): {"$.sent": {Type: generatorDateTime, Format: time.RFC3339}},
+		},
+	}
+
+	rewritten, err := r.rewrite([]byte(`{"interactions":[{"description":"a request","request":{"method":"POST","path":"/orders"}}]}`))
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	var pact struct {
+		Interactions []struct {
+			Request           map[string]interface{}      `json:"request"`
+			RequestGenerators map[string]requestGenerator `json:"requestGenerators"`
+		} `json:"interactions"`
+	}
+	json.Unmarshal(rewritten, &pact)
+	if len(pact.Interactions) != 1 || pact.Interactions[0].Request["generators"] != nil || len(pact.Interactions[0].RequestGenerators) != 1 {
+		t.Fatalf("expected the generators to be written as requestGenerators but got %s", rewritten)
+	}
+
+	dir, _ := ioutil.TempDir("", "pact-generators")
+	defer os.RemoveAll(dir)
+	pactFile := filepath.Join(dir, "pact.json")
+	ioutil.WriteFile(pactFile, rewritten, 0644)
+
+	requests := loadRequestGenerators([]string{pactFile})
+	if len(requests) != 1 || requests[0].Method != "POST" || requests[0].Path != "/orders" || requests[0].Generators["$.sent"].Format != time.RFC3339 {
+		t.Fatalf("expected the generators to be read back but got %+v", requests)
+	}
+}
@@ -0,0 +1,205 @@
+package dsl
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+// requestGuard records requests made by the code under test to hosts other
+// than the Mock Server(s) and the allowed hosts
+type requestGuard struct {
+	// host and ports of the Mock Server(s)
+	host  string
+	ports []int
+
+	// allowedHosts may be a host, or host:port
+	allowedHosts []string
+
+	mu      sync.Mutex
+	blocked map[string]bool
+
+	// installed until uninstall is called
+	installed bool
+}
+
+// newRequestGuard returns a guard allowing requests to the Mock Servers used
+// by a test, and the AllowedHosts
+func (p *Pact) newRequestGuard(servers map[string]*types.MockServer) *requestGuard {
+	g := &requestGuard{
+		host:         p.Host,
+		allowedHosts: p.AllowedHosts,
+		blocked:      make(map[string]bool),
+	}
+	for _, server := range servers {
+		g.ports = append(g.ports, server.Port)
+	}
+
+	// The proxy recording requests forwards them to the Mock Service
+	if p.proxy != nil {
+		if port, err := strconv.Atoi(p.proxy.target.Port()); err == nil {
+			g.ports = append(g.ports, port)
+		}
+	}
+
+	return g
+}
+
+// guardedTests serialises the tests with BlockExternalRequests. Guarding
+// swaps http.DefaultTransport, which is shared by the whole process, so a
+// guard can't tell the requests of its test from those of another test running
+// in parallel, nor could two guards safely swap it at once.
+var guardedTests sync.Mutex
+
+// activeGuard is the guard of the test currently running, installed in
+// http.DefaultTransport whilst it runs
+var activeGuard = struct {
+	sync.Mutex
+	guard    *requestGuard
+	original http.RoundTripper
+}{}
+
+// GuardTransport wraps a transport such that, whilst a test with
+// BlockExternalRequests is running, requests to hosts other than the Mock
+// Server (or AllowedHosts) fail that test. Requests sent via
+// http.DefaultTransport (e.g. by http.DefaultClient) are guarded without
+// this. Use it for API clients with their own transport, e.g.
+//
+//	client := &http.Client{Transport: dsl.GuardTransport(myTransport)}
+func GuardTransport(base http.RoundTripper) http.RoundTripper {
+	return &guardedTransport{base: base}
+}
+
+// guardedTransport fails requests that no active guard allows
+type guardedTransport struct {
+	base http.RoundTripper
+}
+
+func (t *guardedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !allowRequest(req) {
+		return nil, fmt.Errorf("pact: request to %s blocked, as only the Mock Server and AllowedHosts may be called during the test", req.URL.Host)
+	}
+
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return base.RoundTrip(req)
+}
+
+// allowRequest returns true if there is no active guard, or it allows the
+// request. Otherwise the request is recorded as blocked.
+func allowRequest(req *http.Request) bool {
+	activeGuard.Lock()
+	defer activeGuard.Unlock()
+
+	if activeGuard.guard == nil || activeGuard.guard.allows(req) {
+		return true
+	}
+	activeGuard.guard.block(req.URL.Host)
+
+	return false
+}
+
+// install guards http.DefaultTransport until uninstall is called, waiting for
+// the guard of any other test to be uninstalled first
+func (g *requestGuard) install() {
+	guardedTests.Lock()
+
+	activeGuard.Lock()
+	defer activeGuard.Unlock()
+
+	g.installed = true
+	activeGuard.guard = g
+	activeGuard.original = http.DefaultTransport
+	http.DefaultTransport = &guardedTransport{base: activeGuard.original}
+}
+
+// uninstall removes the guard, restoring http.DefaultTransport. Does nothing
+// if the guard isn't installed, e.g. when deferred in case the test exits via
+// runtime.Goexit (t.FailNow)
+func (g *requestGuard) uninstall() {
+	if !g.installed {
+		return
+	}
+	g.installed = false
+
+	activeGuard.Lock()
+	http.DefaultTransport = activeGuard.original
+	activeGuard.guard = nil
+	activeGuard.original = nil
+	activeGuard.Unlock()
+
+	guardedTests.Unlock()
+}
+
+// allows returns true if the request is to a Mock Server or an allowed host
+func (g *requestGuard) allows(req *http.Request) bool {
+	host := req.URL.Hostname()
+	port := req.URL.Port()
+	if port == "" {
+		port = "80"
+		if req.URL.Scheme == "https" {
+			port = "443"
+		}
+	}
+
+	for _, allowed := range g.allowedHosts {
+		if strings.EqualFold(allowed, host) || strings.EqualFold(allowed, net.JoinHostPort(host, port)) {
+			return true
+		}
+	}
+
+	if !g.isLocal(host) {
+		return false
+	}
+	for _, p := range g.ports {
+		if strconv.Itoa(p) == port {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isLocal returns true if host is that of the Mock Server, however addressed
+func (g *requestGuard) isLocal(host string) bool {
+	if strings.EqualFold(host, strings.Trim(g.host, "[]")) || strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+
+	return ip != nil && (ip.IsLoopback() || ip.IsUnspecified())
+}
+
+func (g *requestGuard) block(host string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.blocked[host] = true
+}
+
+// err returns an error listing the blocked hosts, if any
+func (g *requestGuard) err() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if len(g.blocked) == 0 {
+		return nil
+	}
+
+	hosts := make([]string, 0, len(g.blocked))
+	for host := range g.blocked {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+
+	return fmt.Errorf("the test made requests to hosts other than the Mock Server: %s. Check the base URL of your API client, or add the hosts to AllowedHosts", strings.Join(hosts, ", "))
+}
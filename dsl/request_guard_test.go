@@ -0,0 +1,125 @@
+package dsl
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+func TestRequestGuard_BlockExternalRequests(t *testing.T) {
+	ms := setupMockServer(true, t)
+	defer ms.Close()
+	external := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer external.Close()
+
+	transport := http.DefaultTransport
+	pact := &Pact{
+		Server:                &types.MockServer{Port: getPort(ms.URL)},
+		BlockExternalRequests: true,
+	}
+	pact.AddInteraction().UponReceiving("Some name for the test").WithRequest(Request{}).WillRespondWith(Response{})
+
+	var requestErr error
+	err := pact.Verify(func() error {
+		if _, err := http.Get(fmt.Sprintf("http://localhost:%d/", pact.Server.Port)); err != nil {
+			return err
+		}
+		_, requestErr = http.Get(external.URL)
+		return nil
+	})
+
+	if requestErr == nil || !strings.Contains(requestErr.Error(), "blocked") {
+		t.Fatalf("expected the external request to be blocked, got '%v'", requestErr)
+	}
+	if !errors.Is(err, types.ErrMismatch) || !strings.Contains(err.Error(), strings.TrimPrefix(external.URL, "http://")) {
+		t.Fatalf("expected the test to fail naming the external host, got '%v'", err)
+	}
+	if http.DefaultTransport != transport {
+		t.Fatal("expected http.DefaultTransport to be restored")
+	}
+}
+
+func TestRequestGuard_AllowedHosts(t *testing.T) {
+	ms := setupMockServer(true, t)
+	defer ms.Close()
+	external := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer external.Close()
+
+	pact := &Pact{
+		Server:                &types.MockServer{Port: getPort(ms.URL)},
+		BlockExternalRequests: true,
+		AllowedHosts:          []string{strings.TrimPrefix(external.URL, "http://")},
+	}
+	pact.AddInteraction().UponReceiving("Some name for the test").WithRequest(Request{}).WillRespondWith(Response{})
+
+	err := pact.Verify(func() error {
+		_, err := http.Get(external.URL)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("expected the allowed host to be called: %v", err)
+	}
+}
+
+func TestRequestGuard_GuardTransport(t *testing.T) {
+	g := &requestGuard{host: "localhost", ports: []int{1234}, blocked: make(map[string]bool)}
+	g.install()
+	defer g.uninstall()
+
+	client := &http.Client{Transport: GuardTransport(&http.Transport{})}
+	if _, err := client.Get("http://example.com/"); err == nil || !strings.Contains(err.Error(), "blocked") {
+		t.Fatalf("expected the request to be blocked, got '%v'", err)
+	}
+	if err := g.err(); err == nil || !strings.Contains(err.Error(), "example.com") {
+		t.Fatalf("expected example.com to be recorded as blocked, got '%v'", err)
+	}
+
+	for _, u := range []string{"http://localhost:1234/", "http://127.0.0.1:1234/", "http://[::1]:1234/"} {
+		req, _ := http.NewRequest("GET", u, nil)
+		if !g.allows(req) {
+			t.Fatalf("expected a request to %s to be allowed", u)
+		}
+	}
+}
+
+func TestRequestGuard_Serialised(t *testing.T) {
+	first := &requestGuard{host: "localhost", blocked: make(map[string]bool)}
+	second := &requestGuard{host: "localhost", blocked: make(map[string]bool)}
+	transport := http.DefaultTransport
+
+	first.install()
+	installed := make(chan struct{})
+	go func() {
+		second.install()
+		close(installed)
+	}()
+
+	select {
+	case <-installed:
+		t.Fatal("expected the second guard to wait for the first to be uninstalled")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	first.uninstall()
+	<-installed
+
+	req, _ := http.NewRequest("GET", "http://example.com/", nil)
+	if allowRequest(req) {
+		t.Fatal("expected the second guard to block the request")
+	}
+	if first.err() != nil || second.err() == nil {
+		t.Fatal("expected the request to be recorded as blocked by the second guard only")
+	}
+
+	second.uninstall()
+	second.uninstall()
+	if http.DefaultTransport != transport {
+		t.Fatal("expected http.DefaultTransport to be restored")
+	}
+}
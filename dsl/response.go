@@ -5,4 +5,7 @@ type Response struct {
 	Status  int         `json:"status"`
 	Headers MapMatcher  `json:"headers,omitempty"`
 	Body    interface{} `json:"body,omitempty"`
+
+	// err records any failure to load the body e.g. from a fixture file
+	err error
 }
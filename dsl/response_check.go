@@ -0,0 +1,297 @@
+package dsl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ray-xu-deltatre/pact-go/proxy"
+)
+
+// checkedRequest is an interaction request in a pact file, with the fields of
+// its response body that pact-go checks itself during verification, as the
+// verifier cannot
+type checkedRequest struct {
+	Method string
+	Path   string
+
+	// OptionalFields of the response body, see Optional
+	OptionalFields map[string]interface{}
+
+	// JSONStrings in the response body, see JSONString
+	JSONStrings map[string]interface{}
+}
+
+// loadCheckedRequests returns the requests whose responses have fields to
+// check in the given pact files. Pacts fetched from a remote URL are skipped.
+func loadCheckedRequests(pactURLs []string) []checkedRequest {
+	var requests []checkedRequest
+
+	for _, pactURL := range pactURLs {
+		if strings.HasPrefix(pactURL, "http://") || strings.HasPrefix(pactURL, "https://") {
+			continue
+		}
+
+		content, err := ioutil.ReadFile(pactURL)
+		if err != nil {
+			continue
+		}
+
+		var pact struct {
+			Interactions []struct {
+				Request struct {
+					Method string `json:"method"`
+					Path   string `json:"path"`
+				} `json:"request"`
+				OptionalFields map[string]interface{} `json:"optionalFields"`
+				JSONStrings    struct {
+					Response map[string]interface{} `json:"response"`
+				} `json:"jsonStrings"`
+			} `json:"interactions"`
+		}
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.UseNumber()
+		if err = decoder.Decode(&pact); err != nil {
+			log.Printf("[WARN] unable to read the fields to check from pact '%s': %v", pactURL, err)
+			continue
+		}
+
+		for _, i := range pact.Interactions {
+			if len(i.OptionalFields) > 0 || len(i.JSONStrings.Response) > 0 {
+				requests = append(requests, checkedRequest{
+					Method:         i.Request.Method,
+					Path:           i.Request.Path,
+					OptionalFields: i.OptionalFields,
+					JSONStrings:    i.JSONStrings.Response,
+				})
+			}
+		}
+	}
+
+	return requests
+}
+
+// responseMismatches collects the checked fields of responses of the provider
+// that didn't match
+type responseMismatches struct {
+	mu         sync.Mutex
+	mismatches []string
+}
+
+func (m *responseMismatches) add(mismatch string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.mismatches = append(m.mismatches, mismatch)
+}
+
+func (m *responseMismatches) err() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.mismatches) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("responses of the provider did not match:\n\n%s", strings.Join(m.mismatches, "\n"))
+}
+
+// responseRecorder keeps a copy of the body of a response as it is written
+type responseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+
+	return r.ResponseWriter.Write(b)
+}
+
+// responseCheckMiddleware checks the fields of the JSON body of responses of
+// the provider, as per the response of the request in the pact with the same
+// method and path, adding any that don't match to mismatches
+func responseCheckMiddleware(requests []checkedRequest, mismatches *responseMismatches) proxy.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == providerStatesSetupPath {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			for _, request := range requests {
+				if !strings.EqualFold(request.Method, r.Method) || request.Path != r.URL.Path {
+					continue
+				}
+
+				recorder := &responseRecorder{ResponseWriter: w}
+				next.ServeHTTP(recorder, r)
+
+				body := recorder.body.Bytes()
+				for _, mismatch := range append(checkBodyFields(body, request.OptionalFields), checkBodyFields(body, request.JSONStrings)...) {
+					mismatches.add(fmt.Sprintf("%s %s: %s", r.Method, r.URL.Path, mismatch))
+				}
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// checkBodyFields returns a mismatch for each of the fields, by their path
+// e.g. "$.body.nickname", present in the JSON body that doesn't match
+func checkBodyFields(body []byte, fields map[string]interface{}) []string {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var content interface{}
+	if err := decoder.Decode(&content); err != nil {
+		return nil
+	}
+
+	paths := make([]string, 0, len(fields))
+	for path := range fields {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var mismatches []string
+	for _, path := range paths {
+		tokens, err := parseJSONPath("$" + strings.TrimPrefix(path, "$.body"))
+		if err != nil {
+			log.Printf("[WARN] unable to check the field at '%s': %v", path, err)
+			continue
+		}
+
+		for _, value := range presentValues(content, tokens) {
+			if err = matchSerialised(fields[path], value, path, false); err != nil {
+				mismatches = append(mismatches, err.Error())
+			}
+		}
+	}
+
+	return mismatches
+}
+
+// presentValues returns the values at the path given by tokens, ignoring any
+// that don't exist
+func presentValues(content interface{}, tokens []string) []interface{} {
+	if len(tokens) == 0 {
+		return []interface{}{content}
+	}
+
+	var values []interface{}
+	switch c := content.(type) {
+	case map[string]interface{}:
+		if v, ok := c[strings.TrimPrefix(tokens[0], ".")]; ok && strings.HasPrefix(tokens[0], ".") {
+			values = presentValues(v, tokens[1:])
+		}
+	case []interface{}:
+		for _, item := range c {
+			if tokens[0] == "[*]" {
+				values = append(values, presentValues(item, tokens[1:])...)
+			}
+		}
+	}
+
+	return values
+}
+
+// matchSerialised checks the actual value at path against the serialised
+// expected one, which may contain matchers. Plain values are matched by type
+// within a Like or EachLike.
+func matchSerialised(expected interface{}, actual interface{}, path string, byType bool) error {
+	switch e := expected.(type) {
+	case map[string]interface{}:
+		switch e["json_class"] {
+		case "Pact::SomethingLike":
+			return matchSerialised(e["contents"], actual, path, true)
+		case "Pact::ArrayLike":
+			items, ok := actual.([]interface{})
+			if !ok {
+				return fmt.Errorf("%s: expected an array but got %v", path, actual)
+			}
+			if min, ok := e["min"].(json.Number); ok {
+				if n, _ := min.Int64(); int64(len(items)) < n {
+					return fmt.Errorf("%s: expected at least %d items but got %d", path, n, len(items))
+				}
+			}
+			for n, item := range items {
+				if err := matchSerialised(e["contents"], item, fmt.Sprintf("%s[%d]", path, n), true); err != nil {
+					return err
+				}
+			}
+			return nil
+		case "Pact::Term":
+			data, _ := e["data"].(map[string]interface{})
+			matcher, _ := data["matcher"].(map[string]interface{})
+			regex, _ := matcher["s"].(string)
+			r, err := regexp.Compile(regex)
+			if err != nil {
+				return nil
+			}
+			if s, ok := actual.(string); !ok || !r.MatchString(s) {
+				return fmt.Errorf("%s: expected a string matching '%s' but got %v", path, regex, actual)
+			}
+			return nil
+		case optionalClass:
+			return matchSerialised(e["contents"], actual, path, byType)
+		case jsonStringClass:
+			return matchJSONString(e["contents"], actual, path)
+		}
+
+		object, ok := actual.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected an object but got %v", path, actual)
+		}
+		for k, field := range e {
+			value, ok := object[k]
+			if isOptional(field) && !ok {
+				continue
+			}
+			if !ok {
+				return fmt.Errorf("%s: expected a value but it was missing", jsonPathField(path, k))
+			}
+			if err := matchSerialised(field, value, jsonPathField(path, k), byType); err != nil {
+				return err
+			}
+		}
+		return nil
+	case []interface{}:
+		items, ok := actual.([]interface{})
+		if !ok || len(items) != len(e) {
+			return fmt.Errorf("%s: expected an array of %d items but got %v", path, len(e), actual)
+		}
+		for n, item := range e {
+			if err := matchSerialised(item, items[n], fmt.Sprintf("%s[%d]", path, n), byType); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if byType {
+		if reflect.TypeOf(expected) != reflect.TypeOf(actual) {
+			return fmt.Errorf("%s: expected a value like %v but got %v", path, expected, actual)
+		}
+		return nil
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		return fmt.Errorf("%s: expected %v but got %v", path, expected, actual)
+	}
+
+	return nil
+}
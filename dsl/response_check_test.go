@@ -0,0 +1,49 @@
+package dsl
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResponseCheck_Middleware(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "pact-response-check")
+	defer os.RemoveAll(dir)
+
+	pactFile := filepath.Join(dir, "pact.json")
+	ioutil.WriteFile(pactFile, []byte(`{"interactions":[
+		{"description":"a","request":{"method":"GET","path":"/users/1"},"optionalFields":{"$.body.nickname":{"json_class":"Pact::SomethingLike","contents":"bobby"}}},
+		{"description":"b","request":{"method":"GET","path":"/users"}}
+	]}`), 0644)
+
+	requests := loadCheckedRequests([]string{pactFile, "http://localhost/pact.json"})
+	if len(requests) != 1 {
+		t.Fatalf("expected one request with fields to check but got %d", len(requests))
+	}
+
+	mismatches := &responseMismatches{}
+	var body string
+	handler := responseCheckMiddleware(requests, mismatches)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+
+	body = `{"nickname":"b"}`
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/users/1", nil))
+	if err := mismatches.err(); err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	body = `{"nickname":false}`
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/users/1", nil))
+	if recorder.Body.String() != body {
+		t.Fatalf("expected the response to be passed on but got '%s'", recorder.Body.String())
+	}
+	if err := mismatches.err(); err == nil || !strings.Contains(err.Error(), "GET /users/1: $.body.nickname") {
+		t.Fatalf("expected a mismatch of the optional field but got '%v'", err)
+	}
+}
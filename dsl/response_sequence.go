@@ -0,0 +1,186 @@
+package dsl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// sequenceCallHeader is added to the requests of the interactions in a
+// response sequence by the recording proxy, so that the Mock Service can tell
+// the calls apart. It is removed from the pact file.
+const sequenceCallHeader = "X-Pact-Go-Call"
+
+// sequenceResponse is a response given after the first in a sequence
+type sequenceResponse struct {
+	state    string
+	response Response
+}
+
+// ThenRespondWith adds a response to the sequence of responses to the request
+// of the interaction, e.g. for a client polling for a report:
+//
+//	pact.AddInteraction().
+//		Given("A report is being generated").
+//		UponReceiving("A request for the report").
+//		WithRequest(dsl.Request{Method: "GET", Path: dsl.String("/report")}).
+//		WillRespondWith(dsl.Response{Status: 202}).
+//		ThenRespondWith("The report has been generated", dsl.Response{Status: 200})
+//
+// The first call receives the response given by WillRespondWith, the second
+// call the first response given by ThenRespondWith, and so on, with the last
+// response repeated for any further calls. Each call must be made for the
+// interaction to be verified.
+//
+// Each response is written to the pact as an interaction of its own, described
+// by the description of the interaction and the number of the call e.g.
+// "A request for the report (call 2)", and verified against the provider in
+// the given state.
+//
+// Calls are counted by the recording proxy, by method and path, so sequences
+// are not supported over TLS.
+func (i *Interaction) ThenRespondWith(state string, response Response) *Interaction {
+	i.responses = append(i.responses, sequenceResponse{
+		state:    state,
+		response: response,
+	})
+
+	return i
+}
+
+// validateSequence checks that a response sequence is over a supported
+// transport
+func (i *Interaction) validateSequence() error {
+	if i.call > 0 && i.Transport() == TransportHTTPS {
+		return fmt.Errorf("response sequences are not supported over TLS")
+	}
+
+	return nil
+}
+
+// expandSequences replaces each interaction with a response sequence by an
+// interaction for each call, whose request has the sequenceCallHeader
+func expandSequences(interactions []*Interaction) []*Interaction {
+	expanded := make([]*Interaction, 0, len(interactions))
+
+	for _, i := range interactions {
+		if len(i.responses) == 0 {
+			expanded = append(expanded, i)
+			continue
+		}
+
+		steps := append([]sequenceResponse{{state: i.State, response: i.Response}}, i.responses...)
+		for n, step := range steps {
+			call := *i
+			call.Description = fmt.Sprintf("%s (call %d)", i.Description, n+1)
+			call.State = step.state
+			call.Response = step.response
+			call.responses = nil
+			call.call = n + 1
+
+			call.Request.Headers = MapMatcher{}
+			for k, v := range i.Request.Headers {
+				call.Request.Headers[k] = v
+			}
+			call.Request.Headers[sequenceCallHeader] = String(strconv.Itoa(n + 1))
+
+			expanded = append(expanded, &call)
+		}
+	}
+
+	return expanded
+}
+
+// responseSequence counts the calls to the request of an interaction with a
+// response sequence
+type responseSequence struct {
+	method string
+	path   Matcher
+	steps  int
+	calls  int
+}
+
+// setupSequences ensures that the calls to HTTP interactions with response
+// sequences are counted by the recording proxy, which is started on demand
+func (p *Pact) setupSequences(interactions []*Interaction) {
+	var sequences []*responseSequence
+	for _, i := range interactions {
+		if len(i.responses) > 0 && i.Transport() == TransportHTTP {
+			sequences = append(sequences, &responseSequence{
+				method: i.Request.Method,
+				path:   i.Request.Path,
+				steps:  len(i.responses) + 1,
+			})
+		}
+	}
+
+	if len(sequences) > 0 {
+		p.startProxy()
+	}
+	if p.proxy != nil {
+		p.proxy.SetSequences(sequences)
+	}
+}
+
+// nextCall returns the number of the call to a response sequence matching the
+// request, capped at the number of responses, or 0 if there is none
+func (p *mockServerProxy) nextCall(r *http.Request) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, s := range p.sequences {
+		if strings.EqualFold(s.method, r.Method) && pathMatches(s.path, r.URL.Path) {
+			if s.calls < s.steps {
+				s.calls++
+			}
+			return s.calls
+		}
+	}
+
+	return 0
+}
+
+// SetSequences sets the response sequences whose calls are counted
+func (p *mockServerProxy) SetSequences(sequences []*responseSequence) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.sequences = sequences
+}
+
+// withoutSequenceCallHeader removes the sequenceCallHeader from the request of
+// a compact interaction, along with the request headers if it was the only one
+func withoutSequenceCallHeader(raw json.RawMessage) json.RawMessage {
+	var interaction struct {
+		Request struct {
+			Headers map[string]json.RawMessage `json:"headers"`
+		} `json:"request"`
+	}
+	if err := json.Unmarshal(raw, &interaction); err != nil {
+		return raw
+	}
+
+	value, ok := interaction.Request.Headers[sequenceCallHeader]
+	if !ok {
+		return raw
+	}
+
+	key, _ := json.Marshal(sequenceCallHeader)
+	header := append(append(key, ':'), value...)
+	if len(interaction.Request.Headers) == 1 {
+		headers := append(append([]byte(`"headers":{`), header...), '}')
+		if rewritten := bytes.Replace(raw, append([]byte(","), headers...), nil, 1); !bytes.Equal(rewritten, raw) {
+			return rewritten
+		}
+		return bytes.Replace(raw, headers, []byte(`"headers":{}`), 1)
+	}
+
+	if rewritten := bytes.Replace(raw, append([]byte(","), header...), nil, 1); !bytes.Equal(rewritten, raw) {
+		return rewritten
+	}
+
+	return bytes.Replace(raw, append(header, ','), nil, 1)
+}
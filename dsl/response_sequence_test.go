@@ -0,0 +1,95 @@
+package dsl
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResponseSequence_expandSequences(t *testing.T) {
+	i := (&Interaction{}).
+		Given("A report is being generated").
+		UponReceiving("A request for the report").
+		WithRequest(Request{
+			Method:  "GET",
+			Path:    String("/report"),
+			Headers: MapMatcher{"Accept": String("application/json")},
+		}).
+		WillRespondWith(Response{Status: 202}).
+		ThenRespondWith("The report has been generated", Response{Status: 200})
+	other := (&Interaction{}).UponReceiving("A request for the user")
+
+	expanded := expandSequences([]*Interaction{i, other})
+	if len(expanded) != 3 || expanded[2] != other {
+		t.Fatalf("expected an interaction for each call, and the rest unchanged, but got %d", len(expanded))
+	}
+
+	for n, want := range []struct {
+		description string
+		state       string
+		status      int
+	}{
+		{"A request for the report (call 1)", "A report is being generated", 202},
+		{"A request for the report (call 2)", "The report has been generated", 200},
+	} {
+		call := expanded[n]
+		if call.Description != want.description || call.State != want.state || call.Response.Status != want.status {
+			t.Fatalf("expected %v but got '%s', '%s' and %d", want, call.Description, call.State, call.Response.Status)
+		}
+		if call.Request.Headers[sequenceCallHeader] != String(fmt.Sprint(n+1)) || call.Request.Headers["Accept"] == nil {
+			t.Fatalf("expected the call header to be added to the request headers: %v", call.Request.Headers)
+		}
+	}
+	if len(i.Request.Headers) != 1 {
+		t.Fatal("expected the headers of the interaction to be unchanged")
+	}
+
+	if err := expanded[0].WithTransport(TransportHTTPS).validate(); err == nil {
+		t.Fatal("expected response sequences over TLS to be rejected")
+	}
+}
+
+func TestResponseSequence_Proxy(t *testing.T) {
+	var calls []string
+	ms := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Header.Get(sequenceCallHeader))
+	}))
+	defer ms.Close()
+
+	proxy, err := startMockServerProxy("tcp", "localhost", 0, ms.URL, mockServerTimeouts{})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	defer proxy.Stop()
+
+	proxy.SetSequences([]*responseSequence{{method: "GET", path: String("/report"), steps: 2}})
+
+	url := fmt.Sprintf("http://localhost:%d", proxy.Port)
+	for _, path := range []string{"/report", "/user", "/report", "/report"} {
+		if _, err = http.Get(url + path); err != nil {
+			t.Fatal("Error:", err)
+		}
+	}
+
+	if fmt.Sprint(calls) != "[1  2 2]" {
+		t.Fatalf("expected the calls to the sequence to be numbered, repeating the last, but got %q", calls)
+	}
+	if proxy.Requests()[0].Header.Get(sequenceCallHeader) != "" {
+		t.Fatal("expected the call header not to be recorded")
+	}
+}
+
+func TestResponseSequence_withoutSequenceCallHeader(t *testing.T) {
+	for raw, want := range map[string]string{
+		`{"request":{"method":"GET","headers":{"Accept":"*/*","X-Pact-Go-Call":"2"}}}`: `{"request":{"method":"GET","headers":{"Accept":"*/*"}}}`,
+		`{"request":{"method":"GET","headers":{"X-Pact-Go-Call":"2","Accept":"*/*"}}}`: `{"request":{"method":"GET","headers":{"Accept":"*/*"}}}`,
+		`{"request":{"method":"GET","headers":{"X-Pact-Go-Call":"1"}}}`:                `{"request":{"method":"GET"}}`,
+		`{"request":{"method":"GET"}}`:                                                 `{"request":{"method":"GET"}}`,
+	} {
+		if got := withoutSequenceCallHeader(json.RawMessage(raw)); string(got) != want {
+			t.Fatalf("expected '%s' but got '%s'", want, got)
+		}
+	}
+}
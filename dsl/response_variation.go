@@ -0,0 +1,319 @@
+package dsl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	mathrand "math/rand"
+	"net/http"
+	"regexp"
+	"regexp/syntax"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+// variationAlphabet is the characters of generated strings that are only
+// matched by type
+const variationAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789 -_"
+
+// responseVariation replaces the JSON bodies of the responses to the
+// interactions with random values that still match their matchers
+type responseVariation struct {
+	interactions []*Interaction
+	random       *mathrand.Rand
+
+	// examples are kept rather than varied, e.g. those of MockServerURL
+	examples map[string]bool
+}
+
+// verifyWithVariations verifies the interactions as usual, then runs the test
+// again ResponseVariations times against varied responses to the interactions
+// over HTTP
+func (p *Pact) verifyWithVariations(integrationTest func(servers map[string]*types.MockServer) error, selectors ...InteractionSelector) error {
+	selected, _ := selectInteractions(p.snapshotInteractions(), selectors)
+
+	if err := p.verifyWithTransports(integrationTest, selectors...); err != nil {
+		return err
+	}
+	if p.proxy == nil {
+		log.Println("[WARN] unable to vary responses without a proxy in front of the Mock Service")
+		return nil
+	}
+	defer p.proxy.SetVariation(nil)
+
+	seed, ok := p.seed()
+	if !ok {
+		seed = randomSeed()
+	}
+
+	var varied []*Interaction
+	for _, i := range expandSequences(selected) {
+		if i.Transport() != TransportHTTP {
+			continue
+		}
+		expected, err := i.withMatchingModes()
+		if err != nil {
+			return types.NewError(types.ErrInvalidRequest, err)
+		}
+		varied = append(varied, expected)
+	}
+
+	for n := 1; n <= p.ResponseVariations; n++ {
+		p.addInteractions(selected...)
+		p.proxy.SetVariation(&responseVariation{
+			interactions: varied,
+			random:       mathrand.New(mathrand.NewSource(seed + int64(n))),
+			examples:     p.proxy.generatorExamples(),
+		})
+
+		if err := p.verifyWithTransports(integrationTest, selectSame(selected)); err != nil {
+			return fmt.Errorf("%w\n\nThe test failed with response variation %d of %d. Set PACT_SEED=%d to vary the responses the same way again", err, n, p.ResponseVariations, seed)
+		}
+	}
+
+	return nil
+}
+
+// SetVariation sets the variation of the responses of the Mock Service, or
+// none if nil
+func (p *mockServerProxy) SetVariation(variation *responseVariation) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.variation = variation
+}
+
+// generatorExamples returns the example URLs replaced in responses
+func (p *mockServerProxy) generatorExamples() map[string]bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	examples := make(map[string]bool, len(p.generators))
+	for _, g := range p.generators {
+		examples[g.example] = true
+	}
+
+	return examples
+}
+
+// varyResponse replaces the JSON body of the response to the request with a
+// variation of the body of the interaction it matched, if any
+func (p *mockServerProxy) varyResponse(rec *recordedRequest, res *http.Response) error {
+	if p.variation == nil || rec.Passthrough || !isJSONContentType(res.Header.Get("Content-Type")) {
+		return nil
+	}
+
+	var matched *Interaction
+	for _, i := range p.variation.interactions {
+		if i.Response.Status != res.StatusCode || !requestMatches(i.Request, rec) {
+			continue
+		}
+		// The response can't be told apart from that of another interaction
+		if matched != nil {
+			return nil
+		}
+		matched = i
+	}
+	if matched == nil || matched.Response.Body == nil {
+		return nil
+	}
+	if _, ok := matched.Response.Body.(string); ok {
+		return nil
+	}
+
+	serialised, err := serialise(matched.Response.Body)
+	if err != nil {
+		return nil
+	}
+
+	var encoded bytes.Buffer
+	encoder := json.NewEncoder(&encoded)
+	encoder.SetEscapeHTML(false)
+	if err = encoder.Encode(p.variation.vary(serialised, false)); err != nil {
+		return err
+	}
+	body := bytes.TrimSpace(encoded.Bytes())
+
+	ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	res.Body = ioutil.NopCloser(bytes.NewReader(body))
+	res.ContentLength = int64(len(body))
+	if res.Header.Get("Content-Length") != "" {
+		res.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	}
+
+	return nil
+}
+
+// vary returns a random value matching the serialised body v. Values are
+// matched by type, rather than by equality, within a Like or EachLike.
+func (r *responseVariation) vary(v interface{}, byType bool) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		switch value["json_class"] {
+		case "Pact::SomethingLike":
+			return r.vary(value["contents"], true)
+		case "Pact::ArrayLike":
+			min, _ := strconv.Atoi(fmt.Sprint(value["min"]))
+			max, _ := strconv.Atoi(fmt.Sprint(value["max"]))
+			if max < min {
+				max = min + 3
+			}
+			items := make([]interface{}, min+r.random.Intn(max-min+1))
+			for i := range items {
+				items[i] = r.vary(value["contents"], true)
+			}
+			return items
+		case "Pact::Term":
+			return r.varyTerm(value)
+		case optionalClass:
+			return r.vary(value["contents"], byType)
+		case jsonStringClass:
+			return jsonStringExample(value["contents"])
+		}
+
+		// Fields are varied in order, so that a seed always varies them the same way
+		keys := make([]string, 0, len(value))
+		for k := range value {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		obj := make(map[string]interface{}, len(value))
+		for _, k := range keys {
+			// Optional fields are sometimes absent
+			if isOptional(value[k]) && r.random.Intn(2) == 0 {
+				continue
+			}
+			obj[k] = r.vary(value[k], byType)
+		}
+		return obj
+	case []interface{}:
+		items := make([]interface{}, len(value))
+		for i, item := range value {
+			items[i] = r.vary(item, byType)
+		}
+		return items
+	}
+
+	if !byType {
+		return v
+	}
+
+	switch value := v.(type) {
+	case string:
+		return r.randomString(variationAlphabet, r.random.Intn(17))
+	case bool:
+		return r.random.Intn(2) == 0
+	case json.Number:
+		if strings.ContainsAny(value.String(), ".eE") {
+			return json.Number(fmt.Sprintf("%d.%02d", r.random.Intn(2000001)-1000000, 1+r.random.Intn(99)))
+		}
+		return json.Number(strconv.Itoa(r.random.Intn(2000001) - 1000000))
+	}
+
+	return v
+}
+
+// varyTerm returns a random string matching the regex of the serialised Term,
+// or its example if one can't be generated
+func (r *responseVariation) varyTerm(term map[string]interface{}) interface{} {
+	data, _ := term["data"].(map[string]interface{})
+	matcher, _ := data["matcher"].(map[string]interface{})
+	example := data["generate"]
+	regex, ok := matcher["s"].(string)
+	if !ok {
+		return example
+	}
+	if s, isString := example.(string); isString && r.examples[s] {
+		return example
+	}
+
+	re, err := regexp.Compile(regex)
+	if err != nil {
+		return example
+	}
+	parsed, err := syntax.Parse(regex, syntax.Perl)
+	if err != nil {
+		return example
+	}
+
+	var b strings.Builder
+	r.generate(&b, parsed.Simplify())
+	if generated := b.String(); re.MatchString(generated) {
+		return generated
+	}
+
+	return example
+}
+
+// generate writes a random string matching the regex to b. Anchors and word
+// boundaries are ignored, so the result must be checked against the regex.
+func (r *responseVariation) generate(b *strings.Builder, re *syntax.Regexp) {
+	repeat := func(min int, max int) {
+		if max < min {
+			max = min + 3
+		}
+		for n := min + r.random.Intn(max-min+1); n > 0; n-- {
+			r.generate(b, re.Sub[0])
+		}
+	}
+
+	switch re.Op {
+	case syntax.OpLiteral:
+		b.WriteString(string(re.Rune))
+	case syntax.OpCharClass:
+		// Keep to printable ASCII where the class allows, e.g. for [^/]
+		var ranges [][2]rune
+		for i := 0; i+1 < len(re.Rune); i += 2 {
+			lo, hi := re.Rune[i], re.Rune[i+1]
+			if lo < 0x20 {
+				lo = 0x20
+			}
+			if hi > 0x7e {
+				hi = 0x7e
+			}
+			if lo <= hi {
+				ranges = append(ranges, [2]rune{lo, hi})
+			}
+		}
+		if len(ranges) == 0 {
+			return
+		}
+		pick := ranges[r.random.Intn(len(ranges))]
+		b.WriteRune(pick[0] + rune(r.random.Intn(int(pick[1]-pick[0])+1)))
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		b.WriteString(r.randomString(variationAlphabet, 1))
+	case syntax.OpCapture:
+		r.generate(b, re.Sub[0])
+	case syntax.OpStar:
+		repeat(0, 3)
+	case syntax.OpPlus:
+		repeat(1, 4)
+	case syntax.OpQuest:
+		repeat(0, 1)
+	case syntax.OpRepeat:
+		repeat(re.Min, re.Max)
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			r.generate(b, sub)
+		}
+	case syntax.OpAlternate:
+		r.generate(b, re.Sub[r.random.Intn(len(re.Sub))])
+	}
+}
+
+// randomString returns a random string of the given length from the alphabet
+func (r *responseVariation) randomString(alphabet string, length int) string {
+	s := make([]byte, length)
+	for i := range s {
+		s[i] = alphabet[r.random.Intn(len(alphabet))]
+	}
+
+	return string(s)
+}
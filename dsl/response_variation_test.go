@@ -0,0 +1,159 @@
+package dsl
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	mathrand "math/rand"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func newTestVariation(seed int64) *responseVariation {
+	return &responseVariation{random: mathrand.New(mathrand.NewSource(seed))}
+}
+
+func TestResponseVariation_vary(t *testing.T) {
+	body, err := serialise(map[string]interface{}{
+		"id":       Like(10),
+		"price":    Like(9.99),
+		"name":     Like("billy"),
+		"admin":    Like(true),
+		"status":   "active",
+		"email":    Term("billy@example.com", `^[a-z]+@example\.com$`),
+		"tags":     EachLikeBetween("admin", 1, 3),
+		"nickname": Optional(Like("bob")),
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	absent := false
+	for seed := int64(1); seed <= 50; seed++ {
+		varied := newTestVariation(seed).vary(body, false).(map[string]interface{})
+
+		if _, err := varied["id"].(json.Number).Int64(); err != nil {
+			t.Fatalf("expected an integer id but got %v", varied["id"])
+		}
+		if !regexp.MustCompile(`^-?\d+\.\d+$`).MatchString(varied["price"].(json.Number).String()) {
+			t.Fatalf("expected a decimal price but got %v", varied["price"])
+		}
+		if _, ok := varied["name"].(string); !ok {
+			t.Fatalf("expected a string name but got %v", varied["name"])
+		}
+		if _, ok := varied["admin"].(bool); !ok {
+			t.Fatalf("expected a boolean admin but got %v", varied["admin"])
+		}
+		if varied["status"] != "active" {
+			t.Fatalf("expected values without matchers to be kept but got %v", varied["status"])
+		}
+		if !regexp.MustCompile(`^[a-z]+@example\.com$`).MatchString(varied["email"].(string)) {
+			t.Fatalf("expected an email matching the term but got %v", varied["email"])
+		}
+		if tags := varied["tags"].([]interface{}); len(tags) < 1 || len(tags) > 3 {
+			t.Fatalf("expected 1 to 3 tags but got %v", tags)
+		}
+		if _, ok := varied["nickname"]; !ok {
+			absent = true
+		}
+	}
+	if !absent {
+		t.Fatal("expected the optional nickname to be absent in some variations")
+	}
+
+	if a, b := newTestVariation(7).vary(body, false), newTestVariation(7).vary(body, false); !reflect.DeepEqual(a, b) {
+		t.Fatalf("expected the same seed to vary the body the same way: %v and %v", a, b)
+	}
+}
+
+func TestResponseVariation_varyTerm(t *testing.T) {
+	r := newTestVariation(1)
+	r.examples = map[string]bool{"http://localhost:8080/orders/1": true}
+
+	for _, regex := range []string{
+		`^\d{4}-\d{2}-\d{2}$`,
+		`^(GET|POST|PUT)$`,
+		`^[A-F0-9]{8}(-[A-F0-9]{4}){3}-[A-F0-9]{12}$`,
+		`^[^/]+/v\d+$`,
+		`(?i)^ok|fine$`,
+	} {
+		for n := 0; n < 20; n++ {
+			generated := r.varyTerm(map[string]interface{}{"data": map[string]interface{}{"generate": "example", "matcher": map[string]interface{}{"s": regex}}})
+			if s, ok := generated.(string); !ok || (s != "example" && !regexp.MustCompile(regex).MatchString(s)) {
+				t.Fatalf("expected a value matching %s but got %v", regex, generated)
+			}
+		}
+	}
+
+	url := "http://localhost:8080/orders/1"
+	if generated := r.varyTerm(map[string]interface{}{"data": map[string]interface{}{"generate": url, "matcher": map[string]interface{}{"s": `.*(/orders/\d+)$`}}}); generated != url {
+		t.Fatalf("expected the example of a MockServerURL to be kept but got %v", generated)
+	}
+	if generated := r.varyTerm(map[string]interface{}{"data": map[string]interface{}{"generate": "a1", "matcher": map[string]interface{}{"s": `^(?=a)\w+$`}}}); generated != "a1" {
+		t.Fatalf("expected the example of an unsupported regex to be kept but got %v", generated)
+	}
+}
+
+func TestResponseVariation_varyResponse(t *testing.T) {
+	ms := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":10,"name":"billy"}`)
+	}))
+	defer ms.Close()
+
+	proxy, err := startMockServerProxy("tcp", "localhost", 0, ms.URL, mockServerTimeouts{})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	defer proxy.Stop()
+
+	interaction := (&Interaction{}).
+		UponReceiving("A request for billy").
+		WithRequest(Request{Method: "GET", Path: String("/users/10")}).
+		WillRespondWith(Response{Status: 200, Body: map[string]interface{}{"id": 10, "name": Like("billy")}})
+	proxy.SetVariation(&responseVariation{
+		interactions: []*Interaction{interaction},
+		random:       mathrand.New(mathrand.NewSource(3)),
+	})
+
+	var names []string
+	for n := 0; n < 5; n++ {
+		res, err := http.Get(fmt.Sprintf("http://localhost:%d/users/10", proxy.Port))
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		content, _ := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+
+		var body struct {
+			ID   int    `json:"id"`
+			Name string `json:"name"`
+		}
+		if err = json.Unmarshal(content, &body); err != nil || body.ID != 10 {
+			t.Fatalf("expected the id to be kept: %s", content)
+		}
+		names = append(names, body.Name)
+	}
+
+	varied := false
+	for _, name := range names {
+		varied = varied || name != "billy"
+	}
+	if !varied {
+		t.Fatalf("expected the name to be varied: %v", names)
+	}
+
+	proxy.SetVariation(nil)
+	res, err := http.Get(fmt.Sprintf("http://localhost:%d/users/10", proxy.Port))
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	content, _ := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if string(content) != `{"id":10,"name":"billy"}` {
+		t.Fatalf("expected the example without a variation but got %s", content)
+	}
+}
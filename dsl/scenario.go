@@ -0,0 +1,217 @@
+package dsl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Scenario is the consumer and provider of the pact file of a scenario, see
+// Pact.Scenarios
+type Scenario struct {
+	// Consumer name of the pact. Optional, the Consumer of the Pact by default
+	Consumer string
+
+	// Provider name of the pact. Optional, the Provider of the Pact by default
+	Provider string
+}
+
+// InScenario groups the interaction into the named scenario. Interactions in a
+// scenario are written to a pact file of their own, e.g.
+// "myconsumer-myprovider-checkout.json" for the scenario "checkout", rather
+// than the pact between the consumer and provider. This allows different
+// slices of the contract to be published separately: name the consumer or
+// provider of each with Pact.Scenarios so they don't collide once published.
+// If all of the interactions are in scenarios, no pact is written between the
+// consumer and provider.
+func (i *Interaction) InScenario(name string) *Interaction {
+	i.scenario = name
+
+	return i
+}
+
+// scenarioFileName returns the name of the pact file for the scenario, given
+// the name of the pact file between the consumer and provider
+func scenarioFileName(fileName string, scenario string) string {
+	ext := filepath.Ext(fileName)
+
+	return fmt.Sprintf("%s-%s%s", strings.TrimSuffix(fileName, ext), unsafeBranchChars.ReplaceAllString(filenamify(scenario), "_"), ext)
+}
+
+// recordScenario remembers the scenario of an interaction, so that it can be
+// written to the pact file for the scenario
+func (p *Pact) recordScenario(key string, name string) {
+	if name == "" {
+		return
+	}
+
+	if p.scenarios == nil {
+		p.scenarios = make(map[string]string)
+	}
+	p.scenarios[key] = name
+}
+
+// writeScenarios moves the interactions in a scenario from the pact at path to
+// the pact file for the scenario. In "merge" mode, the interactions are merged
+// with those in any existing pact file for the scenario.
+func (p *Pact) writeScenarios(path string) error {
+	if len(p.scenarios) == 0 {
+		return nil
+	}
+
+	original, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var compact bytes.Buffer
+	if err = json.Compact(&compact, original); err != nil {
+		return fmt.Errorf("unable to read pact file '%s': %v", path, err)
+	}
+	doc := compact.Bytes()
+
+	var pact map[string]json.RawMessage
+	if err = json.Unmarshal(doc, &pact); err != nil {
+		return fmt.Errorf("unable to read pact file '%s': %v", path, err)
+	}
+	var interactions []json.RawMessage
+	if err = json.Unmarshal(pact["interactions"], &interactions); err != nil {
+		return fmt.Errorf("unable to read the interactions in pact file '%s': %v", path, err)
+	}
+
+	var rest []json.RawMessage
+	scenarios := make(map[string][]json.RawMessage)
+	for _, interaction := range interactions {
+		if scenario, ok := p.scenarios[rawInteractionKey(interaction)]; ok {
+			scenarios[scenario] = append(scenarios[scenario], interaction)
+		} else {
+			rest = append(rest, interaction)
+		}
+	}
+	if len(scenarios) == 0 {
+		return nil
+	}
+
+	for scenario, interactions := range scenarios {
+		scenarioPath := filepath.Join(filepath.Dir(path), scenarioFileName(filepath.Base(path), scenario))
+
+		if p.PactFileWriteMode == "merge" {
+			if interactions, err = mergeScenario(scenarioPath, interactions); err != nil {
+				return err
+			}
+		}
+
+		scenarioDoc, err := p.scenarioPacticipants(doc, pact, scenario)
+		if err != nil {
+			return err
+		}
+
+		if err = writePactWithInteractions(scenarioPath, scenarioDoc, pact["interactions"], interactions, original); err != nil {
+			return err
+		}
+	}
+
+	// A pact without interactions would only clutter the broker once published
+	if len(rest) == 0 {
+		return os.Remove(path)
+	}
+
+	return writePactWithInteractions(path, doc, pact["interactions"], rest, original)
+}
+
+// scenarioPacticipants returns the compact pact doc with the consumer and
+// provider names replaced by those configured for the scenario, if any
+func (p *Pact) scenarioPacticipants(doc []byte, pact map[string]json.RawMessage, scenario string) ([]byte, error) {
+	names := p.Scenarios[scenario]
+
+	for key, name := range map[string]string{"consumer": names.Consumer, "provider": names.Provider} {
+		raw, ok := pact[key]
+		if name == "" || !ok {
+			continue
+		}
+
+		var pacticipant map[string]interface{}
+		if err := json.Unmarshal(raw, &pacticipant); err != nil {
+			return nil, fmt.Errorf("unable to read the %s of the pact: %v", key, err)
+		}
+		pacticipant["name"] = name
+		renamed, err := json.Marshal(pacticipant)
+		if err != nil {
+			return nil, err
+		}
+
+		// The consumer and provider come before the interactions in the pact
+		field := append([]byte(`"`+key+`":`), raw...)
+		doc = bytes.Replace(doc, field, append([]byte(`"`+key+`":`), renamed...), 1)
+	}
+
+	return doc, nil
+}
+
+// mergeScenario adds the interactions in any existing pact file for the
+// scenario to the given interactions, replacing those with the same key
+func mergeScenario(path string, interactions []json.RawMessage) ([]json.RawMessage, error) {
+	existing, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return interactions, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var pact struct {
+		Interactions []json.RawMessage `json:"interactions"`
+	}
+	if err = json.Unmarshal(existing, &pact); err != nil {
+		return nil, fmt.Errorf("unable to merge with pact file '%s': %v", path, err)
+	}
+
+	keys := make(map[string]bool, len(interactions))
+	for _, interaction := range interactions {
+		keys[rawInteractionKey(interaction)] = true
+	}
+	for _, interaction := range pact.Interactions {
+		if !keys[rawInteractionKey(interaction)] {
+			var compact bytes.Buffer
+			if err = json.Compact(&compact, interaction); err != nil {
+				return nil, err
+			}
+			interactions = append(interactions, compact.Bytes())
+		}
+	}
+
+	sort.SliceStable(interactions, func(a, b int) bool {
+		return rawInteractionKey(interactions[a]) < rawInteractionKey(interactions[b])
+	})
+
+	return interactions, nil
+}
+
+// writePactWithInteractions writes the compact pact doc to path, with its
+// interactions (raw) replaced, formatted as per the original pact file
+func writePactWithInteractions(path string, doc []byte, raw json.RawMessage, interactions []json.RawMessage, original []byte) error {
+	items := make([][]byte, len(interactions))
+	for i, interaction := range interactions {
+		items[i] = interaction
+	}
+	joined := append(append([]byte("["), bytes.Join(items, []byte(","))...), ']')
+
+	// The document is compact, so the array appears verbatim and only once
+	var out bytes.Buffer
+	if err := json.Indent(&out, bytes.Replace(doc, raw, joined, 1), "", "  "); err != nil {
+		return err
+	}
+	if bytes.HasSuffix(original, []byte("\n")) {
+		out.WriteString("\n")
+	}
+
+	return writeFileAtomic(path, out.Bytes(), 0644)
+}
@@ -0,0 +1,96 @@
+package dsl
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestScenario_FileName(t *testing.T) {
+	if got := scenarioFileName("consumer-provider.json", "Checkout/Refunds"); got != "consumer-provider-checkout_refunds.json" {
+		t.Fatalf("expected the scenario in the file name but got '%s'", got)
+	}
+}
+
+func TestScenario_writePactFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pact-go-scenario")
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	defer os.RemoveAll(dir)
+
+	pact := &Pact{
+		Consumer:          "consumer",
+		Provider:          "provider",
+		PactDir:           dir,
+		PactFileWriteMode: "merge",
+		Scenarios:         map[string]Scenario{"checkout": {Provider: "payments"}},
+	}
+	for _, i := range []*Interaction{
+		(&Interaction{}).UponReceiving("login"),
+		(&Interaction{}).UponReceiving("checkout").InScenario("checkout"),
+		(&Interaction{}).UponReceiving("refund").Given("an order").InScenario("checkout"),
+	} {
+		pact.recordScenario(interactionKey(i.Description, i.State), i.scenario)
+	}
+
+	write := func(interactions string) func() error {
+		return func() error {
+			return ioutil.WriteFile(filepath.Join(dir, "consumer-provider.json"), []byte(`{"consumer":{"name":"consumer"},"provider":{"name":"provider"},"interactions":`+interactions+`,"metadata":{"pactSpecification":{"version":"2.0.0"}}}`), 0644)
+		}
+	}
+
+	descriptions := func(file string, provider string) []string {
+		content, err := ioutil.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+
+		var pact struct {
+			Consumer     map[string]string `json:"consumer"`
+			Provider     map[string]string `json:"provider"`
+			Interactions []struct {
+				Description string `json:"description"`
+			} `json:"interactions"`
+		}
+		if err = json.Unmarshal(content, &pact); err != nil {
+			t.Fatal("Error:", err)
+		}
+		if pact.Consumer["name"] != "consumer" || pact.Provider["name"] != provider {
+			t.Fatalf("expected the pact details to be kept in '%s' but got %s", file, content)
+		}
+
+		var descriptions []string
+		for _, i := range pact.Interactions {
+			descriptions = append(descriptions, i.Description)
+		}
+
+		return descriptions
+	}
+
+	err = pact.writePactFile(write(`[{"description":"login"},{"description":"checkout"}]`))
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if got := descriptions("consumer-provider.json", "provider"); !reflect.DeepEqual(got, []string{"login"}) {
+		t.Fatalf("expected the scenario to be removed from the pact but got %v", got)
+	}
+	if got := descriptions("consumer-provider-checkout.json", "payments"); !reflect.DeepEqual(got, []string{"checkout"}) {
+		t.Fatalf("expected the scenario to be written to its own pact but got %v", got)
+	}
+
+	// Interactions from other tests are merged into the pact for the scenario
+	err = pact.writePactFile(write(`[{"description":"refund","providerState":"an order"}]`))
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if got := descriptions("consumer-provider-checkout.json", "payments"); !reflect.DeepEqual(got, []string{"checkout", "refund"}) {
+		t.Fatalf("expected the scenario to be merged but got %v", got)
+	}
+	if _, err = os.Stat(filepath.Join(dir, "consumer-provider.json")); !os.IsNotExist(err) {
+		t.Fatalf("expected no pact to be written without interactions outside of scenarios but got %v", err)
+	}
+}
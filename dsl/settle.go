@@ -0,0 +1,66 @@
+package dsl
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+// defaultQuiesceTimeout is the longest Verify waits for the QuiesceWindow, if
+// there is no SettleTimeout
+const defaultQuiesceTimeout = 10 * time.Second
+
+// settle waits for the requests to the Mock Server to settle once the test has
+// returned, as per the SettleTimeout and QuiesceWindow
+func (p *Pact) settle() {
+	if p.proxy == nil || (p.SettleTimeout <= 0 && p.QuiesceWindow <= 0) {
+		return
+	}
+
+	timeout := p.SettleTimeout
+	if timeout <= 0 {
+		timeout = defaultQuiesceTimeout
+	}
+
+	if !p.proxy.WaitForQuiet(p.QuiesceWindow, timeout) {
+		log.Println("[WARN] requests to the mock server had not settled after waiting", timeout)
+	}
+}
+
+// AwaitInteractions waits for the Mock Server to have matched count requests
+// to interactions since the start of the current Verify, or until ctx is done.
+// Call it at the end of the test passed to Verify when the code under test
+// makes requests asynchronously, so that the interactions are not checked
+// before they have been made:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+//	defer cancel()
+//	return pact.AwaitInteractions(ctx, 3)
+//
+// Requests are only recorded if RecordMismatches, SettleTimeout or
+// QuiesceWindow is set before Verify is called. Interactions over TLS are not
+// supported.
+func (p *Pact) AwaitInteractions(ctx context.Context, count int) error {
+	if p.proxy == nil {
+		return types.NewError(types.ErrInvalidRequest, fmt.Errorf("requests to the mock server are not recorded, set RecordMismatches, SettleTimeout or QuiesceWindow to record them"))
+	}
+
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		matched := p.proxy.Matched()
+		if matched >= count {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return types.NewError(types.ErrMismatch, fmt.Errorf("%d of the %d requests expected were matched: %v", matched, count, ctx.Err()))
+		case <-ticker.C:
+		}
+	}
+}
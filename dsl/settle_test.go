@@ -0,0 +1,69 @@
+package dsl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+func TestSettle_AwaitInteractions(t *testing.T) {
+	ms := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "OK")
+	}))
+	defer ms.Close()
+
+	proxy, err := startMockServerProxy("tcp", "localhost", 0, ms.URL, mockServerTimeouts{})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	defer proxy.Stop()
+	p := &Pact{proxy: proxy}
+
+	go func() {
+		for i := 0; i < 2; i++ {
+			time.Sleep(20 * time.Millisecond)
+			http.Get(fmt.Sprintf("http://localhost:%d/events", proxy.Port))
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err = p.AwaitInteractions(ctx, 2); err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err = p.AwaitInteractions(ctx, 3); !errors.Is(err, types.ErrMismatch) {
+		t.Fatalf("expected a mismatch waiting for a request that is never made but got '%v'", err)
+	}
+
+	if err = (&Pact{}).AwaitInteractions(context.Background(), 1); !errors.Is(err, types.ErrInvalidRequest) {
+		t.Fatalf("expected an error when requests are not recorded but got '%v'", err)
+	}
+}
+
+func TestSettle_QuiesceWindow(t *testing.T) {
+	proxy := &mockServerProxy{lastActive: time.Now()}
+	p := &Pact{proxy: proxy, QuiesceWindow: 50 * time.Millisecond}
+
+	start := time.Now()
+	p.settle()
+	if waited := time.Since(start); waited < 40*time.Millisecond {
+		t.Fatalf("expected to wait for the quiesce window but waited %v", waited)
+	}
+
+	proxy.inFlight = 1
+	p.SettleTimeout = 20 * time.Millisecond
+	start = time.Now()
+	p.settle()
+	if waited := time.Since(start); waited > time.Second {
+		t.Fatalf("expected to give up after the settle timeout but waited %v", waited)
+	}
+}
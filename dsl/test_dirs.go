@@ -0,0 +1,149 @@
+package dsl
+
+import (
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// TestDirs overrides the PactDir and LogDir of a Pact for a single test, see
+// VerifyInDirs. An empty directory defaults to that of the Pact.
+type TestDirs struct {
+	// PactDir is the directory to write the pact of the test to
+	PactDir string
+
+	// LogDir is the directory to write the Mock Service log of the test, and
+	// any fixture updates, to
+	LogDir string
+}
+
+// VerifyInDirs is as per Verify, but writes the artifacts of the test to the
+// given directories, so that the cases of a table-driven test can be kept
+// apart from one another:
+//
+//	for name, tc := range cases {
+//		dirs := dsl.TestDirs{PactDir: filepath.Join("pacts", name)}
+//		err := pact.VerifyInDirs(dirs, tc.test)
+//	}
+//
+// When a PactDir is given, the pact of the interactions verified by the test is
+// written to it once the test passes, and they are then removed from the Mock
+// Service, so that they are not written again by WritePact. Any interactions
+// verified by earlier tests that have not yet been written are written along
+// with them, so call WritePact beforehand when mixing Verify and VerifyInDirs.
+//
+// When a LogDir is given, what the Mock Service logged during the test is
+// copied to a log of the same name in it. The PactDir and LogDir of the Pact
+// remain the defaults for all other tests.
+func (p *Pact) VerifyInDirs(dirs TestDirs, integrationTest func() error, selectors ...InteractionSelector) error {
+	p.Setup(true)
+
+	restore := p.overrideDirs(dirs)
+	defer restore()
+
+	servers := p.mockServers()
+	offsets := logOffsets(servers)
+
+	err := p.Verify(integrationTest, selectors...)
+
+	if dirs.LogDir != "" {
+		for transport, server := range servers {
+			if err := copyLogSince(mockServiceLog(server), offsets[transport], dirs.LogDir); err != nil {
+				log.Println("[WARN] unable to copy the Mock Service log of the test:", err)
+			}
+		}
+	}
+
+	if err != nil || dirs.PactDir == "" {
+		return err
+	}
+
+	return p.writeTestPact()
+}
+
+// overrideDirs replaces the PactDir and LogDir of the Pact with those given,
+// returning a function to restore them
+func (p *Pact) overrideDirs(dirs TestDirs) func() {
+	pactDir, logDir := p.PactDir, p.LogDir
+
+	if dirs.PactDir != "" {
+		p.PactDir = dirs.PactDir
+	}
+	if dirs.LogDir != "" {
+		p.LogDir = dirs.LogDir
+	}
+
+	return func() {
+		p.PactDir, p.LogDir = pactDir, logDir
+	}
+}
+
+// writeTestPact writes the interactions verified by the Mock Services to the
+// (overridden) PactDir, and then removes them from the Mock Services
+func (p *Pact) writeTestPact() error {
+	if err := os.MkdirAll(p.PactDir, os.ModePerm); err != nil {
+		return err
+	}
+
+	err := p.writePactFile(func() error {
+		for _, transport := range transports {
+			if _, ok := p.mockServers()[transport]; !ok {
+				continue
+			}
+
+			mockService := p.mockService(transport)
+			mockService.PactDir = p.cliPactDir()
+			if err := mockService.WritePact(); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, transport := range transports {
+		if _, ok := p.mockServers()[transport]; ok {
+			if err = p.mockService(transport).DeleteSession(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// copyLogSince copies what was written to the log at path since offset to a
+// log of the same name in dir
+func copyLogSince(path string, offset int64, dir string) error {
+	if path == "" {
+		return nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if info, err := f.Stat(); err != nil || info.Size() < offset {
+		offset = 0
+	}
+	if _, err = f.Seek(offset, 0); err != nil {
+		return err
+	}
+
+	content, err := ioutil.ReadAll(f)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filepath.Join(dir, filepath.Base(path)), content, 0644)
+}
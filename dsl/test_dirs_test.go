@@ -0,0 +1,48 @@
+package dsl
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTestDirs_overrideDirs(t *testing.T) {
+	p := &Pact{PactDir: "pacts", LogDir: "logs"}
+
+	restore := p.overrideDirs(TestDirs{PactDir: "pacts/case-1"})
+	if p.PactDir != "pacts/case-1" || p.LogDir != "logs" {
+		t.Fatalf("expected only the PactDir to be overridden but got '%s' and '%s'", p.PactDir, p.LogDir)
+	}
+
+	restore()
+	if p.PactDir != "pacts" || p.LogDir != "logs" {
+		t.Fatalf("expected the directories to be restored but got '%s' and '%s'", p.PactDir, p.LogDir)
+	}
+}
+
+func TestTestDirs_copyLogSince(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "pact-test-dirs")
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "pact.log")
+	ioutil.WriteFile(path, []byte("before\n"), 0644)
+	offset := int64(len("before\n"))
+	ioutil.WriteFile(path, []byte("before\nduring\n"), 0644)
+
+	if err := copyLogSince(path, offset, filepath.Join(dir, "case-1")); err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	content, err := ioutil.ReadFile(filepath.Join(dir, "case-1", "pact.log"))
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if string(content) != "during\n" {
+		t.Fatalf("expected only what was logged since the offset but got '%s'", content)
+	}
+
+	if err = copyLogSince("", 0, dir); err != nil {
+		t.Fatal("expected an unknown log to be ignored but got:", err)
+	}
+}
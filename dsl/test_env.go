@@ -0,0 +1,62 @@
+package dsl
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+// testEnvReplacer replaces the placeholders for the Mock Servers in the values
+// of the TestEnv
+func (p *Pact) testEnvReplacer(servers map[string]*types.MockServer) *strings.Replacer {
+	var replacements []string
+
+	if server, ok := servers[TransportHTTP]; ok {
+		replacements = append(replacements,
+			"${PACT_MOCK_SERVER_URL}", fmt.Sprintf("http://%s:%d", p.Host, server.Port),
+			"${PACT_MOCK_SERVER_PORT}", strconv.Itoa(server.Port),
+		)
+	}
+	if server, ok := servers[TransportHTTPS]; ok {
+		replacements = append(replacements,
+			"${PACT_MOCK_SERVER_TLS_URL}", fmt.Sprintf("https://%s:%d", p.Host, server.Port),
+			"${PACT_MOCK_SERVER_TLS_PORT}", strconv.Itoa(server.Port),
+		)
+	}
+
+	return strings.NewReplacer(replacements...)
+}
+
+// setTestEnv sets the TestEnv for the Mock Servers, returning a function to
+// restore the environment as it was
+func (p *Pact) setTestEnv(servers map[string]*types.MockServer) func() {
+	if len(p.TestEnv) == 0 {
+		return func() {}
+	}
+
+	type previous struct {
+		value string
+		set   bool
+	}
+	restore := make(map[string]previous, len(p.TestEnv))
+	replacer := p.testEnvReplacer(servers)
+
+	for name, value := range p.TestEnv {
+		v, set := os.LookupEnv(name)
+		restore[name] = previous{v, set}
+		os.Setenv(name, replacer.Replace(value))
+	}
+
+	return func() {
+		for name, prev := range restore {
+			if prev.set {
+				os.Setenv(name, prev.value)
+			} else {
+				os.Unsetenv(name)
+			}
+		}
+	}
+}
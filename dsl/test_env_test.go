@@ -0,0 +1,42 @@
+package dsl
+
+import (
+	"os"
+	"testing"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+func TestTestEnv_setTestEnv(t *testing.T) {
+	os.Setenv("PACT_TEST_EXISTING", "before")
+	defer os.Unsetenv("PACT_TEST_EXISTING")
+	os.Unsetenv("PACT_TEST_NEW")
+
+	p := &Pact{
+		Host: "localhost",
+		TestEnv: map[string]string{
+			"PACT_TEST_EXISTING": "${PACT_MOCK_SERVER_URL}/api",
+			"PACT_TEST_NEW":      "${PACT_MOCK_SERVER_TLS_PORT} ${OTHER}",
+		},
+	}
+
+	restore := p.setTestEnv(map[string]*types.MockServer{
+		TransportHTTP:  {Port: 1234},
+		TransportHTTPS: {Port: 5678},
+	})
+
+	if got := os.Getenv("PACT_TEST_EXISTING"); got != "http://localhost:1234/api" {
+		t.Fatalf("expected the mock server URL to be substituted but got '%s'", got)
+	}
+	if got := os.Getenv("PACT_TEST_NEW"); got != "5678 ${OTHER}" {
+		t.Fatalf("expected the TLS mock server port to be substituted but got '%s'", got)
+	}
+
+	restore()
+	if got := os.Getenv("PACT_TEST_EXISTING"); got != "before" {
+		t.Fatalf("expected the variable to be restored but got '%s'", got)
+	}
+	if _, set := os.LookupEnv("PACT_TEST_NEW"); set {
+		t.Fatal("expected the new variable to be unset")
+	}
+}
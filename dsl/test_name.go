@@ -0,0 +1,97 @@
+package dsl
+
+import (
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// ForTest records the name of the Go test that defines the interaction, e.g.
+// t.Name(). It is written to the pact file as a "testname" comment, and
+// included when the interaction fails. Defaults to the name of the calling
+// Test function.
+func (i *Interaction) ForTest(name string) *Interaction {
+	i.testName = name
+
+	return i
+}
+
+// ForTest records the name of the Go test that defines the message, e.g.
+// t.Name(). It is written to the pact file as a "testname" comment. Defaults
+// to the name of the calling Test function.
+func (p *Message) ForTest(name string) *Message {
+	p.testName = name
+
+	return p
+}
+
+// callerTestName returns the name of the Test function in the call stack,
+// if any
+func callerTestName() string {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+
+	for {
+		frame, more := frames.Next()
+
+		if strings.HasSuffix(frame.File, "_test.go") {
+			if name := testFunctionName(frame.Function); name != "" {
+				return name
+			}
+		}
+
+		if !more {
+			return ""
+		}
+	}
+}
+
+// testFunctionName returns the name of the Test function fn belongs to, e.g.
+// "TestLogin" for "github.com/foo/bar.TestLogin.func1"
+func testFunctionName(fn string) string {
+	fn = fn[strings.LastIndex(fn, "/")+1:]
+	parts := strings.Split(fn, ".")
+	if len(parts) < 2 {
+		return ""
+	}
+
+	name := parts[1]
+	if name == "Test" || (strings.HasPrefix(name, "Test") && !isLower(name[len("Test")])) {
+		return name
+	}
+
+	return ""
+}
+
+func isLower(c byte) bool {
+	return c >= 'a' && c <= 'z'
+}
+
+// recordTestName remembers the test that defined an interaction, so that it
+// can be written to the pact file
+func (p *Pact) recordTestName(key string, name string) {
+	if name == "" {
+		return
+	}
+
+	if p.testNames == nil {
+		p.testNames = make(map[string]string)
+	}
+	p.testNames[key] = name
+}
+
+// testNamesOf returns the distinct names of the tests that defined interactions
+func testNamesOf(interactions []*Interaction) []string {
+	seen := make(map[string]bool)
+	var names []string
+
+	for _, i := range interactions {
+		if i.testName != "" && !seen[i.testName] {
+			seen[i.testName] = true
+			names = append(names, i.testName)
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,72 @@
+package dsl
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+func TestTestName_testFunctionName(t *testing.T) {
+	tests := map[string]string{
+		"github.com/foo/bar.TestLogin":              "TestLogin",
+		"github.com/foo/bar.TestLogin.func1":        "TestLogin",
+		"github.com/foo/bar.Test_login.func1.1":     "Test_login",
+		"github.com/foo/bar.Test":                   "Test",
+		"github.com/foo/bar.Testing":                "",
+		"github.com/foo/bar.(*Pact).AddInteraction": "",
+		"main": "",
+	}
+
+	for fn, want := range tests {
+		if got := testFunctionName(fn); got != want {
+			t.Fatalf("expected the test name of '%s' to be '%s' but got '%s'", fn, want, got)
+		}
+	}
+}
+
+func TestTestName_AddInteraction(t *testing.T) {
+	pact := &Pact{pactClient: newMockClient()}
+
+	i := pact.AddInteraction()
+	if i.testName != "TestTestName_AddInteraction" {
+		t.Fatalf("expected the test name to be recorded but got '%s'", i.testName)
+	}
+
+	t.Run("subtest", func(t *testing.T) {
+		i.ForTest(t.Name())
+	})
+	if i.testName != "TestTestName_AddInteraction/subtest" {
+		t.Fatalf("expected the explicit test name to be recorded but got '%s'", i.testName)
+	}
+
+	if m := pact.AddMessage(); m.testName != "TestTestName_AddInteraction" {
+		t.Fatalf("expected the test name to be recorded but got '%s'", m.testName)
+	}
+}
+
+func TestTestName_VerifyMismatch(t *testing.T) {
+	ms := setupMismatchMockServer()
+	defer ms.Close()
+
+	pact := &Pact{
+		Server: &types.MockServer{
+			Port: getPort(ms.URL),
+		},
+	}
+
+	pact.
+		AddInteraction().
+		UponReceiving("Some name for the test").
+		WithRequest(Request{}).
+		WillRespondWith(Response{})
+
+	err := pact.Verify(func() error { return nil })
+
+	if err == nil || !strings.Contains(err.Error(), "Interactions were defined by: TestTestName_VerifyMismatch") {
+		t.Fatalf("expected the test name in the error but got '%v'", err)
+	}
+	if pact.testNames[interactionKey("Some name for the test")] != "TestTestName_VerifyMismatch" {
+		t.Fatalf("expected the test name to be recorded for the pact file, got %v", pact.testNames)
+	}
+}
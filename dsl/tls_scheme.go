@@ -0,0 +1,170 @@
+package dsl
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+// tlsHandshakeRecord is the first byte sent by a TLS client
+const tlsHandshakeRecord = 0x16
+
+var (
+	// tlsToPlainLog is logged by the Mock Service for plain HTTP interactions
+	// when it receives a TLS handshake, e.g. "bad Request-Line `\x16\x03\x01...'"
+	tlsToPlainLog = regexp.MustCompile(`(?i)bad request-line\W+(\\x16\\x03|\x16\x03)`)
+
+	// plainToTLSLog is logged by the Mock Service for interactions over TLS when
+	// it receives a plain HTTP request
+	plainToTLSLog = regexp.MustCompile(`(?i)ssl_accept.*(http request|wrong version number|unknown protocol)`)
+
+	// unmatchedLog is logged by the Mock Service for a request matching no
+	// interaction
+	unmatchedLog = regexp.MustCompile(`No matching interaction found for ([A-Z]+) ([^\s?]+)`)
+)
+
+// schemeListener counts the connections whose first byte is of the wrong
+// scheme for the listener, i.e. a TLS handshake to a plain HTTP listener, or a
+// plain HTTP request to a TLS one
+type schemeListener struct {
+	net.Listener
+
+	tls   bool
+	wrong *int64
+}
+
+func (l schemeListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return conn, err
+	}
+
+	return &schemeConn{Conn: conn, tls: l.tls, wrong: l.wrong}, nil
+}
+
+// schemeConn checks the first byte read from the connection, rather than in
+// Accept, so as not to wait for the client there
+type schemeConn struct {
+	net.Conn
+
+	tls     bool
+	wrong   *int64
+	checked sync.Once
+}
+
+func (c *schemeConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if n > 0 {
+		c.checked.Do(func() {
+			if (b[0] == tlsHandshakeRecord) != c.tls {
+				atomic.AddInt64(c.wrong, 1)
+			}
+		})
+	}
+
+	return n, err
+}
+
+// wrongSchemeConnections returns the number of connections made with the
+// wrong scheme to the proxies in front of the Mock Services, by transport
+func (p *Pact) wrongSchemeConnections() map[string]int64 {
+	counts := make(map[string]int64)
+	if p.proxy != nil {
+		counts[TransportHTTP] = atomic.LoadInt64(&p.proxy.wrongScheme)
+	}
+	if p.mtlsProxy != nil {
+		counts[TransportHTTPS] = atomic.LoadInt64(&p.mtlsProxy.wrongScheme)
+	}
+
+	return counts
+}
+
+// schemeMismatches explains the failure to verify the interactions over the
+// transport, if the client appears to have used the wrong scheme: http:// for
+// interactions over TLS, or https:// for plain HTTP ones. before is the count
+// of wrongSchemeConnections, and offsets the logOffsets, from before the test.
+func (p *Pact) schemeMismatches(transport string, servers map[string]*types.MockServer, interactions map[string][]*Interaction, before map[string]int64, offsets map[string]int64) []string {
+	var mismatches []string
+
+	port := 0
+	if server, ok := servers[transport]; ok {
+		port = server.Port
+	}
+	wrongScheme := p.wrongSchemeConnections()[transport] > before[transport]
+	logged := readLogSince(mockServiceLog(p.mockServers()[transport]), offsets[transport])
+
+	switch transport {
+	case TransportHTTP:
+		if wrongScheme || tlsToPlainLog.MatchString(logged) {
+			mismatches = append(mismatches, fmt.Sprintf("The Mock Server for plain HTTP interactions (port %d) received a TLS handshake: the client appears to be using https:// rather than http://. Declare interactions made over TLS WithTransport(dsl.TransportHTTPS), and use the port of servers[dsl.TransportHTTPS] for them.", port))
+		}
+	case TransportHTTPS:
+		if wrongScheme || plainToTLSLog.MatchString(logged) {
+			mismatches = append(mismatches, fmt.Sprintf("The Mock Server for interactions over TLS (port %d) received a plain HTTP request: the client appears to be using http:// rather than https:// for the interactions declared WithTransport(dsl.TransportHTTPS).", port))
+		}
+	}
+
+	// Requests made to the Mock Server of the other transport
+	for _, other := range transports {
+		if other == transport {
+			continue
+		}
+		server, ok := servers[other]
+		if !ok {
+			continue
+		}
+
+		for _, rec := range p.unmatchedSince(other, offsets[other]) {
+			for _, i := range interactions[transport] {
+				if requestMatches(i.Request, rec) {
+					mismatches = append(mismatches, fmt.Sprintf("%s %s was made to the Mock Server for %s interactions (port %d), but matches the interaction '%s' over %s: use %s://%s:%d for it.", rec.Method, rec.Path, transportName(other), server.Port, i.Description, transportName(transport), schemeOf(transport), p.Host, port))
+					break
+				}
+			}
+		}
+	}
+
+	return mismatches
+}
+
+// unmatchedSince returns the requests to the Mock Server for the transport that
+// matched no interaction, as recorded by the proxy in front of it or else
+// logged by the Mock Service since offset
+func (p *Pact) unmatchedSince(transport string, offset int64) []*recordedRequest {
+	var unmatched []*recordedRequest
+	if transport == TransportHTTP && p.proxy != nil {
+		for _, rec := range p.proxy.Requests() {
+			if rec.Unmatched {
+				unmatched = append(unmatched, rec)
+			}
+		}
+		return unmatched
+	}
+
+	logged := readLogSince(mockServiceLog(p.mockServers()[transport]), offset)
+	for _, match := range unmatchedLog.FindAllStringSubmatch(logged, -1) {
+		unmatched = append(unmatched, &recordedRequest{Method: match[1], Path: match[2]})
+	}
+
+	return unmatched
+}
+
+func transportName(transport string) string {
+	if transport == TransportHTTPS {
+		return "TLS"
+	}
+
+	return "plain HTTP"
+}
+
+func schemeOf(transport string) string {
+	if transport == TransportHTTPS {
+		return "https"
+	}
+
+	return "http"
+}
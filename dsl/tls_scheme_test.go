@@ -0,0 +1,111 @@
+package dsl
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+func TestTLSScheme_HTTPSToPlainProxy(t *testing.T) {
+	ms := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ms.Close()
+
+	proxy, err := startMockServerProxy("tcp", "localhost", 0, ms.URL, mockServerTimeouts{})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	defer proxy.Stop()
+
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}}
+	if _, err := client.Get(fmt.Sprintf("https://localhost:%d/users", proxy.Port)); err == nil {
+		t.Fatal("expected the TLS handshake to fail")
+	}
+	if _, err := http.Get(fmt.Sprintf("http://localhost:%d/users", proxy.Port)); err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	pact := &Pact{proxy: proxy}
+	if n := pact.wrongSchemeConnections()[TransportHTTP]; n != 1 {
+		t.Fatalf("expected 1 connection with the wrong scheme but got %d", n)
+	}
+}
+
+func TestTLSScheme_PlainToTLSListener(t *testing.T) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	var wrong int64
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Listener = schemeListener{Listener: listener, tls: true, wrong: &wrong}
+	server.StartTLS()
+	defer server.Close()
+
+	if _, err := server.Client().Get(server.URL); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if res, err := http.Get(strings.Replace(server.URL, "https://", "http://", 1)); err == nil && res.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected a plain HTTP request to fail but got %d", res.StatusCode)
+	}
+
+	if n := atomic.LoadInt64(&wrong); n != 1 {
+		t.Fatalf("expected 1 connection with the wrong scheme but got %d", n)
+	}
+}
+
+func TestTLSScheme_schemeMismatches(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pact-go-scheme")
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	defer os.RemoveAll(dir)
+
+	plainLog := filepath.Join(dir, "pact.log")
+	tlsLog := filepath.Join(dir, "pact-tls.log")
+	ioutil.WriteFile(plainLog, []byte("I, [2024-01-01] INFO -- : Earlier test\n"), 0644)
+	ioutil.WriteFile(tlsLog, []byte(""), 0644)
+
+	pact := &Pact{
+		Host:      "localhost",
+		Server:    &types.MockServer{Port: 1234, Args: []string{"--log", plainLog}},
+		tlsServer: &types.MockServer{Port: 1235, Args: []string{"--log", tlsLog}},
+	}
+	servers := pact.mockServers()
+	offsets := logOffsets(servers)
+
+	f, _ := os.OpenFile(plainLog, os.O_APPEND|os.O_WRONLY, 0644)
+	fmt.Fprintln(f, "ERROR bad Request-Line `\\x16\\x03\\x01\\x02\\x00\\x01'.")
+	f.Close()
+	ioutil.WriteFile(tlsLog, []byte("E, [2024-01-01] ERROR -- : No matching interaction found for GET /orders\n"), 0644)
+
+	interactions := map[string][]*Interaction{
+		TransportHTTP: {(&Interaction{}).UponReceiving("A request for the orders").WithRequest(Request{Method: "GET", Path: String("/orders")})},
+	}
+
+	mismatches := pact.schemeMismatches(TransportHTTP, servers, interactions, nil, offsets)
+	if len(mismatches) != 2 {
+		t.Fatalf("expected 2 mismatches but got %q", mismatches)
+	}
+	if !strings.Contains(mismatches[0], "(port 1234) received a TLS handshake") {
+		t.Fatalf("expected the TLS handshake to be reported but got '%s'", mismatches[0])
+	}
+	if want := "GET /orders was made to the Mock Server for TLS interactions (port 1235), but matches the interaction 'A request for the orders' over plain HTTP: use http://localhost:1234 for it."; mismatches[1] != want {
+		t.Fatalf("expected '%s' but got '%s'", want, mismatches[1])
+	}
+
+	offsets = logOffsets(servers)
+	if mismatches := pact.schemeMismatches(TransportHTTP, servers, interactions, nil, offsets); len(mismatches) != 0 {
+		t.Fatalf("expected no mismatches logged before the test but got %q", mismatches)
+	}
+}
@@ -0,0 +1,113 @@
+package dsl
+
+import (
+	"crypto/tls"
+	"fmt"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+// Transports an interaction may be expected over
+const (
+	// TransportHTTP is the default, plaintext transport
+	TransportHTTP = "http"
+
+	// TransportHTTPS serves interactions over TLS, from a separate Mock Service
+	TransportHTTPS = "https"
+)
+
+// transports in the order their Mock Services are verified and pacts written
+var transports = []string{TransportHTTP, TransportHTTPS}
+
+func isKnownTransport(transport string) bool {
+	for _, t := range transports {
+		if t == transport {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WithTransport specifies the transport the interaction is expected over,
+// allowing a single test to exercise both plaintext and TLS endpoints.
+// Defaults to TransportHTTP. Use Pact.VerifyWithTransports to find the Mock
+// Server for each transport.
+func (i *Interaction) WithTransport(transport string) *Interaction {
+	i.transport = transport
+
+	return i
+}
+
+// Transport returns the transport the interaction is expected over
+func (i *Interaction) Transport() string {
+	if i.transport == "" {
+		return TransportHTTP
+	}
+
+	return i.transport
+}
+
+// setupTLSServer starts the Mock Service for interactions over TLS, if required.
+// Its interactions are merged into the pact file written by the HTTP Mock Service.
+func (p *Pact) setupTLSServer() error {
+	if p.tlsServer != nil {
+		return nil
+	}
+
+	args := []string{"--ssl"}
+	if p.SSLCert != "" || p.SSLKey != "" {
+		if p.SSLCert == "" || p.SSLKey == "" {
+			return types.NewError(types.ErrInvalidRequest, fmt.Errorf("both SSLCert and SSLKey must be provided"))
+		}
+		args = append(args, "--sslcert", p.SSLCert, "--sslkey", p.SSLKey)
+	} else if p.TLSServerName != "" {
+		certArgs, err := p.setupTLSServerName()
+		if err != nil {
+			return types.NewError(types.ErrServiceStartup, err)
+		}
+		args = append(args, certArgs...)
+	}
+
+	server := p.startMockServer("pact-tls.log", "merge", args...)
+	if server.Error != nil {
+		return server.Error
+	}
+	p.tlsServer = server
+	p.registerMockServer(TransportHTTPS, server, nil)
+
+	return nil
+}
+
+// mockServers returns the running Mock Server for each transport
+func (p *Pact) mockServers() map[string]*types.MockServer {
+	servers := map[string]*types.MockServer{
+		TransportHTTP: p.Server,
+	}
+	if p.tlsServer != nil {
+		servers[TransportHTTPS] = p.tlsServer
+	}
+
+	return servers
+}
+
+// mockService returns the client for the Mock Service of the given transport
+func (p *Pact) mockService(transport string) *MockService {
+	server := p.mockServers()[transport]
+
+	mockService := &MockService{
+		BaseURL:           fmt.Sprintf("%s://%s:%d", transport, p.Host, server.Port),
+		Consumer:          p.Consumer,
+		Provider:          p.Provider,
+		PactFileWriteMode: p.PactFileWriteMode,
+	}
+
+	if transport == TransportHTTPS {
+		mockService.PactFileWriteMode = "merge"
+
+		// The Mock Service is running locally, usually with a self-signed certificate
+		mockService.TLSConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+
+	return mockService
+}
@@ -0,0 +1,130 @@
+package dsl
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+// setupRecordingMockServer records the paths of requests to the Mock Service
+func setupRecordingMockServer(tls bool) (*httptest.Server, func() []string) {
+	var mu sync.Mutex
+	var paths []string
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+	})
+
+	recorded := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string{}, paths...)
+	}
+
+	if tls {
+		return httptest.NewTLSServer(handler), recorded
+	}
+
+	return httptest.NewServer(handler), recorded
+}
+
+func TestTransport_Interaction(t *testing.T) {
+	i := &Interaction{}
+	if i.Transport() != TransportHTTP {
+		t.Fatalf("expected the default transport to be '%s' but got '%s'", TransportHTTP, i.Transport())
+	}
+
+	i.WithTransport(TransportHTTPS)
+	if i.Transport() != TransportHTTPS || i.validate() != nil {
+		t.Fatalf("expected the transport to be '%s' but got '%s'", TransportHTTPS, i.Transport())
+	}
+
+	i.WithTransport("carrier-pigeon")
+	if i.validate() == nil {
+		t.Fatal("expected an unknown transport to be invalid")
+	}
+}
+
+func TestTransport_VerifyWithTransports(t *testing.T) {
+	ms, httpRequests := setupRecordingMockServer(false)
+	defer ms.Close()
+	tlsMs, tlsRequests := setupRecordingMockServer(true)
+	defer tlsMs.Close()
+
+	client := newMockClient()
+	client.MockServer = &types.MockServer{Port: getPort(tlsMs.URL)}
+
+	pact := &Pact{
+		Server: &types.MockServer{
+			Port: getPort(ms.URL),
+		},
+		Consumer:   "My Consumer",
+		Provider:   "My Provider",
+		pactClient: client,
+	}
+
+	pact.
+		AddInteraction().
+		UponReceiving("A plaintext request").
+		WithRequest(Request{Method: "GET", Path: String("/plain")}).
+		WillRespondWith(Response{Status: 200})
+
+	pact.
+		AddInteraction().
+		UponReceiving("A TLS request").
+		WithTransport(TransportHTTPS).
+		WithRequest(Request{Method: "GET", Path: String("/secure")}).
+		WillRespondWith(Response{Status: 200})
+
+	var servers map[string]*types.MockServer
+	err := pact.VerifyWithTransports(func(s map[string]*types.MockServer) error {
+		servers = s
+		return nil
+	})
+
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	if servers[TransportHTTP].Port != getPort(ms.URL) || servers[TransportHTTPS].Port != getPort(tlsMs.URL) {
+		t.Fatalf("expected a mock server for each transport but got %v", servers)
+	}
+
+	if len(client.StartServerArgs) != 1 || !strings.Contains(strings.Join(client.StartServerArgs[0], " "), "--pact-file-write-mode merge --ssl") {
+		t.Fatalf("expected a TLS mock service to be started once but got %v", client.StartServerArgs)
+	}
+
+	want := []string{"POST /interactions", "GET /interactions/verification", "DELETE /interactions"}
+	for name, requests := range map[string][]string{"http": httpRequests(), "https": tlsRequests()} {
+		if strings.Join(requests, ",") != strings.Join(want, ",") {
+			t.Fatalf("expected the %s mock service to receive %v but got %v", name, want, requests)
+		}
+	}
+
+	if err = pact.WritePact(); err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	for name, requests := range map[string][]string{"http": httpRequests(), "https": tlsRequests()} {
+		if requests[len(requests)-1] != "POST /pact" {
+			t.Fatalf("expected the %s mock service to write the pact but got %v", name, requests)
+		}
+	}
+}
+
+func TestTransport_setupTLSServerInvalidCertificate(t *testing.T) {
+	pact := &Pact{
+		SSLCert:    "cert.pem",
+		pactClient: newMockClient(),
+	}
+
+	if err := pact.setupTLSServer(); err == nil {
+		t.Fatal("expected an error when the key is not provided")
+	}
+}
@@ -0,0 +1,55 @@
+package dsl
+
+import (
+	"log"
+	"strings"
+)
+
+// Titles of the sections of a failed verification by the Mock Service
+const (
+	unexpectedRequestsTitle = "Unexpected requests:"
+	missingRequestsTitle    = "Missing requests:"
+	incorrectRequestsTitle  = "Incorrect requests:"
+)
+
+// ignoreUnexpectedRequests returns nil if the Mock Service failed verification
+// only because of requests that match no interaction, logging them instead,
+// and otherwise err as is
+func ignoreUnexpectedRequests(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	unexpected := verificationSection(err.Error(), unexpectedRequestsTitle)
+	if len(unexpected) == 0 ||
+		len(verificationSection(err.Error(), missingRequestsTitle)) > 0 ||
+		len(verificationSection(err.Error(), incorrectRequestsTitle)) > 0 {
+		return err
+	}
+
+	for _, request := range unexpected {
+		log.Println("[WARN] ignoring unexpected request:", request)
+	}
+
+	return nil
+}
+
+// verificationSection returns the (tab indented) requests listed under the
+// title in the verification failure message of the Mock Service
+func verificationSection(message string, title string) []string {
+	var requests []string
+
+	inSection := false
+	for _, line := range strings.Split(message, "\n") {
+		switch {
+		case strings.TrimSpace(line) == title:
+			inSection = true
+		case inSection && strings.HasPrefix(line, "\t"):
+			requests = append(requests, strings.TrimSpace(line))
+		case inSection:
+			return requests
+		}
+	}
+
+	return requests
+}
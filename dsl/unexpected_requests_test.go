@@ -0,0 +1,42 @@
+package dsl
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestIgnoreUnexpectedRequests(t *testing.T) {
+	unexpected := errors.New("Actual interactions do not match expected interactions for mock MockService.\n\nUnexpected requests:\n\tGET /health\n\tGET /metrics\n\nSee logs/pact.log for details.\n")
+	if err := ignoreUnexpectedRequests(unexpected); err != nil {
+		t.Fatalf("expected unexpected requests to be ignored but got '%v'", err)
+	}
+
+	for _, message := range []string{
+		"Actual interactions do not match expected interactions for mock MockService.\n\nMissing requests:\n\tGET /users/1\n\nUnexpected requests:\n\tGET /health\n\nSee logs/pact.log for details.\n",
+		"Actual interactions do not match expected interactions for mock MockService.\n\nIncorrect requests:\n\tPOST /users (request body did not match)\n\nUnexpected requests:\n\tGET /health\n\nSee logs/pact.log for details.\n",
+		"unable to connect to the Mock Service",
+	} {
+		err := errors.New(message)
+		if got := ignoreUnexpectedRequests(err); got != err {
+			t.Fatalf("expected '%s' to fail but got '%v'", message, got)
+		}
+	}
+
+	if ignoreUnexpectedRequests(nil) != nil {
+		t.Fatal("expected no error")
+	}
+}
+
+func TestVerificationSection(t *testing.T) {
+	message := "Missing requests:\n\tGET /a\n\tGET /b\n\nUnexpected requests:\n\tGET /c\n"
+
+	if got := verificationSection(message, missingRequestsTitle); len(got) != 2 || got[1] != "GET /b" {
+		t.Fatalf("unexpected missing requests %q", got)
+	}
+	if got := verificationSection(message, unexpectedRequestsTitle); len(got) != 1 || got[0] != "GET /c" {
+		t.Fatalf("unexpected unexpected requests %q", got)
+	}
+	if got := verificationSection(message, incorrectRequestsTitle); len(got) != 0 {
+		t.Fatalf("expected no incorrect requests but got %q", got)
+	}
+}
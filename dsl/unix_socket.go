@@ -0,0 +1,86 @@
+package dsl
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+// socketProxy serves the Mock Server on a Unix domain socket, which the Mock
+// Service is unable to listen on itself
+type socketProxy struct {
+	// Path of the socket the proxy is listening on
+	Path string
+
+	server *http.Server
+}
+
+// startSocketProxy starts a proxy to the Mock Server listening on the
+// UnixSocket, advertising its path as the SocketPath of the Mock Server
+func (p *Pact) startSocketProxy() error {
+	if p.socketProxy != nil || p.UnixSocket == "" {
+		return nil
+	}
+
+	// A socket left behind by an earlier run would prevent listening
+	if info, err := os.Stat(p.UnixSocket); err == nil && info.Mode()&os.ModeSocket != 0 {
+		os.Remove(p.UnixSocket)
+	}
+
+	listener, err := net.Listen("unix", p.UnixSocket)
+	if err != nil {
+		return types.NewError(types.ErrServiceStartup, fmt.Errorf("unable to listen on unix socket '%s': %v", p.UnixSocket, err))
+	}
+
+	// The Mock Server port is read for each request, as a recording proxy may
+	// be placed in front of the Mock Service later
+	server := p.Server
+	director := func(r *http.Request) {
+		r.URL.Scheme = "http"
+		r.URL.Host = net.JoinHostPort(strings.Trim(p.Host, "[]"), strconv.Itoa(server.Port))
+	}
+	proxy := &socketProxy{
+		Path:   p.UnixSocket,
+		server: &http.Server{Handler: &httputil.ReverseProxy{Director: director}},
+	}
+
+	go func() {
+		if err := proxy.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Println("[ERROR] unix socket proxy:", err)
+		}
+	}()
+
+	log.Println("[DEBUG] started unix socket proxy on:", proxy.Path)
+	p.socketProxy = proxy
+	p.Server.SocketPath = proxy.Path
+
+	return nil
+}
+
+// Stop shuts the proxy down, removing the socket
+func (s *socketProxy) Stop() error {
+	log.Println("[DEBUG] stopping unix socket proxy")
+
+	err := s.server.Close()
+	os.Remove(s.Path)
+
+	return err
+}
+
+// Shutdown is as per mockServerProxy.Shutdown, for the unix socket proxy
+func (s *socketProxy) Shutdown(ctx context.Context) error {
+	log.Println("[DEBUG] shutting down unix socket proxy")
+
+	err := shutdownServer(ctx, s.server)
+	os.Remove(s.Path)
+
+	return err
+}
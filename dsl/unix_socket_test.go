@@ -0,0 +1,61 @@
+package dsl
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+func TestSocketProxy(t *testing.T) {
+	ms := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Method + " " + r.URL.Path))
+	}))
+	defer ms.Close()
+
+	dir, err := ioutil.TempDir("", "pact-go-socket")
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	defer os.RemoveAll(dir)
+
+	pact := &Pact{
+		Server:     &types.MockServer{Port: getPort(ms.URL)},
+		Host:       "localhost",
+		UnixSocket: filepath.Join(dir, "mock.sock"),
+	}
+	if err = pact.startSocketProxy(); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if pact.Server.SocketPath != pact.UnixSocket {
+		t.Fatalf("expected the socket path to be advertised but got '%s'", pact.Server.SocketPath)
+	}
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", pact.Server.SocketPath)
+		},
+	}}
+	res, err := client.Get("http://docker/containers/json")
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	body, _ := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if string(body) != "GET /containers/json" {
+		t.Fatalf("expected the request to reach the mock server but got '%s'", body)
+	}
+
+	if err = pact.socketProxy.Stop(); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err = os.Stat(pact.UnixSocket); !os.IsNotExist(err) {
+		t.Fatalf("expected the socket to be removed but got %v", err)
+	}
+}
@@ -0,0 +1,189 @@
+package dsl
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+// verificationCacheEntry records the successful verification of a pact
+type verificationCacheEntry struct {
+	PactURL         string                           `json:"pactUrl"`
+	Provider        string                           `json:"provider"`
+	ProviderVersion string                           `json:"providerVersion"`
+	VerifiedAt      time.Time                        `json:"verifiedAt"`
+	Responses       []types.ProviderVerifierResponse `json:"responses"`
+}
+
+// verificationCache stores the outcome of successful verifications, keyed by
+// the content of the pact and the provider version, so that unchanged pacts
+// need not be verified again
+type verificationCache struct {
+	dir string
+	now func() time.Time
+}
+
+// key returns the cache key for a pact file, or false if it can't be cached
+// e.g. it is fetched from a remote URL
+func (c *verificationCache) key(pactURL string, provider string, version string) (string, bool) {
+	if strings.HasPrefix(pactURL, "http://") || strings.HasPrefix(pactURL, "https://") {
+		return "", false
+	}
+
+	content, err := ioutil.ReadFile(pactURL)
+	if err != nil {
+		return "", false
+	}
+
+	h := sha256.New()
+	h.Write([]byte(provider + "\x00" + version + "\x00"))
+	h.Write(content)
+
+	return hex.EncodeToString(h.Sum(nil)), true
+}
+
+func (c *verificationCache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
+
+// lookup splits the pacts of request into those with a cached result, and
+// those still to be verified. keys contains the cache key of each pact to be
+// verified that can be cached.
+func (c *verificationCache) lookup(request types.VerifyRequest) (cached []types.ProviderVerifierResponse, remaining []string, keys map[string]string) {
+	keys = make(map[string]string)
+
+	for _, pactURL := range request.PactURLs {
+		key, ok := c.key(pactURL, request.Provider, request.ProviderVersion)
+		if !ok {
+			remaining = append(remaining, pactURL)
+			continue
+		}
+
+		var entry verificationCacheEntry
+		data, err := ioutil.ReadFile(c.path(key))
+		if err == nil && json.Unmarshal(data, &entry) == nil {
+			log.Printf("[INFO] skipping verification of '%s', which was verified at %s\n", pactURL, entry.VerifiedAt.Format(time.RFC3339))
+			cached = append(cached, entry.Responses...)
+			continue
+		}
+
+		remaining = append(remaining, pactURL)
+		keys[pactURL] = key
+	}
+
+	return
+}
+
+// store records the responses of a successful verification of request
+func (c *verificationCache) store(request types.VerifyRequest, keys map[string]string, res []types.ProviderVerifierResponse) {
+	if err := os.MkdirAll(c.dir, os.ModePerm); err != nil {
+		log.Println("[WARN] unable to create verification cache:", err)
+		return
+	}
+
+	for pactURL, key := range keys {
+		responses := responsesForPact(pactURL, res, len(request.PactURLs) == 1)
+		if len(responses) == 0 {
+			continue
+		}
+
+		data, err := json.Marshal(verificationCacheEntry{
+			PactURL:         pactURL,
+			Provider:        request.Provider,
+			ProviderVersion: request.ProviderVersion,
+			VerifiedAt:      c.now(),
+			Responses:       responses,
+		})
+		if err == nil {
+			err = writeFileAtomic(c.path(key), data, 0644)
+		}
+		if err != nil {
+			log.Println("[WARN] unable to write verification cache:", err)
+		}
+	}
+}
+
+// responsesForPact returns the responses from verifying the pact at pactURL.
+// If only a single pact was verified, all responses belong to it.
+func responsesForPact(pactURL string, res []types.ProviderVerifierResponse, single bool) []types.ProviderVerifierResponse {
+	if single {
+		return res
+	}
+
+	want, _ := filepath.Abs(pactURL)
+
+	var responses []types.ProviderVerifierResponse
+	for _, r := range res {
+		for _, example := range r.Examples {
+			if got, _ := filepath.Abs(example.Pact.URL); got == want {
+				responses = append(responses, r)
+				break
+			}
+		}
+	}
+
+	return responses
+}
+
+// verifyProvider runs provider verification, skipping pacts that have already
+// been verified successfully if a VerificationCacheDir is given, or that the
+// broker has recorded as verified if OnlyChangedPacts is set
+func (p *Pact) verifyProvider(request types.VerifyRequest) ([]types.ProviderVerifierResponse, error) {
+	if request.OnlyChangedPacts && len(request.PactURLs) == 0 && request.BrokerURL != "" {
+		reused, remaining, ok, err := p.changedPacts(request)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			// Never fall through to fetching pacts from the broker
+			if len(remaining) == 0 {
+				return reused, nil
+			}
+
+			// The pacts to verify are known, so don't fetch them by selectors again
+			request.PactURLs = remaining
+			request.ConsumerVersionSelectors = nil
+			request.Tags = nil
+			request.IncludeWIPPactsSince = nil
+			request.Args = nil
+			res, err := p.verifyWithRetries(request)
+
+			return append(reused, res...), err
+		}
+	}
+
+	if request.VerificationCacheDir == "" || len(request.PactURLs) == 0 {
+		return p.verifyWithRetries(request)
+	}
+
+	// Without a version, a pact verified once would be skipped even after the
+	// provider changes
+	if request.ProviderVersion == "" {
+		return nil, types.NewError(types.ErrInvalidRequest, fmt.Errorf("'ProviderVersion' is mandatory to cache verifications in a VerificationCacheDir"))
+	}
+
+	cache := &verificationCache{dir: request.VerificationCacheDir, now: p.now()}
+	cached, remaining, keys := cache.lookup(request)
+
+	// Never fall through to fetching pacts from the broker
+	if len(remaining) == 0 {
+		return cached, nil
+	}
+
+	request.PactURLs = remaining
+	res, err := p.verifyWithRetries(request)
+	if err == nil {
+		cache.store(request, keys, res)
+	}
+
+	return append(cached, res...), err
+}
@@ -0,0 +1,139 @@
+package dsl
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+func verifierResponse(pactURL string) types.ProviderVerifierResponse {
+	var res types.ProviderVerifierResponse
+	json.Unmarshal([]byte(fmt.Sprintf(`{"examples":[{"status":"passed","pact":{"url":%q}}]}`, pactURL)), &res)
+
+	return res
+}
+
+func TestVerificationCache_verifyProvider(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pact-go-cache")
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	defer os.RemoveAll(dir)
+
+	one := filepath.Join(dir, "one.json")
+	two := filepath.Join(dir, "two.json")
+	ioutil.WriteFile(one, []byte(`{"consumer":{"name":"one"}}`), 0644)
+	ioutil.WriteFile(two, []byte(`{"consumer":{"name":"two"}}`), 0644)
+
+	c := newMockClient()
+	c.VerifyProviderResponse = []types.ProviderVerifierResponse{verifierResponse(one), verifierResponse(two)}
+	pact := &Pact{pactClient: c}
+	cacheDir := filepath.Join(dir, "cache")
+
+	request := types.VerifyRequest{
+		PactURLs:        []string{one, two, "http://broker/pacts/three"},
+		Provider:        "provider",
+		ProviderVersion: "1.0.0",
+
+		VerificationCacheDir: cacheDir,
+	}
+
+	if _, err = pact.verifyProvider(request); err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	// Only the remote pact needs verifying again
+	c.VerifyProviderResponse = []types.ProviderVerifierResponse{verifierResponse("http://broker/pacts/three")}
+	res, err := pact.verifyProvider(request)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if urls := c.VerifyProviderRequests[1].PactURLs; len(urls) != 1 || urls[0] != "http://broker/pacts/three" {
+		t.Fatalf("expected only the remote pact to be verified but got %v", urls)
+	}
+	if len(res) != 3 {
+		t.Fatalf("expected the cached responses to be returned but got %d responses", len(res))
+	}
+
+	// A change to the pact, or a new provider version, invalidates the cache
+	ioutil.WriteFile(two, []byte(`{"consumer":{"name":"two"},"interactions":[]}`), 0644)
+	request.PactURLs = []string{one, two}
+	pact.verifyProvider(request)
+	if urls := c.VerifyProviderRequests[2].PactURLs; len(urls) != 1 || urls[0] != two {
+		t.Fatalf("expected the changed pact to be verified but got %v", urls)
+	}
+
+	request.ProviderVersion = "1.0.1"
+	pact.verifyProvider(request)
+	if urls := c.VerifyProviderRequests[3].PactURLs; len(urls) != 2 {
+		t.Fatalf("expected both pacts to be verified for a new provider version but got %v", urls)
+	}
+}
+
+func TestVerificationCache_verifyProviderFailure(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pact-go-cache")
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	defer os.RemoveAll(dir)
+
+	one := filepath.Join(dir, "one.json")
+	ioutil.WriteFile(one, []byte(`{}`), 0644)
+
+	c := newMockClient()
+	c.VerifyProviderError = errors.New("verification failed")
+	pact := &Pact{pactClient: c}
+	request := types.VerifyRequest{PactURLs: []string{one}, ProviderVersion: "1.0.0", VerificationCacheDir: dir}
+
+	pact.verifyProvider(request)
+	pact.verifyProvider(request)
+
+	if len(c.VerifyProviderRequests) != 2 {
+		t.Fatal("expected a failed verification not to be cached")
+	}
+}
+
+func TestVerificationCache_Disabled(t *testing.T) {
+	c := newMockClient()
+	pact := &Pact{pactClient: c}
+	request := types.VerifyRequest{PactURLs: []string{"pacts/one.json"}}
+
+	pact.verifyProvider(request)
+	pact.verifyProvider(request)
+
+	if len(c.VerifyProviderRequests) != 2 {
+		t.Fatal("expected verifications not to be cached by default")
+	}
+}
+
+func TestVerificationCache_MissingProviderVersion(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pact-go-cache")
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	defer os.RemoveAll(dir)
+
+	one := filepath.Join(dir, "one.json")
+	ioutil.WriteFile(one, []byte(`{}`), 0644)
+
+	c := newMockClient()
+	c.VerifyProviderResponse = []types.ProviderVerifierResponse{verifierResponse(one)}
+	pact := &Pact{pactClient: c}
+	request := types.VerifyRequest{PactURLs: []string{one}, VerificationCacheDir: filepath.Join(dir, "cache")}
+
+	if _, err = pact.verifyProvider(request); !errors.Is(err, types.ErrInvalidRequest) {
+		t.Fatalf("expected an invalid request error without a ProviderVersion but got '%v'", err)
+	}
+	if len(c.VerifyProviderRequests) != 0 {
+		t.Fatal("expected the pact not to be verified")
+	}
+	if _, err = os.Stat(filepath.Join(dir, "cache")); !os.IsNotExist(err) {
+		t.Fatal("expected nothing to be cached")
+	}
+}
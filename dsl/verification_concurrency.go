@@ -0,0 +1,65 @@
+package dsl
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+// verifyPacts runs provider verification. When a Concurrency greater than 1 is
+// given, each of the PactURLs is verified by a separate verifier process, with
+// up to Concurrency running at once. Responses are returned in the order of the
+// PactURLs.
+func (p *Pact) verifyPacts(request types.VerifyRequest) ([]types.ProviderVerifierResponse, error) {
+	if request.Concurrency <= 1 || len(request.PactURLs) <= 1 {
+		return p.pactClient.VerifyProvider(request)
+	}
+
+	log.Printf("[DEBUG] pact provider verification: verifying %d pacts, %d at a time\n", len(request.PactURLs), request.Concurrency)
+
+	responses := make([][]types.ProviderVerifierResponse, len(request.PactURLs))
+	errs := make([]error, len(request.PactURLs))
+	workers := make(chan struct{}, request.Concurrency)
+
+	var wg sync.WaitGroup
+	for i, pactURL := range request.PactURLs {
+		wg.Add(1)
+		workers <- struct{}{}
+
+		go func(i int, pactURL string) {
+			defer wg.Done()
+			defer func() { <-workers }()
+
+			req := request
+			req.PactURLs = []string{pactURL}
+			req.Args = nil
+
+			responses[i], errs[i] = p.pactClient.VerifyProvider(req)
+		}(i, pactURL)
+	}
+	wg.Wait()
+
+	res := make([]types.ProviderVerifierResponse, 0)
+	var failures []error
+	var messages []string
+	for i, r := range responses {
+		res = append(res, r...)
+
+		if errs[i] != nil {
+			failures = append(failures, errs[i])
+			messages = append(messages, fmt.Sprintf("%s: %s", request.PactURLs[i], errs[i]))
+		}
+	}
+
+	switch len(failures) {
+	case 0:
+		return res, nil
+	case 1:
+		return res, failures[0]
+	}
+
+	return res, types.NewError(types.ErrVerification, fmt.Errorf("%d of %d pacts failed verification:\n\n%s", len(failures), len(request.PactURLs), strings.Join(messages, "\n\n")))
+}
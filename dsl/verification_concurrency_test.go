@@ -0,0 +1,104 @@
+package dsl
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+// concurrentClient records how many verifications run at once
+type concurrentClient struct {
+	*mockClient
+	mu      sync.Mutex
+	running int
+	max     int
+	fail    map[string]bool
+}
+
+func (c *concurrentClient) VerifyProvider(request types.VerifyRequest) ([]types.ProviderVerifierResponse, error) {
+	c.mu.Lock()
+	c.running++
+	if c.running > c.max {
+		c.max = c.running
+	}
+	c.mu.Unlock()
+
+	time.Sleep(20 * time.Millisecond)
+
+	c.mu.Lock()
+	c.running--
+	c.mu.Unlock()
+
+	pactURL := request.PactURLs[0]
+	if c.fail[pactURL] {
+		return []types.ProviderVerifierResponse{verifierResponse(pactURL)}, types.NewError(types.ErrVerification, errors.New("failed"))
+	}
+
+	return []types.ProviderVerifierResponse{verifierResponse(pactURL)}, nil
+}
+
+func TestVerificationConcurrency_verifyPacts(t *testing.T) {
+	c := &concurrentClient{mockClient: newMockClient()}
+	pact := &Pact{pactClient: c}
+
+	var pactURLs []string
+	for i := 0; i < 6; i++ {
+		pactURLs = append(pactURLs, fmt.Sprintf("pacts/%d.json", i))
+	}
+
+	res, err := pact.verifyPacts(types.VerifyRequest{PactURLs: pactURLs, Concurrency: 2})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	if c.max != 2 {
+		t.Fatalf("expected 2 verifications to run at once but got %d", c.max)
+	}
+	if len(res) != 6 {
+		t.Fatalf("expected 6 responses but got %d", len(res))
+	}
+	for i, r := range res {
+		if r.Examples[0].Pact.URL != pactURLs[i] {
+			t.Fatalf("expected responses in the order of the pacts, but got '%s' at %d", r.Examples[0].Pact.URL, i)
+		}
+	}
+}
+
+func TestVerificationConcurrency_verifyPactsFailure(t *testing.T) {
+	c := &concurrentClient{
+		mockClient: newMockClient(),
+		fail:       map[string]bool{"pacts/1.json": true, "pacts/2.json": true},
+	}
+	pact := &Pact{pactClient: c}
+
+	res, err := pact.verifyPacts(types.VerifyRequest{
+		PactURLs:    []string{"pacts/0.json", "pacts/1.json", "pacts/2.json"},
+		Concurrency: 3,
+	})
+
+	if !errors.Is(err, types.ErrVerification) {
+		t.Fatalf("expected a verification error but got '%v'", err)
+	}
+	if !strings.Contains(err.Error(), "2 of 3 pacts failed") || !strings.Contains(err.Error(), "pacts/2.json") {
+		t.Fatalf("unexpected error '%v'", err)
+	}
+	if len(res) != 3 {
+		t.Fatalf("expected all responses to be returned but got %d", len(res))
+	}
+}
+
+func TestVerificationConcurrency_Sequential(t *testing.T) {
+	c := newMockClient()
+	pact := &Pact{pactClient: c}
+
+	pact.verifyPacts(types.VerifyRequest{PactURLs: []string{"pacts/0.json", "pacts/1.json"}})
+
+	if len(c.VerifyProviderRequests) != 1 || len(c.VerifyProviderRequests[0].PactURLs) != 2 {
+		t.Fatal("expected pacts to be verified by a single verifier by default")
+	}
+}
@@ -0,0 +1,260 @@
+package dsl
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"strings"
+
+	"github.com/ray-xu-deltatre/pact-go/broker"
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+// VerificationPlan describes what verifying a provider would do, without
+// replaying any requests, see PlanVerification
+type VerificationPlan struct {
+	Pacts []PlannedPact
+}
+
+// PlannedPact is a pact that would be verified
+type PlannedPact struct {
+	// URL (or path) of the pact
+	URL string
+
+	Consumer string
+	Provider string
+
+	// Pending and WIP are as per the broker: failures of pending or WIP pacts
+	// do not fail verification
+	Pending bool
+	WIP     bool
+
+	// Notices explain why the broker selected the pact
+	Notices []string
+
+	Interactions []PlannedInteraction
+}
+
+// PlannedInteraction is an interaction that would be verified
+type PlannedInteraction struct {
+	Description string
+
+	// States are the provider states that would be set up, and
+	// MissingStateHandlers those without a handler in the StateHandlers (when
+	// there is no ProviderStatesSetupURL)
+	States               []string
+	MissingStateHandlers []string
+}
+
+// plannedPactFile is the content of a pact needed to plan its verification
+type plannedPactFile struct {
+	Consumer     PactName `json:"consumer"`
+	Provider     PactName `json:"provider"`
+	Interactions []struct {
+		Description    string        `json:"description"`
+		ProviderState  string        `json:"providerState"`
+		ProviderStates []types.State `json:"providerStates"`
+	} `json:"interactions"`
+}
+
+// PlanVerification resolves the pacts the request would verify, from the
+// PactURLs or else the broker (as per its selectors, pending and WIP
+// settings), and lists their interactions and the state handlers that would
+// be invoked, without replaying any requests or publishing results. It is
+// intended for reviewing changes to the configuration of a verification, e.g.
+// in CI, and does not need the provider to be running.
+func (p *Pact) PlanVerification(request types.VerifyRequest) (*VerificationPlan, error) {
+	p.setupLogging()
+	log.Println("[DEBUG] pact provider verification plan")
+
+	client := verifyRequestBroker(request)
+
+	var pacts []PlannedPact
+	switch {
+	case len(request.PactURLs) > 0:
+		for _, url := range request.PactURLs {
+			pacts = append(pacts, PlannedPact{URL: url})
+		}
+	case request.BrokerURL != "":
+		var err error
+		if pacts, err = p.brokerPactsForVerification(client, request); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, types.NewError(types.ErrInvalidRequest, fmt.Errorf("one of 'PactURLs' or 'BrokerURL' must be specified"))
+	}
+
+	plan := &VerificationPlan{}
+	for _, pact := range pacts {
+		content, err := readPlannedPact(client, pact.URL)
+		if err != nil {
+			return nil, err
+		}
+
+		pact.Consumer = content.Consumer.Name
+		pact.Provider = content.Provider.Name
+		for _, i := range content.Interactions {
+			interaction := PlannedInteraction{Description: i.Description}
+			if i.ProviderState != "" {
+				interaction.States = append(interaction.States, i.ProviderState)
+			}
+			for _, state := range i.ProviderStates {
+				interaction.States = append(interaction.States, state.Name)
+			}
+			for _, state := range interaction.States {
+				if _, ok := request.StateHandlers[state]; !ok && request.ProviderStatesSetupURL == "" {
+					interaction.MissingStateHandlers = append(interaction.MissingStateHandlers, state)
+				}
+			}
+			pact.Interactions = append(pact.Interactions, interaction)
+		}
+
+		plan.Pacts = append(plan.Pacts, pact)
+	}
+
+	return plan, nil
+}
+
+// verifyRequestBroker returns a client for the broker of the request
+func verifyRequestBroker(request types.VerifyRequest) *broker.Client {
+	return &broker.Client{
+		BrokerURL:      request.BrokerURL,
+		BrokerUsername: request.BrokerUsername,
+		BrokerPassword: request.BrokerPassword,
+		BrokerToken:    request.BrokerToken,
+		TokenSource:    request.BrokerTokenSource,
+	}
+}
+
+// brokerPactsForVerification returns the pacts the broker selects for the
+// provider, as the Pact CLI tools would request them
+func (p *Pact) brokerPactsForVerification(client *broker.Client, request types.VerifyRequest) ([]PlannedPact, error) {
+	provider := request.Provider
+	if provider == "" {
+		provider = p.Provider
+	}
+	if provider == "" {
+		return nil, types.NewError(types.ErrInvalidRequest, fmt.Errorf("'Provider' is mandatory to fetch pacts from a broker"))
+	}
+
+	selectors := request.ConsumerVersionSelectors
+	if len(selectors) == 0 {
+		for _, tag := range request.Tags {
+			selectors = append(selectors, types.ConsumerVersionSelector{Tag: tag, Latest: true})
+		}
+	}
+
+	query := broker.PactsForVerificationRequest{
+		ConsumerVersionSelectors: selectors,
+		ProviderVersionTags:      request.ProviderTags,
+		IncludePendingStatus:     request.EnablePending,
+	}
+	if request.IncludeWIPPactsSince != nil {
+		query.IncludeWipPactsSince = request.IncludeWIPPactsSince.Format("2006-01-02")
+	}
+
+	var pacts []PlannedPact
+	it := client.PactsForVerification(provider, query)
+	for it.Next() {
+		pact := it.Pact()
+		planned := PlannedPact{
+			URL:     pact.URL(),
+			Pending: pact.VerificationProperties.Pending,
+			WIP:     pact.VerificationProperties.Wip,
+		}
+		for _, notice := range pact.VerificationProperties.Notices {
+			planned.Notices = append(planned.Notices, notice.Text)
+		}
+		pacts = append(pacts, planned)
+	}
+
+	return pacts, it.Err()
+}
+
+// readPlannedPact reads the pact at the path or URL, fetching URLs with the
+// credentials of the broker
+func readPlannedPact(client *broker.Client, url string) (*plannedPactFile, error) {
+	var content plannedPactFile
+
+	if strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://") {
+		fetch := *client
+		if fetch.BrokerURL == "" {
+			fetch.BrokerURL = url
+		}
+		pact, err := fetch.GetPactAt(url)
+		if err != nil {
+			return nil, err
+		}
+		if err = json.Unmarshal(pact.Content, &content); err != nil {
+			return nil, types.NewError(types.ErrBroker, fmt.Errorf("unable to parse pact '%s': %v", url, err))
+		}
+
+		return &content, nil
+	}
+
+	data, err := ioutil.ReadFile(url)
+	if err != nil {
+		return nil, types.NewError(types.ErrInvalidRequest, fmt.Errorf("unable to read pact '%s': %v", url, err))
+	}
+	if err = json.Unmarshal(data, &content); err != nil {
+		return nil, types.NewError(types.ErrInvalidRequest, fmt.Errorf("unable to parse pact '%s': %v", url, err))
+	}
+
+	return &content, nil
+}
+
+// String describes the plan e.g.
+//
+//	Pact between MyConsumer and MyProvider (pending): https://broker/pacts/...
+//	  - A request for user 10
+//	      given "User 10 exists"
+//	      given "User 10 is an admin" (no state handler)
+func (plan *VerificationPlan) String() string {
+	if len(plan.Pacts) == 0 {
+		return "No pacts would be verified\n"
+	}
+
+	var b strings.Builder
+	for _, pact := range plan.Pacts {
+		var status []string
+		if pact.Pending {
+			status = append(status, "pending")
+		}
+		if pact.WIP {
+			status = append(status, "WIP")
+		}
+		fmt.Fprintf(&b, "Pact between %s and %s", pact.Consumer, pact.Provider)
+		if len(status) > 0 {
+			fmt.Fprintf(&b, " (%s)", strings.Join(status, ", "))
+		}
+		fmt.Fprintf(&b, ": %s\n", pact.URL)
+
+		for _, notice := range pact.Notices {
+			fmt.Fprintf(&b, "  %s\n", notice)
+		}
+
+		for _, i := range pact.Interactions {
+			fmt.Fprintf(&b, "  - %s\n", i.Description)
+			for _, state := range i.States {
+				fmt.Fprintf(&b, "      given %q", state)
+				if containsString(i.MissingStateHandlers, state) {
+					b.WriteString(" (no state handler)")
+				}
+				b.WriteString("\n")
+			}
+		}
+	}
+
+	return b.String()
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
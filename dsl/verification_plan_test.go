@@ -0,0 +1,106 @@
+package dsl
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+const plannedPact = `{
+  "consumer": {"name": "Billy"},
+  "provider": {"name": "Bobby"},
+  "interactions": [
+    {"description": "A request for user 10", "providerState": "User 10 exists"},
+    {"description": "A request for the admins", "providerStates": [{"name": "User 10 exists"}, {"name": "User 10 is an admin"}]},
+    {"description": "A request for the status"}
+  ]
+}`
+
+func TestPlanVerification_PactURLs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pact-go-plan")
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "billy-bobby.json")
+	if err = ioutil.WriteFile(file, []byte(plannedPact), 0644); err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	pact := &Pact{LogLevel: "ERROR"}
+	plan, err := pact.PlanVerification(types.VerifyRequest{
+		PactURLs: []string{file},
+		StateHandlers: types.StateHandlers{
+			"User 10 exists": func() error { return nil },
+		},
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	want := fmt.Sprintf(`Pact between Billy and Bobby: %s
+  - A request for user 10
+      given "User 10 exists"
+  - A request for the admins
+      given "User 10 exists"
+      given "User 10 is an admin" (no state handler)
+  - A request for the status
+`, file)
+	if plan.String() != want {
+		t.Fatalf("expected the plan:\n%s\nbut got:\n%s", want, plan)
+	}
+
+	if _, err = pact.PlanVerification(types.VerifyRequest{PactURLs: []string{filepath.Join(dir, "missing.json")}}); !errors.Is(err, types.ErrInvalidRequest) {
+		t.Fatalf("expected a missing pact to be an invalid request but got '%v'", err)
+	}
+}
+
+func TestPlanVerification_Broker(t *testing.T) {
+	var query map[string]interface{}
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			fmt.Fprintf(w, `{"_links":{"pb:provider-pacts-for-verification":{"href":"%s/pacts/provider/{provider}/for-verification","templated":true}}}`, server.URL)
+		case "/pacts/provider/Bobby/for-verification":
+			json.NewDecoder(r.Body).Decode(&query)
+			fmt.Fprintf(w, `{"_embedded":{"pacts":[{"verificationProperties":{"pending":true,"notices":[{"when":"before_verification","text":"This pact is pending"}]},"_links":{"self":{"href":"%s/pacts/1"}}}]}}`, server.URL)
+		case "/pacts/1":
+			fmt.Fprint(w, plannedPact)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	pact := &Pact{Provider: "Bobby", LogLevel: "ERROR"}
+	plan, err := pact.PlanVerification(types.VerifyRequest{
+		BrokerURL:              server.URL,
+		Tags:                   []string{"main"},
+		EnablePending:          true,
+		ProviderStatesSetupURL: "http://localhost:8000/setup",
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	if fmt.Sprint(query["consumerVersionSelectors"]) != "[map[all:false latest:true pacticipant: tag:main version:]]" || query["includePendingStatus"] != true {
+		t.Fatalf("unexpected pacts for verification query %v", query)
+	}
+	if len(plan.Pacts) != 1 || !plan.Pacts[0].Pending || len(plan.Pacts[0].Interactions) != 3 {
+		t.Fatalf("unexpected plan %+v", plan)
+	}
+	if out := plan.String(); !strings.HasPrefix(out, "Pact between Billy and Bobby (pending): "+server.URL+"/pacts/1\n  This pact is pending\n") || strings.Contains(out, "no state handler") {
+		t.Fatalf("unexpected plan:\n%s", out)
+	}
+}
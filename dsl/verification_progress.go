@@ -0,0 +1,63 @@
+package dsl
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ray-xu-deltatre/pact-go/proxy"
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+// progressMiddleware reports each request replayed against the provider
+func progressMiddleware(handler types.ProgressHandler) proxy.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != providerStatesSetupPath {
+				handler(types.VerificationEvent{
+					Type:        types.VerificationStarted,
+					Description: r.Method + " " + r.URL.Path,
+				})
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// reportProgress sends an event for each interaction in a line of verifier
+// output, which contains the results of a single pact
+func reportProgress(handler types.ProgressHandler, line string) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.Index(line, "INFO") == 0 {
+		return
+	}
+
+	var res types.ProviderVerifierResponse
+	if err := json.Unmarshal([]byte(line), &res); err != nil {
+		return
+	}
+
+	for _, example := range res.Examples {
+		event := types.VerificationEvent{
+			Type:        types.VerificationPassed,
+			Consumer:    example.Pact.ConsumerName,
+			Provider:    example.Pact.ProviderName,
+			Description: example.Description,
+			Duration:    time.Duration(example.RunTime * float64(time.Second)),
+		}
+
+		switch example.Status {
+		case "passed":
+		case "pending":
+			event.Type = types.VerificationPending
+			event.Message = example.Exception.Message
+		default:
+			event.Type = types.VerificationFailed
+			event.Message = example.Exception.Message
+		}
+
+		handler(event)
+	}
+}
@@ -0,0 +1,52 @@
+package dsl
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+func TestVerificationProgress_reportProgress(t *testing.T) {
+	var events []types.VerificationEvent
+	handler := func(e types.VerificationEvent) {
+		events = append(events, e)
+	}
+
+	reportProgress(handler, "INFO: reading pact")
+	reportProgress(handler, "")
+	reportProgress(handler, `{"examples":[
+		{"description":"A request to login","status":"passed","run_time":0.25,"pact":{"consumer_name":"billy","provider_name":"bobby"}},
+		{"description":"A request to logout","status":"failed","run_time":1,"exception":{"message":"expected 200"}},
+		{"description":"A new request","status":"pending","exception":{"message":"expected 201"}}
+	]}`)
+
+	if len(events) != 3 {
+		t.Fatalf("expected 3 events but got %d", len(events))
+	}
+
+	if e := events[0]; e.Type != types.VerificationPassed || e.Consumer != "billy" || e.Description != "A request to login" || e.Duration != 250*time.Millisecond {
+		t.Fatalf("unexpected event %+v", e)
+	}
+	if e := events[1]; e.Type != types.VerificationFailed || e.Message != "expected 200" || e.Duration != time.Second {
+		t.Fatalf("unexpected event %+v", e)
+	}
+	if e := events[2]; e.Type != types.VerificationPending || e.Message != "expected 201" {
+		t.Fatalf("unexpected event %+v", e)
+	}
+}
+
+func TestVerificationProgress_progressMiddleware(t *testing.T) {
+	var events []types.VerificationEvent
+	handler := progressMiddleware(func(e types.VerificationEvent) {
+		events = append(events, e)
+	})(dummyHandler("X-Dummy-Handler"))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", providerStatesSetupPath, nil))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/users/1", nil))
+
+	if len(events) != 1 || events[0].Type != types.VerificationStarted || events[0].Description != "GET /users/1" {
+		t.Fatalf("expected a started event for the provider request only, got %+v", events)
+	}
+}
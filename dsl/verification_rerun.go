@@ -0,0 +1,345 @@
+package dsl
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+// failureReport records the interactions that failed a provider verification,
+// so that only those can be verified again with RerunFailed
+type failureReport struct {
+	Provider        string              `json:"provider,omitempty"`
+	ProviderVersion string              `json:"providerVersion,omitempty"`
+	Failures        []failedInteraction `json:"failures"`
+}
+
+// failedInteraction is an interaction of a pact that failed verification
+type failedInteraction struct {
+	PactURL        string   `json:"pactUrl"`
+	Consumer       string   `json:"consumer,omitempty"`
+	Description    string   `json:"description,omitempty"`
+	ProviderStates []string `json:"providerStates,omitempty"`
+	Mismatches     []string `json:"mismatches,omitempty"`
+}
+
+// rerunPactFile is the subset of a pact file needed to find its interactions
+type rerunPactFile struct {
+	Interactions []json.RawMessage `json:"interactions"`
+}
+
+// rawInteraction is the description and provider states of an interaction in a
+// pact file
+type rawInteraction struct {
+	Description    string
+	ProviderStates []string
+}
+
+// verifyWithFailureReport runs provider verification of only the interactions
+// in the FailureReportFile if RerunFailed is set, and writes the interactions
+// that fail to the FailureReportFile if one is given
+func (p *Pact) verifyWithFailureReport(request types.VerifyRequest) ([]types.ProviderVerifierResponse, error) {
+	if request.FailureReportFile == "" {
+		if request.RerunFailed {
+			return nil, types.NewError(types.ErrInvalidRequest, fmt.Errorf("'FailureReportFile' is mandatory to verify only the failed interactions"))
+		}
+		return p.verifyProvider(request)
+	}
+
+	originals := make(map[string]string)
+	if request.RerunFailed {
+		report, err := readFailureReport(request.FailureReportFile)
+		if err != nil {
+			return nil, types.NewError(types.ErrInvalidRequest, err)
+		}
+		if len(report.Failures) == 0 {
+			log.Printf("[INFO] no failed interactions to verify again in '%s'\n", request.FailureReportFile)
+			return []types.ProviderVerifierResponse{}, nil
+		}
+
+		dir, err := ioutil.TempDir("", "pact-rerun")
+		if err != nil {
+			return nil, err
+		}
+		defer os.RemoveAll(dir)
+
+		request.PactURLs, originals, err = report.pactFiles(dir)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("[INFO] verifying the %d failed interactions of %d pacts again\n", len(report.Failures), len(request.PactURLs))
+
+		// The pacts to verify are known, so don't fetch them by selectors again,
+		// nor cache the verification of a subset of their interactions
+		request.ConsumerVersionSelectors = nil
+		request.Tags = nil
+		request.IncludeWIPPactsSince = nil
+		request.Args = nil
+		request.OnlyChangedPacts = false
+		request.VerificationCacheDir = ""
+	}
+
+	res, err := p.verifyProvider(request)
+
+	for i := range res {
+		for j := range res[i].Examples {
+			if original, ok := originals[res[i].Examples[j].Pact.URL]; ok {
+				res[i].Examples[j].Pact.URL = original
+			}
+		}
+	}
+
+	report := newFailureReport(request, res)
+	if writeErr := report.write(request.FailureReportFile); writeErr != nil {
+		log.Println("[WARN] unable to write the failure report:", writeErr)
+	} else if len(report.Failures) > 0 {
+		log.Printf("[INFO] wrote %d failed interactions to '%s'\n", len(report.Failures), request.FailureReportFile)
+	}
+
+	return res, err
+}
+
+// newFailureReport returns the report of the interactions that failed in the
+// responses. Failed examples of pacts that can't be read are reported by their
+// full description.
+func newFailureReport(request types.VerifyRequest, res []types.ProviderVerifierResponse) *failureReport {
+	report := &failureReport{
+		Provider:        request.Provider,
+		ProviderVersion: request.ProviderVersion,
+		Failures:        []failedInteraction{},
+	}
+
+	interactions := make(map[string][]rawInteraction)
+	reported := make(map[string]int)
+
+	for _, r := range res {
+		for _, example := range r.Examples {
+			if example.Status != "failed" || example.Pact.URL == "" {
+				continue
+			}
+
+			pactURL := example.Pact.URL
+			if _, ok := interactions[pactURL]; !ok {
+				interactions[pactURL] = readRawInteractions(pactURL)
+			}
+
+			failure := failedInteraction{
+				PactURL:     pactURL,
+				Consumer:    example.Pact.ConsumerName,
+				Description: example.FullDescription,
+			}
+			if interaction, ok := exampleInteraction(example.FullDescription, interactions[pactURL]); ok {
+				failure.Description = interaction.Description
+				failure.ProviderStates = interaction.ProviderStates
+			}
+
+			key := pactURL + "\x00" + interactionKey(failure.Description, failure.ProviderStates...)
+			i, ok := reported[key]
+			if !ok {
+				i = len(report.Failures)
+				reported[key] = i
+				report.Failures = append(report.Failures, failure)
+			}
+			report.Failures[i].Mismatches = append(report.Failures[i].Mismatches, example.Mismatches...)
+		}
+	}
+
+	return report
+}
+
+// readRawInteractions returns the interactions of a local pact file, or none if
+// it is fetched from a remote URL or can't be read
+func readRawInteractions(pactURL string) []rawInteraction {
+	if strings.HasPrefix(pactURL, "http://") || strings.HasPrefix(pactURL, "https://") {
+		return nil
+	}
+
+	content, err := ioutil.ReadFile(pactURL)
+	if err != nil {
+		return nil
+	}
+
+	var pact rerunPactFile
+	if err = json.Unmarshal(content, &pact); err != nil {
+		return nil
+	}
+
+	interactions := make([]rawInteraction, 0, len(pact.Interactions))
+	for _, raw := range pact.Interactions {
+		var interaction struct {
+			Description         string `json:"description"`
+			ProviderState       string `json:"providerState"`
+			LegacyProviderState string `json:"provider_state"`
+			ProviderStates      []struct {
+				Name string `json:"name"`
+			} `json:"providerStates"`
+		}
+		json.Unmarshal(raw, &interaction)
+
+		var states []string
+		for _, s := range []string{interaction.ProviderState, interaction.LegacyProviderState} {
+			if s != "" {
+				states = append(states, s)
+			}
+		}
+		for _, s := range interaction.ProviderStates {
+			states = append(states, s.Name)
+		}
+		interactions = append(interactions, rawInteraction{Description: interaction.Description, ProviderStates: states})
+	}
+
+	return interactions
+}
+
+// exampleInteraction returns the interaction that the example of the verifier
+// with the full description verified, e.g. "Verifying a pact between billy and
+// bobby Given user 1 exists A request for user 1 with GET /users/1 returns a
+// response which has status code 200". The interaction with the longest
+// description, then the most provider states, wins when more than one matches.
+func exampleInteraction(fullDescription string, interactions []rawInteraction) (rawInteraction, bool) {
+	full := strings.ToLower(fullDescription)
+
+	var found rawInteraction
+	ok := false
+	for _, interaction := range interactions {
+		// The verifier capitalises the description of interactions without a
+		// provider state
+		if !strings.Contains(full, " "+strings.ToLower(interaction.Description)+" with ") {
+			continue
+		}
+
+		matched := true
+		for _, state := range interaction.ProviderStates {
+			if !strings.Contains(full, "given "+strings.ToLower(state)) {
+				matched = false
+				break
+			}
+		}
+
+		better := len(interaction.Description) > len(found.Description) ||
+			(len(interaction.Description) == len(found.Description) && len(interaction.ProviderStates) > len(found.ProviderStates))
+		if matched && (!ok || better) {
+			found, ok = interaction, true
+		}
+	}
+
+	return found, ok
+}
+
+// readFailureReport reads the failure report written by a previous verification
+func readFailureReport(path string) (*failureReport, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the failure report: %v", err)
+	}
+
+	var report failureReport
+	if err = json.Unmarshal(content, &report); err != nil {
+		return nil, fmt.Errorf("unable to parse the failure report '%s': %v", path, err)
+	}
+
+	return &report, nil
+}
+
+// write writes the report to path
+func (r *failureReport) write(path string) error {
+	content, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if dir := filepath.Dir(path); dir != "" {
+		if err = os.MkdirAll(dir, os.ModePerm); err != nil {
+			return err
+		}
+	}
+
+	return writeFileAtomic(path, content, 0644)
+}
+
+// pactFiles writes a copy of each local pact with failed interactions to dir,
+// with only those interactions, returning the pacts to verify and the original
+// pact of each copy. Pacts fetched from a remote URL, or whose failures aren't
+// known by interaction, are verified again in full.
+func (r *failureReport) pactFiles(dir string) (pactURLs []string, originals map[string]string, err error) {
+	originals = make(map[string]string)
+
+	failed := make(map[string]map[string]bool)
+	var order []string
+	for _, f := range r.Failures {
+		if _, ok := failed[f.PactURL]; !ok {
+			failed[f.PactURL] = make(map[string]bool)
+			order = append(order, f.PactURL)
+		}
+		failed[f.PactURL][interactionKey(f.Description, f.ProviderStates...)] = true
+	}
+
+	for n, pactURL := range order {
+		copied, err := filterPactInteractions(pactURL, failed[pactURL], filepath.Join(dir, fmt.Sprintf("%d-%s", n, filepath.Base(pactURL))))
+		if err != nil {
+			return nil, nil, err
+		}
+		if copied == "" {
+			log.Printf("[INFO] verifying all interactions of '%s' again\n", pactURL)
+			pactURLs = append(pactURLs, pactURL)
+			continue
+		}
+
+		pactURLs = append(pactURLs, copied)
+		originals[copied] = pactURL
+	}
+
+	return pactURLs, originals, nil
+}
+
+// filterPactInteractions writes a copy of the local pact with only the
+// interactions with the given keys to path, returning the path, or "" if the
+// pact is remote or any of the keys isn't that of one of its interactions
+func filterPactInteractions(pactURL string, keys map[string]bool, path string) (string, error) {
+	if strings.HasPrefix(pactURL, "http://") || strings.HasPrefix(pactURL, "https://") {
+		return "", nil
+	}
+
+	content, err := ioutil.ReadFile(pactURL)
+	if err != nil {
+		return "", types.NewError(types.ErrInvalidRequest, fmt.Errorf("unable to read pact '%s' to verify its failed interactions again: %v", pactURL, err))
+	}
+
+	var pact map[string]json.RawMessage
+	var file rerunPactFile
+	if err = json.Unmarshal(content, &pact); err == nil {
+		err = json.Unmarshal(content, &file)
+	}
+	if err != nil {
+		return "", types.NewError(types.ErrInvalidRequest, fmt.Errorf("unable to parse pact '%s': %v", pactURL, err))
+	}
+
+	var interactions []json.RawMessage
+	found := make(map[string]bool)
+	for _, raw := range file.Interactions {
+		if key := rawInteractionKey(raw); keys[key] {
+			interactions = append(interactions, raw)
+			found[key] = true
+		}
+	}
+	if len(found) < len(keys) {
+		return "", nil
+	}
+
+	pact["interactions"], err = json.Marshal(interactions)
+	if err != nil {
+		return "", err
+	}
+	filtered, err := json.MarshalIndent(pact, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return path, ioutil.WriteFile(path, filtered, 0644)
+}
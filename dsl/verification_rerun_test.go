@@ -0,0 +1,188 @@
+package dsl
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+// brokenInteractionsClient verifies each interaction of the pacts, failing
+// those with the broken descriptions
+type brokenInteractionsClient struct {
+	*mockClient
+	broken   map[string]bool
+	verified []string
+}
+
+func (c *brokenInteractionsClient) VerifyProvider(request types.VerifyRequest) ([]types.ProviderVerifierResponse, error) {
+	c.VerifyProviderRequests = append(c.VerifyProviderRequests, request)
+
+	var res []types.ProviderVerifierResponse
+	var err error
+	for _, pactURL := range request.PactURLs {
+		for _, interaction := range readRawInteractions(pactURL) {
+			c.verified = append(c.verified, interaction.Description)
+
+			status := "passed"
+			if c.broken[interaction.Description] {
+				status = "failed"
+				err = types.NewError(types.ErrVerification, errors.New("failed"))
+			}
+
+			full := "Verifying a pact between billy and bobby " + strings.Title(interaction.Description)
+			if len(interaction.ProviderStates) > 0 {
+				full = fmt.Sprintf("Verifying a pact between billy and bobby Given %s %s", interaction.ProviderStates[0], interaction.Description)
+			}
+			full += " with GET /foo returns a response which has status code 200"
+
+			var r types.ProviderVerifierResponse
+			json.Unmarshal([]byte(fmt.Sprintf(`{"examples":[{"full_description":%q,"status":%q,"mismatches":["expected 200"],"pact":{"consumer_name":"billy","url":%q}}]}`, full, status, pactURL)), &r)
+			res = append(res, r)
+		}
+	}
+
+	return res, err
+}
+
+func TestVerificationRerun_verifyWithFailureReport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pact-go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	pactFile := filepath.Join(dir, "billy-bobby.json")
+	pact := `{
+  "consumer": {"name": "billy"},
+  "provider": {"name": "bobby"},
+  "interactions": [
+    {"description": "a request for foo", "providerState": "foo exists", "request": {"method": "GET", "path": "/foo"}, "response": {"status": 200}},
+    {"description": "a request for foo", "request": {"method": "GET", "path": "/foo"}, "response": {"status": 404}},
+    {"description": "a request for bar", "request": {"method": "GET", "path": "/bar"}, "response": {"status": 200}}
+  ],
+  "metadata": {"pactSpecification": {"version": "2.0.0"}}
+}`
+	if err = ioutil.WriteFile(pactFile, []byte(pact), 0644); err != nil {
+		t.Fatal(err)
+	}
+	reportFile := filepath.Join(dir, "reports", "failures.json")
+
+	c := &brokenInteractionsClient{
+		mockClient: newMockClient(),
+		broken:     map[string]bool{"a request for foo": true},
+	}
+	p := &Pact{pactClient: c}
+	request := types.VerifyRequest{
+		PactURLs:          []string{pactFile},
+		Provider:          "bobby",
+		FailureReportFile: reportFile,
+	}
+
+	if _, err = p.verifyWithFailureReport(request); !errors.Is(err, types.ErrVerification) {
+		t.Fatalf("expected a verification error but got '%v'", err)
+	}
+
+	report, err := readFailureReport(reportFile)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(report.Failures) != 2 || report.Provider != "bobby" {
+		t.Fatalf("expected both interactions for foo to be reported: %+v", report)
+	}
+	if f := report.Failures[0]; f.PactURL != pactFile || f.Consumer != "billy" || f.Description != "a request for foo" ||
+		len(f.ProviderStates) != 1 || f.ProviderStates[0] != "foo exists" || len(f.Mismatches) != 1 {
+		t.Fatalf("expected the interaction given foo exists to be reported: %+v", f)
+	}
+	if f := report.Failures[1]; f.Description != "a request for foo" || len(f.ProviderStates) != 0 {
+		t.Fatalf("expected the interaction without a provider state to be reported: %+v", f)
+	}
+
+	// Fix the interaction given foo exists, and verify only the failures again
+	c.broken = map[string]bool{}
+	c.verified = nil
+	request.RerunFailed = true
+	request.Tags = []string{"master"}
+
+	res, err := p.verifyWithFailureReport(request)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(c.verified) != 2 || c.verified[0] != "a request for foo" || c.verified[1] != "a request for foo" {
+		t.Fatalf("expected only the failed interactions to be verified again: %v", c.verified)
+	}
+	rerun := c.VerifyProviderRequests[1]
+	if len(rerun.PactURLs) != 1 || rerun.PactURLs[0] == pactFile || len(rerun.Tags) != 0 {
+		t.Fatalf("expected a copy of the pact with only the failed interactions to be verified: %+v", rerun)
+	}
+	if len(res) != 2 || res[0].Examples[0].Pact.URL != pactFile {
+		t.Fatalf("expected the results to refer to the original pact: %+v", res)
+	}
+
+	if report, err = readFailureReport(reportFile); err != nil || len(report.Failures) != 0 {
+		t.Fatalf("expected the report to be emptied once the interactions pass: %+v, %v", report, err)
+	}
+
+	// Nothing left to verify
+	c.verified = nil
+	if res, err = p.verifyWithFailureReport(request); err != nil || len(res) != 0 || len(c.verified) != 0 {
+		t.Fatalf("expected nothing to be verified without failures: %+v, %v", res, err)
+	}
+}
+
+func TestVerificationRerun_verifyWithFailureReportMissingReport(t *testing.T) {
+	p := &Pact{pactClient: newMockClient()}
+
+	_, err := p.verifyWithFailureReport(types.VerifyRequest{RerunFailed: true})
+	if !errors.Is(err, types.ErrInvalidRequest) {
+		t.Fatalf("expected an invalid request error without a failure report file but got '%v'", err)
+	}
+
+	_, err = p.verifyWithFailureReport(types.VerifyRequest{RerunFailed: true, FailureReportFile: "does/not/exist.json"})
+	if !errors.Is(err, types.ErrInvalidRequest) {
+		t.Fatalf("expected an invalid request error for a missing failure report but got '%v'", err)
+	}
+}
+
+func TestVerificationRerun_pactFilesRemote(t *testing.T) {
+	report := &failureReport{Failures: []failedInteraction{
+		{PactURL: "http://broker/pacts/provider/bobby/consumer/billy/latest", Description: "a request for foo"},
+	}}
+
+	urls, originals, err := report.pactFiles(os.TempDir())
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(urls) != 1 || urls[0] != report.Failures[0].PactURL || len(originals) != 0 {
+		t.Fatalf("expected remote pacts to be verified in full: %v", urls)
+	}
+}
+
+func TestVerificationRerun_exampleInteraction(t *testing.T) {
+	interactions := []rawInteraction{
+		{Description: "a request for user"},
+		{Description: "a request for user 1"},
+		{Description: "a request for user 1", ProviderStates: []string{"user 1 exists"}},
+	}
+
+	for full, want := range map[string]rawInteraction{
+		"Verifying a pact between billy and bobby A request for user with GET /users returns a response which has status code 200":                         interactions[0],
+		"Verifying a pact between billy and bobby A request for user 1 with GET /users/1 returns a response which has status code 404":                     interactions[1],
+		"Verifying a pact between billy and bobby Given user 1 exists a request for user 1 with GET /users/1 returns a response which has a matching body": interactions[2],
+	} {
+		got, ok := exampleInteraction(full, interactions)
+		if !ok || got.Description != want.Description || len(got.ProviderStates) != len(want.ProviderStates) {
+			t.Fatalf("expected %+v for '%s' but got %+v", want, full, got)
+		}
+	}
+
+	if _, ok := exampleInteraction("Verifying a pact between billy and bobby A request for bar with GET /bar", interactions); ok {
+		t.Fatal("expected no interaction for an unknown description")
+	}
+}
@@ -0,0 +1,108 @@
+package dsl
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+// verifyWithRetries verifies the pacts, verifying those with failed
+// interactions again up to request.Retries times. Interactions that fail and
+// then pass are marked as flaky.
+func (p *Pact) verifyWithRetries(request types.VerifyRequest) ([]types.ProviderVerifierResponse, error) {
+	// The verifier publishes the results of each attempt, so a failure that
+	// then passes when retried would be recorded by the broker too
+	if request.Retries > 0 && request.PublishVerificationResults {
+		return nil, types.NewError(types.ErrInvalidRequest, errors.New("'Retries' cannot be used with 'PublishVerificationResults', as the results of each attempt would be published"))
+	}
+
+	res, err := p.verifyPacts(request)
+
+	for attempt := 1; err != nil && attempt <= request.Retries; attempt++ {
+		failed := failedPactURLs(res)
+
+		// Errors outside of the interactions, e.g. a missing pact, are not
+		// timing issues
+		if len(failed) == 0 {
+			break
+		}
+
+		log.Printf("[INFO] pact provider verification: retrying %d pacts with failed interactions in %v (attempt %d of %d)\n", len(failed), request.RetryDelay, attempt, request.Retries)
+		time.Sleep(request.RetryDelay)
+
+		// The pacts to retry are known, so don't fetch them by selectors again
+		retry := request
+		retry.PactURLs = failed
+		retry.ConsumerVersionSelectors = nil
+		retry.Tags = nil
+		retry.IncludeWIPPactsSince = nil
+		retry.Args = nil
+
+		var again []types.ProviderVerifierResponse
+		again, err = p.verifyPacts(retry)
+		res = mergeRetriedResponses(res, again)
+	}
+
+	return res, err
+}
+
+// failedPactURLs returns the URLs of the pacts with failed interactions
+func failedPactURLs(res []types.ProviderVerifierResponse) []string {
+	var urls []string
+	for _, r := range res {
+		for _, example := range r.Examples {
+			if example.Status == "failed" && example.Pact.URL != "" {
+				urls = append(urls, example.Pact.URL)
+				break
+			}
+		}
+	}
+
+	return urls
+}
+
+// mergeRetriedResponses replaces the responses of the retried pacts in res with
+// those of the retry, marking interactions that failed before but now pass as
+// flaky
+func mergeRetriedResponses(res []types.ProviderVerifierResponse, retried []types.ProviderVerifierResponse) []types.ProviderVerifierResponse {
+	merged := make([]types.ProviderVerifierResponse, len(res))
+	copy(merged, res)
+
+	for _, r := range retried {
+		for i, previous := range merged {
+			if pactURLOf(previous) == "" || pactURLOf(previous) != pactURLOf(r) {
+				continue
+			}
+
+			flaky := map[string]bool{}
+			for _, example := range previous.Examples {
+				if example.Status == "failed" || example.Flaky {
+					flaky[example.FullDescription] = true
+				}
+			}
+
+			r.Summary.FlakyCount = 0
+			for j, example := range r.Examples {
+				if example.Status == "passed" && flaky[example.FullDescription] {
+					r.Examples[j].Flaky = true
+					r.Summary.FlakyCount++
+				}
+			}
+			merged[i] = r
+			break
+		}
+	}
+
+	return merged
+}
+
+// pactURLOf returns the URL of the pact verified in the response
+func pactURLOf(r types.ProviderVerifierResponse) string {
+	if len(r.Examples) == 0 {
+		return ""
+	}
+
+	return r.Examples[0].Pact.URL
+}
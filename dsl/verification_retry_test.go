@@ -0,0 +1,117 @@
+package dsl
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+// flakyClient fails the verification of each pact a number of times
+type flakyClient struct {
+	*mockClient
+	failures map[string]int
+}
+
+func (c *flakyClient) VerifyProvider(request types.VerifyRequest) ([]types.ProviderVerifierResponse, error) {
+	c.VerifyProviderRequests = append(c.VerifyProviderRequests, request)
+
+	var res []types.ProviderVerifierResponse
+	var err error
+	for _, pactURL := range request.PactURLs {
+		status := "passed"
+		if c.failures[pactURL] > 0 {
+			c.failures[pactURL]--
+			status = "failed"
+			err = types.NewError(types.ErrVerification, errors.New("failed"))
+		}
+
+		var r types.ProviderVerifierResponse
+		json.Unmarshal([]byte(fmt.Sprintf(`{"examples":[{"full_description":"a request","status":%q,"pact":{"url":%q}}]}`, status, pactURL)), &r)
+		res = append(res, r)
+	}
+
+	return res, err
+}
+
+func TestVerificationRetry_verifyWithRetries(t *testing.T) {
+	c := &flakyClient{
+		mockClient: newMockClient(),
+		failures:   map[string]int{"pacts/flaky.json": 2},
+	}
+	pact := &Pact{pactClient: c}
+
+	res, err := pact.verifyWithRetries(types.VerifyRequest{
+		PactURLs: []string{"pacts/stable.json", "pacts/flaky.json"},
+		Tags:     []string{"master"},
+		Retries:  2,
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	if len(c.VerifyProviderRequests) != 3 {
+		t.Fatalf("expected 3 verifications but got %d", len(c.VerifyProviderRequests))
+	}
+	retry := c.VerifyProviderRequests[1]
+	if len(retry.PactURLs) != 1 || retry.PactURLs[0] != "pacts/flaky.json" || len(retry.Tags) != 0 {
+		t.Fatalf("expected only the failed pact to be retried: %+v", retry)
+	}
+
+	if len(res) != 2 || res[0].Examples[0].Flaky || !res[1].Examples[0].Flaky || res[1].Summary.FlakyCount != 1 {
+		t.Fatalf("expected only the retried interaction to be flaky: %+v", res)
+	}
+}
+
+func TestVerificationRetry_verifyWithRetriesFailure(t *testing.T) {
+	c := &flakyClient{
+		mockClient: newMockClient(),
+		failures:   map[string]int{"pacts/broken.json": 3},
+	}
+	pact := &Pact{pactClient: c}
+
+	res, err := pact.verifyWithRetries(types.VerifyRequest{
+		PactURLs: []string{"pacts/broken.json"},
+		Retries:  2,
+	})
+	if !errors.Is(err, types.ErrVerification) {
+		t.Fatalf("expected a verification error once the retries are exhausted but got '%v'", err)
+	}
+	if len(res) != 1 || res[0].Examples[0].Status != "failed" || res[0].Examples[0].Flaky {
+		t.Fatalf("expected the interaction to fail: %+v", res)
+	}
+}
+
+func TestVerificationRetry_verifyWithoutRetries(t *testing.T) {
+	c := &flakyClient{
+		mockClient: newMockClient(),
+		failures:   map[string]int{"pacts/flaky.json": 1},
+	}
+	pact := &Pact{pactClient: c}
+
+	if _, err := pact.verifyWithRetries(types.VerifyRequest{PactURLs: []string{"pacts/flaky.json"}}); err == nil {
+		t.Fatal("expected an error without retries")
+	}
+	if len(c.VerifyProviderRequests) != 1 {
+		t.Fatalf("expected a single verification but got %d", len(c.VerifyProviderRequests))
+	}
+}
+
+func TestVerificationRetry_verifyWithRetriesPublishing(t *testing.T) {
+	c := &flakyClient{mockClient: newMockClient()}
+	pact := &Pact{pactClient: c}
+
+	_, err := pact.verifyWithRetries(types.VerifyRequest{
+		PactURLs:                   []string{"pacts/flaky.json"},
+		PublishVerificationResults: true,
+		Retries:                    2,
+	})
+	if !errors.Is(err, types.ErrInvalidRequest) {
+		t.Fatalf("expected retries to be rejected when publishing results but got '%v'", err)
+	}
+	if len(c.VerifyProviderRequests) != 0 {
+		t.Fatalf("expected no verification but got %d", len(c.VerifyProviderRequests))
+	}
+}
@@ -0,0 +1,74 @@
+package dsl
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+// changedPacts resolves the pacts the broker selects for verification,
+// returning a "reused" result for each that the ProviderVersion has already
+// verified successfully, and the URLs of the rest. ok is false if the pacts
+// should instead be fetched from the broker by the verifier as usual, e.g.
+// because some are pending.
+func (p *Pact) changedPacts(request types.VerifyRequest) (reused []types.ProviderVerifierResponse, remaining []string, ok bool, err error) {
+	if request.ProviderVersion == "" {
+		return nil, nil, false, types.NewError(types.ErrInvalidRequest, fmt.Errorf("'ProviderVersion' is mandatory to verify only changed pacts"))
+	}
+
+	client := verifyRequestBroker(request)
+	pacts, err := p.brokerPactsForVerification(client, request)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	for _, pact := range pacts {
+		if pact.Pending || pact.WIP {
+			log.Println("[INFO] verifying all pacts, as some are pending or WIP")
+			return nil, nil, false, nil
+		}
+	}
+
+	for _, pact := range pacts {
+		content, err := client.GetPactAt(pact.URL)
+		if err != nil {
+			return nil, nil, false, err
+		}
+
+		result, err := client.LatestVerificationResultOf(content)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		if result == nil || !result.Success || result.ProviderApplicationVersion != request.ProviderVersion {
+			remaining = append(remaining, pact.URL)
+			continue
+		}
+
+		var names plannedPactFile
+		json.Unmarshal(content.Content, &names)
+
+		log.Printf("[INFO] skipping verification of '%s', which version %s verified at %s\n", pact.URL, request.ProviderVersion, result.VerificationDate)
+		reused = append(reused, reusedVerification(names.Consumer.Name, names.Provider.Name, request.ProviderVersion, result.VerificationDate))
+	}
+
+	return reused, remaining, true, nil
+}
+
+// reusedVerification is the result returned in place of verifying a pact that
+// has already been verified
+func reusedVerification(consumer string, provider string, version string, verifiedAt string) types.ProviderVerifierResponse {
+	res := types.ProviderVerifierResponse{
+		SummaryLine: "0 examples, 0 failures (reused)",
+	}
+	res.Summary.Notices = append(res.Summary.Notices, struct {
+		Text string `json:"text"`
+		When string `json:"when"`
+	}{
+		Text: fmt.Sprintf("Reused the successful verification of the pact between %s and %s by version %s at %s, as the pact has not changed", consumer, provider, version, verifiedAt),
+		When: "before_verification",
+	})
+
+	return res
+}
@@ -0,0 +1,108 @@
+package dsl
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+func setupChangedPactsBroker(pending bool) *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			fmt.Fprintf(w, `{"_links":{"pb:provider-pacts-for-verification":{"href":"%s/pacts/provider/{provider}/for-verification","templated":true}}}`, server.URL)
+		case "/pacts/provider/Bobby/for-verification":
+			fmt.Fprintf(w, `{"_embedded":{"pacts":[{"verificationProperties":{"pending":%v},"_links":{"self":{"href":"%s/pacts/unchanged"}}},{"_links":{"self":{"href":"%s/pacts/changed"}}}]}}`, pending, server.URL, server.URL)
+		case "/pacts/unchanged", "/pacts/changed":
+			fmt.Fprintf(w, `{"consumer":{"name":"Billy"},"provider":{"name":"Bobby"},"_links":{"pb:latest-verification-results":{"href":"%s/results%s"}}}`, server.URL, strings.TrimPrefix(r.URL.Path, "/pacts"))
+		case "/results/unchanged":
+			fmt.Fprint(w, `{"success":true,"providerApplicationVersion":"1.0.0","verificationDate":"2020-01-01T00:00:00Z"}`)
+		case "/results/changed":
+			fmt.Fprint(w, `{"success":true,"providerApplicationVersion":"0.9.0"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	return server
+}
+
+func TestVerification_changedPacts(t *testing.T) {
+	server := setupChangedPactsBroker(false)
+	defer server.Close()
+
+	pact := &Pact{}
+	request := types.VerifyRequest{BrokerURL: server.URL, Provider: "Bobby", ProviderVersion: "1.0.0", OnlyChangedPacts: true}
+
+	reused, remaining, ok, err := pact.changedPacts(request)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if !ok || fmt.Sprint(remaining) != "["+server.URL+"/pacts/changed]" {
+		t.Fatalf("expected only the changed pact to be verified but got %v (%v)", remaining, ok)
+	}
+	if len(reused) != 1 || !strings.Contains(reused[0].Summary.Notices[0].Text, "Reused the successful verification of the pact between Billy and Bobby by version 1.0.0") {
+		t.Fatalf("expected a reused result for the unchanged pact but got %+v", reused)
+	}
+
+	request.ProviderVersion = ""
+	if _, _, _, err = pact.changedPacts(request); !errors.Is(err, types.ErrInvalidRequest) {
+		t.Fatalf("expected the provider version to be mandatory but got '%v'", err)
+	}
+}
+
+func TestVerification_changedPactsPending(t *testing.T) {
+	server := setupChangedPactsBroker(true)
+	defer server.Close()
+
+	pact := &Pact{}
+	_, _, ok, err := pact.changedPacts(types.VerifyRequest{BrokerURL: server.URL, Provider: "Bobby", ProviderVersion: "1.0.0"})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if ok {
+		t.Fatal("expected all pacts to be verified when some are pending")
+	}
+}
+
+func TestVerification_verifyProviderOnlyChangedPacts(t *testing.T) {
+	server := setupChangedPactsBroker(false)
+	defer server.Close()
+
+	since := time.Now()
+	c := newMockClient()
+	pact := &Pact{pactClient: c}
+	res, err := pact.verifyProvider(types.VerifyRequest{
+		BrokerURL:                server.URL,
+		Provider:                 "Bobby",
+		ProviderVersion:          "1.0.0",
+		OnlyChangedPacts:         true,
+		ConsumerVersionSelectors: []types.ConsumerVersionSelector{{Tag: "master"}},
+		Tags:                     []string{"master"},
+		IncludeWIPPactsSince:     &since,
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(res) != 1 || !strings.Contains(res[0].SummaryLine, "(reused)") {
+		t.Fatalf("expected the reused result but got %+v", res)
+	}
+
+	if len(c.VerifyProviderRequests) != 1 {
+		t.Fatalf("expected 1 verification but got %d", len(c.VerifyProviderRequests))
+	}
+	request := c.VerifyProviderRequests[0]
+	if fmt.Sprint(request.PactURLs) != "["+server.URL+"/pacts/changed]" {
+		t.Fatalf("expected only the changed pact to be verified but got %v", request.PactURLs)
+	}
+	if len(request.ConsumerVersionSelectors) != 0 || len(request.Tags) != 0 || request.IncludeWIPPactsSince != nil {
+		t.Fatalf("expected the changed pact not to be fetched by selectors again: %+v", request)
+	}
+}
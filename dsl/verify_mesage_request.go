@@ -2,6 +2,8 @@ package dsl
 
 import (
 	"fmt"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
 )
 
 // VerifyMessageRequest contains the verification logic
@@ -25,6 +27,9 @@ type VerifyMessageRequest struct {
 	// BrokerToken is required when authenticating using the Bearer token mechanism
 	BrokerToken string
 
+	// BrokerTokenSource supplies the bearer token when BrokerToken is not set
+	BrokerTokenSource types.TokenSource
+
 	// PublishVerificationResults to the Pact Broker.
 	PublishVerificationResults bool
 
@@ -34,6 +39,24 @@ type VerifyMessageRequest struct {
 	// ProviderTags is the set of tags to apply to the provider application version when results are published to the broker
 	ProviderTags []string
 
+	// VerificationCacheDir enables caching of successful verifications of local
+	// pact files, keyed by the content of the pact and the ProviderVersion.
+	// Pacts that have already been verified are skipped, and their previous
+	// results returned. Requires ProviderVersion. Optional
+	VerificationCacheDir string
+
+	// Concurrency is the maximum number of pacts to verify at once, when
+	// verifying more than one of PactURLs. State handlers and the provider must
+	// then be able to handle interactions from different pacts concurrently.
+	// Defaults to 1
+	Concurrency int
+
+	// ProgressHandler is called as verification progresses: as each request
+	// is replayed, and with the results of the interactions of each pact once
+	// the verifier has finished it (they are read from its final JSON output,
+	// so they are not live). Optional
+	ProgressHandler types.ProgressHandler
+
 	// MessageHandlers contains a mapped list of message handlers for a provider
 	// that will be rable to produce the correct message format for a given
 	// consumer interaction
@@ -0,0 +1,118 @@
+package dsl
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// defaultWireLogMaxBodySize is the most of each body written to the WireLog,
+// unless WireLogMaxBodySize is given
+const defaultWireLogMaxBodySize = 4096
+
+// RedactAuthorization is the default WireLogRedact, replacing the credentials
+// in the Authorization and Proxy-Authorization headers, and keeping the
+// authentication scheme e.g. "Bearer REDACTED". Other headers are unchanged.
+func RedactAuthorization(name string, value string) string {
+	switch http.CanonicalHeaderKey(name) {
+	case "Authorization", "Proxy-Authorization":
+		return redactCredentials(value)
+	}
+
+	return value
+}
+
+// writeWireLog appends a dump of each request made to the Mock Server in the
+// last Verify, and of its response, to the wire log of the test
+func (p *Pact) writeWireLog(interactions []*Interaction) {
+	if !p.WireLog || p.proxy == nil {
+		return
+	}
+
+	requests := p.proxy.Requests()
+	if len(requests) == 0 {
+		return
+	}
+
+	redact := p.WireLogRedact
+	if redact == nil {
+		redact = RedactAuthorization
+	}
+	maxBodySize := p.WireLogMaxBodySize
+	if maxBodySize <= 0 {
+		maxBodySize = defaultWireLogMaxBodySize
+	}
+
+	var b strings.Builder
+	for _, rec := range requests {
+		writeWireDump(&b, rec, interactions, redact, maxBodySize)
+	}
+
+	name := "wire.log"
+	if names := testNamesOf(interactions); len(names) > 0 {
+		name = "wire-" + unsafeBranchChars.ReplaceAllString(names[0], "_") + ".log"
+	}
+
+	f, err := os.OpenFile(filepath.Join(p.LogDir, name), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err == nil {
+		_, err = f.WriteString(b.String())
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		log.Println("[WARN] unable to write the wire log:", err)
+	}
+}
+
+// writeWireDump writes the request, with lines prefixed by ">", and its
+// response, with lines prefixed by "<", to b
+func writeWireDump(b *strings.Builder, rec *recordedRequest, interactions []*Interaction, redact func(string, string) string, maxBodySize int) {
+	uri := rec.Path
+	if rec.Query != "" {
+		uri += "?" + rec.Query
+	}
+
+	fmt.Fprintf(b, "* %s %s\n", rec.Received.UTC().Format(time.RFC3339Nano), requestOutcome(rec, interactions))
+	fmt.Fprintf(b, "> %s %s\n", rec.Method, uri)
+	writeWireMessage(b, ">", rec.Header, rec.Body, redact, maxBodySize)
+
+	fmt.Fprintf(b, "< %d %s (%s)\n", rec.Status, http.StatusText(rec.Status), rec.Latency.Round(time.Microsecond))
+	writeWireMessage(b, "<", rec.ResponseHeader, rec.ResponseBody, redact, maxBodySize)
+	b.WriteString("\n")
+}
+
+// writeWireMessage writes the sorted, redacted headers and the truncated body
+// of a request or response to b
+func writeWireMessage(b *strings.Builder, prefix string, header http.Header, body []byte, redact func(string, string) string, maxBodySize int) {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, value := range header[name] {
+			fmt.Fprintf(b, "%s %s: %s\n", prefix, name, redact(name, value))
+		}
+	}
+
+	if len(body) == 0 {
+		return
+	}
+
+	fmt.Fprintf(b, "%s\n", prefix)
+	truncated := ""
+	if len(body) > maxBodySize {
+		truncated = fmt.Sprintf("... (%d more bytes)", len(body)-maxBodySize)
+		body = body[:maxBodySize]
+	}
+	for _, line := range strings.Split(string(body)+truncated, "\n") {
+		fmt.Fprintf(b, "%s %s\n", prefix, line)
+	}
+}
@@ -0,0 +1,115 @@
+package dsl
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
+)
+
+func TestWireLog_write(t *testing.T) {
+	ms := setupMismatchMockServer()
+	defer ms.Close()
+
+	dir, err := ioutil.TempDir("", "pact-go-wire-log")
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	defer os.RemoveAll(dir)
+
+	pact := &Pact{
+		Server:             &types.MockServer{Port: getPort(ms.URL)},
+		Host:               "localhost",
+		Network:            "tcp",
+		LogDir:             dir,
+		WireLog:            true,
+		WireLogMaxBodySize: 10,
+	}
+	pact.startProxy()
+	defer pact.proxy.Stop()
+
+	url := fmt.Sprintf("http://localhost:%d", pact.Server.Port)
+	req, _ := http.NewRequest("POST", url+"/users?name=billy", strings.NewReader(`{"name":"billy"}`))
+	req.Header.Set("Authorization", "Bearer s3cr3t")
+	req.Header.Set("Content-Type", "application/json")
+	if _, err = http.DefaultClient.Do(req); err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	interactions := []*Interaction{
+		(&Interaction{testName: "TestClient/users"}).
+			UponReceiving("A request to create billy").
+			WithRequest(Request{Method: "POST", Path: String("/other")}),
+	}
+	pact.writeWireLog(interactions)
+
+	content, err := ioutil.ReadFile(filepath.Join(dir, "wire-TestClient_users.log"))
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	dump := string(content)
+
+	for _, want := range []string{
+		" unmatched\n",
+		"> POST /users?name=billy\n",
+		"> Authorization: Bearer REDACTED\n",
+		"> Content-Type: application/json\n",
+		`> {"name":"b... (6 more bytes)` + "\n",
+		"< 500 Internal Server Error (",
+		"< Content-Type: application/json\n",
+		`< {"message"... (`,
+	} {
+		if !strings.Contains(dump, want) {
+			t.Fatalf("expected %q in the wire log:\n%s", want, dump)
+		}
+	}
+	if strings.Contains(dump, "s3cr3t") {
+		t.Fatalf("expected the credentials to be redacted:\n%s", dump)
+	}
+}
+
+func TestWireLog_writeCustomRedact(t *testing.T) {
+	rec := &recordedRequest{
+		Method: "GET",
+		Path:   "/users/1",
+		Header: http.Header{"X-Api-Key": []string{"s3cr3t"}, "Authorization": []string{"Basic dXNlcg=="}},
+		Status: 200,
+	}
+	redact := func(name string, value string) string {
+		if name == "X-Api-Key" {
+			return "***"
+		}
+		return RedactAuthorization(name, value)
+	}
+
+	var b strings.Builder
+	writeWireDump(&b, rec, nil, redact, defaultWireLogMaxBodySize)
+
+	if !strings.Contains(b.String(), "> X-Api-Key: ***\n") || !strings.Contains(b.String(), "> Authorization: Basic REDACTED\n") {
+		t.Fatalf("expected the headers to be redacted:\n%s", b.String())
+	}
+	if !strings.Contains(b.String(), "< 200 OK (0s)\n") {
+		t.Fatalf("expected the response status:\n%s", b.String())
+	}
+}
+
+func TestWireLog_RedactAuthorization(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"Authorization", "Bearer s3cr3t", "Bearer REDACTED"},
+		{"proxy-authorization", "s3cr3t", "REDACTED"},
+		{"Content-Type", "application/json", "application/json"},
+	} {
+		if got := RedactAuthorization(tc.name, tc.value); got != tc.want {
+			t.Fatalf("expected %q for %s but got %q", tc.want, tc.name, got)
+		}
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"testing"
 
 	"github.com/ray-xu-deltatre/pact-go/dsl"
+	"github.com/ray-xu-deltatre/pact-go/types"
 )
 
 // Example Pact: How to run me!
@@ -75,3 +76,42 @@ func TestConsumer(t *testing.T) {
 		log.Fatalf("Error on Verify: %v", err)
 	}
 }
+
+// The TLS Mock Service presents a certificate issued for the TLSServerName.
+// 1. cd <pact-go>/examples
+// 2. go test -v -tags consumer -run TestConsumerTLSServerName
+func TestConsumerTLSServerName(t *testing.T) {
+	pact := &dsl.Pact{
+		Consumer:      "MyConsumer",
+		Provider:      "MyTLSProvider",
+		Host:          "localhost",
+		TLSServerName: "api.example.com",
+	}
+	defer pact.Teardown()
+
+	pact.
+		AddInteraction().
+		UponReceiving("A request for a session token").
+		WithTransport(dsl.TransportHTTPS).
+		WithRequest(dsl.Request{Method: "POST", Path: dsl.String("/session")}).
+		WillRespondWith(dsl.Response{Status: 201})
+
+	err := pact.VerifyWithTransports(func(servers map[string]*types.MockServer) error {
+		tlsConfig, err := pact.ClientTLSConfig()
+		if err != nil {
+			return err
+		}
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+
+		res, err := client.Post(fmt.Sprintf("https://localhost:%d/session", servers[dsl.TransportHTTPS].Port), "", nil)
+		if err != nil {
+			return err
+		}
+		res.Body.Close()
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Error on Verify: %v", err)
+	}
+}
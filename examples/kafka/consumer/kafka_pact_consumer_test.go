@@ -0,0 +1,72 @@
+// +build consumer
+
+package consumer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/ray-xu-deltatre/pact-go/dsl"
+	"github.com/ray-xu-deltatre/pact-go/examples/kafka/types"
+)
+
+var pact = createPact()
+
+func TestExampleKafkaConsumer_OrderCreated(t *testing.T) {
+	message := pact.AddMessage()
+	message.
+		Given("an order exists").
+		ExpectsToReceive("an order created event").
+		WithKafkaMetadata(dsl.String("orders"), dsl.Term("order-42", `order-\d+`), dsl.MapMatcher{
+			"content-type": dsl.String("application/json"),
+		}).
+		WithContent(dsl.Match(types.OrderCreated{}))
+
+	pact.VerifyMessageConsumer(t, message, dsl.KafkaMessageConsumer(recordHandler))
+}
+
+// recordHandler stands in for the function your Kafka consumer group invokes
+// for each record. With sarama this would be called from ConsumeClaim, e.g.
+//
+//	recordHandler(dsl.KafkaMessage{Topic: msg.Topic, Key: msg.Key, Value: msg.Value})
+var recordHandler = func(record dsl.KafkaMessage) error {
+	if record.Topic != "orders" {
+		return fmt.Errorf("unexpected topic '%s'", record.Topic)
+	}
+
+	var order types.OrderCreated
+	if err := json.Unmarshal(record.Value, &order); err != nil {
+		return err
+	}
+
+	return orderHandler(order)
+}
+
+var orderHandler = func(o types.OrderCreated) error {
+	if o.ID == 0 {
+		return errors.New("expected order, missing fields (id)")
+	}
+
+	// ... actually consume the message
+
+	return nil
+}
+
+// Configuration / Test Data
+var dir, _ = os.Getwd()
+var pactDir = fmt.Sprintf("%s/../../pacts", dir)
+var logDir = fmt.Sprintf("%s/log", dir)
+
+// Setup the Pact client.
+func createPact() dsl.Pact {
+	return dsl.Pact{
+		Consumer: "PactGoKafkaConsumer",
+		Provider: "PactGoKafkaProvider",
+		LogDir:   logDir,
+		PactDir:  pactDir,
+		LogLevel: "INFO",
+	}
+}
@@ -0,0 +1,76 @@
+// +build provider
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ray-xu-deltatre/pact-go/dsl"
+	"github.com/ray-xu-deltatre/pact-go/examples/kafka/types"
+)
+
+// The actual Provider test itself
+func TestExampleKafkaProvider_Success(t *testing.T) {
+	pact := createPact()
+
+	// Map test descriptions to the functions that build the records we publish
+	functionMappings := dsl.MessageHandlers{
+		"an order created event": dsl.KafkaMessageHandler(func(m dsl.Message) (dsl.KafkaMessage, error) {
+			return orderCreatedRecord(types.OrderCreated{
+				ID:       1,
+				Item:     "orange",
+				Quantity: 10,
+			})
+		}),
+	}
+
+	stateMappings := dsl.StateHandlers{
+		"an order exists": func(s dsl.State) error {
+			return nil
+		},
+	}
+
+	// Verify the Provider with local Pact Files
+	pact.VerifyMessageProvider(t, dsl.VerifyMessageRequest{
+		PactURLs:        []string{filepath.ToSlash(fmt.Sprintf("%s/pactgokafkaconsumer-pactgokafkaprovider.json", pactDir))},
+		MessageHandlers: functionMappings,
+		StateHandlers:   stateMappings,
+	})
+}
+
+// orderCreatedRecord is the code that prepares the record handed to the Kafka
+// producer. With sarama this would become a sarama.ProducerMessage, e.g.
+//
+//	&sarama.ProducerMessage{Topic: r.Topic, Key: sarama.ByteEncoder(r.Key), Value: sarama.ByteEncoder(r.Value)}
+func orderCreatedRecord(o types.OrderCreated) (dsl.KafkaMessage, error) {
+	value, err := json.Marshal(o)
+	if err != nil {
+		return dsl.KafkaMessage{}, err
+	}
+
+	return dsl.KafkaMessage{
+		Topic: "orders",
+		Key:   []byte(fmt.Sprintf("order-%d", o.ID)),
+		Value: value,
+		Headers: map[string][]byte{
+			"content-type": []byte("application/json"),
+		},
+	}, nil
+}
+
+// Configuration / Test Data
+var dir, _ = os.Getwd()
+var pactDir = fmt.Sprintf("%s/../../pacts", dir)
+var logDir = fmt.Sprintf("%s/log", dir)
+
+// Setup the Pact client.
+func createPact() dsl.Pact {
+	return dsl.Pact{
+		Provider: "PactGoKafkaProvider",
+		LogDir:   logDir,
+	}
+}
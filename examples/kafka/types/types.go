@@ -0,0 +1,8 @@
+package types
+
+// OrderCreated is the event published to the "orders" topic
+type OrderCreated struct {
+	ID       int    `json:"id" pact:"example=42"`
+	Item     string `json:"item" pact:"example=apple,regex=(apple|orange)"`
+	Quantity int    `json:"quantity" pact:"example=3"`
+}
@@ -0,0 +1,86 @@
+// +build consumer
+
+package consumer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/ray-xu-deltatre/pact-go/dsl"
+	"github.com/ray-xu-deltatre/pact-go/examples/nats/types"
+)
+
+var pact = createPact()
+
+func TestExampleNATSConsumer_Greeting(t *testing.T) {
+	message := pact.AddMessage()
+	message.
+		Given("alice is online").
+		ExpectsToReceive("a greeting").
+		WithMetadata(dsl.MapMatcher{
+			"nats_subject": dsl.String("greetings"),
+		}).
+		WithContent(dsl.Match(types.Greeting{}))
+
+	pact.VerifyMessageConsumer(t, message, natsAdapter(greetingHandler))
+}
+
+// natsAdapter converts the message Pact generates into the *nats.Msg the
+// subscription callback would receive from a real NATS server
+func natsAdapter(handler func(*types.Msg) error) dsl.MessageConsumer {
+	return func(m dsl.Message) error {
+		data, err := json.Marshal(m.Content)
+		if err != nil {
+			return err
+		}
+
+		subject := ""
+		if s, ok := m.Metadata["nats_subject"]; ok {
+			subject = fmt.Sprintf("%v", s.GetValue())
+		}
+
+		return handler(&types.Msg{
+			Subject: subject,
+			Data:    data,
+		})
+	}
+}
+
+// greetingHandler is the callback registered with nc.Subscribe("greetings", ...)
+var greetingHandler = func(msg *types.Msg) error {
+	if msg.Subject != "greetings" {
+		return fmt.Errorf("unexpected subject '%s'", msg.Subject)
+	}
+
+	var g types.Greeting
+	if err := json.Unmarshal(msg.Data, &g); err != nil {
+		return err
+	}
+
+	if g.From == "" {
+		return errors.New("expected greeting, missing fields (from)")
+	}
+
+	// ... actually consume the message
+
+	return nil
+}
+
+// Configuration / Test Data
+var dir, _ = os.Getwd()
+var pactDir = fmt.Sprintf("%s/../../pacts", dir)
+var logDir = fmt.Sprintf("%s/log", dir)
+
+// Setup the Pact client.
+func createPact() dsl.Pact {
+	return dsl.Pact{
+		Consumer: "PactGoNATSConsumer",
+		Provider: "PactGoNATSProvider",
+		LogDir:   logDir,
+		PactDir:  pactDir,
+		LogLevel: "INFO",
+	}
+}
@@ -0,0 +1,90 @@
+// +build provider
+
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ray-xu-deltatre/pact-go/dsl"
+	"github.com/ray-xu-deltatre/pact-go/examples/nats/types"
+)
+
+// capturingPublisher stands in for *nats.Conn, recording what is published
+// instead of sending it to a NATS server
+type capturingPublisher struct {
+	subject string
+	data    []byte
+}
+
+func (c *capturingPublisher) Publish(subject string, data []byte) error {
+	c.subject = subject
+	c.data = data
+	return nil
+}
+
+// sendGreeting is the production code under test
+func sendGreeting(p types.Publisher, from string) error {
+	data, err := json.Marshal(types.Greeting{
+		From:    from,
+		Message: fmt.Sprintf("hello from %s", from),
+	})
+	if err != nil {
+		return err
+	}
+
+	return p.Publish("greetings", data)
+}
+
+// The actual Provider test itself
+func TestExampleNATSProvider_Success(t *testing.T) {
+	pact := createPact()
+
+	functionMappings := dsl.MessageHandlers{
+		"a greeting": func(m dsl.Message) (interface{}, error) {
+			p := &capturingPublisher{}
+			if err := sendGreeting(p, "alice"); err != nil {
+				return nil, err
+			}
+
+			if p.subject != "greetings" {
+				return nil, errors.New("greeting published to the wrong subject")
+			}
+
+			var greeting interface{}
+			err := json.Unmarshal(p.data, &greeting)
+
+			return greeting, err
+		},
+	}
+
+	stateMappings := dsl.StateHandlers{
+		"alice is online": func(s dsl.State) error {
+			return nil
+		},
+	}
+
+	// Verify the Provider with local Pact Files
+	pact.VerifyMessageProvider(t, dsl.VerifyMessageRequest{
+		PactURLs:        []string{filepath.ToSlash(fmt.Sprintf("%s/pactgonatsconsumer-pactgonatsprovider.json", pactDir))},
+		MessageHandlers: functionMappings,
+		StateHandlers:   stateMappings,
+	})
+}
+
+// Configuration / Test Data
+var dir, _ = os.Getwd()
+var pactDir = fmt.Sprintf("%s/../../pacts", dir)
+var logDir = fmt.Sprintf("%s/log", dir)
+
+// Setup the Pact client.
+func createPact() dsl.Pact {
+	return dsl.Pact{
+		Provider: "PactGoNATSProvider",
+		LogDir:   logDir,
+	}
+}
@@ -0,0 +1,20 @@
+package types
+
+// Msg mirrors the fields of nats.Msg (github.com/nats-io/nats.go) used by
+// the example, so it can run without a NATS server or client dependency.
+type Msg struct {
+	Subject string
+	Reply   string
+	Data    []byte
+}
+
+// Publisher is satisfied by *nats.Conn
+type Publisher interface {
+	Publish(subject string, data []byte) error
+}
+
+// Greeting is published to the "greetings" subject
+type Greeting struct {
+	From    string `json:"from" pact:"example=alice"`
+	Message string `json:"message" pact:"example=hello"`
+}
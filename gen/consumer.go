@@ -0,0 +1,486 @@
+// Package gen generates Go code from pact files, such as skeleton consumer
+// tests that re-create the interactions of an existing pact with the DSL.
+package gen
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/format"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Options for generating code
+type Options struct {
+	// Package of the generated code. Defaults to "main"
+	Package string
+
+	// Source is the name of the pact file, mentioned in the generated header
+	Source string
+
+	// Function is the name of the function generated by Interactions.
+	// Defaults to "AddInteractions"
+	Function string
+}
+
+// pactFile is the subset of a (v2 or v3) pact file used for generation
+type pactFile struct {
+	Consumer struct {
+		Name string `json:"name"`
+	} `json:"consumer"`
+	Provider struct {
+		Name string `json:"name"`
+	} `json:"provider"`
+	Interactions []interaction    `json:"interactions"`
+	Messages     *json.RawMessage `json:"messages"`
+}
+
+type interaction struct {
+	Description    string `json:"description"`
+	ProviderState  string `json:"providerState"`
+	ProviderStates []struct {
+		Name string `json:"name"`
+	} `json:"providerStates"`
+	Request  message `json:"request"`
+	Response message `json:"response"`
+}
+
+// message is the request or response of an interaction
+type message struct {
+	Method        string                     `json:"method"`
+	Path          string                     `json:"path"`
+	Query         json.RawMessage            `json:"query"`
+	Status        int                        `json:"status"`
+	Headers       map[string]json.RawMessage `json:"headers"`
+	Body          json.RawMessage            `json:"body"`
+	MatchingRules json.RawMessage            `json:"matchingRules"`
+}
+
+// rule is a matching rule, normalised from the v2 or v3 format
+type rule struct {
+	Match string `json:"match"`
+	Regex string `json:"regex"`
+	Min   *int   `json:"min"`
+	Max   *int   `json:"max"`
+}
+
+// ConsumerTest generates a skeleton consumer test for each interaction in the
+// pact. The request made by the consumer is left as a TODO.
+func ConsumerTest(pact []byte, options Options) ([]byte, error) {
+	p, err := parsePact(pact)
+	if err != nil {
+		return nil, err
+	}
+
+	pkg := options.Package
+	if pkg == "" {
+		pkg = "main"
+	}
+
+	var b bytes.Buffer
+	if options.Source != "" {
+		fmt.Fprintf(&b, "// Skeleton consumer tests generated by pact-go gen from %s.\n", options.Source)
+	} else {
+		fmt.Fprint(&b, "// Skeleton consumer tests generated by pact-go gen.\n")
+	}
+	fmt.Fprint(&b, "// Replace each TODO with a call to the provider using your API client.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	fmt.Fprint(&b, "import (\n\t\"testing\"\n\n\t\"github.com/ray-xu-deltatre/pact-go/dsl\"\n)\n")
+
+	names := make(map[string]int)
+	for _, i := range p.Interactions {
+		name := "Test" + identifier(p.Consumer.Name) + "_" + identifier(i.Description)
+		if names[name]++; names[name] > 1 {
+			name = fmt.Sprintf("%s%d", name, names[name])
+		}
+
+		if err := writeTest(&b, name, p.Consumer.Name, p.Provider.Name, i); err != nil {
+			return nil, fmt.Errorf("interaction '%s': %v", i.Description, err)
+		}
+	}
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("unable to format generated test: %v", err)
+	}
+
+	return src, nil
+}
+
+// parsePact parses a pact file with at least one (HTTP) interaction
+func parsePact(pact []byte) (pactFile, error) {
+	var p pactFile
+	if err := json.Unmarshal(pact, &p); err != nil {
+		return p, fmt.Errorf("unable to parse pact file: %v", err)
+	}
+	if p.Messages != nil {
+		return p, errors.New("message pacts are not supported")
+	}
+	if len(p.Interactions) == 0 {
+		return p, errors.New("the pact has no interactions")
+	}
+
+	return p, nil
+}
+
+// writeTest writes a test re-creating the interaction
+func writeTest(b *bytes.Buffer, name string, consumer string, provider string, i interaction) error {
+	fmt.Fprintf(b, "\nfunc %s(t *testing.T) {\n", name)
+	fmt.Fprintf(b, "pact := &dsl.Pact{\nConsumer: %s,\nProvider: %s,\n}\n", strconv.Quote(consumer), strconv.Quote(provider))
+	fmt.Fprint(b, "defer pact.Teardown()\n\n")
+
+	if err := writeInteraction(b, i); err != nil {
+		return err
+	}
+	fmt.Fprint(b, "\n")
+
+	fmt.Fprint(b, "err := pact.Verify(func() error {\n")
+	b.WriteString("// TODO: call the provider at fmt.Sprintf(\"http://localhost:%d\", pact.Server.Port)\n")
+	fmt.Fprint(b, "// and check the response is handled as expected\n")
+	fmt.Fprint(b, "return nil\n})\n")
+	b.WriteString("if err != nil {\nt.Fatalf(\"Error on Verify: %v\", err)\n}\n}\n")
+
+	return nil
+}
+
+// writeInteraction writes the statement adding the interaction to pact
+func writeInteraction(b *bytes.Buffer, i interaction) error {
+	requestRules, err := parseRules(i.Request.MatchingRules)
+	if err != nil {
+		return err
+	}
+	responseRules, err := parseRules(i.Response.MatchingRules)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprint(b, "pact.\nAddInteraction().\n")
+	if i.ProviderState != "" {
+		fmt.Fprintf(b, "Given(%s).\n", strconv.Quote(i.ProviderState))
+	}
+	for _, state := range i.ProviderStates {
+		fmt.Fprintf(b, "Given(%s).\n", strconv.Quote(state.Name))
+	}
+	fmt.Fprintf(b, "UponReceiving(%s).\n", strconv.Quote(i.Description))
+
+	fmt.Fprint(b, "WithRequest(dsl.Request{\n")
+	fmt.Fprintf(b, "Method: %s,\n", strconv.Quote(i.Request.Method))
+	fmt.Fprintf(b, "Path: %s,\n", stringMatcher(i.Request.Path, requestRules["$.path"]))
+	if err = writeQuery(b, i.Request.Query, requestRules); err != nil {
+		return err
+	}
+	if err = writeHeaders(b, i.Request.Headers, requestRules); err != nil {
+		return err
+	}
+	if err = writeBody(b, i.Request.Body, requestRules); err != nil {
+		return err
+	}
+	fmt.Fprint(b, "}).\n")
+
+	fmt.Fprint(b, "WillRespondWith(dsl.Response{\n")
+	fmt.Fprintf(b, "Status: %d,\n", i.Response.Status)
+	if err = writeHeaders(b, i.Response.Headers, responseRules); err != nil {
+		return err
+	}
+	if err = writeBody(b, i.Response.Body, responseRules); err != nil {
+		return err
+	}
+	fmt.Fprint(b, "})\n")
+
+	return nil
+}
+
+// writeQuery writes the query, which is a string in v2 pacts and a map of
+// values in v3 pacts
+func writeQuery(b *bytes.Buffer, raw json.RawMessage, rules map[string]rule) error {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+
+	values := url.Values{}
+	var query string
+	if err := json.Unmarshal(raw, &query); err == nil {
+		if values, err = url.ParseQuery(query); err != nil {
+			return fmt.Errorf("invalid query: %v", err)
+		}
+	} else if err = json.Unmarshal(raw, (*map[string][]string)(&values)); err != nil {
+		return fmt.Errorf("invalid query: %v", err)
+	}
+	if len(values) == 0 {
+		return nil
+	}
+
+	fmt.Fprint(b, "Query: dsl.MapMatcher{\n")
+	for _, k := range sortedKeys(values) {
+		r, ok := rules["$.query."+k]
+		if !ok {
+			r = rules["$.query."+k+"[0]"]
+		}
+		fmt.Fprintf(b, "%s: %s,", strconv.Quote(k), stringMatcher(values[k][0], r))
+		if len(values[k]) > 1 {
+			fmt.Fprintf(b, " // TODO: also %s", strings.Join(values[k][1:], ", "))
+		}
+		fmt.Fprint(b, "\n")
+	}
+	fmt.Fprint(b, "},\n")
+
+	return nil
+}
+
+// writeHeaders writes the headers, whose values may be joined by commas or
+// (in v3 pacts) be arrays
+func writeHeaders(b *bytes.Buffer, headers map[string]json.RawMessage, rules map[string]rule) error {
+	if len(headers) == 0 {
+		return nil
+	}
+
+	fmt.Fprint(b, "Headers: dsl.MapMatcher{\n")
+	for _, k := range sortedKeys(headers) {
+		var value string
+		if err := json.Unmarshal(headers[k], &value); err != nil {
+			var values []string
+			if err = json.Unmarshal(headers[k], &values); err != nil {
+				return fmt.Errorf("invalid header '%s': %v", k, err)
+			}
+			value = strings.Join(values, ", ")
+		}
+		fmt.Fprintf(b, "%s: %s,\n", strconv.Quote(k), stringMatcher(value, rules["$.headers."+k]))
+	}
+	fmt.Fprint(b, "},\n")
+
+	return nil
+}
+
+// writeBody writes the body as a Go literal, wrapping values with matching
+// rules in the equivalent matcher
+func writeBody(b *bytes.Buffer, raw json.RawMessage, rules map[string]rule) error {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+
+	d := json.NewDecoder(bytes.NewReader(raw))
+	d.UseNumber()
+	var body interface{}
+	if err := d.Decode(&body); err != nil {
+		return fmt.Errorf("invalid body: %v", err)
+	}
+
+	fmt.Fprintf(b, "Body: %s,\n", value(body, "$.body", rules, false))
+
+	return nil
+}
+
+// stringMatcher returns the matcher for a path, query or header value
+func stringMatcher(s string, r rule) string {
+	switch r.Match {
+	case "regex":
+		return fmt.Sprintf("dsl.Term(%s, %s)", strconv.Quote(s), quoteRegex(r.Regex))
+	case "type":
+		return fmt.Sprintf("dsl.Like(%s)", strconv.Quote(s))
+	}
+
+	return fmt.Sprintf("dsl.String(%s)", strconv.Quote(s))
+}
+
+// value returns the Go literal for a body value at path. Within a Like or
+// EachLike matcher (cascaded) rules are implied, as types are matched.
+func value(v interface{}, path string, rules map[string]rule, cascaded bool) string {
+	r, ok := rules[path]
+	if !ok && !cascaded {
+		r = wildcardRule(path, rules)
+	}
+	if r.Match == "" && (r.Min != nil || r.Max != nil) {
+		r.Match = "type"
+	}
+
+	switch r.Match {
+	case "regex":
+		return fmt.Sprintf("dsl.Term(%s, %s)", strconv.Quote(fmt.Sprint(v)), quoteRegex(r.Regex))
+	case "type", "integer", "decimal", "number":
+		if items, isArray := v.([]interface{}); isArray && r.Max != nil && len(items) > 0 {
+			min := 1
+			if r.Min != nil {
+				min = *r.Min
+			}
+			return fmt.Sprintf("dsl.EachLikeBetween(%s, %d, %d)", value(items[0], path+"[*]", rules, true), min, *r.Max)
+		}
+		if items, isArray := v.([]interface{}); isArray && r.Min != nil && len(items) > 0 {
+			return fmt.Sprintf("dsl.EachLike(%s, %d)", value(items[0], path+"[*]", rules, true), *r.Min)
+		}
+		if !cascaded {
+			return fmt.Sprintf("dsl.Like(%s)", value(v, path, rules, true))
+		}
+	}
+
+	switch v := v.(type) {
+	case map[string]interface{}:
+		var fields []string
+		for _, k := range sortedKeys(v) {
+			fields = append(fields, fmt.Sprintf("%s: %s,\n", strconv.Quote(k), value(v[k], fieldPath(path, k), rules, cascaded)))
+		}
+		return "map[string]interface{}{\n" + strings.Join(fields, "") + "}"
+	case []interface{}:
+		var items []string
+		for n, item := range v {
+			items = append(items, value(item, fmt.Sprintf("%s[%d]", path, n), rules, cascaded)+",\n")
+		}
+		return "[]interface{}{\n" + strings.Join(items, "") + "}"
+	case string:
+		return strconv.Quote(v)
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	}
+
+	return "nil"
+}
+
+// wildcardRule returns the rule for all fields of the parent of path, e.g.
+// "$.body.items[*].*" for "$.body.items[*].name"
+func wildcardRule(path string, rules map[string]rule) rule {
+	if i := strings.LastIndexAny(path, ".["); i > 0 {
+		return rules[path[:i]+".*"]
+	}
+
+	return rule{}
+}
+
+var identifierRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// fieldPath returns the JSON path of the field k of the object at path
+func fieldPath(path string, k string) string {
+	if identifierRegex.MatchString(k) {
+		return path + "." + k
+	}
+
+	return path + "['" + k + "']"
+}
+
+// parseRules normalises the matching rules of a v2 or v3 request or response
+// to v2 paths, e.g. "$.body.name", "$.headers.Accept" and "$.path"
+func parseRules(raw json.RawMessage) (map[string]rule, error) {
+	rules := make(map[string]rule)
+	if len(raw) == 0 || string(raw) == "null" {
+		return rules, nil
+	}
+
+	var categories map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &categories); err != nil {
+		return nil, fmt.Errorf("invalid matching rules: %v", err)
+	}
+
+	for key, r := range categories {
+		if strings.HasPrefix(key, "$") {
+			var v2 rule
+			if err := json.Unmarshal(r, &v2); err != nil {
+				return nil, fmt.Errorf("invalid matching rule '%s': %v", key, err)
+			}
+			rules[key] = v2
+			continue
+		}
+
+		if key == "path" {
+			v3, err := parseV3Rule(r)
+			if err != nil {
+				return nil, err
+			}
+			rules["$.path"] = v3
+			continue
+		}
+
+		var paths map[string]json.RawMessage
+		if err := json.Unmarshal(r, &paths); err != nil {
+			return nil, fmt.Errorf("invalid matching rules '%s': %v", key, err)
+		}
+		for path, r := range paths {
+			v3, err := parseV3Rule(r)
+			if err != nil {
+				return nil, err
+			}
+
+			switch key {
+			case "body":
+				rules["$.body"+strings.TrimPrefix(path, "$")] = v3
+			case "header":
+				rules["$.headers."+path] = v3
+			case "query":
+				rules["$.query."+path] = v3
+			}
+		}
+	}
+
+	return rules, nil
+}
+
+// parseV3Rule returns the first of a list of v3 matchers
+func parseV3Rule(raw json.RawMessage) (rule, error) {
+	var v3 struct {
+		Matchers []rule `json:"matchers"`
+	}
+	if err := json.Unmarshal(raw, &v3); err != nil {
+		return rule{}, fmt.Errorf("invalid matching rule: %v", err)
+	}
+	if len(v3.Matchers) == 0 {
+		return rule{}, nil
+	}
+
+	return v3.Matchers[0], nil
+}
+
+// quoteRegex quotes a regular expression, preferring a raw string literal
+func quoteRegex(regex string) string {
+	if strconv.CanBackquote(regex) {
+		return "`" + regex + "`"
+	}
+
+	return strconv.Quote(regex)
+}
+
+// identifier converts a description such as "a request for user 1" into
+// part of a Go identifier, e.g. "ARequestForUser1"
+func identifier(s string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// sortedKeys returns the keys of a map in order, for stable output
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	switch m := m.(type) {
+	case map[string]interface{}:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	case map[string]json.RawMessage:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	case url.Values:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,136 @@
+package gen
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func assertGenerated(t *testing.T, src []byte, expected ...string) {
+	t.Helper()
+
+	if _, err := parser.ParseFile(token.NewFileSet(), "consumer_test.go", src, 0); err != nil {
+		t.Fatalf("expected valid Go but got %v:\n%s", err, src)
+	}
+	for _, e := range expected {
+		if !strings.Contains(string(src), e) {
+			t.Fatalf("expected the generated test to contain '%s':\n%s", e, src)
+		}
+	}
+}
+
+func TestConsumerTest_V2(t *testing.T) {
+	pact := `{
+		"consumer": {"name": "billing-ui"},
+		"provider": {"name": "billing"},
+		"interactions": [{
+			"description": "a request for invoices",
+			"providerState": "invoices exist",
+			"request": {
+				"method": "GET",
+				"path": "/invoices/1",
+				"query": "status=paid&status=due",
+				"headers": {"Accept": "application/json"},
+				"matchingRules": {"$.path": {"match": "regex", "regex": "^/invoices/[0-9]+$"}}
+			},
+			"response": {
+				"status": 200,
+				"body": {"invoices": [{"id": 1, "total": 9.99}], "paid": true, "next page": null},
+				"matchingRules": {
+					"$.body.invoices": {"min": 1},
+					"$.body.invoices[*].*": {"match": "type"},
+					"$.body['next page']": {"match": "type"}
+				}
+			}
+		}]
+	}`
+
+	src, err := ConsumerTest([]byte(pact), Options{Package: "billing", Source: "billing-ui-billing.json"})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	assertGenerated(t, src,
+		"package billing",
+		"from billing-ui-billing.json",
+		"func TestBillingUi_ARequestForInvoices(t *testing.T)",
+		`Given("invoices exist")`,
+		"dsl.Term(\"/invoices/1\", `^/invoices/[0-9]+$`)",
+		`"status": dsl.String("paid"), // TODO: also due`,
+		`"Accept": dsl.String("application/json")`,
+		`"invoices": dsl.EachLike(map[string]interface{}{`,
+		`"total": 9.99`,
+		`"paid":      true`,
+		`"next page": dsl.Like(nil)`,
+	)
+}
+
+func TestConsumerTest_V3(t *testing.T) {
+	pact := `{
+		"consumer": {"name": "billing-ui"},
+		"provider": {"name": "billing"},
+		"interactions": [{
+			"description": "a request to pay",
+			"providerStates": [{"name": "an invoice exists"}, {"name": "the user is logged in"}],
+			"request": {
+				"method": "POST",
+				"path": "/invoices/1/payments",
+				"query": {"confirm": ["true"]},
+				"headers": {"Content-Type": ["application/json"]},
+				"body": {"amount": 10},
+				"matchingRules": {
+					"body": {"$.amount": {"matchers": [{"match": "integer"}]}},
+					"header": {"Content-Type": {"matchers": [{"match": "regex", "regex": "application/json.*"}]}}
+				}
+			},
+			"response": {"status": 201}
+		}],
+		"metadata": {"pactSpecification": {"version": "3.0.0"}}
+	}`
+
+	src, err := ConsumerTest([]byte(pact), Options{})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	assertGenerated(t, src,
+		"package main",
+		`Given("an invoice exists").`,
+		`Given("the user is logged in").`,
+		`"confirm": dsl.String("true")`,
+		"dsl.Term(\"application/json\", `application/json.*`)",
+		`"amount": dsl.Like(10)`,
+		"Status: 201",
+	)
+}
+
+func TestConsumerTest_DuplicateDescriptions(t *testing.T) {
+	pact := `{
+		"consumer": {"name": "ui"},
+		"provider": {"name": "api"},
+		"interactions": [
+			{"description": "a request", "providerState": "one", "request": {"method": "GET", "path": "/"}, "response": {"status": 200}},
+			{"description": "a request", "providerState": "two", "request": {"method": "GET", "path": "/"}, "response": {"status": 404}}
+		]
+	}`
+
+	src, err := ConsumerTest([]byte(pact), Options{})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	assertGenerated(t, src, "func TestUi_ARequest(t", "func TestUi_ARequest2(t")
+}
+
+func TestConsumerTest_Invalid(t *testing.T) {
+	for _, pact := range []string{
+		`not json`,
+		`{"consumer": {"name": "ui"}, "provider": {"name": "api"}, "interactions": []}`,
+		`{"consumer": {"name": "ui"}, "provider": {"name": "api"}, "messages": []}`,
+	} {
+		if _, err := ConsumerTest([]byte(pact), Options{}); err == nil {
+			t.Fatalf("expected an error for pact '%s'", pact)
+		}
+	}
+}
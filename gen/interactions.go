@@ -0,0 +1,57 @@
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strconv"
+)
+
+// Interactions generates a function that adds the interactions in the pact to
+// a dsl.Pact, so that they can be shared by the consumer tests of several
+// packages that call the same provider endpoints:
+//
+//	func AddInteractions(pact *dsl.Pact)
+func Interactions(pact []byte, options Options) ([]byte, error) {
+	p, err := parsePact(pact)
+	if err != nil {
+		return nil, err
+	}
+
+	pkg := options.Package
+	if pkg == "" {
+		pkg = "main"
+	}
+	function := options.Function
+	if function == "" {
+		function = "AddInteractions"
+	}
+
+	var b bytes.Buffer
+	if options.Source != "" {
+		fmt.Fprintf(&b, "// Interactions generated by pact-go gen from %s.\n\n", options.Source)
+	} else {
+		fmt.Fprint(&b, "// Interactions generated by pact-go gen.\n\n")
+	}
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	fmt.Fprint(&b, "import \"github.com/ray-xu-deltatre/pact-go/dsl\"\n\n")
+
+	fmt.Fprintf(&b, "// %s adds the interactions between %s and %s to pact\n", function, strconv.Quote(p.Consumer.Name), strconv.Quote(p.Provider.Name))
+	fmt.Fprintf(&b, "func %s(pact *dsl.Pact) {\n", function)
+	for n, i := range p.Interactions {
+		if n > 0 {
+			fmt.Fprint(&b, "\n")
+		}
+		if err = writeInteraction(&b, i); err != nil {
+			return nil, fmt.Errorf("interaction '%s': %v", i.Description, err)
+		}
+	}
+	fmt.Fprint(&b, "}\n")
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("unable to format generated interactions: %v", err)
+	}
+
+	return src, nil
+}
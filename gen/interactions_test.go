@@ -0,0 +1,63 @@
+package gen
+
+import "testing"
+
+func TestInteractions(t *testing.T) {
+	pact := `{
+		"consumer": {"name": "ui"},
+		"provider": {"name": "api"},
+		"interactions": [
+			{"description": "a request", "providerState": "one", "request": {"method": "GET", "path": "/"}, "response": {"status": 200}},
+			{"description": "another request", "request": {"method": "DELETE", "path": "/"}, "response": {"status": 204}}
+		]
+	}`
+
+	src, err := Interactions([]byte(pact), Options{Package: "api", Source: "ui-api.json"})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	assertGenerated(t, src,
+		"package api",
+		"from ui-api.json",
+		"func AddInteractions(pact *dsl.Pact) {",
+		`UponReceiving("a request")`,
+		`UponReceiving("another request")`,
+		"Status: 204",
+	)
+
+	src, err = Interactions([]byte(pact), Options{Function: "AddAPIInteractions"})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	assertGenerated(t, src, "package main", "func AddAPIInteractions(pact *dsl.Pact) {")
+}
+
+func TestInteractions_EachLikeBetween(t *testing.T) {
+	pact := `{
+		"consumer": {"name": "ui"},
+		"provider": {"name": "api"},
+		"interactions": [{
+			"description": "a request for lines",
+			"request": {"method": "GET", "path": "/lines"},
+			"response": {
+				"status": 200,
+				"body": {"lines": ["line"], "tags": ["tag"]},
+				"matchingRules": {"$.body.lines": {"min": 1, "max": 3}, "$.body.tags": {"max": 2}}
+			}
+		}]
+	}`
+
+	src, err := Interactions([]byte(pact), Options{})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	assertGenerated(t, src, `"lines": dsl.EachLikeBetween("line", 1, 3)`, `"tags":  dsl.EachLikeBetween("tag", 1, 2)`)
+}
+
+func TestInteractions_Invalid(t *testing.T) {
+	if _, err := Interactions([]byte(`{"interactions": []}`), Options{}); err == nil {
+		t.Fatal("expected an error for a pact without interactions")
+	}
+}
@@ -0,0 +1,155 @@
+package gen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ProviderState is a provider state that a provider must be able to set up
+// to verify the pacts it was found in
+type ProviderState struct {
+	// Name of the state e.g. "user 1 exists"
+	Name string
+
+	// Params are the names of the parameters of the state, in v3 pacts
+	Params []string
+
+	// Consumers whose pacts require the state
+	Consumers []string
+
+	// Interactions (or messages) given the state, by their description
+	Interactions []string
+}
+
+// statesPactFile is the subset of a (v2 or v3) pact file with provider states
+type statesPactFile struct {
+	Consumer struct {
+		Name string `json:"name"`
+	} `json:"consumer"`
+	Interactions []statefulInteraction `json:"interactions"`
+	Messages     []statefulInteraction `json:"messages"`
+}
+
+type statefulInteraction struct {
+	Description    string `json:"description"`
+	ProviderState  string `json:"providerState"`
+	ProviderStates []struct {
+		Name   string                 `json:"name"`
+		Params map[string]interface{} `json:"params"`
+	} `json:"providerStates"`
+}
+
+// ProviderStates returns the catalogue of provider states in the pacts, in
+// order of their name
+func ProviderStates(pacts ...[]byte) ([]ProviderState, error) {
+	states := make(map[string]*ProviderState)
+
+	add := func(name string, params map[string]interface{}, consumer string, description string) {
+		state, ok := states[name]
+		if !ok {
+			state = &ProviderState{Name: name}
+			states[name] = state
+		}
+		for param := range params {
+			state.Params = appendDistinct(state.Params, param)
+		}
+		state.Consumers = appendDistinct(state.Consumers, consumer)
+		state.Interactions = appendDistinct(state.Interactions, description)
+	}
+
+	for n, pact := range pacts {
+		var p statesPactFile
+		if err := json.Unmarshal(pact, &p); err != nil {
+			return nil, fmt.Errorf("unable to parse pact file %d: %v", n+1, err)
+		}
+
+		for _, i := range append(p.Interactions, p.Messages...) {
+			if i.ProviderState != "" {
+				add(i.ProviderState, nil, p.Consumer.Name, i.Description)
+			}
+			for _, s := range i.ProviderStates {
+				add(s.Name, s.Params, p.Consumer.Name, i.Description)
+			}
+		}
+	}
+
+	catalogue := make([]ProviderState, 0, len(states))
+	for _, state := range states {
+		sort.Strings(state.Params)
+		sort.Strings(state.Consumers)
+		sort.Strings(state.Interactions)
+		catalogue = append(catalogue, *state)
+	}
+	sort.Slice(catalogue, func(a, b int) bool {
+		return catalogue[a].Name < catalogue[b].Name
+	})
+
+	return catalogue, nil
+}
+
+// StateHandlers generates a function returning a skeleton state handler for
+// each of the provider states, to be given as the StateHandlers of the
+// types.VerifyRequest of the provider:
+//
+//	func StateHandlers() types.StateHandlers
+//
+// The set up of each state is left as a TODO.
+func StateHandlers(states []ProviderState, options Options) ([]byte, error) {
+	pkg := options.Package
+	if pkg == "" {
+		pkg = "main"
+	}
+	function := options.Function
+	if function == "" {
+		function = "StateHandlers"
+	}
+
+	var b bytes.Buffer
+	if options.Source != "" {
+		fmt.Fprintf(&b, "// Provider state handlers generated by pact-go gen from %s.\n", options.Source)
+	} else {
+		fmt.Fprint(&b, "// Provider state handlers generated by pact-go gen.\n")
+	}
+	fmt.Fprint(&b, "// Replace each TODO with the set up of the state.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	fmt.Fprint(&b, "import \"github.com/ray-xu-deltatre/pact-go/types\"\n\n")
+
+	fmt.Fprintf(&b, "// %s sets up the provider states required by the consumers\n", function)
+	fmt.Fprintf(&b, "func %s() types.StateHandlers {\n", function)
+	fmt.Fprint(&b, "return types.StateHandlers{\n")
+	for _, state := range states {
+		fmt.Fprintf(&b, "%s: func() error {\n", strconv.Quote(state.Name))
+		fmt.Fprintf(&b, "// TODO: set up the state for %s\n", strings.Join(state.Consumers, ", "))
+		if len(state.Params) > 0 {
+			fmt.Fprintf(&b, "// with the params %s\n", strings.Join(state.Params, ", "))
+		}
+		fmt.Fprint(&b, "return nil\n},\n")
+	}
+	fmt.Fprint(&b, "}\n}\n")
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("unable to format generated state handlers: %v", err)
+	}
+
+	return src, nil
+}
+
+// appendDistinct appends s to values, unless it is empty or already present
+func appendDistinct(values []string, s string) []string {
+	if s == "" {
+		return values
+	}
+	for _, v := range values {
+		if v == s {
+			return values
+		}
+	}
+
+	return append(values, s)
+}
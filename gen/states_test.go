@@ -0,0 +1,68 @@
+package gen
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestProviderStates(t *testing.T) {
+	v2 := `{
+		"consumer": {"name": "ui"},
+		"interactions": [
+			{"description": "a request for user 1", "providerState": "user 1 exists"},
+			{"description": "a request for users", "providerState": "user 1 exists"},
+			{"description": "a health check"}
+		]
+	}`
+	v3 := `{
+		"consumer": {"name": "billing"},
+		"messages": [
+			{"description": "an invoice", "providerStates": [{"name": "user 1 exists", "params": {"id": 1}}, {"name": "an order", "params": {"total": 10, "currency": "EUR"}}]}
+		]
+	}`
+
+	states, err := ProviderStates([]byte(v2), []byte(v3))
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	expected := []ProviderState{
+		{Name: "an order", Params: []string{"currency", "total"}, Consumers: []string{"billing"}, Interactions: []string{"an invoice"}},
+		{Name: "user 1 exists", Params: []string{"id"}, Consumers: []string{"billing", "ui"}, Interactions: []string{"a request for user 1", "a request for users", "an invoice"}},
+	}
+	if !reflect.DeepEqual(states, expected) {
+		t.Fatalf("want %+v, got %+v", expected, states)
+	}
+
+	if _, err = ProviderStates([]byte("{")); err == nil {
+		t.Fatal("expected an error for an invalid pact")
+	}
+}
+
+func TestStateHandlers(t *testing.T) {
+	states := []ProviderState{
+		{Name: "an order", Params: []string{"currency", "total"}, Consumers: []string{"billing"}},
+		{Name: "user 1 exists", Consumers: []string{"billing", "ui"}},
+	}
+
+	src, err := StateHandlers(states, Options{Package: "provider", Source: "ui-api.json"})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	assertGenerated(t, src,
+		"package provider",
+		"from ui-api.json",
+		"func StateHandlers() types.StateHandlers {",
+		`"an order": func() error {`,
+		"// with the params currency, total",
+		`"user 1 exists": func() error {`,
+		"// TODO: set up the state for billing, ui",
+	)
+
+	src, err = StateHandlers(nil, Options{Function: "ProviderStates"})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	assertGenerated(t, src, "package main", "func ProviderStates() types.StateHandlers {")
+}
@@ -0,0 +1,172 @@
+package install
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+
+	"github.com/ray-xu-deltatre/pact-go/broker"
+	"github.com/ray-xu-deltatre/pact-go/types"
+	"github.com/ray-xu-deltatre/pact-go/utils"
+)
+
+// DoctorOptions describes the environment checked by Doctor
+type DoctorOptions struct {
+	// PactDir and LogDir must be writable, if given
+	PactDir string
+	LogDir  string
+
+	// Broker is checked to be reachable, with the credentials given, if set
+	Broker *broker.Client
+
+	// Ports must be able to allocate a port for the Mock Service. Defaults to
+	// utils.EphemeralPorts
+	Ports utils.PortAllocator
+}
+
+// Check is the result of one of the checks made by Doctor
+type Check struct {
+	// Name of the check e.g. "pact-mock-service"
+	Name string
+
+	// Detail of a successful check e.g. the version found
+	Detail string
+
+	// Err is the failure of the check, if any, categorised as per the other
+	// functions of the library e.g. types.ErrCLITools
+	Err error
+
+	// Remedy suggests how to fix a failure
+	Remedy string
+}
+
+// Doctor checks that the environment is able to run Pact tests: that the Pact
+// CLI tools are installed at compatible versions, that the directories pacts
+// and logs are written to are writable, that a port can be found for the Mock
+// Service and that the Pact Broker, if any, can be reached and accepts the
+// credentials given. Every check is made, even if earlier ones fail.
+func (i *Installer) Doctor(options DoctorOptions) []Check {
+	var checks []Check
+
+	binaries := make([]string, 0, len(versionMap))
+	for binary := range versionMap {
+		binaries = append(binaries, binary)
+	}
+	sort.Strings(binaries)
+	for _, binary := range binaries {
+		checks = append(checks, i.checkBinary(binary))
+	}
+
+	for _, dir := range []struct {
+		name string
+		path string
+	}{
+		{"PactDir", options.PactDir},
+		{"LogDir", options.LogDir},
+	} {
+		if dir.path != "" {
+			checks = append(checks, checkWritable(dir.name, dir.path))
+		}
+	}
+
+	checks = append(checks, checkPorts(options.Ports))
+
+	if options.Broker != nil && options.Broker.BrokerURL != "" {
+		checks = append(checks, checkBroker(options.Broker))
+	}
+
+	return checks
+}
+
+// checkBinary checks that a Pact CLI tool is installed at a compatible version
+func (i *Installer) checkBinary(binary string) Check {
+	check := Check{Name: binary}
+
+	version, err := i.GetVersionForBinary(binary)
+	if err != nil {
+		check.Err = types.NewError(types.ErrCLITools, fmt.Errorf("unable to run %s: %v", binary, err))
+		check.Remedy = "Install the pact-ruby-standalone distribution (https://github.com/pact-foundation/pact-ruby-standalone/releases) and add its bin directory to the PATH"
+		return check
+	}
+
+	if err = i.CheckVersion(binary, version); err != nil {
+		check.Err = types.NewError(types.ErrCLITools, err)
+		check.Remedy = fmt.Sprintf("Install a version of the pact-ruby-standalone distribution that includes %s %s", binary, versionMap[binary])
+		return check
+	}
+
+	check.Detail = version
+
+	return check
+}
+
+// checkWritable checks that files can be created in the directory at path,
+// creating it if need be
+func checkWritable(name string, path string) Check {
+	check := Check{Name: name, Detail: path}
+
+	err := os.MkdirAll(path, os.ModePerm)
+	if err == nil {
+		var f *os.File
+		if f, err = ioutil.TempFile(path, ".pact-go-doctor"); err == nil {
+			f.Close()
+			os.Remove(f.Name())
+		}
+	}
+
+	if err != nil {
+		check.Err = types.NewError(types.ErrInvalidRequest, fmt.Errorf("%s '%s' is not writable: %v", name, path, err))
+		check.Remedy = fmt.Sprintf("Set the %s of the Pact to a writable directory, or fix the permissions of '%s'", name, path)
+	}
+
+	return check
+}
+
+// checkPorts checks that a port can be allocated for the Mock Service
+func checkPorts(ports utils.PortAllocator) Check {
+	check := Check{Name: "Mock Service port"}
+
+	if ports == nil {
+		ports = utils.EphemeralPorts
+	}
+
+	port, err := ports.AllocatePort()
+	if err != nil {
+		check.Err = types.NewError(types.ErrServiceStartup, fmt.Errorf("unable to find a free port: %v", err))
+		check.Remedy = "Free up a port in the allowed range, or allow more ports with AllowedMockServerPorts or the PortAllocator of the Pact"
+		return check
+	}
+
+	check.Detail = fmt.Sprintf("%d is free", port)
+
+	return check
+}
+
+// checkBroker checks that the Pact Broker can be reached with the credentials
+// of the client
+func checkBroker(client *broker.Client) Check {
+	check := Check{Name: "Pact Broker", Detail: client.BrokerURL}
+
+	if _, err := client.Index(); err != nil {
+		check.Err = err
+		check.Remedy = "Check the broker URL, and that it can be reached from here e.g. via the HTTPS_PROXY"
+		if errors.Is(err, types.ErrBrokerAuth) {
+			check.Remedy = "Check the broker username and password, or token, and that they have not expired"
+		}
+	}
+
+	return check
+}
+
+// Healthy returns the first failure of the checks, if any
+func Healthy(checks []Check) error {
+	for _, check := range checks {
+		if check.Err != nil {
+			return check.Err
+		}
+	}
+
+	return nil
+}
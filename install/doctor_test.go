@@ -0,0 +1,77 @@
+package install
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ray-xu-deltatre/pact-go/broker"
+	"github.com/ray-xu-deltatre/pact-go/types"
+	"github.com/ray-xu-deltatre/pact-go/utils"
+)
+
+func TestInstaller_Doctor(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "pact-doctor")
+	defer os.RemoveAll(dir)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprintln(w, `{"_links":{}}`)
+	}))
+	defer ts.Close()
+
+	i := getInstaller("1.0.0", nil)
+	checks := i.Doctor(DoctorOptions{
+		PactDir: filepath.Join(dir, "pacts"),
+		LogDir:  filepath.Join(dir, "logs"),
+		Broker:  &broker.Client{BrokerURL: ts.URL, BrokerToken: "token"},
+	})
+
+	if len(checks) != 7 {
+		t.Fatalf("expected 7 checks but got %d", len(checks))
+	}
+	if err := Healthy(checks); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if checks[0].Name != "pact-broker" || checks[0].Detail != "1.0.0" {
+		t.Fatalf("expected the version of each CLI tool but got %v", checks[0])
+	}
+	if _, err := os.Stat(filepath.Join(dir, "pacts")); err != nil {
+		t.Fatal("expected the PactDir to be created")
+	}
+}
+
+func TestInstaller_DoctorFailures(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer ts.Close()
+
+	file, _ := ioutil.TempFile("", "pact-doctor")
+	file.Close()
+	defer os.Remove(file.Name())
+
+	i := getInstaller("2.0.0", nil)
+	checks := i.Doctor(DoctorOptions{
+		PactDir: filepath.Join(file.Name(), "pacts"),
+		Broker:  &broker.Client{BrokerURL: ts.URL, BrokerToken: "expired"},
+		Ports:   utils.PortAllocatorFunc(func() (int, error) { return 0, errors.New("no ports left") }),
+	})
+
+	for n, category := range []error{types.ErrCLITools, types.ErrCLITools, types.ErrCLITools, types.ErrInvalidRequest, types.ErrServiceStartup, types.ErrBrokerAuth} {
+		if !errors.Is(checks[n].Err, category) || checks[n].Remedy == "" {
+			t.Fatalf("expected check '%s' to fail with %v and a remedy but got %v", checks[n].Name, category, checks[n])
+		}
+	}
+	if !errors.Is(Healthy(checks), types.ErrCLITools) {
+		t.Fatal("expected the first failure to be returned")
+	}
+}
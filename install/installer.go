@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	goversion "github.com/hashicorp/go-version"
+	"github.com/ray-xu-deltatre/pact-go/types"
 )
 
 // Installer manages the underlying Ruby installation
@@ -33,7 +34,8 @@ func NewInstaller() *Installer {
 	return &Installer{commander: realCommander{}}
 }
 
-// CheckInstallation checks installation of all of the tools
+// CheckInstallation checks installation of all of the tools. Failures are
+// a types.ErrCLITools.
 func (i *Installer) CheckInstallation() error {
 
 	for binary, versionRange := range versionMap {
@@ -41,11 +43,11 @@ func (i *Installer) CheckInstallation() error {
 
 		version, err := i.GetVersionForBinary(binary)
 		if err != nil {
-			return err
+			return types.NewError(types.ErrCLITools, err)
 		}
 
 		if err = i.CheckVersion(binary, version); err != nil {
-			return err
+			return types.NewError(types.ErrCLITools, err)
 		}
 	}
 
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+
+	"github.com/ray-xu-deltatre/pact-go/types"
 )
 
 type testCommander struct {
@@ -112,4 +114,8 @@ func TestInstaller_CheckInstallationError(t *testing.T) {
 	if err == nil {
 		t.Fatal("Want error, got nil")
 	}
+
+	if !errors.Is(err, types.ErrCLITools) {
+		t.Fatal("Want ErrCLITools, got", err)
+	}
 }
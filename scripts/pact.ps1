@@ -70,18 +70,20 @@ foreach ($package in $packages) {
 Write-Host "--> Testing E2E examples"
 Write-Host "Running consumer tests"
 docker-compose up -d
-go test -tags=consumer -count=1 github.com/ray-xu-deltatre/pact-go/examples/... -run TestExample
+Push-Location examples
+go test -tags=consumer -count=1 ./... -run TestExample
 if ($LastExitCode -ne 0) {
   Write-Host "ERROR: Test failed, logging failure"
   $exitCode=1
 }
 
 Write-Host "Running provider tests"
-go test -tags=provider -count=1 github.com/ray-xu-deltatre/pact-go/examples/... -run TestExample
+go test -tags=provider -count=1 ./... -run TestExample
 if ($LastExitCode -ne 0) {
   Write-Host "ERROR: Test failed, logging failure"
   $exitCode=1
 }
+Pop-Location
 
 # Shutdown
 Write-Host "Shutting down any remaining pact processes :)"
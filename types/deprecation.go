@@ -0,0 +1,45 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Deprecation of a resource of the provider, as announced by the Deprecation
+// and Sunset headers of its response to a request. See
+// VerifyRequest.ReportDeprecations
+type Deprecation struct {
+	// Request the response was to, e.g. "GET /users/10"
+	Request string
+
+	// Deprecation is the value of the Deprecation header, e.g. "@1688169599"
+	// or "true", if any
+	Deprecation string
+
+	// Sunset is the value of the Sunset header, the date after which the
+	// resource is expected to be removed, if any
+	Sunset string
+
+	// Links to documentation of the deprecation, from the Link headers with
+	// the relation type "deprecation" or "sunset"
+	Links []string
+}
+
+func (d Deprecation) String() string {
+	var details []string
+	if d.Deprecation != "" && !strings.EqualFold(d.Deprecation, "true") {
+		details = append(details, fmt.Sprintf("deprecated %s", d.Deprecation))
+	}
+	if d.Sunset != "" {
+		details = append(details, fmt.Sprintf("sunset %s", d.Sunset))
+	}
+	for _, link := range d.Links {
+		details = append(details, fmt.Sprintf("see %s", link))
+	}
+
+	if len(details) == 0 {
+		return fmt.Sprintf("%s is deprecated", d.Request)
+	}
+
+	return fmt.Sprintf("%s is deprecated (%s)", d.Request, strings.Join(details, ", "))
+}
@@ -0,0 +1,20 @@
+package types
+
+import "testing"
+
+func TestDeprecation_String(t *testing.T) {
+	for _, tt := range []struct {
+		deprecation Deprecation
+		want        string
+	}{
+		{Deprecation{Request: "GET /users", Deprecation: "true"}, "GET /users is deprecated"},
+		{
+			Deprecation{Request: "GET /users", Deprecation: "@1688169599", Sunset: "Sun, 30 Jun 2024 23:59:59 GMT", Links: []string{"https://example.com/deprecation"}},
+			"GET /users is deprecated (deprecated @1688169599, sunset Sun, 30 Jun 2024 23:59:59 GMT, see https://example.com/deprecation)",
+		},
+	} {
+		if got := tt.deprecation.String(); got != tt.want {
+			t.Fatalf("expected '%s' but got '%s'", tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package types
+
+import (
+	"errors"
+)
+
+// Categories of failure returned by the library. Use errors.Is to branch on
+// the category of an error, rather than matching its message e.g.
+//
+//	if errors.Is(err, types.ErrMismatch) { ... }
+//
+// The underlying error is available via errors.Unwrap or errors.As.
+var (
+	// ErrMismatch indicates the Mock Service did not receive the expected interactions
+	ErrMismatch = errors.New("pact: interactions did not match")
+
+	// ErrVerification indicates that a provider failed verification
+	ErrVerification = errors.New("pact: provider verification failed")
+
+	// ErrBroker indicates a failed interaction with a Pact Broker
+	ErrBroker = errors.New("pact: broker request failed")
+
+	// ErrBrokerAuth indicates the Pact Broker rejected the credentials provided.
+	// Errors of this category are also an ErrBroker.
+	ErrBrokerAuth = errors.New("pact: broker authentication failed")
+
+	// ErrCLITools indicates the Pact CLI tools are missing or out of date
+	ErrCLITools = errors.New("pact: CLI tools are missing or out of date")
+
+	// ErrServiceStartup indicates a Pact CLI service, such as the Mock Service,
+	// failed to start
+	ErrServiceStartup = errors.New("pact: service failed to start")
+
+	// ErrMessage indicates a message pact could not be created
+	ErrMessage = errors.New("pact: unable to create message")
+
+	// ErrInvalidRequest indicates invalid input e.g. a missing mandatory field
+	ErrInvalidRequest = errors.New("pact: invalid request")
+
+	// ErrLimitExceeded indicates a pact exceeded a limit set on the Pact, e.g.
+	// its MaxInteractions. Errors of this category are also an
+	// ErrInvalidRequest.
+	ErrLimitExceeded = errors.New("pact: limit exceeded")
+)
+
+// parentCategories maps sub-categories to their more general category
+var parentCategories = map[error]error{
+	ErrBrokerAuth:    ErrBroker,
+	ErrLimitExceeded: ErrInvalidRequest,
+}
+
+// Exit codes for each category of failure, for use by CLI tools and scripts
+const (
+	ExitCodeOK             = 0
+	ExitCodeError          = 1
+	ExitCodeMismatch       = 2
+	ExitCodeVerification   = 3
+	ExitCodeBroker         = 4
+	ExitCodeBrokerAuth     = 5
+	ExitCodeCLITools       = 6
+	ExitCodeServiceStartup = 7
+	ExitCodeMessage        = 8
+	ExitCodeInvalidRequest = 9
+	ExitCodeLimitExceeded  = 10
+)
+
+var exitCodes = []struct {
+	category error
+	code     int
+}{
+	// Sub-categories must come before their parent
+	{ErrBrokerAuth, ExitCodeBrokerAuth},
+	{ErrBroker, ExitCodeBroker},
+	{ErrLimitExceeded, ExitCodeLimitExceeded},
+	{ErrMismatch, ExitCodeMismatch},
+	{ErrVerification, ExitCodeVerification},
+	{ErrCLITools, ExitCodeCLITools},
+	{ErrServiceStartup, ExitCodeServiceStartup},
+	{ErrMessage, ExitCodeMessage},
+	{ErrInvalidRequest, ExitCodeInvalidRequest},
+}
+
+// Error is an error with a category. The message of the underlying error is
+// preserved.
+type Error struct {
+	// Category is one of the Err* values in this package
+	Category error
+
+	// Err is the underlying error
+	Err error
+}
+
+// NewError categorises err. A nil err returns nil.
+func NewError(category error, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	// Keep the most specific category if the error is already categorised
+	var e *Error
+	if errors.As(err, &e) && inCategory(e.Category, category) {
+		return err
+	}
+
+	return &Error{
+		Category: category,
+		Err:      err,
+	}
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the error is of the target category, or any of its parents
+func (e *Error) Is(target error) bool {
+	return inCategory(e.Category, target)
+}
+
+func inCategory(category error, target error) bool {
+	for ; category != nil; category = parentCategories[category] {
+		if category == target {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ExitCode returns the exit code for the category of err, ExitCodeOK if err is
+// nil, or ExitCodeError if it has no category.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitCodeOK
+	}
+
+	for _, c := range exitCodes {
+		if errors.Is(err, c.category) {
+			return c.code
+		}
+	}
+
+	return ExitCodeError
+}
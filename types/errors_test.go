@@ -0,0 +1,75 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestError_Is(t *testing.T) {
+	cause := errors.New("401 Unauthorized")
+	err := NewError(ErrBrokerAuth, cause)
+
+	if !errors.Is(err, ErrBrokerAuth) {
+		t.Fatal("expected error to be an ErrBrokerAuth")
+	}
+	if !errors.Is(err, ErrBroker) {
+		t.Fatal("expected error to be an ErrBroker")
+	}
+	if errors.Is(err, ErrMismatch) {
+		t.Fatal("expected error not to be an ErrMismatch")
+	}
+	if !errors.Is(err, cause) {
+		t.Fatal("expected the underlying error to be preserved")
+	}
+	if err.Error() != "401 Unauthorized" {
+		t.Fatalf("expected the underlying message to be preserved but got '%s'", err.Error())
+	}
+
+	wrapped := fmt.Errorf("publishing: %w", err)
+	var e *Error
+	if !errors.As(wrapped, &e) || e.Category != ErrBrokerAuth {
+		t.Fatal("expected to find the categorised error in the chain")
+	}
+}
+
+func TestError_NewError(t *testing.T) {
+	if NewError(ErrMismatch, nil) != nil {
+		t.Fatal("expected a nil error to remain nil")
+	}
+
+	err := NewError(ErrBroker, NewError(ErrBrokerAuth, errors.New("denied")))
+	if !errors.Is(err, ErrBrokerAuth) {
+		t.Fatal("expected the more specific category to be kept")
+	}
+
+	err = NewError(ErrVerification, NewError(ErrInvalidRequest, errors.New("invalid")))
+	if !errors.Is(err, ErrVerification) || !errors.Is(err, ErrInvalidRequest) {
+		t.Fatal("expected both categories to be present")
+	}
+}
+
+func TestError_ExitCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{nil, ExitCodeOK},
+		{errors.New("unknown"), ExitCodeError},
+		{NewError(ErrMismatch, errors.New("mismatch")), ExitCodeMismatch},
+		{NewError(ErrVerification, errors.New("failed")), ExitCodeVerification},
+		{NewError(ErrBroker, errors.New("500")), ExitCodeBroker},
+		{NewError(ErrBrokerAuth, errors.New("401")), ExitCodeBrokerAuth},
+		{NewError(ErrCLITools, errors.New("missing")), ExitCodeCLITools},
+		{NewError(ErrServiceStartup, errors.New("timeout")), ExitCodeServiceStartup},
+		{NewError(ErrMessage, errors.New("bad")), ExitCodeMessage},
+		{NewError(ErrInvalidRequest, errors.New("bad")), ExitCodeInvalidRequest},
+		{NewError(ErrLimitExceeded, errors.New("too many")), ExitCodeLimitExceeded},
+	}
+
+	for _, tt := range tests {
+		if got := ExitCode(tt.err); got != tt.want {
+			t.Fatalf("expected exit code %d for '%v' but got %d", tt.want, tt.err, got)
+		}
+	}
+}
@@ -6,4 +6,8 @@ type MockServer struct {
 	Port  int
 	Error error
 	Args  []string
+
+	// SocketPath is the Unix domain socket the Mock Server also listens on, if
+	// any, see Pact.UnixSocket
+	SocketPath string
 }
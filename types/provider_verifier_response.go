@@ -25,12 +25,21 @@ type ProviderVerifierResponse struct {
 			Message   string   `json:"message"`
 			Backtrace []string `json:"backtrace"`
 		} `json:"exception,omitempty"`
+
+		// Flaky is true if the interaction passed only when retried. See
+		// VerifyRequest.Retries
+		Flaky bool `json:"-"`
+
+		// Deprecation announced by the provider in its response to the
+		// interaction, if any. See VerifyRequest.ReportDeprecations
+		Deprecation *Deprecation `json:"-"`
 	} `json:"examples"`
 	Summary struct {
 		Duration                     float64 `json:"duration"`
 		ExampleCount                 int     `json:"example_count"`
 		FailureCount                 int     `json:"failure_count"`
 		PendingCount                 int     `json:"pending_count"`
+		FlakyCount                   int     `json:"-"`
 		ErrorsOutsideOfExamplesCount int     `json:"errors_outside_of_examples_count"`
 		Notices                      []struct {
 			Text string `json:"text"`
@@ -38,4 +47,9 @@ type ProviderVerifierResponse struct {
 		} `json:"notices"`
 	} `json:"summary"`
 	SummaryLine string `json:"summary_line"`
+
+	// Output is the log output (stderr, and any stdout other than the results)
+	// of the verifier process that produced this response. It is only printed
+	// to the test log if verification fails, or when running go test -v.
+	Output string `json:"-"`
 }
@@ -3,6 +3,7 @@ package types
 import (
 	"errors"
 	"fmt"
+	"time"
 )
 
 // PublishRequest contains the details required to Publish Pacts to a broker.
@@ -22,6 +23,26 @@ type PublishRequest struct {
 	// BrokerToken is required when authenticating using the Bearer token mechanism
 	BrokerToken string
 
+	// BrokerTokenSource supplies the bearer token when BrokerToken is not set
+	BrokerTokenSource TokenSource
+
+	// MaxRetries is the number of times to retry publishing a pact that fails
+	// with a 5xx or a 429 response, honouring any Retry-After
+	// header. If set, or ProxyURL is, pacts are published with the broker
+	// package rather than the Pact CLI tools. Optional
+	MaxRetries int
+
+	// RetryBackoff is the delay before the first retry, doubling with each
+	// subsequent retry. Defaults to 1s
+	RetryBackoff time.Duration
+
+	// MaxRetryBackoff caps the delay between retries. Defaults to 30s
+	MaxRetryBackoff time.Duration
+
+	// ProxyURL is the proxy to publish through, overriding the HTTP_PROXY and
+	// HTTPS_PROXY environment variables. Optional
+	ProxyURL string
+
 	// ConsumerVersion is the semantical version of the consumer API.
 	ConsumerVersion string
 
@@ -29,6 +50,13 @@ type PublishRequest struct {
 	// e.g. "production", "master" and "development" are some common examples.
 	Tags []string
 
+	// ConsumerRepositoryURL, ConsumerMainBranch and ConsumerLabels describe
+	// the consumers of the pacts in the broker. If any are set, the consumers
+	// are created or updated with them after the pacts are published. Optional
+	ConsumerRepositoryURL string
+	ConsumerMainBranch    string
+	ConsumerLabels        []string
+
 	// Verbose increases verbosity of output
 	// Deprecated
 	Verbose bool
@@ -67,8 +95,12 @@ func (p *PublishRequest) Validate() error {
 	}
 	p.Args = append(p.Args, "--broker-base-url", p.PactBroker)
 
-	if p.BrokerToken != "" {
-		p.Args = append(p.Args, "--broker-token", p.BrokerToken)
+	token, err := brokerToken(p.BrokerToken, p.BrokerTokenSource)
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		p.Args = append(p.Args, "--broker-token", token)
 	}
 
 	if p.ConsumerVersion == "" {
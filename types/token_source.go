@@ -0,0 +1,41 @@
+package types
+
+// TokenSource supplies bearer tokens for authenticating to a Pact Broker, so
+// that short-lived tokens (e.g. OIDC tokens issued to CI jobs) can be used in
+// place of a static BrokerToken. Token is called whenever a token is required,
+// so implementations should cache tokens until they expire.
+//
+// An oauth2.TokenSource from golang.org/x/oauth2 can be used with:
+//
+//	types.TokenSourceFunc(func() (string, error) {
+//		t, err := ts.Token()
+//		if err != nil {
+//			return "", err
+//		}
+//		return t.AccessToken, nil
+//	})
+type TokenSource interface {
+	Token() (string, error)
+}
+
+// TokenSourceFunc is a function that implements TokenSource
+type TokenSourceFunc func() (string, error)
+
+// Token returns the result of calling f
+func (f TokenSourceFunc) Token() (string, error) {
+	return f()
+}
+
+// brokerToken returns the static token if given, otherwise a token from source
+func brokerToken(token string, source TokenSource) (string, error) {
+	if token != "" || source == nil {
+		return token, nil
+	}
+
+	t, err := source.Token()
+	if err != nil {
+		return "", NewError(ErrBrokerAuth, err)
+	}
+
+	return t, nil
+}
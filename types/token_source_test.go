@@ -0,0 +1,61 @@
+package types
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTokenSource_PublishRequest(t *testing.T) {
+	p := PublishRequest{
+		PactBroker:      "http://foo.com",
+		PactURLs:        []string{"pacts/foo.json"},
+		ConsumerVersion: "1.0.0",
+		BrokerTokenSource: TokenSourceFunc(func() (string, error) {
+			return "short-lived", nil
+		}),
+	}
+
+	if err := p.Validate(); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if !contains(p.Args, "short-lived") {
+		t.Fatalf("expected the token to be passed as an argument: %v", p.Args)
+	}
+}
+
+func TestTokenSource_VerifyRequest(t *testing.T) {
+	v := VerifyRequest{
+		ProviderBaseURL: "http://localhost:8080",
+		BrokerURL:       "http://foo.com",
+		ProviderVersion: "1.0.0",
+		BrokerToken:     "static",
+		BrokerTokenSource: TokenSourceFunc(func() (string, error) {
+			return "short-lived", nil
+		}),
+	}
+
+	if err := v.Validate(); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if !contains(v.Args, "static") || contains(v.Args, "short-lived") {
+		t.Fatalf("expected a static token to take precedence: %v", v.Args)
+	}
+
+	v.BrokerToken = ""
+	v.BrokerTokenSource = TokenSourceFunc(func() (string, error) {
+		return "", errors.New("unable to fetch token")
+	})
+	if err := v.Validate(); !errors.Is(err, ErrBrokerAuth) {
+		t.Fatalf("expected a broker authentication error but got '%v'", err)
+	}
+}
+
+func contains(args []string, value string) bool {
+	for _, a := range args {
+		if a == value {
+			return true
+		}
+	}
+
+	return false
+}
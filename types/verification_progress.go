@@ -0,0 +1,50 @@
+package types
+
+import "time"
+
+// VerificationEventType is the type of a VerificationEvent
+type VerificationEventType string
+
+const (
+	// VerificationStarted is sent when a request is replayed against the provider
+	VerificationStarted VerificationEventType = "started"
+
+	// VerificationPassed is sent when an interaction passed verification, once
+	// its pact has been verified
+	VerificationPassed VerificationEventType = "passed"
+
+	// VerificationFailed is sent when an interaction failed verification, once
+	// its pact has been verified
+	VerificationFailed VerificationEventType = "failed"
+
+	// VerificationPending is sent when a pending interaction failed
+	// verification, once its pact has been verified
+	VerificationPending VerificationEventType = "pending"
+)
+
+// VerificationEvent reports the progress of a provider verification
+type VerificationEvent struct {
+	Type VerificationEventType
+
+	// Consumer and Provider of the pact. Not known for VerificationStarted
+	Consumer string
+	Provider string
+
+	// Description of the interaction e.g. "A request to login",
+	// or the method and path of the request for VerificationStarted
+	Description string
+
+	// Duration of the verification of the interaction
+	Duration time.Duration
+
+	// Message contains the reason for a failure
+	Message string
+}
+
+// ProgressHandler is called as provider verification progresses.
+// VerificationStarted is sent live, as each request is replayed. The results of
+// the interactions of a pact are only sent once the verifier has finished the
+// whole pact, as they are read from its final JSON output, so they arrive
+// together rather than as each interaction is verified. It may be called
+// concurrently when verifying pacts in parallel.
+type ProgressHandler func(VerificationEvent)
@@ -0,0 +1,79 @@
+package types
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// VerifyConfig is the declarative form of a VerifyRequest, to be kept in a
+// YAML (or JSON) file so that the verification setup can be reviewed and
+// shared. Environment variables in the file, e.g. ${PACT_BROKER_TOKEN}, are
+// expanded. See LoadVerifyRequest.
+type VerifyConfig struct {
+	Provider        string   `yaml:"provider"`
+	ProviderBaseURL string   `yaml:"providerBaseUrl"`
+	PactURLs        []string `yaml:"pactUrls"`
+
+	Broker struct {
+		URL      string `yaml:"url"`
+		Username string `yaml:"username"`
+		Password string `yaml:"password"`
+		Token    string `yaml:"token"`
+	} `yaml:"broker"`
+
+	ConsumerVersionSelectors []ConsumerVersionSelector `yaml:"consumerVersionSelectors"`
+	ConsumerVersionTags      []string                  `yaml:"consumerVersionTags"`
+	EnablePending            bool                      `yaml:"enablePending"`
+	FailIfNoPactsFound       bool                      `yaml:"failIfNoPactsFound"`
+
+	ProviderStatesSetupURL string   `yaml:"providerStatesSetupUrl"`
+	CustomProviderHeaders  []string `yaml:"customProviderHeaders"`
+
+	Publish struct {
+		Results         bool     `yaml:"results"`
+		ProviderVersion string   `yaml:"providerVersion"`
+		ProviderTags    []string `yaml:"providerTags"`
+	} `yaml:"publish"`
+}
+
+// LoadVerifyRequest reads a VerifyConfig from the YAML (or JSON) file at path.
+// Unknown keys are an error, to catch typos. Settings that can only be given
+// in code, such as StateHandlers, may be added to the request returned.
+func LoadVerifyRequest(path string) (VerifyRequest, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return VerifyRequest{}, NewError(ErrInvalidRequest, fmt.Errorf("unable to read verification config: %v", err))
+	}
+
+	var config VerifyConfig
+	if err = yaml.UnmarshalStrict([]byte(os.ExpandEnv(string(content))), &config); err != nil {
+		return VerifyRequest{}, NewError(ErrInvalidRequest, fmt.Errorf("invalid verification config '%s': %v", path, err))
+	}
+
+	return config.VerifyRequest(), nil
+}
+
+// VerifyRequest returns the request described by the config
+func (c VerifyConfig) VerifyRequest() VerifyRequest {
+	return VerifyRequest{
+		Provider:                   c.Provider,
+		ProviderBaseURL:            c.ProviderBaseURL,
+		PactURLs:                   c.PactURLs,
+		BrokerURL:                  c.Broker.URL,
+		BrokerUsername:             c.Broker.Username,
+		BrokerPassword:             c.Broker.Password,
+		BrokerToken:                c.Broker.Token,
+		ConsumerVersionSelectors:   c.ConsumerVersionSelectors,
+		Tags:                       c.ConsumerVersionTags,
+		EnablePending:              c.EnablePending,
+		FailIfNoPactsFound:         c.FailIfNoPactsFound,
+		ProviderStatesSetupURL:     c.ProviderStatesSetupURL,
+		CustomProviderHeaders:      c.CustomProviderHeaders,
+		PublishVerificationResults: c.Publish.Results,
+		ProviderVersion:            c.Publish.ProviderVersion,
+		ProviderTags:               c.Publish.ProviderTags,
+	}
+}
@@ -0,0 +1,97 @@
+package types
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeVerifyConfig(t *testing.T, name, content string) string {
+	dir, err := ioutil.TempDir("", "pact-go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, name)
+	if err = ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestVerifyConfig_LoadYAML(t *testing.T) {
+	os.Setenv("PACT_CONFIG_TEST_TOKEN", "secret")
+	defer os.Unsetenv("PACT_CONFIG_TEST_TOKEN")
+
+	path := writeVerifyConfig(t, "verify.yml", `
+provider: bobby
+providerBaseUrl: http://localhost:8080
+broker:
+  url: http://broker.example.com
+  token: ${PACT_CONFIG_TEST_TOKEN}
+consumerVersionSelectors:
+  - tag: master
+    latest: true
+enablePending: true
+providerStatesSetupUrl: http://localhost:8080/setup
+publish:
+  results: true
+  providerVersion: 1.0.0
+  providerTags: [master]
+`)
+	defer os.RemoveAll(filepath.Dir(path))
+
+	request, err := LoadVerifyRequest(path)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	expected := VerifyRequest{
+		Provider:                   "bobby",
+		ProviderBaseURL:            "http://localhost:8080",
+		BrokerURL:                  "http://broker.example.com",
+		BrokerToken:                "secret",
+		ConsumerVersionSelectors:   []ConsumerVersionSelector{{Tag: "master", Latest: true}},
+		EnablePending:              true,
+		ProviderStatesSetupURL:     "http://localhost:8080/setup",
+		PublishVerificationResults: true,
+		ProviderVersion:            "1.0.0",
+		ProviderTags:               []string{"master"},
+	}
+	if !reflect.DeepEqual(request, expected) {
+		t.Fatalf("expected %+v but got %+v", expected, request)
+	}
+}
+
+func TestVerifyConfig_LoadJSON(t *testing.T) {
+	path := writeVerifyConfig(t, "verify.json", `{
+  "providerBaseUrl": "http://localhost:8080",
+  "pactUrls": ["pacts/foo-bar.json"]
+}`)
+	defer os.RemoveAll(filepath.Dir(path))
+
+	request, err := LoadVerifyRequest(path)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if request.ProviderBaseURL != "http://localhost:8080" || !reflect.DeepEqual(request.PactURLs, []string{"pacts/foo-bar.json"}) {
+		t.Fatalf("unexpected request: %+v", request)
+	}
+}
+
+func TestVerifyConfig_LoadUnknownKey(t *testing.T) {
+	path := writeVerifyConfig(t, "verify.yml", "providerBaseURL: http://localhost:8080\n")
+	defer os.RemoveAll(filepath.Dir(path))
+
+	if _, err := LoadVerifyRequest(path); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected an invalid request error for an unknown key but got '%v'", err)
+	}
+}
+
+func TestVerifyConfig_LoadMissingFile(t *testing.T) {
+	if _, err := LoadVerifyRequest("does-not-exist.yml"); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected an invalid request error for a missing file but got '%v'", err)
+	}
+}
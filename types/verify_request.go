@@ -54,6 +54,9 @@ type VerifyRequest struct {
 	// BrokerToken is required when authenticating using the Bearer token mechanism
 	BrokerToken string
 
+	// BrokerTokenSource supplies the bearer token when BrokerToken is not set
+	BrokerTokenSource TokenSource
+
 	// FailIfNoPactsFound configures the framework to return an error
 	// if no pacts were found when looking up from a broker
 	FailIfNoPactsFound bool
@@ -114,6 +117,57 @@ type VerifyRequest struct {
 	// Useful for debugging issues with the framework itself
 	PactLogLevel string
 
+	// VerificationCacheDir enables caching of successful verifications of local
+	// pact files, keyed by the content of the pact and the ProviderVersion.
+	// Pacts that have already been verified are skipped, and their previous
+	// results returned. Requires ProviderVersion. Optional
+	VerificationCacheDir string
+
+	// OnlyChangedPacts skips the pacts fetched from the broker that this
+	// ProviderVersion has already verified successfully, i.e. whose content has
+	// not changed since, returning a "reused" result for each in their place.
+	// All pacts are verified if any is pending or WIP. Optional
+	OnlyChangedPacts bool
+
+	// Concurrency is the maximum number of pacts to verify at once, when
+	// verifying more than one of PactURLs. State handlers and the provider must
+	// then be able to handle interactions from different pacts concurrently.
+	// Defaults to 1
+	Concurrency int
+
+	// Retries is the number of times to verify pacts with failed interactions
+	// again, e.g. when provider states are only eventually consistent.
+	// Interactions that pass when retried are marked as Flaky in the results.
+	// Cannot be used with PublishVerificationResults, as the verifier would
+	// publish the results of each attempt. Optional
+	Retries int
+
+	// RetryDelay is the time to wait before each retry. Optional
+	RetryDelay time.Duration
+
+	// FailureReportFile is the file to write the interactions that failed
+	// verification to, as JSON, and to read them from with RerunFailed.
+	// Optional
+	FailureReportFile string
+
+	// RerunFailed verifies only the interactions in the FailureReportFile,
+	// e.g. while fixing them, and rewrites the report with those that still
+	// fail. The interactions of remote pacts can't be selected, so those pacts
+	// are verified in full. Optional
+	RerunFailed bool
+
+	// ReportDeprecations records the Deprecation and Sunset headers of the
+	// responses of the provider, and reports them as the Deprecation of the
+	// interactions in the results, so that consumers learn of resources that
+	// are to be removed. Optional
+	ReportDeprecations bool
+
+	// ProgressHandler is called as verification progresses: as each request
+	// is replayed, and with the results of the interactions of each pact once
+	// the verifier has finished it (they are read from its final JSON output,
+	// so they are not live). Optional
+	ProgressHandler ProgressHandler
+
 	// Verbose increases verbosity of output
 	// Deprecated
 	Verbose bool
@@ -186,8 +240,12 @@ func (v *VerifyRequest) Validate() error {
 		v.Args = append(v.Args, "--pact-broker-base-url", v.BrokerURL)
 	}
 
-	if v.BrokerToken != "" {
-		v.Args = append(v.Args, "--broker-token", v.BrokerToken)
+	token, err := brokerToken(v.BrokerToken, v.BrokerTokenSource)
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		v.Args = append(v.Args, "--broker-token", token)
 	}
 
 	if v.BrokerURL != "" && v.ProviderVersion == "" {
@@ -0,0 +1,132 @@
+package utils
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// PortAllocator finds a port for a service, such as the Mock Service or the
+// verification proxy, to listen on. Implementations must be safe for
+// concurrent use.
+type PortAllocator interface {
+	AllocatePort() (int, error)
+}
+
+// PortAllocatorFunc allows a function to be used as a PortAllocator
+type PortAllocatorFunc func() (int, error)
+
+// AllocatePort calls f()
+func (f PortAllocatorFunc) AllocatePort() (int, error) {
+	return f()
+}
+
+// EphemeralPorts asks the kernel for a random free port, as per GetFreePort.
+// This is the default.
+var EphemeralPorts PortAllocator = PortAllocatorFunc(GetFreePort)
+
+// portRange allocates ports from a list or range, handing them out in turn so
+// that services started in parallel don't race for the same free port
+type portRange struct {
+	mu    sync.Mutex
+	ports []int
+	next  int
+}
+
+// PortRange allocates free ports from the given list ("8081,8085") or range
+// ("8081-8085") of ports, as per FindPortInRange. Ports are handed out in turn,
+// so that services started at the same time are given different ports, e.g.
+// in heavily parallel CI jobs that are each assigned a block of ports.
+func PortRange(ports string) (PortAllocator, error) {
+	parsed, err := parsePorts(ports)
+	if err != nil {
+		return nil, err
+	}
+
+	return &portRange{ports: parsed}, nil
+}
+
+// AllocatePort returns the next free port in the range
+func (r *portRange) AllocatePort() (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := 0; i < len(r.ports); i++ {
+		port := r.ports[r.next]
+		r.next = (r.next + 1) % len(r.ports)
+
+		if checkPort(port) == nil {
+			return port, nil
+		}
+	}
+
+	return 0, errors.New("all passed ports are unusable")
+}
+
+// PortsFromEnv allocates ports from the list or range given by the environment
+// variable name (e.g. PACT_PORTS=8081-8085), as per PortRange. Ephemeral ports
+// are allocated if the variable is not set, e.g. outside of CI.
+func PortsFromEnv(name string) PortAllocator {
+	var once sync.Once
+	var allocator PortAllocator
+	var err error
+
+	return PortAllocatorFunc(func() (int, error) {
+		once.Do(func() {
+			ports := os.Getenv(name)
+			if ports == "" {
+				allocator = EphemeralPorts
+				return
+			}
+			allocator, err = PortRange(ports)
+		})
+
+		if err != nil {
+			return 0, err
+		}
+
+		return allocator.AllocatePort()
+	})
+}
+
+// parsePorts expands a list or range of ports, as accepted by FindPortInRange
+func parsePorts(s string) ([]int, error) {
+	s = strings.TrimSpace(s)
+
+	if !strings.Contains(s, "-") {
+		var ports []int
+		for _, p := range strings.Split(s, ",") {
+			port, err := strconv.Atoi(strings.TrimSpace(p))
+			if err != nil {
+				return nil, err
+			}
+			ports = append(ports, port)
+		}
+		return ports, nil
+	}
+
+	bounds := strings.Split(s, "-")
+	if len(bounds) != 2 {
+		return nil, errors.New("invalid range passed")
+	}
+	lower, err := strconv.Atoi(bounds[0])
+	if err != nil {
+		return nil, err
+	}
+	upper, err := strconv.Atoi(bounds[1])
+	if err != nil {
+		return nil, err
+	}
+	if upper < lower {
+		return nil, errors.New("invalid range passed")
+	}
+
+	ports := make([]int, 0, upper-lower+1)
+	for port := lower; port <= upper; port++ {
+		ports = append(ports, port)
+	}
+
+	return ports, nil
+}
@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"net"
+	"os"
+	"testing"
+)
+
+func Test_PortRange(t *testing.T) {
+	allocator, err := PortRange("6670-6672")
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	for _, expected := range []int{6670, 6671, 6672, 6670} {
+		port, err := allocator.AllocatePort()
+		if err != nil {
+			t.Fatalf("Error: %v", err)
+		}
+		if port != expected {
+			t.Fatalf("Expected port to be %d got %d", expected, port)
+		}
+	}
+}
+
+func Test_PortRangeWithUsedPorts(t *testing.T) {
+	l, err := net.Listen("tcp", "localhost:6673")
+	if err != nil {
+		t.Fatalf("Could not bind to port 6673 in test")
+	}
+	defer l.Close()
+
+	allocator, _ := PortRange("6673,6674")
+	if port, _ := allocator.AllocatePort(); port != 6674 {
+		t.Fatalf("Expected port to be 6674 got %d", port)
+	}
+
+	allocator, _ = PortRange("6673")
+	if _, err = allocator.AllocatePort(); err == nil || err.Error() != "all passed ports are unusable" {
+		t.Fatalf("expected all ports to be unusable, got %v", err)
+	}
+}
+
+func Test_PortRangeInvalid(t *testing.T) {
+	for _, ports := range []string{"abc", "6680-abc", "6681-6680", "1-2-3"} {
+		if _, err := PortRange(ports); err == nil {
+			t.Fatalf("expected an error for ports '%s'", ports)
+		}
+	}
+}
+
+func Test_PortsFromEnv(t *testing.T) {
+	os.Setenv("PACT_GO_TEST_PORTS", "6675")
+	defer os.Unsetenv("PACT_GO_TEST_PORTS")
+
+	port, err := PortsFromEnv("PACT_GO_TEST_PORTS").AllocatePort()
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if port != 6675 {
+		t.Fatalf("Expected port to be 6675 got %d", port)
+	}
+
+	port, err = PortsFromEnv("PACT_GO_TEST_PORTS_UNSET").AllocatePort()
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if port <= 0 {
+		t.Fatalf("Expected an ephemeral port, got %d", port)
+	}
+}